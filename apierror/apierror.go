@@ -0,0 +1,260 @@
+// Package apierror is the error every handler reports. The ErrorHandler
+// middleware renders it as
+//
+//	{"error": "food item was not found", "code": "not_found", "request_id": "..."}
+//
+// with "fields" added when the request body failed validation. Codes are
+// listed in docs/errors.md.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type Code string
+
+const (
+	CodeBadRequest         Code = "bad_request"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeUnauthorized       Code = "unauthorized"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeForbidden          Code = "forbidden"
+	CodeNotFound           Code = "not_found"
+	CodeConflict           Code = "conflict"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeIdempotencyReused  Code = "idempotency_key_reused"
+	CodeIdempotencyPending Code = "idempotency_key_in_progress"
+	CodePayloadTooLarge    Code = "payload_too_large"
+	CodeUnsupportedMedia   Code = "unsupported_media_type"
+	CodeInternal           Code = "internal_error"
+)
+
+// Codes is every code an error can have
+var Codes = []Code{
+	CodeBadRequest, CodeValidationFailed, CodeUnauthorized, CodeInvalidCredentials, CodeForbidden, CodeNotFound,
+	CodeConflict, CodeAlreadyExists, CodeIdempotencyReused, CodeIdempotencyPending, CodePayloadTooLarge, CodeUnsupportedMedia, CodeInternal,
+}
+
+// statusCodes is the code an error gets from its status when none is given
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMedia,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusInternalServerError:   CodeInternal,
+}
+
+// Error is an HTTP status with a code clients can switch on, a message for
+// people and, for validation failures, what is wrong with each field.
+// Cause is logged but never sent.
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Cause   error
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ErrorBody is what an error is sent to the client as
+type ErrorBody struct {
+	Error      string       `json:"error"`
+	Code       Code         `json:"code"`
+	Request_id string       `json:"request_id"`
+	Fields     []FieldError `json:"fields,omitempty"`
+}
+
+func (e *Error) Body(requestID string) ErrorBody {
+	return ErrorBody{Error: e.Message, Code: e.Code, Request_id: requestID, Fields: e.Fields}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// New is an error with the code that goes with status
+func New(status int, message string) *Error {
+	code, ok := statusCodes[status]
+	if !ok {
+		code = CodeInternal
+	}
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// WithCode replaces the code that goes with the status by a more precise one
+func (e *Error) WithCode(code Code) *Error {
+	e.Code = code
+	return e
+}
+
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, message)
+}
+
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, message)
+}
+
+// InvalidField is a 422 for one field of the body, such as an ID that
+// refers to nothing
+func InvalidField(field string, rule string, message string) *Error {
+	e := New(http.StatusUnprocessableEntity, message)
+	e.Fields = []FieldError{{Field: field, Rule: rule, Message: message}}
+	return e
+}
+
+// Internal hides cause from the client behind message
+func Internal(message string, cause error) *Error {
+	e := New(http.StatusInternalServerError, message)
+	e.Cause = cause
+	return e
+}
+
+// Wrap turns err into an Error with status. Validation failures become 422
+// with their fields, malformed JSON a 400, and the text of errors behind a
+// 500 is replaced so it does not leak.
+func Wrap(status int, err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return Validation(validationErrs)
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		e := New(http.StatusUnprocessableEntity, "request body has a value of the wrong type")
+		e.Fields = []FieldError{{Field: typeErr.Field, Rule: "type", Message: "must be " + jsonType(typeErr.Type.Kind().String())}}
+		return e
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return BadRequest("request body is not valid JSON")
+	}
+	if status >= http.StatusInternalServerError {
+		return Internal("internal server error", err)
+	}
+	return New(status, err.Error())
+}
+
+// Validation lists every field that failed, named as in the JSON body
+func Validation(errs validator.ValidationErrors) *Error {
+	e := New(http.StatusUnprocessableEntity, "request body failed validation")
+	for _, fieldErr := range errs {
+		e.Fields = append(e.Fields, FieldError{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Rule:    fieldErr.Tag(),
+			Message: ruleMessage(fieldErr),
+		})
+	}
+	return e
+}
+
+// fieldPath drops the struct name validator puts in front, "Food.price"
+// becomes "price"
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "min":
+		return "must be at least " + param + unit(fieldErr)
+	case "max":
+		return "must be at most " + param + unit(fieldErr)
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be at least " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be at most " + param
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(param, " ", ", ")
+	case "email":
+		return "must be an email address"
+	case "alphanum":
+		return "must only contain letters and digits"
+	case "unique":
+		return "must not repeat values"
+	case "datetime":
+		return "must be a time formatted as " + param
+	case "gtefield":
+		return "must be at least " + param
+	case "nefield":
+		return "must differ from " + param
+	}
+	//eq=A|eq=B style alternatives
+	if strings.HasPrefix(fieldErr.Tag(), "eq=") {
+		return "must be one of " + strings.ReplaceAll(strings.ReplaceAll(fieldErr.Tag(), "eq=", ""), "|", ", ")
+	}
+	return "failed the " + fieldErr.Tag() + " rule"
+}
+
+// unit is what min and max count for strings and lists
+func unit(fieldErr validator.FieldError) string {
+	switch fieldErr.Kind().String() {
+	case "string":
+		return " characters long"
+	case "slice", "array", "map":
+		return " items long"
+	}
+	return ""
+}
+
+func jsonType(kind string) string {
+	switch kind {
+	case "string":
+		return "a string"
+	case "bool":
+		return "true or false"
+	case "slice", "array":
+		return "an array"
+	case "struct", "map":
+		return "an object"
+	}
+	return "a number"
+}
@@ -0,0 +1,99 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Local stores blobs as files below a directory
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating image directory: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	name := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	//written beside the target and renamed, readers never see half a file
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (Object, error) {
+	if err := CheckKey(key); err != nil {
+		return Object{}, ErrNotFound
+	}
+	file, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return Object{}, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return Object{}, ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Object{
+		ReadCloser:   file,
+		Content_type: contentType,
+		Size:         info.Size(),
+		Etag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		Modified_at:  info.ModTime(),
+	}, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(l.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
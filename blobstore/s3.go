@@ -0,0 +1,179 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Options point S3 at AWS or any compatible service. Path style puts the
+// bucket in the path instead of the host name, which MinIO and most local
+// stand-ins expect.
+type S3Options struct {
+	Endpoint   string
+	Region     string
+	Bucket     string
+	Access_key string
+	Secret_key string
+	Path_style bool
+}
+
+// S3 stores blobs as objects of one bucket, signing requests with AWS
+// signature version 4
+type S3 struct {
+	options  S3Options
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3(options S3Options) (*S3, error) {
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("s3 endpoint must be an http or https url, got %q", options.Endpoint)
+	}
+	return &S3{options: options, endpoint: endpoint, client: &http.Client{}}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s.failure(resp)
+	}
+	return nil
+}
+
+func (s *S3) Open(ctx context.Context, key string) (Object, error) {
+	if err := CheckKey(key); err != nil {
+		return Object{}, ErrNotFound
+	}
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return Object{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return Object{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return Object{}, s.failure(resp)
+	}
+
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return Object{
+		ReadCloser:   resp.Body,
+		Content_type: resp.Header.Get("Content-Type"),
+		Size:         resp.ContentLength,
+		Etag:         resp.Header.Get("ETag"),
+		Modified_at:  modified,
+	}, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.failure(resp)
+	}
+	return nil
+}
+
+func (s *S3) do(ctx context.Context, method string, key string, contentType string, data []byte) (*http.Response, error) {
+	target := *s.endpoint
+	if s.options.Path_style {
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.options.Bucket + "/" + key
+	} else {
+		target.Host = s.options.Bucket + "." + target.Host
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + key
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.ContentLength = int64(len(data))
+	s.sign(req, data, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign adds the Authorization header of signature version 4, the keys
+// CheckKey allows need no escaping so the path is already canonical
+func (s *S3) sign(req *http.Request, payload []byte, now time.Time) {
+	payloadHash := sha256Hex(payload)
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{"host": req.URL.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers = []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+		values["content-type"] = contentType
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.options.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.options.Secret_key), day)
+	signingKey = hmacSHA256(signingKey, s.options.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.options.Access_key+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func (s *S3) failure(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %d %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store keeps uploaded files under slash separated keys. The local
+// filesystem and any S3-compatible service implement it, so the S3 one can
+// be exercised against a local stand-in such as MinIO.
+type Store interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// Open returns ErrNotFound when there is no blob under key, the caller
+	// closes the returned Object
+	Open(ctx context.Context, key string) (Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Object is a stored blob being read
+type Object struct {
+	io.ReadCloser
+	Content_type string
+	Size         int64
+	Etag         string
+	Modified_at  time.Time
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*(/[a-z0-9][a-z0-9._-]*)*$`)
+
+// CheckKey keeps keys to lowercase path segments, which are safe as file
+// names and need no escaping in URLs
+func CheckKey(key string) error {
+	if !keyPattern.MatchString(key) || strings.Contains(key, "..") {
+		return ErrInvalidKey
+	}
+	return nil
+}
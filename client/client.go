@@ -0,0 +1,191 @@
+// Package client calls the REST API, integration tests use it against a
+// running server. The types and methods in client_gen.go are generated from
+// docs/openapi.json, run go generate after changing a route.
+package client
+
+//go:generate go run ../cmd/clientgen -spec ../docs/openapi.json -out client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the API at Base_url, Token is sent on every request once set
+type Client struct {
+	Base_url string
+	Token    string
+	Http     *http.Client
+}
+
+func New(baseURL string) *Client {
+	return &Client{Base_url: strings.TrimSuffix(baseURL, "/"), Http: http.DefaultClient}
+}
+
+// NewIdempotencyKey is a random Idempotency-Key for a POST, send the same
+// one again when retrying it
+func NewIdempotencyKey() *string {
+	key := make([]byte, 16)
+	rand.Read(key)
+	encoded := hex.EncodeToString(key)
+	return &encoded
+}
+
+// Error is a response with a status of 400 or more, Body is empty when
+// the response was not the JSON error body
+type Error struct {
+	Status int
+	Body   ErrorBody
+}
+
+func (e *Error) Error() string {
+	if e.Body.Error != nil {
+		return fmt.Sprintf("%d %s: %s", e.Status, stringValue(e.Body.Code), *e.Body.Error)
+	}
+	return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// request is one call, the generated methods fill it in
+type request struct {
+	method       string
+	path         string
+	query        url.Values
+	header       http.Header
+	body         io.Reader
+	content_type string
+}
+
+func newRequest(method string, path string) *request {
+	return &request{method: method, path: path, query: url.Values{}, header: http.Header{}}
+}
+
+// pathValue escapes a path parameter
+func pathValue(value string) string {
+	return url.PathEscape(value)
+}
+
+// param adds a query or header parameter that was set, value is a pointer
+// for parameters that may be left out
+func (r *request) param(in string, name string, value interface{}) {
+	text, ok := paramText(value)
+	if !ok {
+		return
+	}
+	if in == "header" {
+		r.header.Set(name, text)
+	} else {
+		r.query.Set(name, text)
+	}
+}
+
+func paramText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *bool:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return v.Format(time.RFC3339), true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	}
+	return fmt.Sprint(value), true
+}
+
+func (r *request) json(body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	r.body, r.content_type = bytes.NewReader(data), "application/json"
+	return nil
+}
+
+func (r *request) raw(body io.Reader, contentType string) {
+	r.body, r.content_type = body, contentType
+}
+
+// do sends r and returns the body of a successful response
+func (c *Client) do(ctx context.Context, r *request) ([]byte, error) {
+	target := c.Base_url + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, r.body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if r.content_type != "" {
+		req.Header.Set("Content-Type", r.content_type)
+	}
+	if c.Token != "" {
+		req.Header.Set("token", c.Token)
+	}
+
+	httpClient := c.Http
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		apiErr := &Error{Status: res.StatusCode}
+		json.Unmarshal(data, &apiErr.Body)
+		return nil, apiErr
+	}
+	return data, nil
+}
+
+// decode reads a JSON response into out
+func decode(data []byte, out interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
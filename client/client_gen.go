@@ -0,0 +1,3095 @@
+// Code generated by clientgen from the OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type AppliedDiscount struct {
+	Amount       *json.Number   `json:"amount,omitempty"`
+	Applied_at   *time.Time     `json:"applied_at,omitempty"`
+	Approved_by  *string        `json:"approved_by,omitempty"`
+	Coupon_code  *string        `json:"coupon_code,omitempty"`
+	Name         *string        `json:"name,omitempty"`
+	Order_items  []DiscountLine `json:"order_items,omitempty"`
+	Promotion_id *string        `json:"promotion_id,omitempty"`
+	Reason       *string        `json:"reason,omitempty"`
+	Type         *string        `json:"type,omitempty"`
+}
+
+type Availability struct {
+	Ends_at    *time.Time `json:"ends_at,omitempty"`
+	Party_size *int       `json:"party_size,omitempty"`
+	Tables     []Table    `json:"tables,omitempty"`
+	Time       *time.Time `json:"time,omitempty"`
+}
+
+type CashDrawer struct {
+	ID            *string          `json:"ID,omitempty"`
+	Closed_at     *time.Time       `json:"closed_at,omitempty"`
+	Closed_by     *string          `json:"closed_by,omitempty"`
+	Counted_cash  *json.Number     `json:"counted_cash,omitempty"`
+	Drawer_id     *string          `json:"drawer_id,omitempty"`
+	Expected_cash *json.Number     `json:"expected_cash,omitempty"`
+	Movements     []DrawerMovement `json:"movements,omitempty"`
+	Opened_at     *time.Time       `json:"opened_at,omitempty"`
+	Opened_by     *string          `json:"opened_by,omitempty"`
+	Opening_float *json.Number     `json:"opening_float,omitempty"`
+	Status        *string          `json:"status,omitempty"`
+	Variance      *json.Number     `json:"variance,omitempty"`
+	Z_report_id   *string          `json:"z_report_id,omitempty"`
+}
+
+type CashDrawerPage struct {
+	Has_more    *bool        `json:"has_more,omitempty"`
+	Items       []CashDrawer `json:"items,omitempty"`
+	Limit       *int         `json:"limit,omitempty"`
+	Next_cursor *string      `json:"next_cursor,omitempty"`
+	Total_count *int64       `json:"total_count,omitempty"`
+}
+
+type CategorySalesRow struct {
+	Category *string      `json:"category,omitempty"`
+	Quantity *int         `json:"quantity,omitempty"`
+	Revenue  *json.Number `json:"revenue,omitempty"`
+}
+
+type CategorySalesRowReport struct {
+	From     *time.Time         `json:"from,omitempty"`
+	Report   *string            `json:"report,omitempty"`
+	Rows     []CategorySalesRow `json:"rows,omitempty"`
+	Timezone *string            `json:"timezone,omitempty"`
+	To       *time.Time         `json:"to,omitempty"`
+}
+
+type ClosedDrawer struct {
+	Drawer   *CashDrawer `json:"drawer,omitempty"`
+	Z_report *ZReport    `json:"z_report,omitempty"`
+}
+
+type CompRequest struct {
+	Amount         *json.Number `json:"amount,omitempty"`
+	Order_item_ids []string     `json:"order_item_ids,omitempty"`
+	Reason         string       `json:"reason"`
+}
+
+type CouponRedemption struct {
+	Coupon_code string `json:"coupon_code"`
+}
+
+type DeletedNote struct {
+	Deleted *bool   `json:"deleted,omitempty"`
+	Note_id *string `json:"note_id,omitempty"`
+}
+
+type DiscountLine struct {
+	Amount        *json.Number `json:"amount,omitempty"`
+	Order_item_id *string      `json:"order_item_id,omitempty"`
+}
+
+type DrawerMovement struct {
+	Amount     json.Number `json:"amount"`
+	Created_at *time.Time  `json:"created_at,omitempty"`
+	Created_by *string     `json:"created_by,omitempty"`
+	Reason     *string     `json:"reason,omitempty"`
+	Type       string      `json:"type"`
+}
+
+type ErrorBody struct {
+	Code       *string      `json:"code,omitempty"`
+	Error      *string      `json:"error,omitempty"`
+	Fields     []FieldError `json:"fields,omitempty"`
+	Request_id *string      `json:"request_id,omitempty"`
+}
+
+type EvenSplit struct {
+	Balance_due  *json.Number  `json:"balance_due,omitempty"`
+	Guests       *int          `json:"guests,omitempty"`
+	Invoice_id   *string       `json:"invoice_id,omitempty"`
+	Shares       []json.Number `json:"shares,omitempty"`
+	Total_amount *json.Number  `json:"total_amount,omitempty"`
+}
+
+type FieldError struct {
+	Field   *string `json:"field,omitempty"`
+	Message *string `json:"message,omitempty"`
+	Rule    *string `json:"rule,omitempty"`
+}
+
+type Food struct {
+	ID              *string         `json:"ID,omitempty"`
+	Allergens       []string        `json:"allergens,omitempty"`
+	Available       *bool           `json:"available,omitempty"`
+	Created_at      *time.Time      `json:"created_at,omitempty"`
+	Dietary_tags    []string        `json:"dietary_tags,omitempty"`
+	Food_id         *string         `json:"food_id,omitempty"`
+	Food_image      *string         `json:"food_image,omitempty"`
+	Image           *FoodImage      `json:"image,omitempty"`
+	Menu_id         string          `json:"menu_id"`
+	Modifier_groups []ModifierGroup `json:"modifier_groups,omitempty"`
+	Name            string          `json:"name"`
+	Nutrition       *Nutrition      `json:"nutrition,omitempty"`
+	Price           json.Number     `json:"price"`
+	Recipe          []RecipeLine    `json:"recipe,omitempty"`
+	Station         *string         `json:"station,omitempty"`
+	Updated_at      *time.Time      `json:"updated_at,omitempty"`
+	Variants        []FoodVariant   `json:"variants,omitempty"`
+}
+
+type FoodImage struct {
+	Content_type *string          `json:"content_type,omitempty"`
+	Height       *int             `json:"height,omitempty"`
+	Thumbnails   []ImageThumbnail `json:"thumbnails,omitempty"`
+	Url          *string          `json:"url,omitempty"`
+	Width        *int             `json:"width,omitempty"`
+}
+
+type FoodPage struct {
+	Has_more    *bool   `json:"has_more,omitempty"`
+	Items       []Food  `json:"items,omitempty"`
+	Limit       *int    `json:"limit,omitempty"`
+	Next_cursor *string `json:"next_cursor,omitempty"`
+	Total_count *int64  `json:"total_count,omitempty"`
+}
+
+type FoodSalesRow struct {
+	Category  *string      `json:"category,omitempty"`
+	Food_id   *string      `json:"food_id,omitempty"`
+	Food_name *string      `json:"food_name,omitempty"`
+	Quantity  *int         `json:"quantity,omitempty"`
+	Revenue   *json.Number `json:"revenue,omitempty"`
+}
+
+type FoodSalesRowReport struct {
+	From     *time.Time     `json:"from,omitempty"`
+	Report   *string        `json:"report,omitempty"`
+	Rows     []FoodSalesRow `json:"rows,omitempty"`
+	Timezone *string        `json:"timezone,omitempty"`
+	To       *time.Time     `json:"to,omitempty"`
+}
+
+type FoodVariant struct {
+	Name  string       `json:"name"`
+	Price *json.Number `json:"price,omitempty"`
+}
+
+type GuestShare struct {
+	Amount         *json.Number `json:"amount,omitempty"`
+	Guest          *int         `json:"guest,omitempty"`
+	Order_item_ids []string     `json:"order_item_ids,omitempty"`
+}
+
+type ImageThumbnail struct {
+	Height *int    `json:"height,omitempty"`
+	Url    *string `json:"url,omitempty"`
+	Width  *int    `json:"width,omitempty"`
+}
+
+type Ingredient struct {
+	ID                  *string    `json:"ID,omitempty"`
+	Created_at          *time.Time `json:"created_at,omitempty"`
+	Ingredient_id       *string    `json:"ingredient_id,omitempty"`
+	Low_stock           *bool      `json:"low_stock,omitempty"`
+	Low_stock_threshold *float64   `json:"low_stock_threshold,omitempty"`
+	Name                string     `json:"name"`
+	Stock               float64    `json:"stock"`
+	Unit                string     `json:"unit"`
+	Updated_at          *time.Time `json:"updated_at,omitempty"`
+}
+
+type IngredientPage struct {
+	Has_more    *bool        `json:"has_more,omitempty"`
+	Items       []Ingredient `json:"items,omitempty"`
+	Limit       *int         `json:"limit,omitempty"`
+	Next_cursor *string      `json:"next_cursor,omitempty"`
+	Total_count *int64       `json:"total_count,omitempty"`
+}
+
+type Invoice struct {
+	ID               *string           `json:"ID,omitempty"`
+	Amount_paid      *json.Number      `json:"amount_paid,omitempty"`
+	Balance_due      *json.Number      `json:"balance_due,omitempty"`
+	Change_due       *json.Number      `json:"change_due,omitempty"`
+	Created_at       *time.Time        `json:"created_at,omitempty"`
+	Discount_amount  *json.Number      `json:"discount_amount,omitempty"`
+	Discounts        []AppliedDiscount `json:"discounts,omitempty"`
+	Invoice_id       *string           `json:"invoice_id,omitempty"`
+	Net_amount       *json.Number      `json:"net_amount,omitempty"`
+	Order_id         *string           `json:"order_id,omitempty"`
+	Payment_due_date *time.Time        `json:"payment_due_date,omitempty"`
+	Payment_method   *string           `json:"payment_method,omitempty"`
+	Payment_status   *string           `json:"payment_status,omitempty"`
+	Tax_amount       *json.Number      `json:"tax_amount,omitempty"`
+	Tax_breakdown    []TaxLine         `json:"tax_breakdown,omitempty"`
+	Total_amount     *json.Number      `json:"total_amount,omitempty"`
+	Updated_at       *time.Time        `json:"updated_at,omitempty"`
+}
+
+type InvoicePage struct {
+	Has_more    *bool     `json:"has_more,omitempty"`
+	Items       []Invoice `json:"items,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Next_cursor *string   `json:"next_cursor,omitempty"`
+	Total_count *int64    `json:"total_count,omitempty"`
+}
+
+type InvoiceViewFormat struct {
+	Amount_paid      *json.Number      `json:"Amount_paid,omitempty"`
+	Balance_due      *json.Number      `json:"Balance_due,omitempty"`
+	Change_due       *json.Number      `json:"Change_due,omitempty"`
+	Discount_amount  *json.Number      `json:"Discount_amount,omitempty"`
+	Discounts        []AppliedDiscount `json:"Discounts,omitempty"`
+	Invoice_id       *string           `json:"Invoice_id,omitempty"`
+	Net_amount       *json.Number      `json:"Net_amount,omitempty"`
+	Order_details    json.RawMessage   `json:"Order_details,omitempty"`
+	Order_id         *string           `json:"Order_id,omitempty"`
+	Payment_due      json.RawMessage   `json:"Payment_due,omitempty"`
+	Payment_due_date *time.Time        `json:"Payment_due_date,omitempty"`
+	Payment_method   *string           `json:"Payment_method,omitempty"`
+	Payment_status   *string           `json:"Payment_status,omitempty"`
+	Payments         []Payment         `json:"Payments,omitempty"`
+	Table_number     json.RawMessage   `json:"Table_number,omitempty"`
+	Tax_amount       *json.Number      `json:"Tax_amount,omitempty"`
+	Tax_breakdown    []TaxLine         `json:"Tax_breakdown,omitempty"`
+	Total_amount     *json.Number      `json:"Total_amount,omitempty"`
+}
+
+type ItemSplit struct {
+	Balance_due       *json.Number `json:"balance_due,omitempty"`
+	Invoice_id        *string      `json:"invoice_id,omitempty"`
+	Shares            []GuestShare `json:"shares,omitempty"`
+	Unassigned_amount *json.Number `json:"unassigned_amount,omitempty"`
+}
+
+type ItemSplitRequest struct {
+	Guests []json.RawMessage `json:"guests"`
+}
+
+type KitchenTicket struct {
+	Allergy_warnings []string   `json:"allergy_warnings,omitempty"`
+	Created_at       *time.Time `json:"created_at,omitempty"`
+	Food_id          *string    `json:"food_id,omitempty"`
+	Food_name        *string    `json:"food_name,omitempty"`
+	Kitchen_status   *string    `json:"kitchen_status,omitempty"`
+	Modifiers        []string   `json:"modifiers,omitempty"`
+	Notes            []string   `json:"notes,omitempty"`
+	Order_id         *string    `json:"order_id,omitempty"`
+	Order_item_id    *string    `json:"order_item_id,omitempty"`
+	Quantity         *int       `json:"quantity,omitempty"`
+	Station          *string    `json:"station,omitempty"`
+	Table_number     *int       `json:"table_number,omitempty"`
+	Updated_at       *time.Time `json:"updated_at,omitempty"`
+	Variant          *string    `json:"variant,omitempty"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type Menu struct {
+	ID         *string        `json:"ID,omitempty"`
+	Category   string         `json:"category"`
+	Created_at *time.Time     `json:"created_at,omitempty"`
+	End_date   *time.Time     `json:"end_date,omitempty"`
+	Menu_id    *string        `json:"menu_id,omitempty"`
+	Name       string         `json:"name"`
+	Schedules  []MenuSchedule `json:"schedules,omitempty"`
+	Start_date *time.Time     `json:"start_date,omitempty"`
+	Timezone   *string        `json:"timezone,omitempty"`
+	Updated_at *time.Time     `json:"updated_at,omitempty"`
+}
+
+type MenuPage struct {
+	Has_more    *bool   `json:"has_more,omitempty"`
+	Items       []Menu  `json:"items,omitempty"`
+	Limit       *int    `json:"limit,omitempty"`
+	Next_cursor *string `json:"next_cursor,omitempty"`
+	Total_count *int64  `json:"total_count,omitempty"`
+}
+
+type MenuSchedule struct {
+	Days  []string `json:"days"`
+	End   string   `json:"end"`
+	Start string   `json:"start"`
+}
+
+type Message struct {
+	Message *string `json:"message,omitempty"`
+}
+
+type ModifierGroup struct {
+	Max_select *int             `json:"max_select,omitempty"`
+	Min_select *int             `json:"min_select,omitempty"`
+	Name       string           `json:"name"`
+	Options    []ModifierOption `json:"options"`
+}
+
+type ModifierOption struct {
+	Name        string       `json:"name"`
+	Price_delta *json.Number `json:"price_delta,omitempty"`
+}
+
+type Note struct {
+	ID               *string    `json:"ID,omitempty"`
+	Allergy_warnings []string   `json:"allergy_warnings,omitempty"`
+	Author_id        *string    `json:"author_id,omitempty"`
+	Created_at       *time.Time `json:"created_at,omitempty"`
+	Note_id          *string    `json:"note_id,omitempty"`
+	Subject_id       string     `json:"subject_id"`
+	Subject_type     string     `json:"subject_type"`
+	Text             string     `json:"text"`
+	Title            *string    `json:"title,omitempty"`
+	Updated_at       *time.Time `json:"updated_at,omitempty"`
+}
+
+type NotePage struct {
+	Has_more    *bool   `json:"has_more,omitempty"`
+	Items       []Note  `json:"items,omitempty"`
+	Limit       *int    `json:"limit,omitempty"`
+	Next_cursor *string `json:"next_cursor,omitempty"`
+	Total_count *int64  `json:"total_count,omitempty"`
+}
+
+type Nutrition struct {
+	Calories      *float64 `json:"calories,omitempty"`
+	Carbohydrates *float64 `json:"carbohydrates,omitempty"`
+	Fat           *float64 `json:"fat,omitempty"`
+	Fibre         *float64 `json:"fibre,omitempty"`
+	Protein       *float64 `json:"protein,omitempty"`
+	Salt          *float64 `json:"salt,omitempty"`
+	Saturated_fat *float64 `json:"saturated_fat,omitempty"`
+	Serving_size  *float64 `json:"serving_size,omitempty"`
+	Sugars        *float64 `json:"sugars,omitempty"`
+}
+
+type Order struct {
+	ID             *string             `json:"ID,omitempty"`
+	Covers         *int                `json:"covers,omitempty"`
+	Created_at     *time.Time          `json:"created_at,omitempty"`
+	Order_date     time.Time           `json:"order_date"`
+	Order_id       *string             `json:"order_id,omitempty"`
+	Status         *string             `json:"status,omitempty"`
+	Status_history []OrderStatusChange `json:"status_history,omitempty"`
+	Table_id       string              `json:"table_id"`
+	Updated_at     *time.Time          `json:"updated_at,omitempty"`
+}
+
+type OrderDetails struct {
+	Notes        []Note            `json:"notes,omitempty"`
+	Order_items  []OrderItemDetail `json:"order_items,omitempty"`
+	Payment_due  *json.Number      `json:"payment_due,omitempty"`
+	Table_number *int              `json:"table_number,omitempty"`
+	Total_count  *int              `json:"total_count,omitempty"`
+}
+
+type OrderItem struct {
+	ID               *string             `json:"ID,omitempty"`
+	Allergy_warnings []string            `json:"allergy_warnings,omitempty"`
+	Created_at       *time.Time          `json:"created_at,omitempty"`
+	Food_id          string              `json:"food_id"`
+	Kitchen_status   *string             `json:"kitchen_status,omitempty"`
+	Line_price       *json.Number        `json:"line_price,omitempty"`
+	Modifiers        []OrderItemModifier `json:"modifiers,omitempty"`
+	Order_id         *string             `json:"order_id,omitempty"`
+	Order_item_id    *string             `json:"order_item_id,omitempty"`
+	Quantity         int                 `json:"quantity"`
+	Station          *string             `json:"station,omitempty"`
+	Stock_used       []StockUse          `json:"stock_used,omitempty"`
+	Unit_price       *json.Number        `json:"unit_price,omitempty"`
+	Updated_at       *time.Time          `json:"updated_at,omitempty"`
+	Variant          *string             `json:"variant,omitempty"`
+}
+
+type OrderItemDetail struct {
+	Allergy_warnings []string            `json:"allergy_warnings,omitempty"`
+	Amount           *json.Number        `json:"amount,omitempty"`
+	Category         *string             `json:"category,omitempty"`
+	Food_id          *string             `json:"food_id,omitempty"`
+	Food_image       *string             `json:"food_image,omitempty"`
+	Food_name        *string             `json:"food_name,omitempty"`
+	Modifiers        []OrderItemModifier `json:"modifiers,omitempty"`
+	Notes            []Note              `json:"notes,omitempty"`
+	Order_id         *string             `json:"order_id,omitempty"`
+	Order_item_id    *string             `json:"order_item_id,omitempty"`
+	Price            *json.Number        `json:"price,omitempty"`
+	Quantity         *int                `json:"quantity,omitempty"`
+	Table_id         *string             `json:"table_id,omitempty"`
+	Table_number     *int                `json:"table_number,omitempty"`
+	Variant          *string             `json:"variant,omitempty"`
+}
+
+type OrderItemModifier struct {
+	Group       string       `json:"group"`
+	Name        string       `json:"name"`
+	Price_delta *json.Number `json:"price_delta,omitempty"`
+}
+
+type OrderItemPack struct {
+	Covers        *int        `json:"covers,omitempty"`
+	Menu_override *bool       `json:"menu_override,omitempty"`
+	Order_items   []OrderItem `json:"order_items,omitempty"`
+	Table_id      *string     `json:"table_id,omitempty"`
+}
+
+type OrderItemPage struct {
+	Has_more    *bool       `json:"has_more,omitempty"`
+	Items       []OrderItem `json:"items,omitempty"`
+	Limit       *int        `json:"limit,omitempty"`
+	Next_cursor *string     `json:"next_cursor,omitempty"`
+	Total_count *int64      `json:"total_count,omitempty"`
+}
+
+type OrderPage struct {
+	Has_more    *bool   `json:"has_more,omitempty"`
+	Items       []Order `json:"items,omitempty"`
+	Limit       *int    `json:"limit,omitempty"`
+	Next_cursor *string `json:"next_cursor,omitempty"`
+	Total_count *int64  `json:"total_count,omitempty"`
+}
+
+type OrderStatusChange struct {
+	Changed_at *time.Time `json:"changed_at,omitempty"`
+	Changed_by *string    `json:"changed_by,omitempty"`
+	From       *string    `json:"from,omitempty"`
+	To         *string    `json:"to,omitempty"`
+}
+
+type Payment struct {
+	ID             *string      `json:"ID,omitempty"`
+	Amount         *json.Number `json:"amount,omitempty"`
+	Change_due     *json.Number `json:"change_due,omitempty"`
+	Created_at     *time.Time   `json:"created_at,omitempty"`
+	Created_by     *string      `json:"created_by,omitempty"`
+	Invoice_id     *string      `json:"invoice_id,omitempty"`
+	Method         string       `json:"method"`
+	Order_item_ids []string     `json:"order_item_ids,omitempty"`
+	Payment_id     *string      `json:"payment_id,omitempty"`
+	Tendered       *json.Number `json:"tendered,omitempty"`
+	Voucher_code   *string      `json:"voucher_code,omitempty"`
+}
+
+type PaymentMixRow struct {
+	Amount   *json.Number `json:"amount,omitempty"`
+	Method   *string      `json:"method,omitempty"`
+	Payments *int         `json:"payments,omitempty"`
+	Share    *float64     `json:"share,omitempty"`
+}
+
+type PaymentMixRowReport struct {
+	From     *time.Time      `json:"from,omitempty"`
+	Report   *string         `json:"report,omitempty"`
+	Rows     []PaymentMixRow `json:"rows,omitempty"`
+	Timezone *string         `json:"timezone,omitempty"`
+	To       *time.Time      `json:"to,omitempty"`
+}
+
+type PaymentPage struct {
+	Has_more    *bool     `json:"has_more,omitempty"`
+	Items       []Payment `json:"items,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Next_cursor *string   `json:"next_cursor,omitempty"`
+	Total_count *int64    `json:"total_count,omitempty"`
+}
+
+type PaymentReceipt struct {
+	Invoice *Invoice `json:"invoice,omitempty"`
+	Payment *Payment `json:"payment,omitempty"`
+}
+
+type Promotion struct {
+	ID           *string      `json:"ID,omitempty"`
+	Active       *bool        `json:"active,omitempty"`
+	Amount       *json.Number `json:"amount,omitempty"`
+	Buy_quantity *int         `json:"buy_quantity,omitempty"`
+	Coupon_code  *string      `json:"coupon_code,omitempty"`
+	Created_at   *time.Time   `json:"created_at,omitempty"`
+	Expires_at   *time.Time   `json:"expires_at,omitempty"`
+	Food_ids     []string     `json:"food_ids,omitempty"`
+	Get_quantity *int         `json:"get_quantity,omitempty"`
+	Name         string       `json:"name"`
+	Percent      *float64     `json:"percent,omitempty"`
+	Promotion_id *string      `json:"promotion_id,omitempty"`
+	Starts_at    *time.Time   `json:"starts_at,omitempty"`
+	Times_used   *int         `json:"times_used,omitempty"`
+	Type         string       `json:"type"`
+	Updated_at   *time.Time   `json:"updated_at,omitempty"`
+	Usage_limit  *int         `json:"usage_limit,omitempty"`
+}
+
+type PromotionPage struct {
+	Has_more    *bool       `json:"has_more,omitempty"`
+	Items       []Promotion `json:"items,omitempty"`
+	Limit       *int        `json:"limit,omitempty"`
+	Next_cursor *string     `json:"next_cursor,omitempty"`
+	Total_count *int64      `json:"total_count,omitempty"`
+}
+
+type RecipeLine struct {
+	Ingredient_id string   `json:"ingredient_id"`
+	Quantity      *float64 `json:"quantity,omitempty"`
+}
+
+type RefreshRequest struct {
+	Refresh_token string `json:"refresh_token"`
+}
+
+type Reservation struct {
+	ID               *string    `json:"ID,omitempty"`
+	Created_at       *time.Time `json:"created_at,omitempty"`
+	Duration_minutes *int       `json:"duration_minutes,omitempty"`
+	Ends_at          *time.Time `json:"ends_at,omitempty"`
+	Guest_name       string     `json:"guest_name"`
+	Order_id         *string    `json:"order_id,omitempty"`
+	Party_size       int        `json:"party_size"`
+	Phone            string     `json:"phone"`
+	Reservation_id   *string    `json:"reservation_id,omitempty"`
+	Reservation_time time.Time  `json:"reservation_time"`
+	Status           *string    `json:"status,omitempty"`
+	Table_id         *string    `json:"table_id,omitempty"`
+	Updated_at       *time.Time `json:"updated_at,omitempty"`
+}
+
+type ReservationPage struct {
+	Has_more    *bool         `json:"has_more,omitempty"`
+	Items       []Reservation `json:"items,omitempty"`
+	Limit       *int          `json:"limit,omitempty"`
+	Next_cursor *string       `json:"next_cursor,omitempty"`
+	Total_count *int64        `json:"total_count,omitempty"`
+}
+
+type RestockRequest struct {
+	Quantity float64 `json:"quantity"`
+}
+
+type RevenueRow struct {
+	Discounts *json.Number `json:"discounts,omitempty"`
+	Gross     *json.Number `json:"gross,omitempty"`
+	Invoices  *int         `json:"invoices,omitempty"`
+	Net       *json.Number `json:"net,omitempty"`
+	Period    *string      `json:"period,omitempty"`
+	Tax       *json.Number `json:"tax,omitempty"`
+}
+
+type RevenueRowReport struct {
+	From     *time.Time   `json:"from,omitempty"`
+	Report   *string      `json:"report,omitempty"`
+	Rows     []RevenueRow `json:"rows,omitempty"`
+	Timezone *string      `json:"timezone,omitempty"`
+	To       *time.Time   `json:"to,omitempty"`
+}
+
+type SeatedReservation struct {
+	Order       *Order       `json:"order,omitempty"`
+	Reservation *Reservation `json:"reservation,omitempty"`
+}
+
+type SeatedWaitlistEntry struct {
+	Order          *Order         `json:"order,omitempty"`
+	Waitlist_entry *WaitlistEntry `json:"waitlist_entry,omitempty"`
+}
+
+type SignupResult struct {
+	InsertedID *string `json:"InsertedID,omitempty"`
+}
+
+type StockUse struct {
+	Ingredient_id *string  `json:"ingredient_id,omitempty"`
+	Quantity      *float64 `json:"quantity,omitempty"`
+}
+
+type Table struct {
+	ID               *string    `json:"ID,omitempty"`
+	Created_at       *time.Time `json:"created_at,omitempty"`
+	Number_of_guests int        `json:"number_of_guests"`
+	Table_id         *string    `json:"table_id,omitempty"`
+	Table_number     int        `json:"table_number"`
+	Updated_at       *time.Time `json:"updated_at,omitempty"`
+}
+
+type TableCoversRow struct {
+	Covers       *int         `json:"covers,omitempty"`
+	Orders       *int         `json:"orders,omitempty"`
+	Revenue      *json.Number `json:"revenue,omitempty"`
+	Table_id     *string      `json:"table_id,omitempty"`
+	Table_number *int         `json:"table_number,omitempty"`
+}
+
+type TableCoversRowReport struct {
+	From     *time.Time       `json:"from,omitempty"`
+	Report   *string          `json:"report,omitempty"`
+	Rows     []TableCoversRow `json:"rows,omitempty"`
+	Timezone *string          `json:"timezone,omitempty"`
+	To       *time.Time       `json:"to,omitempty"`
+}
+
+type TablePage struct {
+	Has_more    *bool   `json:"has_more,omitempty"`
+	Items       []Table `json:"items,omitempty"`
+	Limit       *int    `json:"limit,omitempty"`
+	Next_cursor *string `json:"next_cursor,omitempty"`
+	Total_count *int64  `json:"total_count,omitempty"`
+}
+
+type TaxLine struct {
+	Inclusive      *bool        `json:"inclusive,omitempty"`
+	Name           *string      `json:"name,omitempty"`
+	Rate           *float64     `json:"rate,omitempty"`
+	Tax_amount     *json.Number `json:"tax_amount,omitempty"`
+	Tax_rate_id    *string      `json:"tax_rate_id,omitempty"`
+	Taxable_amount *json.Number `json:"taxable_amount,omitempty"`
+}
+
+type TaxRate struct {
+	ID           *string    `json:"ID,omitempty"`
+	Active       *bool      `json:"active,omitempty"`
+	Created_at   *time.Time `json:"created_at,omitempty"`
+	Inclusive    bool       `json:"inclusive"`
+	Jurisdiction *string    `json:"jurisdiction,omitempty"`
+	Name         string     `json:"name"`
+	Rate         float64    `json:"rate"`
+	Tax_rate_id  *string    `json:"tax_rate_id,omitempty"`
+	Updated_at   *time.Time `json:"updated_at,omitempty"`
+}
+
+type TaxRatePage struct {
+	Has_more    *bool     `json:"has_more,omitempty"`
+	Items       []TaxRate `json:"items,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Next_cursor *string   `json:"next_cursor,omitempty"`
+	Total_count *int64    `json:"total_count,omitempty"`
+}
+
+type TaxRule struct {
+	ID          *string    `json:"ID,omitempty"`
+	Active      *bool      `json:"active,omitempty"`
+	Category    *string    `json:"category,omitempty"`
+	Created_at  *time.Time `json:"created_at,omitempty"`
+	Food_id     *string    `json:"food_id,omitempty"`
+	Tax_rate_id string     `json:"tax_rate_id"`
+	Tax_rule_id *string    `json:"tax_rule_id,omitempty"`
+	Updated_at  *time.Time `json:"updated_at,omitempty"`
+}
+
+type TaxRulePage struct {
+	Has_more    *bool     `json:"has_more,omitempty"`
+	Items       []TaxRule `json:"items,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Next_cursor *string   `json:"next_cursor,omitempty"`
+	Total_count *int64    `json:"total_count,omitempty"`
+}
+
+type TicketSummary struct {
+	Average_per_cover *json.Number `json:"average_per_cover,omitempty"`
+	Average_ticket    *json.Number `json:"average_ticket,omitempty"`
+	Covers            *int         `json:"covers,omitempty"`
+	Gross             *json.Number `json:"gross,omitempty"`
+	Invoices          *int         `json:"invoices,omitempty"`
+}
+
+type TicketSummaryReport struct {
+	From     *time.Time     `json:"from,omitempty"`
+	Report   *string        `json:"report,omitempty"`
+	Rows     *TicketSummary `json:"rows,omitempty"`
+	Timezone *string        `json:"timezone,omitempty"`
+	To       *time.Time     `json:"to,omitempty"`
+}
+
+type TokenPair struct {
+	Refresh_token *string `json:"refresh_token,omitempty"`
+	Token         *string `json:"token,omitempty"`
+}
+
+type User struct {
+	ID         *string    `json:"ID,omitempty"`
+	Avatar     *string    `json:"avatar,omitempty"`
+	Created_at *time.Time `json:"created_at,omitempty"`
+	Email      string     `json:"email"`
+	First_name string     `json:"first_name"`
+	Last_name  string     `json:"last_name"`
+	Password   string     `json:"password"`
+	Phone      string     `json:"phone"`
+	Role       *string    `json:"role,omitempty"`
+	Updated_at *time.Time `json:"updated_at,omitempty"`
+	User_id    *string    `json:"user_id,omitempty"`
+}
+
+type UserProfile struct {
+	Avatar     *string    `json:"avatar,omitempty"`
+	Created_at *time.Time `json:"created_at,omitempty"`
+	Email      *string    `json:"email,omitempty"`
+	First_name *string    `json:"first_name,omitempty"`
+	Last_name  *string    `json:"last_name,omitempty"`
+	Phone      *string    `json:"phone,omitempty"`
+	Role       *string    `json:"role,omitempty"`
+	Updated_at *time.Time `json:"updated_at,omitempty"`
+	User_id    *string    `json:"user_id,omitempty"`
+}
+
+type UserProfilePage struct {
+	Has_more    *bool         `json:"has_more,omitempty"`
+	Items       []UserProfile `json:"items,omitempty"`
+	Limit       *int          `json:"limit,omitempty"`
+	Next_cursor *string       `json:"next_cursor,omitempty"`
+	Total_count *int64        `json:"total_count,omitempty"`
+}
+
+type VoidSummary struct {
+	Amount *json.Number `json:"amount,omitempty"`
+	Items  *int         `json:"items,omitempty"`
+	Orders *int         `json:"orders,omitempty"`
+}
+
+type WaitlistEntry struct {
+	ID                     *string    `json:"ID,omitempty"`
+	Created_at             *time.Time `json:"created_at,omitempty"`
+	Estimated_wait_minutes *int       `json:"estimated_wait_minutes,omitempty"`
+	Guest_name             string     `json:"guest_name"`
+	Order_id               *string    `json:"order_id,omitempty"`
+	Party_size             int        `json:"party_size"`
+	Phone                  *string    `json:"phone,omitempty"`
+	Quoted_wait_minutes    *int       `json:"quoted_wait_minutes,omitempty"`
+	Seated_at              *time.Time `json:"seated_at,omitempty"`
+	Status                 *string    `json:"status,omitempty"`
+	Table_id               *string    `json:"table_id,omitempty"`
+	Updated_at             *time.Time `json:"updated_at,omitempty"`
+	Waitlist_id            *string    `json:"waitlist_id,omitempty"`
+}
+
+type WaitlistEntryPage struct {
+	Has_more    *bool           `json:"has_more,omitempty"`
+	Items       []WaitlistEntry `json:"items,omitempty"`
+	Limit       *int            `json:"limit,omitempty"`
+	Next_cursor *string         `json:"next_cursor,omitempty"`
+	Total_count *int64          `json:"total_count,omitempty"`
+}
+
+type WaitlistSeating struct {
+	Table_id string `json:"table_id"`
+}
+
+type ZReport struct {
+	ID            *string         `json:"ID,omitempty"`
+	Cash_payments *json.Number    `json:"cash_payments,omitempty"`
+	Closed_by     *string         `json:"closed_by,omitempty"`
+	Counted_cash  *json.Number    `json:"counted_cash,omitempty"`
+	Created_at    *time.Time      `json:"created_at,omitempty"`
+	Discounts     *json.Number    `json:"discounts,omitempty"`
+	Drawer_id     *string         `json:"drawer_id,omitempty"`
+	Drops         *json.Number    `json:"drops,omitempty"`
+	Expected_cash *json.Number    `json:"expected_cash,omitempty"`
+	Gross         *json.Number    `json:"gross,omitempty"`
+	Invoices      *int            `json:"invoices,omitempty"`
+	Net           *json.Number    `json:"net,omitempty"`
+	Number        *int            `json:"number,omitempty"`
+	Opening_float *json.Number    `json:"opening_float,omitempty"`
+	Payments      []PaymentMixRow `json:"payments,omitempty"`
+	Payouts       *json.Number    `json:"payouts,omitempty"`
+	Period_end    *time.Time      `json:"period_end,omitempty"`
+	Period_start  *time.Time      `json:"period_start,omitempty"`
+	Tax           *json.Number    `json:"tax,omitempty"`
+	Variance      *json.Number    `json:"variance,omitempty"`
+	Voids         *VoidSummary    `json:"voids,omitempty"`
+	Z_report_id   *string         `json:"z_report_id,omitempty"`
+}
+
+type ZReportPage struct {
+	Has_more    *bool     `json:"has_more,omitempty"`
+	Items       []ZReport `json:"items,omitempty"`
+	Limit       *int      `json:"limit,omitempty"`
+	Next_cursor *string   `json:"next_cursor,omitempty"`
+	Total_count *int64    `json:"total_count,omitempty"`
+}
+
+// AcceptOrderParams are the query and header parameters of AcceptOrder
+type AcceptOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// AcceptOrder sends POST /order/{order_id}/accept: accept a placed order
+func (c *Client) AcceptOrder(ctx context.Context, order_id string, params *AcceptOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/accept")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// AddDrawerMovementParams are the query and header parameters of AddDrawerMovement
+type AddDrawerMovementParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// AddDrawerMovement sends POST /drawers/{drawer_id}/movements: record a drop or payout
+func (c *Client) AddDrawerMovement(ctx context.Context, drawer_id string, params *AddDrawerMovementParams, body DrawerMovement) (CashDrawer, error) {
+	var out CashDrawer
+	r := newRequest("POST", "/drawers/"+pathValue(drawer_id)+"/movements")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// AddToWaitlistParams are the query and header parameters of AddToWaitlist
+type AddToWaitlistParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// AddToWaitlist sends POST /waitlist: add a walk-in party to the waitlist
+func (c *Client) AddToWaitlist(ctx context.Context, params *AddToWaitlistParams, body WaitlistEntry) (WaitlistEntry, error) {
+	var out WaitlistEntry
+	r := newRequest("POST", "/waitlist")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// ApplyCouponParams are the query and header parameters of ApplyCoupon
+type ApplyCouponParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// ApplyCoupon sends POST /invoice/{invoice_id}/coupons: apply a coupon to an unpaid invoice
+func (c *Client) ApplyCoupon(ctx context.Context, invoice_id string, params *ApplyCouponParams, body CouponRedemption) (Invoice, error) {
+	var out Invoice
+	r := newRequest("POST", "/invoice/"+pathValue(invoice_id)+"/coupons")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CancelOrderParams are the query and header parameters of CancelOrder
+type CancelOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CancelOrder sends POST /order/{order_id}/cancel: cancel an order the kitchen has not started
+func (c *Client) CancelOrder(ctx context.Context, order_id string, params *CancelOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/cancel")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CancelReservationParams are the query and header parameters of CancelReservation
+type CancelReservationParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CancelReservation sends POST /reservation/{reservation_id}/cancel: cancel a reservation
+func (c *Client) CancelReservation(ctx context.Context, reservation_id string, params *CancelReservationParams) (Reservation, error) {
+	var out Reservation
+	r := newRequest("POST", "/reservation/"+pathValue(reservation_id)+"/cancel")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CloseDrawerParams are the query and header parameters of CloseDrawer
+type CloseDrawerParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CloseDrawer sends POST /drawers/{drawer_id}/close: close a drawer with the counted cash and write its Z report
+func (c *Client) CloseDrawer(ctx context.Context, drawer_id string, params *CloseDrawerParams, body CashDrawer) (ClosedDrawer, error) {
+	var out ClosedDrawer
+	r := newRequest("POST", "/drawers/"+pathValue(drawer_id)+"/close")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CompInvoiceParams are the query and header parameters of CompInvoice
+type CompInvoiceParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CompInvoice sends POST /invoice/{invoice_id}/comps: give away order items or an amount
+func (c *Client) CompInvoice(ctx context.Context, invoice_id string, params *CompInvoiceParams, body CompRequest) (Invoice, error) {
+	var out Invoice
+	r := newRequest("POST", "/invoice/"+pathValue(invoice_id)+"/comps")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateFoodParams are the query and header parameters of CreateFood
+type CreateFoodParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateFood sends POST /foods: create a food item
+func (c *Client) CreateFood(ctx context.Context, params *CreateFoodParams, body Food) (Food, error) {
+	var out Food
+	r := newRequest("POST", "/foods")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateIngredientParams are the query and header parameters of CreateIngredient
+type CreateIngredientParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateIngredient sends POST /ingredients: create an ingredient
+func (c *Client) CreateIngredient(ctx context.Context, params *CreateIngredientParams, body Ingredient) (Ingredient, error) {
+	var out Ingredient
+	r := newRequest("POST", "/ingredients")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateInvoiceParams are the query and header parameters of CreateInvoice
+type CreateInvoiceParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateInvoice sends POST /invoice: create the invoice of an order
+func (c *Client) CreateInvoice(ctx context.Context, params *CreateInvoiceParams, body Invoice) (Invoice, error) {
+	var out Invoice
+	r := newRequest("POST", "/invoice")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateMenuParams are the query and header parameters of CreateMenu
+type CreateMenuParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateMenu sends POST /menu: create a menu
+func (c *Client) CreateMenu(ctx context.Context, params *CreateMenuParams, body Menu) (Menu, error) {
+	var out Menu
+	r := newRequest("POST", "/menu")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateNoteParams are the query and header parameters of CreateNote
+type CreateNoteParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateNote sends POST /notes: write a note on an order, order item, table or reservation
+func (c *Client) CreateNote(ctx context.Context, params *CreateNoteParams, body Note) (Note, error) {
+	var out Note
+	r := newRequest("POST", "/notes")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateOrderParams are the query and header parameters of CreateOrder
+type CreateOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateOrder sends POST /order: open an order for a table
+func (c *Client) CreateOrder(ctx context.Context, params *CreateOrderParams, body Order) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateOrderItemsParams are the query and header parameters of CreateOrderItems
+type CreateOrderItemsParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateOrderItems sends POST /orderItems: place an order with its items
+func (c *Client) CreateOrderItems(ctx context.Context, params *CreateOrderItemsParams, body OrderItemPack) ([]OrderItem, error) {
+	var out []OrderItem
+	r := newRequest("POST", "/orderItems")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreatePaymentParams are the query and header parameters of CreatePayment
+type CreatePaymentParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreatePayment sends POST /invoice/{invoice_id}/payments: pay towards an invoice
+func (c *Client) CreatePayment(ctx context.Context, invoice_id string, params *CreatePaymentParams, body Payment) (PaymentReceipt, error) {
+	var out PaymentReceipt
+	r := newRequest("POST", "/invoice/"+pathValue(invoice_id)+"/payments")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreatePromotionParams are the query and header parameters of CreatePromotion
+type CreatePromotionParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreatePromotion sends POST /promotions: create a promotion
+func (c *Client) CreatePromotion(ctx context.Context, params *CreatePromotionParams, body Promotion) (Promotion, error) {
+	var out Promotion
+	r := newRequest("POST", "/promotions")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateReservationParams are the query and header parameters of CreateReservation
+type CreateReservationParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateReservation sends POST /reservation: book a table
+func (c *Client) CreateReservation(ctx context.Context, params *CreateReservationParams, body Reservation) (Reservation, error) {
+	var out Reservation
+	r := newRequest("POST", "/reservation")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateTableParams are the query and header parameters of CreateTable
+type CreateTableParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateTable sends POST /table: create a table
+func (c *Client) CreateTable(ctx context.Context, params *CreateTableParams, body Table) (Table, error) {
+	var out Table
+	r := newRequest("POST", "/table")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateTaxRateParams are the query and header parameters of CreateTaxRate
+type CreateTaxRateParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateTaxRate sends POST /taxRates: create a tax rate
+func (c *Client) CreateTaxRate(ctx context.Context, params *CreateTaxRateParams, body TaxRate) (TaxRate, error) {
+	var out TaxRate
+	r := newRequest("POST", "/taxRates")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// CreateTaxRuleParams are the query and header parameters of CreateTaxRule
+type CreateTaxRuleParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// CreateTaxRule sends POST /taxRules: create a tax rule
+func (c *Client) CreateTaxRule(ctx context.Context, params *CreateTaxRuleParams, body TaxRule) (TaxRule, error) {
+	var out TaxRule
+	r := newRequest("POST", "/taxRules")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// DeleteNote sends DELETE /notes/{note_id}: delete a note
+func (c *Client) DeleteNote(ctx context.Context, note_id string) (DeletedNote, error) {
+	var out DeletedNote
+	r := newRequest("DELETE", "/notes/"+pathValue(note_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// FinishKitchenItemParams are the query and header parameters of FinishKitchenItem
+type FinishKitchenItemParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// FinishKitchenItem sends POST /kitchen/items/{orderItem_id}/done: mark an order item as done
+func (c *Client) FinishKitchenItem(ctx context.Context, orderItem_id string, params *FinishKitchenItemParams) (KitchenTicket, error) {
+	var out KitchenTicket
+	r := newRequest("POST", "/kitchen/items/"+pathValue(orderItem_id)+"/done")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetActiveMenusParams are the query and header parameters of GetActiveMenus
+type GetActiveMenusParams struct {
+	// now by default
+	At *time.Time
+}
+
+// GetActiveMenus sends GET /menu/active: list the menus being served
+func (c *Client) GetActiveMenus(ctx context.Context, params *GetActiveMenusParams) ([]Menu, error) {
+	var out []Menu
+	r := newRequest("GET", "/menu/active")
+	if params != nil {
+		r.param("query", "at", params.At)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetAvailabilityParams are the query and header parameters of GetAvailability
+type GetAvailabilityParams struct {
+	// how many guests
+	Party_size int
+	// when the party arrives
+	Time time.Time
+	// how long the table is needed, at least 15
+	Duration_minutes *int
+}
+
+// GetAvailability sends GET /reservations/availability: find free tables for a party
+func (c *Client) GetAvailability(ctx context.Context, params *GetAvailabilityParams) (Availability, error) {
+	var out Availability
+	r := newRequest("GET", "/reservations/availability")
+	if params != nil {
+		r.param("query", "party_size", params.Party_size)
+		r.param("query", "time", params.Time)
+		r.param("query", "duration_minutes", params.Duration_minutes)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetAverageTicketParams are the query and header parameters of GetAverageTicket
+type GetAverageTicketParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetAverageTicket sends GET /reports/tickets: average ticket and spend per cover
+func (c *Client) GetAverageTicket(ctx context.Context, params *GetAverageTicketParams) (TicketSummaryReport, error) {
+	var out TicketSummaryReport
+	r := newRequest("GET", "/reports/tickets")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetCoversByTableParams are the query and header parameters of GetCoversByTable
+type GetCoversByTableParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetCoversByTable sends GET /reports/tables: covers and revenue by table
+func (c *Client) GetCoversByTable(ctx context.Context, params *GetCoversByTableParams) (TableCoversRowReport, error) {
+	var out TableCoversRowReport
+	r := newRequest("GET", "/reports/tables")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetDrawer sends GET /drawers/{drawer_id}: get a cash drawer
+func (c *Client) GetDrawer(ctx context.Context, drawer_id string) (CashDrawer, error) {
+	var out CashDrawer
+	r := newRequest("GET", "/drawers/"+pathValue(drawer_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetDrawersParams are the query and header parameters of GetDrawers
+type GetDrawersParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of closed_at, opened_at, status separated by commas, with a - before descending ones, -opened_at by default
+	Sort *string
+	// one value or several separated by commas
+	Opened_by *string
+	// one value or several separated by commas
+	Status *string
+	// opened_at from this time on
+	From *time.Time
+	// opened_at before this time
+	To *time.Time
+}
+
+// GetDrawers sends GET /drawers: list cash drawers
+func (c *Client) GetDrawers(ctx context.Context, params *GetDrawersParams) (CashDrawerPage, error) {
+	var out CashDrawerPage
+	r := newRequest("GET", "/drawers")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "opened_by", params.Opened_by)
+		r.param("query", "status", params.Status)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetEvenSplitParams are the query and header parameters of GetEvenSplit
+type GetEvenSplitParams struct {
+	// how many guests share the bill
+	Guests int
+}
+
+// GetEvenSplit sends GET /invoice/{invoice_id}/split: split the balance due evenly
+func (c *Client) GetEvenSplit(ctx context.Context, invoice_id string, params *GetEvenSplitParams) (EvenSplit, error) {
+	var out EvenSplit
+	r := newRequest("GET", "/invoice/"+pathValue(invoice_id)+"/split")
+	if params != nil {
+		r.param("query", "guests", params.Guests)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetFood sends GET /foods/{food_id}: get a food item
+func (c *Client) GetFood(ctx context.Context, food_id string) (Food, error) {
+	var out Food
+	r := newRequest("GET", "/foods/"+pathValue(food_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetFoodsParams are the query and header parameters of GetFoods
+type GetFoodsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, name, price, station separated by commas, with a - before descending ones, name by default
+	Sort *string
+	// one value or several separated by commas
+	Menu_id *string
+	// one value or several separated by commas
+	Station *string
+	// allergens separated by commas, foods that declare none of them
+	Exclude_allergens *string
+	// dietary tags separated by commas, foods that have all of them
+	Diet *string
+}
+
+// GetFoods sends GET /foods: list food items
+func (c *Client) GetFoods(ctx context.Context, params *GetFoodsParams) (FoodPage, error) {
+	var out FoodPage
+	r := newRequest("GET", "/foods")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "menu_id", params.Menu_id)
+		r.param("query", "station", params.Station)
+		r.param("query", "exclude_allergens", params.Exclude_allergens)
+		r.param("query", "diet", params.Diet)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetImage sends GET /images/{key}: download an uploaded image
+func (c *Client) GetImage(ctx context.Context, key string) ([]byte, error) {
+	r := newRequest("GET", "/images/"+pathValue(key))
+	return c.do(ctx, r)
+}
+
+// GetIngredient sends GET /ingredients/{ingredient_id}: get an ingredient
+func (c *Client) GetIngredient(ctx context.Context, ingredient_id string) (Ingredient, error) {
+	var out Ingredient
+	r := newRequest("GET", "/ingredients/"+pathValue(ingredient_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetIngredientsParams are the query and header parameters of GetIngredients
+type GetIngredientsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, name, stock, unit separated by commas, with a - before descending ones, name by default
+	Sort *string
+	// one value or several separated by commas
+	Unit *string
+	// only those at or below their low stock threshold
+	Low_stock *bool
+}
+
+// GetIngredients sends GET /ingredients: list ingredients
+func (c *Client) GetIngredients(ctx context.Context, params *GetIngredientsParams) (IngredientPage, error) {
+	var out IngredientPage
+	r := newRequest("GET", "/ingredients")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "unit", params.Unit)
+		r.param("query", "low_stock", params.Low_stock)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetInvoice sends GET /invoice/{invoice_id}: get an invoice with its order items and payments
+func (c *Client) GetInvoice(ctx context.Context, invoice_id string) (InvoiceViewFormat, error) {
+	var out InvoiceViewFormat
+	r := newRequest("GET", "/invoice/"+pathValue(invoice_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetInvoicePDF sends GET /invoice/{invoice_id}/pdf: download an invoice as PDF
+func (c *Client) GetInvoicePDF(ctx context.Context, invoice_id string) ([]byte, error) {
+	r := newRequest("GET", "/invoice/"+pathValue(invoice_id)+"/pdf")
+	return c.do(ctx, r)
+}
+
+// GetInvoiceReceiptParams are the query and header parameters of GetInvoiceReceipt
+type GetInvoiceReceiptParams struct {
+	// paper width in millimetres, 80 by default
+	Width *int
+	// text by default
+	Format *string
+}
+
+// GetInvoiceReceipt sends GET /invoice/{invoice_id}/receipt: print a receipt of an invoice
+func (c *Client) GetInvoiceReceipt(ctx context.Context, invoice_id string, params *GetInvoiceReceiptParams) ([]byte, error) {
+	r := newRequest("GET", "/invoice/"+pathValue(invoice_id)+"/receipt")
+	if params != nil {
+		r.param("query", "width", params.Width)
+		r.param("query", "format", params.Format)
+	}
+	return c.do(ctx, r)
+}
+
+// GetInvoicesParams are the query and header parameters of GetInvoices
+type GetInvoicesParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, payment_due_date, payment_method, payment_status, total_amount separated by commas, with a - before descending ones, -created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Order_id *string
+	// one value or several separated by commas
+	Payment_method *string
+	// one value or several separated by commas
+	Payment_status *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetInvoices sends GET /invoice: list invoices
+func (c *Client) GetInvoices(ctx context.Context, params *GetInvoicesParams) (InvoicePage, error) {
+	var out InvoicePage
+	r := newRequest("GET", "/invoice")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "order_id", params.Order_id)
+		r.param("query", "payment_method", params.Payment_method)
+		r.param("query", "payment_status", params.Payment_status)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetKitchenTicketsParams are the query and header parameters of GetKitchenTickets
+type GetKitchenTicketsParams struct {
+	// only the tickets of this station
+	Station *string
+}
+
+// GetKitchenTickets sends GET /kitchen/tickets: list open kitchen tickets
+func (c *Client) GetKitchenTickets(ctx context.Context, params *GetKitchenTicketsParams) ([]KitchenTicket, error) {
+	var out []KitchenTicket
+	r := newRequest("GET", "/kitchen/tickets")
+	if params != nil {
+		r.param("query", "station", params.Station)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetMenu sends GET /menu/{menu_id}: get a menu
+func (c *Client) GetMenu(ctx context.Context, menu_id string) (Menu, error) {
+	var out Menu
+	r := newRequest("GET", "/menu/"+pathValue(menu_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetMenusParams are the query and header parameters of GetMenus
+type GetMenusParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of category, created_at, name separated by commas, with a - before descending ones, category,name by default
+	Sort *string
+	// one value or several separated by commas
+	Category *string
+	// one value or several separated by commas
+	Name *string
+}
+
+// GetMenus sends GET /menu: list menus
+func (c *Client) GetMenus(ctx context.Context, params *GetMenusParams) (MenuPage, error) {
+	var out MenuPage
+	r := newRequest("GET", "/menu")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "category", params.Category)
+		r.param("query", "name", params.Name)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetNote sends GET /notes/{note_id}: get a note
+func (c *Client) GetNote(ctx context.Context, note_id string) (Note, error) {
+	var out Note
+	r := newRequest("GET", "/notes/"+pathValue(note_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetNotesParams are the query and header parameters of GetNotes
+type GetNotesParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, updated_at separated by commas, with a - before descending ones, created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Author_id *string
+	// one value or several separated by commas
+	Subject_id *string
+	// one value or several separated by commas
+	Subject_type *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetNotes sends GET /notes: list notes
+func (c *Client) GetNotes(ctx context.Context, params *GetNotesParams) (NotePage, error) {
+	var out NotePage
+	r := newRequest("GET", "/notes")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "author_id", params.Author_id)
+		r.param("query", "subject_id", params.Subject_id)
+		r.param("query", "subject_type", params.Subject_type)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOpenAPI sends GET /openapi.json: this document
+func (c *Client) GetOpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var out json.RawMessage
+	r := newRequest("GET", "/openapi.json")
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOrder sends GET /order/{order_id}: get an order
+func (c *Client) GetOrder(ctx context.Context, order_id string) (Order, error) {
+	var out Order
+	r := newRequest("GET", "/order/"+pathValue(order_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOrderItem sends GET /orderItems/{orderItem_id}: get an order item
+func (c *Client) GetOrderItem(ctx context.Context, orderItem_id string) (OrderItem, error) {
+	var out OrderItem
+	r := newRequest("GET", "/orderItems/"+pathValue(orderItem_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOrderItemsParams are the query and header parameters of GetOrderItems
+type GetOrderItemsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, kitchen_status, order_id, station, updated_at separated by commas, with a - before descending ones, -created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Food_id *string
+	// one value or several separated by commas
+	Kitchen_status *string
+	// one value or several separated by commas
+	Order_id *string
+	// one value or several separated by commas
+	Station *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetOrderItems sends GET /orderItems: list order items
+func (c *Client) GetOrderItems(ctx context.Context, params *GetOrderItemsParams) (OrderItemPage, error) {
+	var out OrderItemPage
+	r := newRequest("GET", "/orderItems")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "food_id", params.Food_id)
+		r.param("query", "kitchen_status", params.Kitchen_status)
+		r.param("query", "order_id", params.Order_id)
+		r.param("query", "station", params.Station)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOrderItemsByOrder sends GET /orderItems-order/{order_id}: get the order items of an order with their foods
+func (c *Client) GetOrderItemsByOrder(ctx context.Context, order_id string) (OrderDetails, error) {
+	var out OrderDetails
+	r := newRequest("GET", "/orderItems-order/"+pathValue(order_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetOrdersParams are the query and header parameters of GetOrders
+type GetOrdersParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, order_date, status, table_id separated by commas, with a - before descending ones, -created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Status *string
+	// one value or several separated by commas
+	Table_id *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetOrders sends GET /orders: list orders
+func (c *Client) GetOrders(ctx context.Context, params *GetOrdersParams) (OrderPage, error) {
+	var out OrderPage
+	r := newRequest("GET", "/orders")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "status", params.Status)
+		r.param("query", "table_id", params.Table_id)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetPaymentMixParams are the query and header parameters of GetPaymentMix
+type GetPaymentMixParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetPaymentMix sends GET /reports/payments: payments by method
+func (c *Client) GetPaymentMix(ctx context.Context, params *GetPaymentMixParams) (PaymentMixRowReport, error) {
+	var out PaymentMixRowReport
+	r := newRequest("GET", "/reports/payments")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetPaymentsParams are the query and header parameters of GetPayments
+type GetPaymentsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, method separated by commas, with a - before descending ones, created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Method *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetPayments sends GET /invoice/{invoice_id}/payments: list the payments of an invoice
+func (c *Client) GetPayments(ctx context.Context, invoice_id string, params *GetPaymentsParams) (PaymentPage, error) {
+	var out PaymentPage
+	r := newRequest("GET", "/invoice/"+pathValue(invoice_id)+"/payments")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "method", params.Method)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetPromotion sends GET /promotions/{promotion_id}: get a promotion
+func (c *Client) GetPromotion(ctx context.Context, promotion_id string) (Promotion, error) {
+	var out Promotion
+	r := newRequest("GET", "/promotions/"+pathValue(promotion_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetPromotionsParams are the query and header parameters of GetPromotions
+type GetPromotionsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, expires_at, name, type separated by commas, with a - before descending ones, -created_at by default
+	Sort   *string
+	Active *bool
+	// one value or several separated by commas
+	Coupon_code *string
+	// one value or several separated by commas
+	Type *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetPromotions sends GET /promotions: list promotions
+func (c *Client) GetPromotions(ctx context.Context, params *GetPromotionsParams) (PromotionPage, error) {
+	var out PromotionPage
+	r := newRequest("GET", "/promotions")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "active", params.Active)
+		r.param("query", "coupon_code", params.Coupon_code)
+		r.param("query", "type", params.Type)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetReservation sends GET /reservation/{reservation_id}: get a reservation
+func (c *Client) GetReservation(ctx context.Context, reservation_id string) (Reservation, error) {
+	var out Reservation
+	r := newRequest("GET", "/reservation/"+pathValue(reservation_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetReservationsParams are the query and header parameters of GetReservations
+type GetReservationsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, party_size, reservation_time, status separated by commas, with a - before descending ones, reservation_time by default
+	Sort       *string
+	Party_size *float64
+	// one value or several separated by commas
+	Phone *string
+	// one value or several separated by commas
+	Status *string
+	// one value or several separated by commas
+	Table_id *string
+	// reservation_time from this time on
+	From *time.Time
+	// reservation_time before this time
+	To *time.Time
+}
+
+// GetReservations sends GET /reservations: list reservations
+func (c *Client) GetReservations(ctx context.Context, params *GetReservationsParams) (ReservationPage, error) {
+	var out ReservationPage
+	r := newRequest("GET", "/reservations")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "party_size", params.Party_size)
+		r.param("query", "phone", params.Phone)
+		r.param("query", "status", params.Status)
+		r.param("query", "table_id", params.Table_id)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetRevenueByCategoryParams are the query and header parameters of GetRevenueByCategory
+type GetRevenueByCategoryParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetRevenueByCategory sends GET /reports/categories: revenue by menu category
+func (c *Client) GetRevenueByCategory(ctx context.Context, params *GetRevenueByCategoryParams) (CategorySalesRowReport, error) {
+	var out CategorySalesRowReport
+	r := newRequest("GET", "/reports/categories")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetRevenueByDayParams are the query and header parameters of GetRevenueByDay
+type GetRevenueByDayParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetRevenueByDay sends GET /reports/revenue/daily: revenue by day
+func (c *Client) GetRevenueByDay(ctx context.Context, params *GetRevenueByDayParams) (RevenueRowReport, error) {
+	var out RevenueRowReport
+	r := newRequest("GET", "/reports/revenue/daily")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetRevenueByHourParams are the query and header parameters of GetRevenueByHour
+type GetRevenueByHourParams struct {
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetRevenueByHour sends GET /reports/revenue/hourly: revenue by hour of the day
+func (c *Client) GetRevenueByHour(ctx context.Context, params *GetRevenueByHourParams) (RevenueRowReport, error) {
+	var out RevenueRowReport
+	r := newRequest("GET", "/reports/revenue/hourly")
+	if params != nil {
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetSwaggerUI sends GET /swagger: swagger UI for this document
+func (c *Client) GetSwaggerUI(ctx context.Context) ([]byte, error) {
+	r := newRequest("GET", "/swagger")
+	return c.do(ctx, r)
+}
+
+// GetTable sends GET /table/{table_id}: get a table
+func (c *Client) GetTable(ctx context.Context, table_id string) (Table, error) {
+	var out Table
+	r := newRequest("GET", "/table/"+pathValue(table_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTablesParams are the query and header parameters of GetTables
+type GetTablesParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, number_of_guests, table_number separated by commas, with a - before descending ones, table_number by default
+	Sort             *string
+	Number_of_guests *float64
+	Table_number     *float64
+}
+
+// GetTables sends GET /table: list tables
+func (c *Client) GetTables(ctx context.Context, params *GetTablesParams) (TablePage, error) {
+	var out TablePage
+	r := newRequest("GET", "/table")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "number_of_guests", params.Number_of_guests)
+		r.param("query", "table_number", params.Table_number)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTaxRate sends GET /taxRates/{tax_rate_id}: get a tax rate
+func (c *Client) GetTaxRate(ctx context.Context, tax_rate_id string) (TaxRate, error) {
+	var out TaxRate
+	r := newRequest("GET", "/taxRates/"+pathValue(tax_rate_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTaxRatesParams are the query and header parameters of GetTaxRates
+type GetTaxRatesParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, jurisdiction, name, rate separated by commas, with a - before descending ones, name by default
+	Sort   *string
+	Active *bool
+	// one value or several separated by commas
+	Jurisdiction *string
+}
+
+// GetTaxRates sends GET /taxRates: list tax rates
+func (c *Client) GetTaxRates(ctx context.Context, params *GetTaxRatesParams) (TaxRatePage, error) {
+	var out TaxRatePage
+	r := newRequest("GET", "/taxRates")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "active", params.Active)
+		r.param("query", "jurisdiction", params.Jurisdiction)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTaxRule sends GET /taxRules/{tax_rule_id}: get a tax rule
+func (c *Client) GetTaxRule(ctx context.Context, tax_rule_id string) (TaxRule, error) {
+	var out TaxRule
+	r := newRequest("GET", "/taxRules/"+pathValue(tax_rule_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTaxRulesParams are the query and header parameters of GetTaxRules
+type GetTaxRulesParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of category, created_at separated by commas, with a - before descending ones, created_at by default
+	Sort   *string
+	Active *bool
+	// one value or several separated by commas
+	Category *string
+	// one value or several separated by commas
+	Food_id *string
+	// one value or several separated by commas
+	Tax_rate_id *string
+}
+
+// GetTaxRules sends GET /taxRules: list tax rules
+func (c *Client) GetTaxRules(ctx context.Context, params *GetTaxRulesParams) (TaxRulePage, error) {
+	var out TaxRulePage
+	r := newRequest("GET", "/taxRules")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "active", params.Active)
+		r.param("query", "category", params.Category)
+		r.param("query", "food_id", params.Food_id)
+		r.param("query", "tax_rate_id", params.Tax_rate_id)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetTopFoodsParams are the query and header parameters of GetTopFoods
+type GetTopFoodsParams struct {
+	// how many foods, 10 by default
+	Limit *int
+	// a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default
+	From *string
+	// a date, included, or an RFC 3339 time, excluded, the end of today by default
+	To *string
+	// json by default, csv downloads the rows
+	Format *string
+}
+
+// GetTopFoods sends GET /reports/foods/top: foods by quantity sold
+func (c *Client) GetTopFoods(ctx context.Context, params *GetTopFoodsParams) (FoodSalesRowReport, error) {
+	var out FoodSalesRowReport
+	r := newRequest("GET", "/reports/foods/top")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+		r.param("query", "format", params.Format)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetUser sends GET /user/{user_id}: get a user
+func (c *Client) GetUser(ctx context.Context, user_id string) (UserProfile, error) {
+	var out UserProfile
+	r := newRequest("GET", "/user/"+pathValue(user_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetUsersParams are the query and header parameters of GetUsers
+type GetUsersParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, email, first_name, last_name, role separated by commas, with a - before descending ones, created_at by default
+	Sort *string
+	// one value or several separated by commas
+	Email *string
+	// one value or several separated by commas
+	Role *string
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetUsers sends GET /users: list users
+func (c *Client) GetUsers(ctx context.Context, params *GetUsersParams) (UserProfilePage, error) {
+	var out UserProfilePage
+	r := newRequest("GET", "/users")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "email", params.Email)
+		r.param("query", "role", params.Role)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetWaitlistParams are the query and header parameters of GetWaitlist
+type GetWaitlistParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of created_at, party_size separated by commas, with a - before descending ones, created_at by default
+	Sort       *string
+	Party_size *float64
+	// created_at from this time on
+	From *time.Time
+	// created_at before this time
+	To *time.Time
+}
+
+// GetWaitlist sends GET /waitlist: list the waiting parties with a wait estimate
+func (c *Client) GetWaitlist(ctx context.Context, params *GetWaitlistParams) (WaitlistEntryPage, error) {
+	var out WaitlistEntryPage
+	r := newRequest("GET", "/waitlist")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "party_size", params.Party_size)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetZReport sends GET /zreports/{z_report_id}: get a Z report
+func (c *Client) GetZReport(ctx context.Context, z_report_id string) (ZReport, error) {
+	var out ZReport
+	r := newRequest("GET", "/zreports/"+pathValue(z_report_id))
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// GetZReportsParams are the query and header parameters of GetZReports
+type GetZReportsParams struct {
+	// items per page, 20 by default
+	Limit *int
+	// next_cursor of the previous page
+	Cursor *string
+	// up to 3 of number, period_end separated by commas, with a - before descending ones, -number by default
+	Sort *string
+	// one value or several separated by commas
+	Closed_by *string
+	// one value or several separated by commas
+	Drawer_id *string
+	Number    *float64
+	// period_end from this time on
+	From *time.Time
+	// period_end before this time
+	To *time.Time
+}
+
+// GetZReports sends GET /zreports: list Z reports
+func (c *Client) GetZReports(ctx context.Context, params *GetZReportsParams) (ZReportPage, error) {
+	var out ZReportPage
+	r := newRequest("GET", "/zreports")
+	if params != nil {
+		r.param("query", "limit", params.Limit)
+		r.param("query", "cursor", params.Cursor)
+		r.param("query", "sort", params.Sort)
+		r.param("query", "closed_by", params.Closed_by)
+		r.param("query", "drawer_id", params.Drawer_id)
+		r.param("query", "number", params.Number)
+		r.param("query", "from", params.From)
+		r.param("query", "to", params.To)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// HeadImage sends HEAD /images/{key}: check an uploaded image
+func (c *Client) HeadImage(ctx context.Context, key string) error {
+	r := newRequest("HEAD", "/images/"+pathValue(key))
+	_, err := c.do(ctx, r)
+	return err
+}
+
+// LeaveWaitlistParams are the query and header parameters of LeaveWaitlist
+type LeaveWaitlistParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// LeaveWaitlist sends POST /waitlist/{waitlist_id}/leave: take a party off the waitlist
+func (c *Client) LeaveWaitlist(ctx context.Context, waitlist_id string, params *LeaveWaitlistParams) (WaitlistEntry, error) {
+	var out WaitlistEntry
+	r := newRequest("POST", "/waitlist/"+pathValue(waitlist_id)+"/leave")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// Login sends POST /user/login: sign in and get a token
+func (c *Client) Login(ctx context.Context, body LoginRequest) (TokenPair, error) {
+	var out TokenPair
+	r := newRequest("POST", "/user/login")
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// LogoutParams are the query and header parameters of Logout
+type LogoutParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// Logout sends POST /user/logout: sign out of this session
+func (c *Client) Logout(ctx context.Context, params *LogoutParams) (Message, error) {
+	var out Message
+	r := newRequest("POST", "/user/logout")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// MarkReservationNoShowParams are the query and header parameters of MarkReservationNoShow
+type MarkReservationNoShowParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// MarkReservationNoShow sends POST /reservation/{reservation_id}/no-show: mark a reservation as a no-show
+func (c *Client) MarkReservationNoShow(ctx context.Context, reservation_id string, params *MarkReservationNoShowParams) (Reservation, error) {
+	var out Reservation
+	r := newRequest("POST", "/reservation/"+pathValue(reservation_id)+"/no-show")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// OpenDrawerParams are the query and header parameters of OpenDrawer
+type OpenDrawerParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// OpenDrawer sends POST /drawers: open a cash drawer with its opening float
+func (c *Client) OpenDrawer(ctx context.Context, params *OpenDrawerParams, body CashDrawer) (CashDrawer, error) {
+	var out CashDrawer
+	r := newRequest("POST", "/drawers")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// PrepareOrderParams are the query and header parameters of PrepareOrder
+type PrepareOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// PrepareOrder sends POST /order/{order_id}/prepare: start preparing an accepted order
+func (c *Client) PrepareOrder(ctx context.Context, order_id string, params *PrepareOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/prepare")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// ReadyOrderParams are the query and header parameters of ReadyOrder
+type ReadyOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// ReadyOrder sends POST /order/{order_id}/ready: mark an order ready to serve
+func (c *Client) ReadyOrder(ctx context.Context, order_id string, params *ReadyOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/ready")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// RefreshToken sends POST /user/refresh: trade a refresh token for a new pair
+func (c *Client) RefreshToken(ctx context.Context, body RefreshRequest) (TokenPair, error) {
+	var out TokenPair
+	r := newRequest("POST", "/user/refresh")
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// RestockIngredientParams are the query and header parameters of RestockIngredient
+type RestockIngredientParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// RestockIngredient sends POST /ingredients/{ingredient_id}/restock: add a delivery to the stock
+func (c *Client) RestockIngredient(ctx context.Context, ingredient_id string, params *RestockIngredientParams, body RestockRequest) (Ingredient, error) {
+	var out Ingredient
+	r := newRequest("POST", "/ingredients/"+pathValue(ingredient_id)+"/restock")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// RevokeUserSessionsParams are the query and header parameters of RevokeUserSessions
+type RevokeUserSessionsParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// RevokeUserSessions sends POST /user/{user_id}/revoke: sign a user out everywhere
+func (c *Client) RevokeUserSessions(ctx context.Context, user_id string, params *RevokeUserSessionsParams) (Message, error) {
+	var out Message
+	r := newRequest("POST", "/user/"+pathValue(user_id)+"/revoke")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// SeatReservationParams are the query and header parameters of SeatReservation
+type SeatReservationParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// SeatReservation sends POST /reservation/{reservation_id}/seat: seat a reservation and open its order
+func (c *Client) SeatReservation(ctx context.Context, reservation_id string, params *SeatReservationParams) (SeatedReservation, error) {
+	var out SeatedReservation
+	r := newRequest("POST", "/reservation/"+pathValue(reservation_id)+"/seat")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// SeatWaitlistEntryParams are the query and header parameters of SeatWaitlistEntry
+type SeatWaitlistEntryParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// SeatWaitlistEntry sends POST /waitlist/{waitlist_id}/seat: seat a waiting party and open its order
+func (c *Client) SeatWaitlistEntry(ctx context.Context, waitlist_id string, params *SeatWaitlistEntryParams, body WaitlistSeating) (SeatedWaitlistEntry, error) {
+	var out SeatedWaitlistEntry
+	r := newRequest("POST", "/waitlist/"+pathValue(waitlist_id)+"/seat")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// ServeOrderParams are the query and header parameters of ServeOrder
+type ServeOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// ServeOrder sends POST /order/{order_id}/serve: mark an order served
+func (c *Client) ServeOrder(ctx context.Context, order_id string, params *ServeOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/serve")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// Signup sends POST /user/signup: create an account
+func (c *Client) Signup(ctx context.Context, body User) (SignupResult, error) {
+	var out SignupResult
+	r := newRequest("POST", "/user/signup")
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// SplitByItemsParams are the query and header parameters of SplitByItems
+type SplitByItemsParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// SplitByItems sends POST /invoice/{invoice_id}/split: price the order items each guest had
+func (c *Client) SplitByItems(ctx context.Context, invoice_id string, params *SplitByItemsParams, body ItemSplitRequest) (ItemSplit, error) {
+	var out ItemSplit
+	r := newRequest("POST", "/invoice/"+pathValue(invoice_id)+"/split")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// StartKitchenItemParams are the query and header parameters of StartKitchenItem
+type StartKitchenItemParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// StartKitchenItem sends POST /kitchen/items/{orderItem_id}/preparing: mark an order item as being prepared
+func (c *Client) StartKitchenItem(ctx context.Context, orderItem_id string, params *StartKitchenItemParams) (KitchenTicket, error) {
+	var out KitchenTicket
+	r := newRequest("POST", "/kitchen/items/"+pathValue(orderItem_id)+"/preparing")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// StreamKitchenTicketsParams are the query and header parameters of StreamKitchenTickets
+type StreamKitchenTicketsParams struct {
+	// only the tickets of this station
+	Station *string
+}
+
+// StreamKitchenTickets sends GET /kitchen/stream: follow kitchen tickets as Server-Sent Events
+func (c *Client) StreamKitchenTickets(ctx context.Context, params *StreamKitchenTicketsParams) ([]byte, error) {
+	r := newRequest("GET", "/kitchen/stream")
+	if params != nil {
+		r.param("query", "station", params.Station)
+	}
+	return c.do(ctx, r)
+}
+
+// UpdateFood sends PATCH /foods/{food_id}: update a food item
+func (c *Client) UpdateFood(ctx context.Context, food_id string, body Food) (Food, error) {
+	var out Food
+	r := newRequest("PATCH", "/foods/"+pathValue(food_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateIngredient sends PATCH /ingredients/{ingredient_id}: update an ingredient
+func (c *Client) UpdateIngredient(ctx context.Context, ingredient_id string, body Ingredient) (Ingredient, error) {
+	var out Ingredient
+	r := newRequest("PATCH", "/ingredients/"+pathValue(ingredient_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateInvoice sends PATCH /invoice/{invoice_id}: update an invoice
+func (c *Client) UpdateInvoice(ctx context.Context, invoice_id string, body Invoice) (Invoice, error) {
+	var out Invoice
+	r := newRequest("PATCH", "/invoice/"+pathValue(invoice_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateMenu sends PATCH /menu/{menu_id}: update a menu
+func (c *Client) UpdateMenu(ctx context.Context, menu_id string, body Menu) (Menu, error) {
+	var out Menu
+	r := newRequest("PATCH", "/menu/"+pathValue(menu_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateNote sends PATCH /notes/{note_id}: update a note
+func (c *Client) UpdateNote(ctx context.Context, note_id string, body Note) (Note, error) {
+	var out Note
+	r := newRequest("PATCH", "/notes/"+pathValue(note_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateOrder sends PATCH /order/{order_id}: update an order
+func (c *Client) UpdateOrder(ctx context.Context, order_id string, body Order) (Order, error) {
+	var out Order
+	r := newRequest("PATCH", "/order/"+pathValue(order_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateOrderItem sends PATCH /orderItems/{orderItem_id}: update an order item
+func (c *Client) UpdateOrderItem(ctx context.Context, orderItem_id string, body OrderItem) (OrderItem, error) {
+	var out OrderItem
+	r := newRequest("PATCH", "/orderItems/"+pathValue(orderItem_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdatePromotion sends PATCH /promotions/{promotion_id}: update a promotion
+func (c *Client) UpdatePromotion(ctx context.Context, promotion_id string, body Promotion) (Promotion, error) {
+	var out Promotion
+	r := newRequest("PATCH", "/promotions/"+pathValue(promotion_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateReservation sends PATCH /reservation/{reservation_id}: update a reservation
+func (c *Client) UpdateReservation(ctx context.Context, reservation_id string, body Reservation) (Reservation, error) {
+	var out Reservation
+	r := newRequest("PATCH", "/reservation/"+pathValue(reservation_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateTable sends PATCH /table/{table_id}: update a table
+func (c *Client) UpdateTable(ctx context.Context, table_id string, body Table) (Table, error) {
+	var out Table
+	r := newRequest("PATCH", "/table/"+pathValue(table_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateTaxRate sends PATCH /taxRates/{tax_rate_id}: update a tax rate
+func (c *Client) UpdateTaxRate(ctx context.Context, tax_rate_id string, body TaxRate) (TaxRate, error) {
+	var out TaxRate
+	r := newRequest("PATCH", "/taxRates/"+pathValue(tax_rate_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateTaxRule sends PATCH /taxRules/{tax_rule_id}: update a tax rule
+func (c *Client) UpdateTaxRule(ctx context.Context, tax_rule_id string, body TaxRule) (TaxRule, error) {
+	var out TaxRule
+	r := newRequest("PATCH", "/taxRules/"+pathValue(tax_rule_id))
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UpdateUserRole sends PATCH /user/{user_id}/role: change the role of a user
+func (c *Client) UpdateUserRole(ctx context.Context, user_id string, body User) (UserProfile, error) {
+	var out UserProfile
+	r := newRequest("PATCH", "/user/"+pathValue(user_id)+"/role")
+	if err := r.json(body); err != nil {
+		return out, err
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// UploadFoodImageParams are the query and header parameters of UploadFoodImage
+type UploadFoodImageParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// UploadFoodImage sends POST /foods/{food_id}/image: upload the image of a food item
+func (c *Client) UploadFoodImage(ctx context.Context, food_id string, params *UploadFoodImageParams, body io.Reader, contentType string) (Food, error) {
+	var out Food
+	r := newRequest("POST", "/foods/"+pathValue(food_id)+"/image")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	r.raw(body, contentType)
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
+
+// VoidOrderParams are the query and header parameters of VoidOrder
+type VoidOrderParams struct {
+	// a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)
+	Idempotency_Key *string
+}
+
+// VoidOrder sends POST /order/{order_id}/void: void an order the kitchen has started
+func (c *Client) VoidOrder(ctx context.Context, order_id string, params *VoidOrderParams) (Order, error) {
+	var out Order
+	r := newRequest("POST", "/order/"+pathValue(order_id)+"/void")
+	if params != nil {
+		r.param("header", "Idempotency-Key", params.Idempotency_Key)
+	}
+	data, err := c.do(ctx, r)
+	if err != nil {
+		return out, err
+	}
+	err = decode(data, &out)
+	return out, err
+}
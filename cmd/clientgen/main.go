@@ -0,0 +1,341 @@
+// Command clientgen writes the types and methods of package client from the
+// OpenAPI document, go generate in client runs it
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"os"
+	"restaurant-management/openapi"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	specPath := flag.String("spec", "docs/openapi.json", "the OpenAPI document")
+	outPath := flag.String("out", "client/client_gen.go", "the file to write")
+	packageName := flag.String("package", "client", "the package of the file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc := &openapi.Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		log.Fatalf("%s: %v", *specPath, err)
+	}
+
+	g := &generator{doc: doc}
+	g.types()
+	g.operations()
+
+	file := &bytes.Buffer{}
+	fmt.Fprintf(file, "// Code generated by clientgen from the OpenAPI document. DO NOT EDIT.\n\npackage %s\n\nimport (\n", *packageName)
+	for _, pkg := range []string{"context", "encoding/json", "io", "time"} {
+		if strings.Contains(g.out.String(), pkg[strings.LastIndex(pkg, "/")+1:]+".") {
+			fmt.Fprintf(file, "%q\n", pkg)
+		}
+	}
+	fmt.Fprintf(file, ")\n\n")
+	file.Write(g.out.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		log.Fatalf("generated code does not compile: %v", err)
+	}
+	if err := os.WriteFile(*outPath, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	doc *openapi.Document
+	out bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.out, format, args...)
+}
+
+// types writes a type for every component schema
+func (g *generator) types() {
+	for _, name := range sortedKeys(g.doc.Components.Schemas) {
+		schema := g.doc.Components.Schemas[name]
+		if schema.Description != "" {
+			g.printf("// %s %s\n", exported(name), schema.Description)
+		}
+		if schema.Type != "object" || len(schema.Properties) == 0 {
+			g.printf("type %s %s\n\n", exported(name), g.goType(schema))
+			continue
+		}
+		g.printf("type %s struct {\n", exported(name))
+		g.fields(schema)
+		g.printf("}\n\n")
+	}
+}
+
+func (g *generator) fields(schema *openapi.Schema) {
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties[name]
+		goType := g.goType(property)
+		//the server sets readonly fields, a client leaves them out
+		optional := !required[name] || property.Read_only
+		tag := name
+		if optional {
+			tag += ",omitempty"
+		}
+		if (optional || property.Nullable) && !nilable(goType) {
+			goType = "*" + goType
+		}
+		g.printf("%s %s `json:%q`\n", exported(name), goType, tag)
+	}
+}
+
+// goType is the Go type of a schema, refs are the generated types
+func (g *generator) goType(schema *openapi.Schema) string {
+	if schema == nil {
+		return "json.RawMessage"
+	}
+	if schema.Ref != "" {
+		return exported(openapi.RefName(schema))
+	}
+	if len(schema.All_of) == 1 {
+		return g.goType(schema.All_of[0])
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		switch schema.Format {
+		case "int32", "int64":
+			return schema.Format
+		}
+		return "int"
+	case "number":
+		switch schema.Format {
+		case "decimal":
+			//amounts keep their exact decimal
+			return "json.Number"
+		case "float":
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items)
+	case "object":
+		if schema.Additional_properties != nil && len(schema.Properties) == 0 {
+			return "map[string]" + g.goType(schema.Additional_properties)
+		}
+	}
+	return "json.RawMessage"
+}
+
+// nilable types are left out or null without a pointer
+func nilable(goType string) bool {
+	return strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "json.RawMessage"
+}
+
+// paramType is the Go type of a query or header parameter, paramText in
+// client.go writes it
+func paramType(schema *openapi.Schema) string {
+	switch schema.Type {
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		if schema.Format == "date-time" {
+			return "time.Time"
+		}
+	}
+	return "string"
+}
+
+type operation struct {
+	method string
+	path   string
+	op     *openapi.Operation
+}
+
+// operations writes a method for every operation, ordered by operation id
+func (g *generator) operations() {
+	operations := []operation{}
+	for path, item := range g.doc.Paths {
+		for method, op := range *item {
+			operations = append(operations, operation{strings.ToUpper(method), path, op})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].op.Operation_id < operations[j].op.Operation_id
+	})
+	for _, operation := range operations {
+		g.operation(operation)
+	}
+}
+
+func (g *generator) operation(o operation) {
+	name := exported(o.op.Operation_id)
+
+	var pathParams, otherParams []openapi.Parameter
+	for _, param := range o.op.Parameters {
+		if param.In == "path" {
+			pathParams = append(pathParams, param)
+		} else {
+			otherParams = append(otherParams, param)
+		}
+	}
+
+	if len(otherParams) > 0 {
+		g.printf("// %sParams are the query and header parameters of %s\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, param := range otherParams {
+			goType := paramType(param.Schema)
+			if !param.Required {
+				goType = "*" + goType
+			}
+			if param.Description != "" {
+				g.printf("// %s\n", param.Description)
+			}
+			g.printf("%s %s\n", exported(param.Name), goType)
+		}
+		g.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, identifier(param.Name)+" string")
+	}
+	if len(otherParams) > 0 {
+		args = append(args, "params *"+name+"Params")
+	}
+	var bodyType string
+	if body := o.op.Request_body; body != nil {
+		if media := body.Content["application/json"]; media != nil {
+			bodyType = g.goType(media.Schema)
+			args = append(args, "body "+bodyType)
+		} else {
+			bodyType = "io.Reader"
+			args = append(args, "body io.Reader", "contentType string")
+		}
+	}
+
+	resultType := ""
+	if success := o.op.Responses["200"]; success != nil && len(success.Content) > 0 {
+		if media := success.Content["application/json"]; media != nil {
+			resultType = g.goType(media.Schema)
+		} else {
+			resultType = "[]byte"
+		}
+	}
+
+	summary := o.op.Summary
+	if summary != "" {
+		summary = ": " + strings.ToLower(summary[:1]) + summary[1:]
+	}
+	g.printf("// %s sends %s %s%s\n", name, o.method, o.path, summary)
+	if resultType == "" {
+		g.printf("func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+	} else {
+		g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), resultType)
+	}
+	fail := "return err"
+	switch resultType {
+	case "":
+	case "[]byte":
+		fail = "return nil, err"
+	default:
+		g.printf("var out %s\n", resultType)
+		fail = "return out, err"
+	}
+
+	path := fmt.Sprintf("%q", o.path)
+	for _, param := range pathParams {
+		path = strings.Replace(path, "{"+param.Name+"}", `"+pathValue(`+identifier(param.Name)+`)+"`, 1)
+	}
+	path = strings.TrimSuffix(strings.TrimPrefix(path, `""+`), `+""`)
+	g.printf("r := newRequest(%q, %s)\n", o.method, path)
+
+	if len(otherParams) > 0 {
+		g.printf("if params != nil {\n")
+		for _, param := range otherParams {
+			g.printf("r.param(%q, %q, params.%s)\n", param.In, param.Name, exported(param.Name))
+		}
+		g.printf("}\n")
+	}
+	switch bodyType {
+	case "":
+	case "io.Reader":
+		g.printf("r.raw(body, contentType)\n")
+	default:
+		g.printf("if err := r.json(body); err != nil {\n%s\n}\n", fail)
+	}
+
+	switch resultType {
+	case "":
+		g.printf("_, err := c.do(ctx, r)\nreturn err\n")
+	case "[]byte":
+		g.printf("return c.do(ctx, r)\n")
+	default:
+		g.printf("data, err := c.do(ctx, r)\nif err != nil {\n%s\n}\nerr = decode(data, &out)\nreturn out, err\n", fail)
+	}
+	g.printf("}\n\n")
+}
+
+// exported is a name of the document as an exported Go name, the
+// snake_case of the models is kept as in Food_id
+func exported(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			runes[i] = '_'
+		}
+	}
+	if len(runes) == 0 || !unicode.IsLetter(runes[0]) {
+		runes = append([]rune{'X'}, runes...)
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// identifier is a parameter name as a Go variable name, names the
+// generated methods use themselves are suffixed too
+func identifier(name string) string {
+	switch name {
+	case "ctx", "params", "body", "contentType", "out", "r", "data", "err", "c":
+		return name + "_"
+	}
+	if token.IsKeyword(name) {
+		return name + "_"
+	}
+	return name
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,413 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is everything the server needs at startup. Values come from the
+// defaults below, then the optional file named by CONFIG_FILE (.yaml, .yml
+// or .toml), then environment variables, each overriding the one before.
+type Config struct {
+	Storage string       `yaml:"storage" toml:"storage"`
+	Server  ServerConfig `yaml:"server" toml:"server"`
+	Mongo   MongoConfig  `yaml:"mongo" toml:"mongo"`
+	Auth    AuthConfig   `yaml:"auth" toml:"auth"`
+
+	Reservations ReservationsConfig `yaml:"reservations" toml:"reservations"`
+	Restaurant   RestaurantConfig   `yaml:"restaurant" toml:"restaurant"`
+	Images       ImagesConfig       `yaml:"images" toml:"images"`
+}
+
+// ServerConfig is how the API is served. In development mode requests and
+// responses are checked against the OpenAPI document. Answers to POST
+// requests with an Idempotency-Key are replayed for Idempotency_key_lifetime.
+type ServerConfig struct {
+	Mode                     string   `yaml:"mode" toml:"mode"`
+	Listen_address           string   `yaml:"listen_address" toml:"listen_address"`
+	Read_timeout             Duration `yaml:"read_timeout" toml:"read_timeout"`
+	Write_timeout            Duration `yaml:"write_timeout" toml:"write_timeout"`
+	Request_timeout          Duration `yaml:"request_timeout" toml:"request_timeout"`
+	Idempotency_key_lifetime Duration `yaml:"idempotency_key_lifetime" toml:"idempotency_key_lifetime"`
+}
+
+type MongoConfig struct {
+	Uri             string   `yaml:"uri" toml:"uri"`
+	Username        string   `yaml:"username" toml:"username"`
+	Password        string   `yaml:"password" toml:"password"`
+	Auth_source     string   `yaml:"auth_source" toml:"auth_source"`
+	Database        string   `yaml:"database" toml:"database"`
+	Connect_timeout Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+}
+
+type AuthConfig struct {
+	Secret_key             string   `yaml:"secret_key" toml:"secret_key"`
+	Token_lifetime         Duration `yaml:"token_lifetime" toml:"token_lifetime"`
+	Refresh_token_lifetime Duration `yaml:"refresh_token_lifetime" toml:"refresh_token_lifetime"`
+	Bcrypt_cost            int      `yaml:"bcrypt_cost" toml:"bcrypt_cost"`
+}
+
+// ReservationsConfig sizes table bookings and walk-in wait estimates
+type ReservationsConfig struct {
+	Default_duration Duration `yaml:"default_duration" toml:"default_duration"`
+	Table_turn_time  Duration `yaml:"table_turn_time" toml:"table_turn_time"`
+}
+
+// RestaurantConfig is printed at the top of invoices and receipts. The
+// jurisdiction picks which tax rates apply, rates without one always do.
+// Every amount is in the one currency, an ISO 4217 code with two decimals.
+// Menu schedules without a timezone of their own follow Timezone.
+type RestaurantConfig struct {
+	Name         string `yaml:"name" toml:"name"`
+	Address      string `yaml:"address" toml:"address"`
+	Phone        string `yaml:"phone" toml:"phone"`
+	Tax_number   string `yaml:"tax_number" toml:"tax_number"`
+	Jurisdiction string `yaml:"jurisdiction" toml:"jurisdiction"`
+	Currency     string `yaml:"currency" toml:"currency"`
+	Timezone     string `yaml:"timezone" toml:"timezone"`
+	Footer       string `yaml:"footer" toml:"footer"`
+}
+
+// ImagesConfig is where uploaded food images are kept. Links are Public_url
+// followed by the key, the server answers them under /images unless a CDN
+// or the bucket itself serves them.
+type ImagesConfig struct {
+	Store            string   `yaml:"store" toml:"store"`
+	Directory        string   `yaml:"directory" toml:"directory"`
+	Public_url       string   `yaml:"public_url" toml:"public_url"`
+	Max_upload_bytes int64    `yaml:"max_upload_bytes" toml:"max_upload_bytes"`
+	Thumbnail_widths []int    `yaml:"thumbnail_widths" toml:"thumbnail_widths"`
+	S3               S3Config `yaml:"s3" toml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint   string `yaml:"endpoint" toml:"endpoint"`
+	Region     string `yaml:"region" toml:"region"`
+	Bucket     string `yaml:"bucket" toml:"bucket"`
+	Access_key string `yaml:"access_key" toml:"access_key"`
+	Secret_key string `yaml:"secret_key" toml:"secret_key"`
+	Path_style bool   `yaml:"path_style" toml:"path_style"`
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+const (
+	StorageMongo  = "mongodb"
+	StorageMemory = "memory"
+)
+
+const (
+	ModeProduction  = "production"
+	ModeDevelopment = "development"
+)
+
+const (
+	ImageStoreLocal = "local"
+	ImageStoreS3    = "s3"
+)
+
+// Default returns the values used when neither the file nor the
+// environment set them. There is no default secret key.
+func Default() Config {
+	return Config{
+		Storage: StorageMongo,
+		Server: ServerConfig{
+			Mode:                     ModeProduction,
+			Listen_address:           ":8000",
+			Read_timeout:             Duration{15 * time.Second},
+			Write_timeout:            Duration{120 * time.Second},
+			Request_timeout:          Duration{100 * time.Second},
+			Idempotency_key_lifetime: Duration{24 * time.Hour},
+		},
+		//the database keeps the spelling of the first versions, installs
+		//that never set a name keep their data
+		Mongo: MongoConfig{
+			Uri:             "mongodb://localhost:27017",
+			Database:        "restautant",
+			Connect_timeout: Duration{10 * time.Second},
+		},
+		Auth: AuthConfig{
+			Token_lifetime:         Duration{30 * time.Minute},
+			Refresh_token_lifetime: Duration{24 * time.Hour},
+			Bcrypt_cost:            14,
+		},
+		Reservations: ReservationsConfig{
+			Default_duration: Duration{90 * time.Minute},
+			Table_turn_time:  Duration{60 * time.Minute},
+		},
+		Restaurant: RestaurantConfig{
+			Name:     "Restaurant",
+			Currency: "USD",
+			Timezone: "UTC",
+			Footer:   "Thank you for dining with us",
+		},
+		Images: ImagesConfig{
+			Store:            ImageStoreLocal,
+			Directory:        "images",
+			Public_url:       "/images",
+			Max_upload_bytes: 5 << 20,
+			Thumbnail_widths: []int{160, 320, 640},
+			S3:               S3Config{Region: "us-east-1"},
+		},
+	}
+}
+
+// Load reads the configuration and validates it, the server must not start
+// when it returns an error.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	setString(&cfg.Storage, "STORAGE")
+
+	//PORT is kept for older deployments, LISTEN_ADDRESS wins when both are set
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Server.Listen_address = ":" + port
+	}
+	setString(&cfg.Server.Listen_address, "LISTEN_ADDRESS")
+	setString(&cfg.Server.Mode, "SERVER_MODE")
+
+	setString(&cfg.Mongo.Uri, "MONGODB_URI")
+	setString(&cfg.Mongo.Username, "MONGODB_USERNAME")
+	setString(&cfg.Mongo.Password, "MONGODB_PASSWORD")
+	setString(&cfg.Mongo.Auth_source, "MONGODB_AUTH_SOURCE")
+	setString(&cfg.Mongo.Database, "MONGODB_DATABASE")
+	setString(&cfg.Auth.Secret_key, "SECRET_KEY")
+	setString(&cfg.Restaurant.Name, "RESTAURANT_NAME")
+	setString(&cfg.Restaurant.Address, "RESTAURANT_ADDRESS")
+	setString(&cfg.Restaurant.Phone, "RESTAURANT_PHONE")
+	setString(&cfg.Restaurant.Tax_number, "RESTAURANT_TAX_NUMBER")
+	setString(&cfg.Restaurant.Jurisdiction, "RESTAURANT_JURISDICTION")
+	setString(&cfg.Restaurant.Currency, "RESTAURANT_CURRENCY")
+	setString(&cfg.Restaurant.Timezone, "RESTAURANT_TIMEZONE")
+	setString(&cfg.Restaurant.Footer, "RESTAURANT_FOOTER")
+	setString(&cfg.Images.Store, "IMAGE_STORE")
+	setString(&cfg.Images.Directory, "IMAGE_DIRECTORY")
+	setString(&cfg.Images.Public_url, "IMAGE_PUBLIC_URL")
+	setString(&cfg.Images.S3.Endpoint, "S3_ENDPOINT")
+	setString(&cfg.Images.S3.Region, "S3_REGION")
+	setString(&cfg.Images.S3.Bucket, "S3_BUCKET")
+	setString(&cfg.Images.S3.Access_key, "S3_ACCESS_KEY")
+	setString(&cfg.Images.S3.Secret_key, "S3_SECRET_KEY")
+	if value := os.Getenv("S3_PATH_STYLE"); value != "" {
+		pathStyle, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("S3_PATH_STYLE: %w", err)
+		}
+		cfg.Images.S3.Path_style = pathStyle
+	}
+
+	durations := map[string]*Duration{
+		"READ_TIMEOUT":             &cfg.Server.Read_timeout,
+		"WRITE_TIMEOUT":            &cfg.Server.Write_timeout,
+		"REQUEST_TIMEOUT":          &cfg.Server.Request_timeout,
+		"IDEMPOTENCY_KEY_LIFETIME": &cfg.Server.Idempotency_key_lifetime,
+		"MONGODB_CONNECT_TIMEOUT":  &cfg.Mongo.Connect_timeout,
+		"TOKEN_LIFETIME":           &cfg.Auth.Token_lifetime,
+		"REFRESH_TOKEN_LIFETIME":   &cfg.Auth.Refresh_token_lifetime,
+		"RESERVATION_DURATION":     &cfg.Reservations.Default_duration,
+		"TABLE_TURN_TIME":          &cfg.Reservations.Table_turn_time,
+	}
+	for name, d := range durations {
+		if value := os.Getenv(name); value != "" {
+			if err := d.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+
+	if value := os.Getenv("BCRYPT_COST"); value != "" {
+		cost, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("BCRYPT_COST: %w", err)
+		}
+		cfg.Auth.Bcrypt_cost = cost
+	}
+	if value := os.Getenv("IMAGE_MAX_UPLOAD_BYTES"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("IMAGE_MAX_UPLOAD_BYTES: %w", err)
+		}
+		cfg.Images.Max_upload_bytes = size
+	}
+	return nil
+}
+
+func setString(field *string, name string) {
+	if value := os.Getenv(name); value != "" {
+		*field = value
+	}
+}
+
+// Validate reports every invalid setting at once
+func (cfg Config) Validate() error {
+	var problems []string
+
+	switch cfg.Storage {
+	case StorageMongo:
+		if cfg.Mongo.Uri == "" {
+			problems = append(problems, "mongo uri is required")
+		}
+		if cfg.Mongo.Database == "" {
+			problems = append(problems, "mongo database is required")
+		}
+		if cfg.Mongo.Password != "" && cfg.Mongo.Username == "" {
+			problems = append(problems, "mongo password is set without a username")
+		}
+		if cfg.Mongo.Connect_timeout.Duration <= 0 {
+			problems = append(problems, "mongo connect_timeout must be positive")
+		}
+	case StorageMemory:
+	default:
+		problems = append(problems, fmt.Sprintf("storage must be %s or %s, got %q", StorageMongo, StorageMemory, cfg.Storage))
+	}
+
+	if cfg.Server.Mode != ModeProduction && cfg.Server.Mode != ModeDevelopment {
+		problems = append(problems, fmt.Sprintf("server mode must be %s or %s, got %q", ModeProduction, ModeDevelopment, cfg.Server.Mode))
+	}
+	if cfg.Server.Listen_address == "" {
+		problems = append(problems, "server listen_address is required")
+	}
+	if cfg.Server.Read_timeout.Duration <= 0 || cfg.Server.Write_timeout.Duration <= 0 || cfg.Server.Request_timeout.Duration <= 0 {
+		problems = append(problems, "server timeouts must be positive")
+	}
+	if cfg.Server.Write_timeout.Duration <= cfg.Server.Request_timeout.Duration {
+		problems = append(problems, "server write_timeout must be longer than request_timeout")
+	}
+	if cfg.Server.Idempotency_key_lifetime.Duration <= 0 {
+		problems = append(problems, "server idempotency_key_lifetime must be positive")
+	}
+
+	if len(cfg.Auth.Secret_key) < 32 {
+		problems = append(problems, "auth secret_key must be at least 32 characters")
+	}
+	if cfg.Auth.Token_lifetime.Duration <= 0 {
+		problems = append(problems, "auth token_lifetime must be positive")
+	}
+	if cfg.Auth.Refresh_token_lifetime.Duration <= cfg.Auth.Token_lifetime.Duration {
+		problems = append(problems, "auth refresh_token_lifetime must be longer than token_lifetime")
+	}
+	if cfg.Auth.Bcrypt_cost < bcrypt.MinCost || cfg.Auth.Bcrypt_cost > bcrypt.MaxCost {
+		problems = append(problems, fmt.Sprintf("auth bcrypt_cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+
+	if cfg.Reservations.Default_duration.Duration <= 0 || cfg.Reservations.Table_turn_time.Duration <= 0 {
+		problems = append(problems, "reservations default_duration and table_turn_time must be positive")
+	}
+
+	if cfg.Restaurant.Name == "" {
+		problems = append(problems, "restaurant name is required")
+	}
+	if !currencyCode.MatchString(cfg.Restaurant.Currency) {
+		problems = append(problems, fmt.Sprintf("restaurant currency must be a three letter code like USD, got %q", cfg.Restaurant.Currency))
+	}
+	if _, err := time.LoadLocation(cfg.Restaurant.Timezone); err != nil || cfg.Restaurant.Timezone == "" {
+		problems = append(problems, fmt.Sprintf("restaurant timezone must be an IANA name like Europe/London, got %q", cfg.Restaurant.Timezone))
+	}
+
+	switch cfg.Images.Store {
+	case ImageStoreLocal:
+		if cfg.Images.Directory == "" {
+			problems = append(problems, "images directory is required")
+		}
+	case ImageStoreS3:
+		if cfg.Images.S3.Endpoint == "" || cfg.Images.S3.Bucket == "" || cfg.Images.S3.Region == "" {
+			problems = append(problems, "images s3 endpoint, region and bucket are required")
+		}
+		if cfg.Images.S3.Access_key == "" || cfg.Images.S3.Secret_key == "" {
+			problems = append(problems, "images s3 access_key and secret_key are required")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("images store must be %s or %s, got %q", ImageStoreLocal, ImageStoreS3, cfg.Images.Store))
+	}
+	if cfg.Images.Public_url == "" {
+		problems = append(problems, "images public_url is required")
+	}
+	if cfg.Images.Max_upload_bytes <= 0 {
+		problems = append(problems, "images max_upload_bytes must be positive")
+	}
+	for _, width := range cfg.Images.Thumbnail_widths {
+		if width < 16 || width > 4096 {
+			problems = append(problems, fmt.Sprintf("images thumbnail_widths must be between 16 and 4096, got %d", width))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Location is the restaurant timezone, Validate has already checked it
+func (restaurant RestaurantConfig) Location() *time.Location {
+	location, err := time.LoadLocation(restaurant.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Duration reads "30m" style values from both YAML and TOML files
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,433 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errDrawerAlreadyOpen = errors.New("another drawer is already open, close it first")
+var errDrawerClosed = errors.New("drawer is already closed")
+var errCountedCashRequired = errors.New("counted_cash is required to close the drawer")
+
+type DrawerController struct {
+	drawers  repository.DrawerRepository
+	zReports repository.ZReportRepository
+	reports  repository.ReportRepository
+
+	location *time.Location
+	timeout  time.Duration
+}
+
+func NewDrawerController(drawers repository.DrawerRepository, zReports repository.ZReportRepository, reports repository.ReportRepository, location *time.Location, timeout time.Duration) *DrawerController {
+	return &DrawerController{drawers: drawers, zReports: zReports, reports: reports, location: location, timeout: timeout}
+}
+
+var drawerListSpec = listSpec{
+	fields: map[string]listField{
+		"status":    {path: "status", kind: textField, filter: true, sort: true},
+		"opened_by": {path: "opened_by", kind: textField, filter: true},
+		"opened_at": {path: "opened_at", kind: timeField, sort: true},
+		"closed_at": {path: "closed_at", kind: timeField, sort: true},
+	},
+	dateField:   "opened_at",
+	defaultSort: "-opened_at",
+}
+
+func (dc *DrawerController) GetDrawers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, drawerListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := dc.drawers.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing drawers", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+// GetDrawer shows an open drawer with the cash expected in it so far
+func (dc *DrawerController) GetDrawer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+
+		drawer, err := dc.drawers.FindByID(ctx, c.Param("drawer_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("drawer was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the drawer", err))
+			return
+		}
+
+		if equalStatus(drawer.Status, models.DrawerOpen) {
+			period, err := dc.closingPeriod(ctx, drawer, time.Now())
+			if err != nil {
+				c.Error(apierror.Internal("error occured while counting the drawer", err))
+				return
+			}
+			payments, err := dc.reports.PaymentMix(ctx, period)
+			if err != nil {
+				c.Error(apierror.Internal("error occured while counting the drawer", err))
+				return
+			}
+			expected := expectedCash(drawer, payments)
+			drawer.Expected_cash = &expected
+		}
+		c.JSON(http.StatusOK, drawer)
+	}
+}
+
+// OpenDrawer starts a shift with opening_float in the drawer
+func (dc *DrawerController) OpenDrawer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+		var drawer models.CashDrawer
+
+		if err := c.ShouldBindJSON(&drawer); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.StructPartial(drawer, "Opening_float"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if drawer.Opening_float == nil {
+			float := models.Cents(0)
+			drawer.Opening_float = &float
+		}
+
+		status := models.DrawerOpen
+		drawer.ID = primitive.NewObjectID()
+		drawer.Drawer_id = drawer.ID.Hex()
+		drawer.Status = &status
+		drawer.Movements = []models.DrawerMovement{}
+		drawer.Counted_cash, drawer.Expected_cash, drawer.Variance, drawer.Z_report_id, drawer.Closed_at = nil, nil, nil, nil, nil
+		drawer.Opened_by = c.GetString("uid")
+		drawer.Closed_by = ""
+		drawer.Opened_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+		opened, err := dc.drawers.Open(ctx, drawer)
+		if err != nil {
+			c.Error(apierror.Internal("drawer was not opened", err))
+			return
+		}
+		if !opened {
+			c.Error(apierror.Wrap(http.StatusConflict, errDrawerAlreadyOpen))
+			return
+		}
+		c.JSON(http.StatusOK, drawer)
+	}
+}
+
+// AddDrawerMovement records a cash drop or a payout, payouts need a reason
+func (dc *DrawerController) AddDrawerMovement() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+		var movement models.DrawerMovement
+
+		if err := c.ShouldBindJSON(&movement); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(movement); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		movement.Created_by = c.GetString("uid")
+		movement.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+		drawerId := c.Param("drawer_id")
+		if _, ok := dc.loadOpenDrawer(ctx, c, drawerId); !ok {
+			return
+		}
+		added, err := dc.drawers.AddMovement(ctx, drawerId, movement)
+		if err != nil {
+			c.Error(apierror.Internal("drawer movement was not recorded", err))
+			return
+		}
+		if !added {
+			c.Error(apierror.Wrap(http.StatusConflict, errDrawerClosed))
+			return
+		}
+
+		drawer, err := dc.drawers.FindByID(ctx, drawerId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("drawer was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the drawer", err))
+			return
+		}
+		c.JSON(http.StatusOK, drawer)
+	}
+}
+
+// ClosedDrawer is a drawer that was just closed and its Z report
+type ClosedDrawer struct {
+	Drawer   models.CashDrawer `json:"drawer"`
+	Z_report models.ZReport    `json:"z_report"`
+}
+
+// CloseDrawer compares counted_cash with what the drawer should hold and
+// writes the Z report of everything since the previous one. Invoices of
+// the period cannot be edited afterwards.
+func (dc *DrawerController) CloseDrawer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+		var count models.CashDrawer
+
+		if err := c.ShouldBindJSON(&count); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if count.Counted_cash == nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, errCountedCashRequired))
+			return
+		}
+		if validationErr := validate.StructPartial(count, "Counted_cash"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		drawer, ok := dc.loadOpenDrawer(ctx, c, c.Param("drawer_id"))
+		if !ok {
+			return
+		}
+
+		now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		report, err := dc.zReport(ctx, drawer, *count.Counted_cash, now)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while building the Z report", err))
+			return
+		}
+		report.Closed_by = c.GetString("uid")
+
+		status := models.DrawerClosed
+		closing := repository.DrawerClose{
+			Counted_cash:  report.Counted_cash,
+			Expected_cash: report.Expected_cash,
+			Variance:      report.Variance,
+			Z_report_id:   report.Z_report_id,
+			Closed_by:     report.Closed_by,
+			Closed_at:     now,
+		}
+		closed, err := dc.drawers.Close(ctx, drawer.Drawer_id, closing)
+		if err != nil {
+			c.Error(apierror.Internal("drawer was not closed", err))
+			return
+		}
+		if !closed {
+			c.Error(apierror.Wrap(http.StatusConflict, errDrawerClosed))
+			return
+		}
+		if err := dc.zReports.Insert(ctx, report); err != nil {
+			//the request may have timed out, the drawer is reopened anyway
+			reopenCtx, cancelReopen := context.WithTimeout(context.Background(), dc.timeout)
+			defer cancelReopen()
+			if reopenErr := dc.drawers.Reopen(reopenCtx, drawer.Drawer_id); reopenErr != nil {
+				c.Error(apierror.Internal("Z report was not stored and the drawer could not be reopened", fmt.Errorf("%v, reopening: %w", err, reopenErr)))
+				return
+			}
+			c.Error(apierror.Internal("Z report was not stored, the drawer is still open", err))
+			return
+		}
+
+		drawer.Status = &status
+		drawer.Counted_cash = &report.Counted_cash
+		drawer.Expected_cash = &report.Expected_cash
+		drawer.Variance = &report.Variance
+		drawer.Z_report_id = &report.Z_report_id
+		drawer.Closed_by = report.Closed_by
+		drawer.Closed_at = &now
+		c.JSON(http.StatusOK, ClosedDrawer{Drawer: drawer, Z_report: report})
+	}
+}
+
+var zReportListSpec = listSpec{
+	fields: map[string]listField{
+		"drawer_id":  {path: "drawer_id", kind: textField, filter: true},
+		"closed_by":  {path: "closed_by", kind: textField, filter: true},
+		"number":     {path: "number", kind: numberField, filter: true, sort: true},
+		"period_end": {path: "period_end", kind: timeField, sort: true},
+	},
+	dateField:   "period_end",
+	defaultSort: "-number",
+}
+
+func (dc *DrawerController) GetZReports() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, zReportListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := dc.zReports.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing Z reports", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (dc *DrawerController) GetZReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+		defer cancel()
+
+		report, err := dc.zReports.FindByID(ctx, c.Param("z_report_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("Z report was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the Z report", err))
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// loadOpenDrawer writes the error response itself and returns false when the
+// drawer is missing or closed
+func (dc *DrawerController) loadOpenDrawer(ctx context.Context, c *gin.Context, drawerId string) (models.CashDrawer, bool) {
+	drawer, err := dc.drawers.FindByID(ctx, drawerId)
+	if err == repository.ErrNotFound {
+		c.Error(apierror.NotFound("drawer was not found"))
+		return drawer, false
+	}
+	if err != nil {
+		c.Error(apierror.Internal("error occured while fetching the drawer", err))
+		return drawer, false
+	}
+	if !equalStatus(drawer.Status, models.DrawerOpen) {
+		c.Error(apierror.Wrap(http.StatusConflict, errDrawerClosed))
+		return drawer, false
+	}
+	return drawer, true
+}
+
+// closingPeriod runs from the end of the previous Z report, or from when
+// the drawer was opened before the first one, so no sale is left out of a
+// Z report
+func (dc *DrawerController) closingPeriod(ctx context.Context, drawer models.CashDrawer, end time.Time) (repository.ReportRange, error) {
+	period := repository.ReportRange{From: drawer.Opened_at, To: end, Location: dc.location}
+	latest, err := dc.zReports.Latest(ctx)
+	if err == repository.ErrNotFound {
+		return period, nil
+	}
+	if err != nil {
+		return period, err
+	}
+	period.From = latest.Period_end
+	return period, nil
+}
+
+// zReport adds up the period of the drawer from the sales reports
+func (dc *DrawerController) zReport(ctx context.Context, drawer models.CashDrawer, counted models.Money, now time.Time) (models.ZReport, error) {
+	report := models.ZReport{Number: 1, Drawer_id: drawer.Drawer_id, Period_end: now, Created_at: now}
+	report.ID = primitive.NewObjectID()
+	report.Z_report_id = report.ID.Hex()
+	if latest, err := dc.zReports.Latest(ctx); err == nil {
+		report.Number = latest.Number + 1
+	} else if err != repository.ErrNotFound {
+		return report, err
+	}
+
+	period, err := dc.closingPeriod(ctx, drawer, now)
+	if err != nil {
+		return report, err
+	}
+	report.Period_start = period.From
+
+	revenue, err := dc.reports.RevenueByDay(ctx, period)
+	if err != nil {
+		return report, err
+	}
+	report.Gross, report.Net, report.Tax, report.Discounts = models.Cents(0), models.Cents(0), models.Cents(0), models.Cents(0)
+	for _, row := range revenue {
+		report.Invoices += row.Invoices
+		report.Gross = report.Gross.Add(row.Gross)
+		report.Net = report.Net.Add(row.Net)
+		report.Tax = report.Tax.Add(row.Tax)
+		report.Discounts = report.Discounts.Add(row.Discounts)
+	}
+	if report.Payments, err = dc.reports.PaymentMix(ctx, period); err != nil {
+		return report, err
+	}
+	if report.Voids, err = dc.reports.Voids(ctx, period); err != nil {
+		return report, err
+	}
+
+	report.Opening_float = *drawer.Opening_float
+	report.Cash_payments = cashPayments(report.Payments)
+	report.Drops, report.Payouts = drawer.CashOut()
+	report.Expected_cash = expectedCash(drawer, report.Payments)
+	report.Counted_cash = counted
+	report.Variance = counted.Sub(report.Expected_cash)
+	return report, nil
+}
+
+// expectedCash is the float and the cash taken, less what left the drawer
+func expectedCash(drawer models.CashDrawer, payments []models.PaymentMixRow) models.Money {
+	drops, payouts := drawer.CashOut()
+	expected := models.Cents(0)
+	if drawer.Opening_float != nil {
+		expected = *drawer.Opening_float
+	}
+	return expected.Add(cashPayments(payments)).Sub(drops).Sub(payouts)
+}
+
+func cashPayments(payments []models.PaymentMixRow) models.Money {
+	for _, row := range payments {
+		if row.Method == models.PaymentCash {
+			return row.Amount
+		}
+	}
+	return models.Cents(0)
+}
+
+// checkInvoiceOpen writes a 409 and returns false once a Z report has
+// closed the period the invoice was issued in, which is the period the
+// report counts it in. Payments are not checked, a late payment counts in
+// the period it is taken.
+func checkInvoiceOpen(ctx context.Context, c *gin.Context, zReports repository.ZReportRepository, invoice models.Invoice) bool {
+	latest, err := zReports.Latest(ctx)
+	if err == repository.ErrNotFound {
+		return true
+	}
+	if err != nil {
+		c.Error(apierror.Internal("error occured while checking the Z reports", err))
+		return false
+	}
+	if !invoice.Created_at.After(latest.Period_end) {
+		c.Error(apierror.Conflict(fmt.Sprintf("invoice belongs to a period closed by Z report %d and can no longer be changed", latest.Number)))
+		return false
+	}
+	return true
+}
@@ -2,224 +2,526 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
-	"math"
+	"io"
 	"net/http"
-	database "restaurant-management/database"
+	"reflect"
+	"restaurant-management/apierror"
+	"restaurant-management/blobstore"
+	"restaurant-management/config"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
-	"strconv"
+	"restaurant-management/repository"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var foodCollection *mongo.Collection = database.OpenCollection(database.Client, "food")
 var validate = validator.New()
 
-func GetFoods() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		recordPerPage, err := strconv.Atoi(c.Query("recordPerPage"))
-
-		if err != nil || recordPerPage < 1 {
-			recordPerPage = 10
-		}
+func init() {
+	//amounts are validated by their cents, so gt=0 and the like work on them
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(models.Money).Amount
+	}, models.Money{})
 
-		page, err := strconv.Atoi(c.Query("page"))
-		if err != nil || page < 1 {
-			page = 1
-		}
-
-		startIndex := (page - 1) * recordPerPage
+	//validation errors name fields as they appear in the JSON body, this also
+	//applies to the validator gin uses while binding
+	validate.RegisterTagNameFunc(jsonFieldName)
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(jsonFieldName)
+	}
+}
 
-		startIndex, err = strconv.Atoi(c.Query("startIndex"))
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
 
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+type FoodController struct {
+	foods       repository.FoodRepository
+	menus       repository.MenuRepository
+	ingredients repository.IngredientRepository
+	images      blobstore.Store
+	imageConfig config.ImagesConfig
 
-		matchStage := bson.D{{Key: "$match", Value: bson.D{}}}
+	timeout time.Duration
+}
 
-		groupStage := bson.D{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: bson.D{{Key: "_id", Value: "null"}}},
-			{Key: "total_count", Value: bson.D{{Key: "$sum", Value: 1}}},
-			{Key: "data", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}},
-		}}}
+func NewFoodController(foods repository.FoodRepository, menus repository.MenuRepository, ingredients repository.IngredientRepository, images blobstore.Store, imageConfig config.ImagesConfig, timeout time.Duration) *FoodController {
+	return &FoodController{foods: foods, menus: menus, ingredients: ingredients, images: images, imageConfig: imageConfig, timeout: timeout}
+}
 
-		projectStage := bson.D{
-			{Key: "$project", Value: bson.D{
-				{Key: "_id", Value: 0},
-				{Key: "total_count", Value: 1},
-				{Key: "food_items", Value: bson.D{{Key: "$slice", Value: []interface{}{"$data", startIndex, recordPerPage}}}},
-			}}}
+var foodListSpec = listSpec{
+	fields: map[string]listField{
+		"menu_id":    {path: "menu_id", kind: textField, filter: true},
+		"station":    {path: "station", kind: textField, filter: true, sort: true},
+		"name":       {path: "name", kind: textField, sort: true},
+		"price":      {path: "price.amount", kind: numberField, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "name",
+}
 
-		cursor, err := foodCollection.Aggregate(ctx, mongo.Pipeline{matchStage, groupStage, projectStage})
+func (fc *FoodController) GetFoods() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, foodListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing food items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allFoods []bson.M
-		if err = cursor.All(ctx, allFoods); err != nil { // &allFoods
-			log.Fatal(err)
+		filter, err := foodFilter(c.Query("exclude_allergens"), c.Query("diet"))
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 
-		c.JSON(http.StatusOK, allFoods[0])
-
+		page, err := fc.foods.Page(ctx, filter, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing food items", err))
+			return
+		}
+		if err := markAvailability(ctx, fc.ingredients, page.Items); err != nil {
+			c.Error(apierror.Internal("error occured while checking ingredient stock", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
 	}
 }
-func GetFood() gin.HandlerFunc {
+func (fc *FoodController) GetFood() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		var ctx, cancel = context.WithTimeout(context.Background(), fc.timeout)
+		defer cancel()
 		foodId := c.Param("food_id")
 
-		var food models.Food
-		err := foodCollection.FindOne(ctx, bson.M{"food_id": foodId}).Decode(&food)
-		defer cancel()
+		food, err := fc.foods.FindByID(ctx, foodId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("food item was not found"))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("error occured while fetching the food items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
-		c.JSON(http.StatusOK, food)
+		foods := []models.Food{food}
+		if err := markAvailability(ctx, fc.ingredients, foods); err != nil {
+			c.Error(apierror.Internal("error occured while checking ingredient stock", err))
+			return
+		}
+		c.JSON(http.StatusOK, foods[0])
 	}
 }
-func CreateFood() gin.HandlerFunc {
+func (fc *FoodController) CreateFood() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
+		defer cancel()
 		var food models.Food
-		var menu models.Menu
-		if err := c.BindJSON(&food); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&food); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		validationErr := validate.Struct(food)
 		if validationErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if err := food.CheckOptions(); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if err := food.CheckDiet(); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if err := fc.checkRecipe(ctx, food.Recipe); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		if food.Menu_id != nil {
-
-			err := menuCollection.FindOne(ctx, bson.M{"menu_id": food.Menu_id}).Decode(&menu)
-			defer cancel()
-			if err != nil {
-				msg := fmt.Sprintf("menu was not found")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			if _, err := fc.menus.FindByID(ctx, *food.Menu_id); err == repository.ErrNotFound {
+				c.Error(apierror.InvalidField("menu_id", "exists", "menu was not found"))
+				return
+			} else if err != nil {
+				c.Error(apierror.Internal("error occured while fetching the menu", err))
 				return
 			}
 		}
 
+		if food.Station != nil {
+			station := strings.ToLower(*food.Station)
+			food.Station = &station
+		}
+
 		food.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 		food.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 		food.ID = primitive.NewObjectID()
 		food.Food_id = food.ID.Hex()
-		var num = toFixed(*food.Price, 2)
-		food.Price = &num
-
-		result, insertErr := foodCollection.InsertOne(ctx, food)
-		if insertErr != nil {
+		if insertErr := fc.foods.Insert(ctx, food); insertErr != nil {
 			msg := fmt.Sprintf("Food item was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, insertErr))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, food)
 
 	}
 }
 
-func round(num float64) int {
-	//return int(num + math.Copysign(0.5, num))
-	res := int(num + math.Copysign(0.5, num))
-	return res
-}
-func toFixed(num float64, precision int) float64 {
-	// output := math.Pow(10, float64(precision))
-	// return float64(round(num*output)) / output
-	output := math.Pow(10, float64(precision))
-	resu := float64(round(num*output)) / output
-	return resu
-
-}
-
-func UpdateFood() gin.HandlerFunc {
+func (fc *FoodController) UpdateFood() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
+		defer cancel()
 		var food models.Food
-		var menu models.Menu
 
 		foodID := c.Param("food_id")
 
-		if err := c.BindJSON(&food); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&food); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var updateObj primitive.D
+		var update repository.FoodUpdate
 
 		if food.Namme != "" {
-			updateObj = append(updateObj, bson.E{"name", food.Namme})
+			update.Name = &food.Namme
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "please provide food name"})
+			c.Error(apierror.BadRequest("please provide food name"))
 			return
 		}
 		if food.Price != nil {
-			updateObj = append(updateObj, bson.E{"price", food.Price})
+			if validationErr := validate.StructPartial(food, "Price"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Price = food.Price
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "please provide food price"})
+			c.Error(apierror.BadRequest("please provide food price"))
 			return
 		}
+		//images are uploaded to /foods/:food_id/image, a link given instead
+		//replaces the uploaded one
 		if food.Food_image != nil {
-			updateObj = append(updateObj, bson.E{"food_image", food.Food_image})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "please provide food_image"})
-			return
+			update.Food_image = food.Food_image
 		}
 		if food.Menu_id != nil {
-			if err := menuCollection.FindOne(ctx, bson.M{"menu_id": food.Menu_id}).Decode(&menu); err != nil {
-				msg := fmt.Sprintf("Menu was not found")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
-				defer cancel()
+			if _, err := fc.menus.FindByID(ctx, *food.Menu_id); err == repository.ErrNotFound {
+				c.Error(apierror.InvalidField("menu_id", "exists", "menu was not found"))
+				return
+			} else if err != nil {
+				c.Error(apierror.Internal("error occured while fetching the menu", err))
+				return
 			}
-			updateObj = append(updateObj, bson.E{"menu_id", food.Menu_id}) /////  bson.E{"menu", food.Price} alter
+			update.Menu_id = food.Menu_id
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "please provide menu_id"})
+			c.Error(apierror.BadRequest("please provide menu_id"))
 			return
 		}
 
-		food.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		updateObj = append(updateObj, bson.E{"updated_at", food.Updated_at})
+		if food.Station != nil {
+			station := strings.ToLower(*food.Station)
+			update.Station = &station
+		}
 
-		upsert := true
-		filter := bson.M{"menu_id": foodID}
-		opt := options.UpdateOptions{
-			Upsert: &upsert,
+		//variants and modifier groups are replaced as a whole when given
+		if food.Variants != nil || food.Modifier_groups != nil {
+			if validationErr := validate.StructPartial(food, "Variants", "Modifier_groups"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			if err := food.CheckOptions(); err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
+			}
+		}
+		if food.Variants != nil {
+			update.Variants = food.Variants
+		}
+		if food.Modifier_groups != nil {
+			update.Modifier_groups = food.Modifier_groups
+		}
+		if food.Recipe != nil {
+			if validationErr := validate.StructPartial(food, "Recipe"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			if err := fc.checkRecipe(ctx, food.Recipe); err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
+			}
+			update.Recipe = food.Recipe
 		}
 
-		result, err := foodCollection.UpdateOne(
+		//allergens and dietary tags are replaced as a whole too, and checked
+		//against the stored ones when only one of them is given
+		if food.Allergens != nil || food.Dietary_tags != nil {
+			if validationErr := validate.StructPartial(food, "Allergens", "Dietary_tags"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			stored, err := fc.foods.FindByID(ctx, foodID)
+			if err == repository.ErrNotFound {
+				c.Error(apierror.NotFound("food item was not found"))
+				return
+			}
+			if err != nil {
+				c.Error(apierror.Internal("error occured while fetching the food items", err))
+				return
+			}
+			if food.Allergens != nil {
+				stored.Allergens = food.Allergens
+				update.Allergens = food.Allergens
+			}
+			if food.Dietary_tags != nil {
+				stored.Dietary_tags = food.Dietary_tags
+				update.Dietary_tags = food.Dietary_tags
+			}
+			if err := stored.CheckDiet(); err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
+			}
+		}
+		if food.Nutrition != nil {
+			if validationErr := validate.StructPartial(food, "Nutrition"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Nutrition = food.Nutrition
+		}
 
-			ctx,
-			filter,
-			bson.D{
-				{"$set", updateObj},
-			},
-			&opt,
-		)
+		food.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = food.Updated_at
+
+		found, err := fc.foods.Update(ctx, foodID, update)
 		if err != nil {
 			msg := fmt.Sprintf("food item update failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("food item was not found"))
 			return
 		}
+
+		updatedFood, err := fc.foods.FindByID(ctx, foodID)
+		if err != nil {
+			msg := fmt.Sprintf("error occured while fetching the food items")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedFood)
+
+	}
+}
+
+// checkRecipe makes sure every ingredient of recipe exists, once
+func (fc *FoodController) checkRecipe(ctx context.Context, recipe []models.RecipeLine) error {
+	seen := map[string]bool{}
+	for _, line := range recipe {
+		if seen[line.Ingredient_id] {
+			return fmt.Errorf("ingredient %s is listed twice in the recipe", line.Ingredient_id)
+		}
+		seen[line.Ingredient_id] = true
+		if _, err := fc.ingredients.FindByID(ctx, line.Ingredient_id); err != nil {
+			return fmt.Errorf("ingredient %s was not found", line.Ingredient_id)
+		}
+	}
+	return nil
+}
+
+// foodFilter reads the comma separated ?exclude_allergens= and ?diet= of
+// GET /foods
+func foodFilter(excludeAllergens string, diets string) (repository.FoodFilter, error) {
+	var filter repository.FoodFilter
+	for _, allergen := range splitList(excludeAllergens) {
+		if !contains(models.Allergens, allergen) {
+			return filter, fmt.Errorf("unknown allergen %s, use one of %s", allergen, strings.Join(models.Allergens, ", "))
+		}
+		filter.Exclude_allergens = append(filter.Exclude_allergens, allergen)
+	}
+	for _, diet := range splitList(diets) {
+		if !contains(models.Diets, diet) {
+			return filter, fmt.Errorf("unknown diet %s, use one of %s", diet, strings.Join(models.Diets, ", "))
+		}
+		filter.Diets = append(filter.Diets, diet)
+	}
+	return filter, nil
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxImageUpload is the largest body of an image upload, the limit leaves
+// room for the multipart headers around the file
+func MaxImageUpload(images config.ImagesConfig) int64 {
+	return images.Max_upload_bytes + 64<<10
+}
+
+// UploadFoodImage takes the multipart field "image", stores it with a
+// thumbnail for each configured width and links the food to them. Keys
+// carry a hash of the upload, so the stored files never change and can be
+// cached forever.
+func (fc *FoodController) UploadFoodImage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
 		defer cancel()
-		c.JSON(http.StatusOK, result)
 
+		foodID := c.Param("food_id")
+		food, err := fc.foods.FindByID(ctx, foodID)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("food item was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the food items", err))
+			return
+		}
+
+		maxSize := fc.imageConfig.Max_upload_bytes
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImageUpload(fc.imageConfig))
+		fileHeader, err := c.FormFile("image")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || (err == nil && fileHeader.Size > maxSize) {
+			c.Error(apierror.New(http.StatusRequestEntityTooLarge, fmt.Sprintf("image must be at most %d bytes", maxSize)))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.BadRequest("please upload the image as the multipart field image"))
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.Error(apierror.BadRequest("image could not be read"))
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			c.Error(apierror.BadRequest("image could not be read"))
+			return
+		}
+
+		img, contentType, err := helpers.DecodeImage(data)
+		if err == helpers.ErrImageType {
+			c.Error(apierror.Wrap(http.StatusUnsupportedMediaType, err))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		sum := sha256.Sum256(data)
+		prefix := "foods/" + strings.ToLower(food.Food_id) + "/" + hex.EncodeToString(sum[:8])
+		bounds := img.Bounds()
+		image := models.FoodImage{
+			Content_type: contentType,
+			Width:        bounds.Dx(),
+			Height:       bounds.Dy(),
+			Thumbnails:   []models.ImageThumbnail{},
+		}
+
+		key := prefix + "/original." + helpers.ImageTypes[contentType]
+		if err := fc.images.Put(ctx, key, contentType, data); err != nil {
+			c.Error(apierror.Internal("image could not be stored", err))
+			return
+		}
+		image.Url = fc.imageURL(key)
+		image.Keys = append(image.Keys, key)
+
+		//thumbnails are never wider than the upload
+		widths := append([]int{}, fc.imageConfig.Thumbnail_widths...)
+		sort.Ints(widths)
+		for _, width := range widths {
+			if width >= bounds.Dx() {
+				break
+			}
+			thumbnail := helpers.Thumbnail(img, width)
+			encoded, thumbnailType, err := helpers.EncodeThumbnail(thumbnail, contentType)
+			if err != nil {
+				fc.removeImages(image.Keys, food.Image)
+				c.Error(apierror.Internal("thumbnail could not be made", err))
+				return
+			}
+			key := fmt.Sprintf("%s/w%d.%s", prefix, width, helpers.ImageTypes[thumbnailType])
+			if err := fc.images.Put(ctx, key, thumbnailType, encoded); err != nil {
+				fc.removeImages(image.Keys, food.Image)
+				c.Error(apierror.Internal("image could not be stored", err))
+				return
+			}
+			image.Keys = append(image.Keys, key)
+			image.Thumbnails = append(image.Thumbnails, models.ImageThumbnail{
+				Url:    fc.imageURL(key),
+				Width:  thumbnail.Bounds().Dx(),
+				Height: thumbnail.Bounds().Dy(),
+			})
+		}
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update := repository.FoodUpdate{Food_image: &image.Url, Image: &image, Updated_at: updatedAt}
+		if _, err := fc.foods.Update(ctx, foodID, update); err != nil {
+			fc.removeImages(image.Keys, food.Image)
+			c.Error(apierror.Internal("food item update failed", err))
+			return
+		}
+
+		//the previous upload is no longer linked from anywhere
+		if food.Image != nil {
+			fc.removeImages(food.Image.Keys, &image)
+		}
+
+		updatedFood, err := fc.foods.FindByID(ctx, foodID)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the food items", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedFood)
+	}
+}
+
+func (fc *FoodController) imageURL(key string) string {
+	return strings.TrimSuffix(fc.imageConfig.Public_url, "/") + "/" + key
+}
+
+// removeImages deletes stored keys, except those kept uploads still use
+// because the same file was uploaded again. Failures only leave files
+// behind, so they are not reported.
+func (fc *FoodController) removeImages(keys []string, kept *models.FoodImage) {
+	ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
+	defer cancel()
+	for _, key := range keys {
+		if kept != nil && contains(kept.Keys, key) {
+			continue
+		}
+		fc.images.Delete(ctx, key)
 	}
 }
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/blobstore"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageController struct {
+	images blobstore.Store
+
+	timeout time.Duration
+}
+
+func NewImageController(images blobstore.Store, timeout time.Duration) *ImageController {
+	return &ImageController{images: images, timeout: timeout}
+}
+
+// GetImage serves a stored image. Uploads never overwrite a key, so
+// browsers and CDNs may keep them for a year without asking again.
+func (imc *ImageController) GetImage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), imc.timeout)
+		defer cancel()
+
+		key := strings.TrimPrefix(c.Param("key"), "/")
+		object, err := imc.images.Open(ctx, key)
+		if err == blobstore.ErrNotFound {
+			c.Error(apierror.NotFound("image was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while reading the image", err))
+			return
+		}
+		defer object.Close()
+
+		headers := map[string]string{
+			"Cache-Control":          "public, max-age=31536000, immutable",
+			"X-Content-Type-Options": "nosniff",
+		}
+		if object.Etag != "" {
+			headers["ETag"] = object.Etag
+		}
+		if !object.Modified_at.IsZero() {
+			headers["Last-Modified"] = object.Modified_at.UTC().Format(http.TimeFormat)
+		}
+		if object.Etag != "" && c.GetHeader("If-None-Match") == object.Etag {
+			for name, value := range headers {
+				c.Header(name, value)
+			}
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.DataFromReader(http.StatusOK, object.Size, object.Content_type, object, headers)
+	}
+}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IngredientController struct {
+	ingredients repository.IngredientRepository
+
+	timeout time.Duration
+}
+
+func NewIngredientController(ingredients repository.IngredientRepository, timeout time.Duration) *IngredientController {
+	return &IngredientController{ingredients: ingredients, timeout: timeout}
+}
+
+var ingredientListSpec = listSpec{
+	fields: map[string]listField{
+		"unit":       {path: "unit", kind: textField, filter: true, sort: true},
+		"name":       {path: "name", kind: textField, sort: true},
+		"stock":      {path: "stock", kind: numberField, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "name",
+}
+
+// GetIngredients lists the ingredients, only those low on stock with
+// ?low_stock=true
+func (igc *IngredientController) GetIngredients() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), igc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, ingredientListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := igc.ingredients.Page(ctx, c.Query("low_stock") == "true", query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing ingredients", err))
+			return
+		}
+		for i := range page.Items {
+			page.Items[i].Low_stock = page.Items[i].IsLow()
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (igc *IngredientController) GetIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), igc.timeout)
+		defer cancel()
+
+		ingredient, err := igc.ingredients.FindByID(ctx, c.Param("ingredient_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("ingredient was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the ingredient", err))
+			return
+		}
+		ingredient.Low_stock = ingredient.IsLow()
+		c.JSON(http.StatusOK, ingredient)
+	}
+}
+
+func (igc *IngredientController) CreateIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), igc.timeout)
+		defer cancel()
+
+		var ingredient models.Ingredient
+		if err := c.ShouldBindJSON(&ingredient); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(ingredient); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		stock := models.RoundStock(*ingredient.Stock)
+		ingredient.Stock = &stock
+		ingredient.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		ingredient.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		ingredient.ID = primitive.NewObjectID()
+		ingredient.Ingredient_id = ingredient.ID.Hex()
+
+		if err := igc.ingredients.Insert(ctx, ingredient); err != nil {
+			c.Error(apierror.Internal("ingredient was not created", err))
+			return
+		}
+		ingredient.Low_stock = ingredient.IsLow()
+		c.JSON(http.StatusOK, ingredient)
+	}
+}
+
+// UpdateIngredient changes the fields sent. Setting stock records a count
+// of what is on the shelf, deliveries go through RestockIngredient.
+func (igc *IngredientController) UpdateIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), igc.timeout)
+		defer cancel()
+
+		var ingredient models.Ingredient
+		if err := c.ShouldBindJSON(&ingredient); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		ingredientId := c.Param("ingredient_id")
+
+		var update repository.IngredientUpdate
+		if ingredient.Name != nil {
+			if validationErr := validate.StructPartial(ingredient, "Name"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Name = ingredient.Name
+		}
+		if ingredient.Unit != nil {
+			if validationErr := validate.StructPartial(ingredient, "Unit"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Unit = ingredient.Unit
+		}
+		if ingredient.Stock != nil {
+			if *ingredient.Stock < 0 {
+				c.Error(apierror.BadRequest("stock cannot be negative"))
+				return
+			}
+			stock := models.RoundStock(*ingredient.Stock)
+			update.Stock = &stock
+		}
+		if ingredient.Low_stock_threshold != nil {
+			if *ingredient.Low_stock_threshold < 0 {
+				c.Error(apierror.BadRequest("low_stock_threshold cannot be negative"))
+				return
+			}
+			update.Low_stock_threshold = ingredient.Low_stock_threshold
+		}
+
+		ingredient.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = ingredient.Updated_at
+
+		found, err := igc.ingredients.Update(ctx, ingredientId, update)
+		if err != nil {
+			c.Error(apierror.Internal("ingredient update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("ingredient was not found"))
+			return
+		}
+
+		updatedIngredient, err := igc.ingredients.FindByID(ctx, ingredientId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the ingredient", err))
+			return
+		}
+		updatedIngredient.Low_stock = updatedIngredient.IsLow()
+		c.JSON(http.StatusOK, updatedIngredient)
+	}
+}
+
+type RestockRequest struct {
+	Quantity *float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+// RestockIngredient adds a delivery to the stock
+func (igc *IngredientController) RestockIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), igc.timeout)
+		defer cancel()
+
+		var restock RestockRequest
+		if err := c.ShouldBindJSON(&restock); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(restock); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		ingredientId := c.Param("ingredient_id")
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		found, err := igc.ingredients.AdjustStock(ctx, ingredientId, models.RoundStock(*restock.Quantity), updatedAt)
+		if err != nil {
+			c.Error(apierror.Internal("ingredient update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("ingredient was not found"))
+			return
+		}
+
+		updatedIngredient, err := igc.ingredients.FindByID(ctx, ingredientId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the ingredient", err))
+			return
+		}
+		updatedIngredient.Low_stock = updatedIngredient.IsLow()
+		c.JSON(http.StatusOK, updatedIngredient)
+	}
+}
+
+type outOfStockError struct {
+	ingredient string
+}
+
+func (e outOfStockError) Error() string {
+	return fmt.Sprintf("there is not enough %s left", e.ingredient)
+}
+
+// takeStock takes uses out of stock, all of them or none: when one
+// ingredient runs short the ones already taken are given back
+func takeStock(ctx context.Context, ingredients repository.IngredientRepository, uses []models.StockUse) error {
+	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	for i, use := range uses {
+		taken, err := ingredients.AdjustStock(ctx, use.Ingredient_id, -use.Quantity, updatedAt)
+		if err == nil && !taken {
+			err = outOfStockError{use.Ingredient_id}
+			if ingredient, findErr := ingredients.FindByID(ctx, use.Ingredient_id); findErr == nil && ingredient.Name != nil {
+				err = outOfStockError{*ingredient.Name}
+			}
+		}
+		if err != nil {
+			if returnErr := returnStock(ctx, ingredients, uses[:i]); returnErr != nil {
+				return returnErr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// returnStock puts uses back into stock, ingredients deleted since are
+// skipped
+func returnStock(ctx context.Context, ingredients repository.IngredientRepository, uses []models.StockUse) error {
+	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	for _, use := range uses {
+		if _, err := ingredients.AdjustStock(ctx, use.Ingredient_id, use.Quantity, updatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// combineStockUses adds up the uses of the same ingredient
+func combineStockUses(uses []models.StockUse) []models.StockUse {
+	combined := []models.StockUse{}
+	index := map[string]int{}
+	for _, use := range uses {
+		i, ok := index[use.Ingredient_id]
+		if !ok {
+			index[use.Ingredient_id] = len(combined)
+			combined = append(combined, use)
+			continue
+		}
+		combined[i].Quantity = models.RoundStock(combined[i].Quantity + use.Quantity)
+	}
+	return combined
+}
+
+// markAvailability sets Available on foods, a food is 86'd when the stock
+// of an ingredient is short of what one of it takes
+func markAvailability(ctx context.Context, ingredients repository.IngredientRepository, foods []models.Food) error {
+	allIngredients, err := ingredients.List(ctx)
+	if err != nil {
+		return err
+	}
+	stock := map[string]float64{}
+	for _, ingredient := range allIngredients {
+		if ingredient.Stock != nil {
+			stock[ingredient.Ingredient_id] = *ingredient.Stock
+		}
+	}
+	for i := range foods {
+		available := true
+		for _, line := range foods[i].Recipe {
+			if stock[line.Ingredient_id] < line.Quantity {
+				available = false
+				break
+			}
+		}
+		foods[i].Available = &available
+	}
+	return nil
+}
@@ -1,19 +1,20 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"net/http"
-	database "restaurant-management/database"
+	"restaurant-management/apierror"
+	"restaurant-management/config"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InvoiceViewFormat struct {
@@ -25,95 +26,336 @@ type InvoiceViewFormat struct {
 	Table_number     interface{}
 	Payment_due_date time.Time
 	Order_details    interface{}
+	Discounts        []models.AppliedDiscount
+	Discount_amount  models.Money
+	Net_amount       models.Money
+	Tax_amount       models.Money
+	Tax_breakdown    []models.TaxLine
+	Total_amount     models.Money
+	Amount_paid      models.Money
+	Balance_due      models.Money
+	Change_due       models.Money
+	Payments         []models.Payment
 }
 
-var invoiceCollection *mongo.Collection = database.OpenCollection(database.Client, "invoice")
+type InvoiceController struct {
+	invoices   repository.InvoiceRepository
+	orders     repository.OrderRepository
+	orderItems repository.OrderItemRepository
+	payments   repository.PaymentRepository
+	taxRates   repository.TaxRateRepository
+	taxRules   repository.TaxRuleRepository
+	promotions repository.PromotionRepository
+	zReports   repository.ZReportRepository
+	restaurant config.RestaurantConfig
 
-func GetInvoices() gin.HandlerFunc {
+	timeout time.Duration
+}
+
+func NewInvoiceController(invoices repository.InvoiceRepository, orders repository.OrderRepository, orderItems repository.OrderItemRepository, payments repository.PaymentRepository, taxRates repository.TaxRateRepository, taxRules repository.TaxRuleRepository, promotions repository.PromotionRepository, zReports repository.ZReportRepository, restaurant config.RestaurantConfig, timeout time.Duration) *InvoiceController {
+	return &InvoiceController{invoices: invoices, orders: orders, orderItems: orderItems, payments: payments, taxRates: taxRates, taxRules: taxRules, promotions: promotions, zReports: zReports, restaurant: restaurant, timeout: timeout}
+}
+
+var invoiceListSpec = listSpec{
+	fields: map[string]listField{
+		"order_id":         {path: "order_id", kind: textField, filter: true},
+		"payment_status":   {path: "payment_status", kind: textField, filter: true, sort: true},
+		"payment_method":   {path: "payment_method", kind: textField, filter: true, sort: true},
+		"total_amount":     {path: "total_amount.amount", kind: numberField, sort: true},
+		"payment_due_date": {path: "payment_due_date", kind: timeField, sort: true},
+		"created_at":       {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "-created_at",
+}
+
+func (ic *InvoiceController) GetInvoices() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		cursor, err := invoiceCollection.Find(context.TODO(), bson.M{})
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, invoiceListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error cooured while listing invoice item")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allInvoices []bson.M
-		if err := cursor.All(ctx, &allInvoices); err != nil {
-			log.Fatal(err)
+		page, err := ic.invoices.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing invoice items", err))
 			return
 		}
-		c.JSON(http.StatusOK, allInvoices)
+		c.JSON(http.StatusOK, page)
 	}
 }
 
-func GetInvoice() gin.HandlerFunc {
+func (ic *InvoiceController) GetInvoice() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
+		defer cancel()
 
-		var invoice models.Invoice
 		var invoiceID = c.Param("invoice_id")
 
-		err := orderCollection.FindOne(ctx, bson.M{"invoice_id": invoiceID}).Decode(&invoice)
+		invoice, orderDetails, payments, ok := ic.loadInvoice(ctx, c, invoiceID)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, newInvoiceView(invoice, orderDetails, payments))
+	}
+}
+
+// GetInvoicePDF renders the invoice as an A4 PDF
+func (ic *InvoiceController) GetInvoicePDF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
 		defer cancel()
-		if err != nil {
-			msg := fmt.Sprintf("error occoured while listing invoice item")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+
+		var invoiceID = c.Param("invoice_id")
+
+		invoice, orderDetails, payments, ok := ic.loadInvoice(ctx, c, invoiceID)
+		if !ok {
 			return
 		}
-		var invoiceView InvoiceViewFormat
 
-		allOrderItems, err := AlltheItemsInAnOrder(invoice.Order_id)
+		var rendered bytes.Buffer
+		if err := helpers.RenderInvoicePDF(&rendered, ic.newInvoiceDocument(invoice, orderDetails, payments)); err != nil {
+			c.Error(apierror.Internal("invoice could not be rendered", err))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"invoice-%s.pdf\"", invoice.Invoice_id))
+		c.Data(http.StatusOK, "application/pdf", rendered.Bytes())
+	}
+}
+
+// GetInvoiceReceipt renders the invoice for a thermal printer, width is the
+// paper width in mm (58 or 80, default 80) and format is text or escpos.
+func (ic *InvoiceController) GetInvoiceReceipt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
+		defer cancel()
+
+		var invoiceID = c.Param("invoice_id")
+
+		width, err := strconv.Atoi(c.DefaultQuery("width", "80"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(apierror.BadRequest("width must be 58 or 80"))
+			return
+		}
+		columns, err := helpers.ReceiptColumnsFor(width)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		format := c.DefaultQuery("format", "text")
+		if format != "text" && format != "escpos" {
+			c.Error(apierror.BadRequest("format must be text or escpos"))
 			return
 		}
 
-		invoiceView.Order_id = invoice.Order_id
-		invoiceView.Payment_due_date = invoice.Payment_due_date
+		invoice, orderDetails, payments, ok := ic.loadInvoice(ctx, c, invoiceID)
+		if !ok {
+			return
+		}
 
-		invoiceView.Payment_method = "null"
-		if invoice.Payment_method != nil {
-			invoiceView.Payment_method = *invoice.Payment_method
+		var rendered bytes.Buffer
+		if err := helpers.RenderReceipt(&rendered, ic.newInvoiceDocument(invoice, orderDetails, payments), columns, format == "escpos"); err != nil {
+			c.Error(apierror.Internal("receipt could not be rendered", err))
+			return
 		}
-		invoiceView.Invoice_id = invoice.Invoice_id
-		invoiceView.Payment_status = invoice.Payment_status
-		invoiceView.Payment_due = allOrderItems[0]["payment_due"]
-		invoiceView.Table_number = allOrderItems[0]["table_number"]
-		invoiceView.Order_details = allOrderItems[0]["order_items"]
+		if format == "escpos" {
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"receipt-%s.bin\"", invoice.Invoice_id))
+			c.Data(http.StatusOK, "application/octet-stream", rendered.Bytes())
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", rendered.Bytes())
+	}
+}
+
+// loadInvoice fetches the invoice with the joined order details, on failure
+// it has already answered the request
+func (ic *InvoiceController) loadInvoice(ctx context.Context, c *gin.Context, invoiceID string) (models.Invoice, models.OrderDetails, []models.Payment, bool) {
+	invoice, err := ic.invoices.FindByID(ctx, invoiceID)
+	if err == repository.ErrNotFound {
+		c.Error(apierror.NotFound("invoice was not found"))
+		return invoice, models.OrderDetails{}, nil, false
+	}
+	if err != nil {
+		msg := fmt.Sprintf("error occoured while listing invoice item")
+		c.Error(apierror.Internal(msg, err))
+		return invoice, models.OrderDetails{}, nil, false
+	}
+
+	allOrderItems, err := ic.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+	if err != nil {
+		c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+		return invoice, allOrderItems, nil, false
+	}
+	payments, err := ic.payments.ListByInvoice(ctx, invoice.Invoice_id)
+	if err != nil {
+		c.Error(apierror.Internal("error occured while listing payments", err))
+		return invoice, allOrderItems, nil, false
+	}
+	return invoice, allOrderItems, payments, true
+}
+
+func newInvoiceView(invoice models.Invoice, allOrderItems models.OrderDetails, payments []models.Payment) InvoiceViewFormat {
+	var invoiceView InvoiceViewFormat
+
+	invoiceView.Order_id = invoice.Order_id
+	invoiceView.Payment_due_date = invoice.Payment_due_date
 
-		c.JSON(http.StatusOK, invoiceView)
+	invoiceView.Payment_method = "null"
+	if invoice.Payment_method != nil {
+		invoiceView.Payment_method = *invoice.Payment_method
+	}
+	invoiceView.Invoice_id = invoice.Invoice_id
+	invoiceView.Payment_status = invoice.Payment_status
+	invoiceView.Payment_due = allOrderItems.Payment_due
+	invoiceView.Table_number = allOrderItems.Table_number
+	invoiceView.Order_details = allOrderItems.Order_items
+
+	invoiceView.Discounts, invoiceView.Discount_amount = invoiceDiscounts(invoice)
+	invoiceView.Total_amount = invoiceTotal(invoice, allOrderItems)
+	invoiceView.Net_amount, invoiceView.Tax_amount, invoiceView.Tax_breakdown = invoiceTax(invoice, invoiceView.Total_amount)
+	invoiceView.Amount_paid = invoice.Amount_paid
+	invoiceView.Balance_due = invoiceView.Total_amount.Sub(invoice.Amount_paid)
+	invoiceView.Change_due = invoice.Change_due
+	invoiceView.Payments = payments
+
+	return invoiceView
+}
+
+func (ic *InvoiceController) newInvoiceDocument(invoice models.Invoice, allOrderItems models.OrderDetails, payments []models.Payment) helpers.InvoiceDocument {
+	total := invoiceTotal(invoice, allOrderItems)
+	net, tax, breakdown := invoiceTax(invoice, total)
+	doc := helpers.InvoiceDocument{
+		Restaurant:     ic.restaurant,
+		Invoice_id:     invoice.Invoice_id,
+		Order_id:       invoice.Order_id,
+		Table_number:   allOrderItems.Table_number,
+		Items_total:    allOrderItems.Payment_due,
+		Subtotal:       net,
+		Tax:            tax,
+		Taxes:          breakdown,
+		Total:          total,
+		Amount_paid:    invoice.Amount_paid,
+		Balance_due:    total.Sub(invoice.Amount_paid),
+		Change_due:     invoice.Change_due,
+		Payment_method: "-",
+		Payment_status: models.PaymentPending,
+		Issued_at:      invoice.Created_at,
+		Due_date:       invoice.Payment_due_date,
+	}
+	if invoice.Payment_method != nil {
+		doc.Payment_method = *invoice.Payment_method
+	}
+	if invoice.Payment_status != nil {
+		doc.Payment_status = *invoice.Payment_status
+	}
+
+	for _, discount := range invoice.Discounts {
+		name := discount.Name
+		if discount.Coupon_code != "" {
+			name += " (" + discount.Coupon_code + ")"
+		}
+		doc.Discounts = append(doc.Discounts, helpers.InvoiceDiscount{Name: name, Amount: discount.Amount})
+	}
+	for _, item := range allOrderItems.Order_items {
+		var line helpers.InvoiceLine
+		if item.Food_name != nil {
+			line.Name = *item.Food_name
+		}
+		if item.Quantity != nil {
+			line.Quantity = *item.Quantity
+		}
+		if item.Variant != nil {
+			line.Variant = *item.Variant
+		}
+		for _, modifier := range item.Modifiers {
+			line.Modifiers = append(line.Modifiers, modifier.Label())
+		}
+		if item.Amount != nil {
+			line.Amount = *item.Amount
+		}
+		doc.Lines = append(doc.Lines, line)
+	}
+	for _, payment := range payments {
+		var line helpers.InvoicePayment
+		if payment.Method != nil {
+			line.Method = *payment.Method
+		}
+		if payment.Amount != nil {
+			line.Amount = *payment.Amount
+		}
+		doc.Payments = append(doc.Payments, line)
 	}
+	return doc
 }
 
-func CreateInvoice() gin.HandlerFunc {
+func (ic *InvoiceController) CreateInvoice() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
+		defer cancel()
 
 		var invoice models.Invoice
-		var order models.Order
 
-		if err := c.BindJSON(&invoice); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&invoice); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
+
+		//invoices always start unpaid, payments move them on
+		status := models.PaymentPending
+		invoice.Payment_status = &status
+
 		if validatorErr := validate.Struct(invoice); validatorErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": validatorErr.Error()})
+			c.Error(apierror.Wrap(http.StatusBadRequest, validatorErr))
+			return
+		}
+		order, err := ic.orders.FindByID(ctx, invoice.Order_id)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.InvalidField("order_id", "exists", "order was not found"))
 			return
 		}
-		err := invoiceCollection.FindOne(ctx, bson.M{"_order_id": invoice.Order_id}).Decode(&order)
-		defer cancel()
 		if err != nil {
 			msg := fmt.Sprintf("message: Order was not found")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		if order.CurrentStatus() != models.OrderServed {
+			c.Error(apierror.Conflict(fmt.Sprintf("order is %s, invoices can only be created for served orders", order.CurrentStatus())))
+			return
+		}
+		//an order is invoiced once, a second invoice would be paid and
+		//counted in the reports twice
+		existing, err := ic.invoices.FindByOrderID(ctx, invoice.Order_id)
+		if err == nil {
+			c.Error(errOrderInvoiced(existing))
+			return
+		}
+		if err != repository.ErrNotFound {
+			c.Error(apierror.Internal("error occured while looking for the invoice of the order", err))
 			return
 		}
 
-		status := "PANDING"
-		if invoice.Payment_status == nil {
-			invoice.Payment_status = &status
+		allOrderItems, err := ic.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+			return
+		}
+		discounts, err := automaticDiscounts(ctx, ic.promotions, allOrderItems)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while applying promotions", err))
+			return
 		}
+		taxes, err := priceOrder(ctx, ic.taxRates, ic.taxRules, ic.restaurant.Jurisdiction, allOrderItems, discounts)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while calculating tax", err))
+			return
+		}
+		invoice.Amount_paid = models.Money{Currency: taxes.Gross.Currency}
+		invoice.Change_due = models.Money{Currency: taxes.Gross.Currency}
+		setInvoicePrice(&invoice, taxes, discounts)
 
 		invoice.Payment_due_date, _ = time.Parse(time.RFC3339, time.Now().AddDate(0, 0, 1).Format(time.RFC3339))
 		invoice.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
@@ -121,65 +363,202 @@ func CreateInvoice() gin.HandlerFunc {
 		invoice.ID = primitive.NewObjectID()
 		invoice.Invoice_id = invoice.ID.Hex()
 
-		result, err := invoiceCollection.InsertOne(ctx, invoice)
-		defer cancel()
+		err = ic.invoices.Insert(ctx, invoice)
+		if err == repository.ErrDuplicate {
+			//another request invoiced it since the check above
+			c.Error(errOrderInvoiced(models.Invoice{}))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("Invoice was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
 
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, invoice)
+	}
+}
+
+// errOrderInvoiced answers a second invoice for the order of invoice
+func errOrderInvoiced(invoice models.Invoice) *apierror.Error {
+	if invoice.Invoice_id == "" {
+		return apierror.Conflict("order already has an invoice").WithCode(apierror.CodeAlreadyExists)
 	}
+	return apierror.Conflict(fmt.Sprintf("order already has invoice %s", invoice.Invoice_id)).WithCode(apierror.CodeAlreadyExists)
 }
-func UpdateInvoice() gin.HandlerFunc {
+
+// UpdateInvoice changes the payment method. Setting payment_status to PAID
+// records a payment of the whole balance with that method, the status
+// itself only ever follows the recorded payments.
+func (ic *InvoiceController) UpdateInvoice() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), ic.timeout)
+		defer cancel()
 		invoiceID := c.Param("invoice_id")
 
 		var invoice models.Invoice
-		if err := c.BindJSON(&invoice); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&invoice); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 
-		filter := bson.M{"invoice_id": invoiceID}
-		var updateObj primitive.D
+		var update repository.InvoiceUpdate
 
 		if invoice.Payment_method != nil {
-			updateObj = append(updateObj, bson.E{"payment_method", invoice.Payment_method})
+			update.Payment_method = invoice.Payment_method
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "please provide payment method"})
+			c.Error(apierror.BadRequest("please provide payment method"))
 			return
 		}
-		if invoice.Payment_status != nil {
-			updateObj = append(updateObj, bson.E{"payment_ststus", invoice.Payment_status})
+		if validationErr := validate.StructPartial(invoice, "Payment_method"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
 		}
-		status := "PANDING"
-		if invoice.Payment_status == nil {
-			invoice.Payment_status = &status
+		if invoice.Payment_status != nil && *invoice.Payment_status != models.PaymentPaid {
+			c.Error(apierror.BadRequest("payment_status follows the recorded payments, only PAID can be set"))
+			return
+		}
+
+		storedInvoice, err := ic.invoices.FindByID(ctx, invoiceID)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("invoice was not found"))
+			return
 		}
-		invoice.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		updateObj = append(updateObj, bson.E{"updated_at", invoice.Updated_at})
-		upsert := true
-		opt := options.UpdateOptions{
-			Upsert: &upsert,
-		}
-		result, err := invoiceCollection.UpdateOne(
-			ctx,
-			filter,
-			bson.D{
-				{"$set", updateObj},
-			},
-			&opt,
-		)
 		if err != nil {
-			msg := fmt.Sprintf("invoice item upodate failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			msg := fmt.Sprintf("error occoured while listing invoice item")
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		payInFull := invoice.Payment_status != nil && (storedInvoice.Payment_status == nil || *storedInvoice.Payment_status != models.PaymentPaid)
+		if payInFull {
+			allOrderItems, err := ic.orderItems.AlltheItemsInAnOrder(ctx, storedInvoice.Order_id)
+			if err != nil {
+				c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+				return
+			}
+			balance := invoiceTotal(storedInvoice, allOrderItems).Sub(storedInvoice.Amount_paid)
+			payment := models.Payment{Method: invoice.Payment_method, Amount: &balance}
+			if _, _, err := applyPayment(ctx, ic.payments, ic.invoices, ic.orders, storedInvoice, allOrderItems, payment, c.GetString("uid")); err != nil {
+				c.Error(apierror.Wrap(paymentErrorStatus(err), err))
+				return
+			}
+		} else {
+			//paying stays possible after the Z report, the method does not
+			if !checkInvoiceOpen(ctx, c, ic.zReports, storedInvoice) {
+				return
+			}
+			invoice.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+			update.Updated_at = invoice.Updated_at
+
+			found, err := ic.invoices.Update(ctx, invoiceID, update)
+			if err != nil {
+				msg := fmt.Sprintf("invoice item upodate failed")
+				c.Error(apierror.Internal(msg, err))
+				return
+			}
+			if !found {
+				c.Error(apierror.NotFound("invoice was not found"))
+				return
+			}
+		}
+
+		updatedInvoice, err := ic.invoices.FindByID(ctx, invoiceID)
+		if err != nil {
+			msg := fmt.Sprintf("error occoured while listing invoice item")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedInvoice)
 
 	}
 }
+
+// priceOrder takes discounts off the order items of an invoice and taxes
+// what is left with the tax rates and rules in force now. The result is
+// stored on the invoice so later changes to the rates do not alter it.
+func priceOrder(ctx context.Context, taxRates repository.TaxRateRepository, taxRules repository.TaxRuleRepository, jurisdiction string, allOrderItems models.OrderDetails, discounts []models.AppliedDiscount) (helpers.TaxResult, error) {
+	rates, err := taxRates.List(ctx)
+	if err != nil {
+		return helpers.TaxResult{}, err
+	}
+	rules, err := taxRules.List(ctx)
+	if err != nil {
+		return helpers.TaxResult{}, err
+	}
+
+	remaining := helpers.RemainingPrices(discountItems(allOrderItems), discounts)
+	var items []helpers.TaxItem
+	for i, orderItem := range allOrderItems.Order_items {
+		item := helpers.TaxItem{Amount: remaining[i].Amount}
+		if orderItem.Food_id != nil {
+			item.Food_id = *orderItem.Food_id
+		}
+		if orderItem.Category != nil {
+			item.Category = *orderItem.Category
+		}
+		items = append(items, item)
+	}
+	return helpers.CalculateTax(items, rates, rules, jurisdiction), nil
+}
+
+// setInvoicePrice stores the discounts and the taxes worked out from them
+// on an invoice that has no payments yet
+func setInvoicePrice(invoice *models.Invoice, taxes helpers.TaxResult, discounts []models.AppliedDiscount) {
+	discountAmount := models.Cents(0)
+	for _, discount := range discounts {
+		discountAmount = discountAmount.Add(discount.Amount)
+	}
+	total := taxes.Gross
+	balance := total.Sub(invoice.Amount_paid)
+	invoice.Discounts = discounts
+	invoice.Discount_amount = &discountAmount
+	invoice.Net_amount = &taxes.Net
+	invoice.Tax_amount = &taxes.Tax
+	invoice.Tax_breakdown = taxes.Breakdown
+	invoice.Total_amount = &total
+	invoice.Balance_due = &balance
+}
+
+func discountItems(allOrderItems models.OrderDetails) []helpers.DiscountItem {
+	var items []helpers.DiscountItem
+	for _, orderItem := range allOrderItems.Order_items {
+		item := helpers.DiscountItem{Order_item_id: orderItem.Order_item_id, Amount: models.Cents(0)}
+		if orderItem.Food_id != nil {
+			item.Food_id = *orderItem.Food_id
+		}
+		if orderItem.Amount != nil {
+			item.Amount = *orderItem.Amount
+		}
+		if orderItem.Quantity != nil {
+			item.Quantity = *orderItem.Quantity
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// invoiceTax is the net, tax and breakdown stored on invoice, invoices from
+// before taxes were calculated carry none and are all net
+func invoiceTax(invoice models.Invoice, total models.Money) (models.Money, models.Money, []models.TaxLine) {
+	if invoice.Net_amount == nil || invoice.Tax_amount == nil {
+		return total, models.Money{Currency: total.Currency}, []models.TaxLine{}
+	}
+	breakdown := invoice.Tax_breakdown
+	if breakdown == nil {
+		breakdown = []models.TaxLine{}
+	}
+	return *invoice.Net_amount, *invoice.Tax_amount, breakdown
+}
+
+// invoiceDiscounts is what was taken off invoice, invoices from before
+// promotions existed had nothing taken off
+func invoiceDiscounts(invoice models.Invoice) ([]models.AppliedDiscount, models.Money) {
+	discounts := invoice.Discounts
+	if discounts == nil {
+		discounts = []models.AppliedDiscount{}
+	}
+	if invoice.Discount_amount == nil {
+		return discounts, models.Cents(0)
+	}
+	return discounts, *invoice.Discount_amount
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// next step for each kitchen status, cooks can only bump items forward
+var kitchenTransitions = map[string]string{
+	models.KitchenPending:   models.KitchenPreparing,
+	models.KitchenPreparing: models.KitchenDone,
+}
+
+type KitchenController struct {
+	orderItems repository.OrderItemRepository
+	foods      repository.FoodRepository
+	orders     repository.OrderRepository
+	tables     repository.TableRepository
+	notes      repository.NoteRepository
+	broker     *helpers.KitchenBroker
+
+	timeout        time.Duration
+	streamLifetime time.Duration
+}
+
+// NewKitchenController ends every stream after streamLifetime so it finishes
+// before the server write timeout cuts it off, displays reconnect and resync.
+func NewKitchenController(orderItems repository.OrderItemRepository, foods repository.FoodRepository, orders repository.OrderRepository, tables repository.TableRepository, notes repository.NoteRepository, broker *helpers.KitchenBroker, timeout time.Duration, streamLifetime time.Duration) *KitchenController {
+	return &KitchenController{orderItems: orderItems, foods: foods, orders: orders, tables: tables, notes: notes, broker: broker, timeout: timeout, streamLifetime: streamLifetime}
+}
+
+func (kc *KitchenController) GetTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), kc.timeout)
+		defer cancel()
+
+		tickets, err := kc.openTickets(ctx, strings.ToLower(c.Query("station")))
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing kitchen tickets", err))
+			return
+		}
+		c.JSON(http.StatusOK, tickets)
+	}
+}
+
+// StreamTickets is a Server-Sent Events feed. Each connection starts with a
+// "snapshot" event of all open tickets, then gets a "ticket" event whenever
+// an item is created or bumped, or cancelled with its order.
+func (kc *KitchenController) StreamTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		station := strings.ToLower(c.Query("station"))
+
+		//subscribe before the snapshot so nothing created in between is lost
+		events, unsubscribe := kc.broker.Subscribe(station)
+		defer unsubscribe()
+
+		ctx, cancel := context.WithTimeout(context.Background(), kc.timeout)
+		tickets, err := kc.openTickets(ctx, station)
+		cancel()
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing kitchen tickets", err))
+			return
+		}
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no")
+		c.SSEvent("snapshot", tickets)
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(15 * time.Second)
+		defer heartbeat.Stop()
+		closeStream := time.After(kc.streamLifetime)
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-closeStream:
+				return
+			case <-heartbeat.C:
+				c.SSEvent("heartbeat", time.Now().Unix())
+				c.Writer.Flush()
+			case orderItem, ok := <-events:
+				if !ok {
+					return
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), kc.timeout)
+				ticket, err := kc.ticket(ctx, orderItem)
+				cancel()
+				if err != nil {
+					return
+				}
+				c.SSEvent("ticket", ticket)
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// BumpItem moves an order item to status in the kitchen and tells every display
+func (kc *KitchenController) BumpItem(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), kc.timeout)
+		defer cancel()
+
+		orderItemId := c.Param("orderItem_id")
+
+		orderItem, err := kc.orderItems.FindByID(ctx, orderItemId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("order item was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the Order Items", err))
+			return
+		}
+
+		from := models.KitchenPending
+		if orderItem.Kitchen_status != nil {
+			from = *orderItem.Kitchen_status
+		}
+		if kitchenTransitions[from] != status {
+			c.Error(apierror.Conflict(fmt.Sprintf("order item cannot go from %s to %s", from, status)))
+			return
+		}
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		updated, err := kc.orderItems.UpdateKitchenStatus(ctx, orderItemId, orderItem.Kitchen_status, status, updatedAt)
+		if err != nil {
+			c.Error(apierror.Internal("Order item update failed", err))
+			return
+		}
+		if !updated {
+			c.Error(apierror.Conflict("order item was bumped by someone else"))
+			return
+		}
+
+		orderItem.Kitchen_status = &status
+		orderItem.Updated_at = updatedAt
+		kc.broker.Publish(orderItem)
+
+		ticket, err := kc.ticket(ctx, orderItem)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while building the kitchen ticket", err))
+			return
+		}
+		c.JSON(http.StatusOK, ticket)
+	}
+}
+
+func (kc *KitchenController) openTickets(ctx context.Context, station string) ([]models.KitchenTicket, error) {
+	openOrderItems, err := kc.orderItems.ListOpenKitchenItems(ctx, station)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := []models.KitchenTicket{}
+	for _, orderItem := range openOrderItems {
+		ticket, err := kc.ticket(ctx, orderItem)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// ticket joins an order item with its food name, table number and the
+// notes of the item and of its order
+func (kc *KitchenController) ticket(ctx context.Context, orderItem models.OrderItem) (models.KitchenTicket, error) {
+	ticket := models.KitchenTicket{
+		Order_item_id:    orderItem.Order_item_id,
+		Order_id:         orderItem.Order_id,
+		Food_id:          orderItem.Food_id,
+		Quantity:         orderItem.Quantity,
+		Variant:          orderItem.Variant,
+		Modifiers:        []string{},
+		Notes:            []string{},
+		Allergy_warnings: []string{},
+		Station:          orderItem.Station,
+		Kitchen_status:   orderItem.Kitchen_status,
+		Created_at:       orderItem.Created_at,
+		Updated_at:       orderItem.Updated_at,
+	}
+	for _, modifier := range orderItem.Modifiers {
+		ticket.Modifiers = append(ticket.Modifiers, modifier.Label())
+	}
+
+	var orderedFood models.Food
+	if orderItem.Food_id != nil {
+		food, err := kc.foods.FindByID(ctx, *orderItem.Food_id)
+		if err != nil && err != repository.ErrNotFound {
+			return ticket, err
+		}
+		ticket.Food_name = food.Namme
+		orderedFood = food
+	}
+
+	order, err := kc.orders.FindByID(ctx, orderItem.Order_id)
+	if err != nil && err != repository.ErrNotFound {
+		return ticket, err
+	}
+	if order.Table_id != nil {
+		table, err := kc.tables.FindByID(ctx, *order.Table_id)
+		if err != nil && err != repository.ErrNotFound {
+			return ticket, err
+		}
+		ticket.Table_number = table.Table_number
+	}
+
+	itemNotes, err := kc.notes.ListBySubject(ctx, models.NoteOrderItem, orderItem.Order_item_id)
+	if err != nil {
+		return ticket, err
+	}
+	orderNotes, err := kc.notes.ListBySubject(ctx, models.NoteOrder, orderItem.Order_id)
+	if err != nil {
+		return ticket, err
+	}
+	notes := append(itemNotes, orderNotes...)
+	for _, note := range notes {
+		ticket.Notes = append(ticket.Notes, note.Label())
+	}
+	ticket.Allergy_warnings = orderedFood.AllergyWarnings(notes)
+
+	return ticket, nil
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"fmt"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	maxSortFields    = 3
+)
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	numberField
+	boolField
+	timeField
+)
+
+// listField is a query parameter a list endpoint filters or sorts by and
+// the stored field it stands for. Text filters take several values
+// separated by commas.
+type listField struct {
+	path   string
+	kind   fieldKind
+	filter bool
+	sort   bool
+}
+
+// listSpec is what a list endpoint accepts besides limit and cursor. The
+// from and to parameters bound dateField, from included and to not, and
+// sort is a comma separated list of fields with a "-" before descending
+// ones.
+type listSpec struct {
+	fields      map[string]listField
+	dateField   string
+	defaultSort string
+}
+
+// listQuery reads the page a list endpoint is asked for, its errors are
+// meant for the client
+func listQuery(c *gin.Context, spec listSpec) (repository.ListQuery, error) {
+	query := repository.ListQuery{Limit: defaultListLimit, Cursor: c.Query("cursor")}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+		}
+		query.Limit = limit
+	}
+
+	for name, field := range spec.fields {
+		value := c.Query(name)
+		if !field.filter || value == "" {
+			continue
+		}
+		if field.kind == textField {
+			values := []interface{}{}
+			for _, v := range splitValues(value) {
+				values = append(values, v)
+			}
+			query.Filters = append(query.Filters, repository.Filter{Field: field.path, Op: repository.FilterIn, Value: values})
+			continue
+		}
+		parsed, err := parseListValue(field.kind, value)
+		if err != nil {
+			return query, fmt.Errorf("%s %s", name, err.Error())
+		}
+		query.Filters = append(query.Filters, repository.Filter{Field: field.path, Op: repository.FilterEq, Value: parsed})
+	}
+
+	for name, op := range map[string]string{"from": repository.FilterGte, "to": repository.FilterLt} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		if spec.dateField == "" {
+			return query, fmt.Errorf("this list cannot be filtered by %s", name)
+		}
+		parsed, err := parseListValue(timeField, value)
+		if err != nil {
+			return query, fmt.Errorf("%s %s", name, err.Error())
+		}
+		query.Filters = append(query.Filters, repository.Filter{Field: spec.dateField, Op: op, Value: parsed})
+	}
+
+	sortBy := c.DefaultQuery("sort", spec.defaultSort)
+	seen := map[string]bool{}
+	for _, name := range splitValues(sortBy) {
+		descending := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		field, ok := spec.fields[name]
+		if !ok || !field.sort {
+			return query, fmt.Errorf("cannot sort by %s, try one of %s", name, strings.Join(spec.sortable(), ", "))
+		}
+		if seen[name] {
+			return query, fmt.Errorf("%s is sorted by twice", name)
+		}
+		seen[name] = true
+		query.Sort = append(query.Sort, repository.Sort{Field: field.path, Descending: descending})
+	}
+	if len(query.Sort) > maxSortFields {
+		return query, fmt.Errorf("sort takes at most %d fields", maxSortFields)
+	}
+
+	if _, err := query.After(); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func parseListValue(kind fieldKind, value string) (interface{}, error) {
+	switch kind {
+	case numberField:
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number, nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return number, nil
+	case boolField:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return parsed, nil
+	case timeField:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("must be an RFC 3339 time")
+		}
+		return parsed, nil
+	}
+	return value, nil
+}
+
+// splitValues is splitList keeping the case, stored values are matched
+// exactly
+func splitValues(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (spec listSpec) sortable() []string {
+	names := []string{}
+	for name, field := range spec.fields {
+		if field.sort {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// listSpecs are the list endpoints by the path they are served at
+var listSpecs = map[string]listSpec{
+	"/drawers":                      drawerListSpec,
+	"/zreports":                     zReportListSpec,
+	"/foods":                        foodListSpec,
+	"/ingredients":                  ingredientListSpec,
+	"/invoice":                      invoiceListSpec,
+	"/invoice/:invoice_id/payments": paymentListSpec,
+	"/menu":                         menuListSpec,
+	"/notes":                        noteListSpec,
+	"/orders":                       orderListSpec,
+	"/orderItems":                   orderItemListSpec,
+	"/promotions":                   promotionListSpec,
+	"/reservations":                 reservationListSpec,
+	"/table":                        tableListSpec,
+	"/taxRates":                     taxRateListSpec,
+	"/taxRules":                     taxRuleListSpec,
+	"/users":                        userListSpec,
+	"/waitlist":                     waitlistListSpec,
+}
+
+// ListParameters documents the query parameters of the list endpoint
+// served at path
+func ListParameters(path string) []openapi.Parameter {
+	spec, ok := listSpecs[path]
+	if !ok {
+		panic("no list is served at " + path)
+	}
+
+	minLimit, maxLimit := float64(1), float64(maxListLimit)
+	params := []openapi.Parameter{
+		{Name: "limit", Description: fmt.Sprintf("items per page, %d by default", defaultListLimit), Schema: &openapi.Schema{Type: "integer", Minimum: &minLimit, Maximum: &maxLimit}},
+		{Name: "cursor", Description: "next_cursor of the previous page", Schema: &openapi.Schema{Type: "string"}},
+		{
+			Name:        "sort",
+			Description: fmt.Sprintf("up to %d of %s separated by commas, with a - before descending ones, %s by default", maxSortFields, strings.Join(spec.sortable(), ", "), spec.defaultSort),
+			Schema:      &openapi.Schema{Type: "string"},
+		},
+	}
+
+	names := []string{}
+	for name, field := range spec.fields {
+		if field.filter {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param := openapi.Parameter{Name: name, Schema: listValueSchema(spec.fields[name].kind)}
+		if spec.fields[name].kind == textField {
+			param.Description = "one value or several separated by commas"
+		}
+		params = append(params, param)
+	}
+
+	if spec.dateField != "" {
+		params = append(params,
+			openapi.Parameter{Name: "from", Description: spec.dateField + " from this time on", Schema: listValueSchema(timeField)},
+			openapi.Parameter{Name: "to", Description: spec.dateField + " before this time", Schema: listValueSchema(timeField)},
+		)
+	}
+	return params
+}
+
+func listValueSchema(kind fieldKind) *openapi.Schema {
+	switch kind {
+	case numberField:
+		return &openapi.Schema{Type: "number"}
+	case boolField:
+		return &openapi.Schema{Type: "boolean"}
+	case timeField:
+		return &openapi.Schema{Type: "string", Format: "date-time"}
+	}
+	return &openapi.Schema{Type: "string"}
+}
@@ -3,65 +3,121 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
-	"restaurant-management/database"
+	"restaurant-management/apierror"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var menuCollection *mongo.Collection = database.OpenCollection(database.Client, "menu")
+type MenuController struct {
+	menus repository.MenuRepository
 
-func GetMenus() gin.HandlerFunc {
+	location *time.Location
+	timeout  time.Duration
+}
+
+func NewMenuController(menus repository.MenuRepository, location *time.Location, timeout time.Duration) *MenuController {
+	return &MenuController{menus: menus, location: location, timeout: timeout}
+}
+
+var menuListSpec = listSpec{
+	fields: map[string]listField{
+		"name":       {path: "name", kind: textField, filter: true, sort: true},
+		"category":   {path: "category", kind: textField, filter: true, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "category,name",
+}
+
+func (mc *MenuController) GetMenus() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		result, err := menuCollection.Find(context.TODO(), bson.M{})
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, menuListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while fetching the menu items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allMenu []bson.M
-		if err = result.All(ctx, allMenu); err != nil { //&allMenu
-			log.Fatal(err)
+		page, err := mc.menus.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the menu items", err))
 			return
 		}
-		c.JSON(http.StatusOK, allMenu)
+		c.JSON(http.StatusOK, page)
 	}
 }
-func GetMenu() gin.HandlerFunc {
+func (mc *MenuController) GetMenu() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		menuId := c.Param("menu_id")
-		var menu models.Menu
-		err := menuCollection.FindOne(ctx, bson.M{"menu_id": menuId}).Decode(&menu)
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
 		defer cancel()
+		menuId := c.Param("menu_id")
+		menu, err := mc.menus.FindByID(ctx, menuId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("menu was not found"))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("error occured while fetching the menu items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
 		c.JSON(http.StatusOK, menu)
 
 	}
 }
-func CreateMenu() gin.HandlerFunc {
+
+// GetActiveMenus lists the menus that can be ordered from now, or at the
+// RFC 3339 time given in ?at=
+func (mc *MenuController) GetActiveMenus() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
+		defer cancel()
+
+		at := time.Now()
+		if value := c.Query("at"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.Error(apierror.BadRequest("at must be an RFC 3339 time"))
+				return
+			}
+			at = parsed
+		}
+
+		allMenu, err := mc.menus.List(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error occured while fetching the menu items")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		activeMenus := []models.Menu{}
+		for _, menu := range allMenu {
+			if menu.IsActive(at, mc.location) {
+				activeMenus = append(activeMenus, menu)
+			}
+		}
+		c.JSON(http.StatusOK, activeMenus)
+	}
+}
+func (mc *MenuController) CreateMenu() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
+		defer cancel()
 		var menu models.Menu
-		if err := c.BindJSON(&menu); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&menu); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		if vadidationErr := validate.Struct(&menu); vadidationErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": vadidationErr.Error()})
+			c.Error(apierror.Wrap(http.StatusBadRequest, vadidationErr))
+			return
+		}
+		if _, err := menu.Location(mc.location); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		menu.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
@@ -69,72 +125,89 @@ func CreateMenu() gin.HandlerFunc {
 		menu.ID = primitive.NewObjectID()
 		menu.Menu_id = menu.ID.Hex()
 
-		result, insertErr := menuCollection.InsertOne(ctx, menu)
-		if insertErr != nil {
+		if insertErr := mc.menus.Insert(ctx, menu); insertErr != nil {
 			msg := fmt.Sprintf("Menu item was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, insertErr))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, menu)
 	}
 }
 func inTimeSpan(start *time.Time, end *time.Time, check time.Time) bool {
 	return start.After(time.Now()) && end.After(*start)
 }
-func UpdateMenu() gin.HandlerFunc {
+func (mc *MenuController) UpdateMenu() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
+		defer cancel()
 		var menu models.Menu
-		if err := c.BindJSON(&menu); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&menu); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		menuID := c.Param("menu_id")
-		filter := bson.M{"menu_id": menuID}
 
-		var updateObj primitive.D
+		var update repository.MenuUpdate
 
 		if menu.Start_Date != nil && menu.End_Date != nil {
 			if !inTimeSpan(menu.Start_Date, menu.End_Date, time.Now()) {
 				msg := fmt.Sprintf("kindly retype the time")
-				c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+				c.Error(apierror.BadRequest(msg))
 				return
 			}
-			updateObj = append(updateObj, bson.E{"start_date", menu.Start_Date})
-			updateObj = append(updateObj, bson.E{"end_date", menu.End_Date})
+			update.Start_date = menu.Start_Date
+			update.End_date = menu.End_Date
+		}
 
-			if menu.Name != "" {
-				updateObj = append(updateObj, bson.E{"name", menu.Name})
-			} else {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "please provide menu name"})
+		if menu.Schedules != nil {
+			if err := validate.Var(menu.Schedules, "dive"); err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
 				return
 			}
-			if menu.Category != "" {
-				updateObj = append(updateObj, bson.E{"category", menu.Category})
-			} else {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide menu category"})
+			update.Schedules = menu.Schedules
+		}
+
+		if menu.Timezone != nil {
+			if _, err := menu.Location(mc.location); err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
 			}
+			update.Timezone = menu.Timezone
+		}
 
-			menu.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-			updateObj = append(updateObj, bson.E{"update_at", menu.Updated_at})
+		if menu.Name != "" {
+			update.Name = &menu.Name
+		} else {
+			c.Error(apierror.BadRequest("please provide menu name"))
+			return
+		}
+		if menu.Category != "" {
+			update.Category = &menu.Category
+		} else {
+			c.Error(apierror.BadRequest("Please provide menu category"))
+			return
+		}
 
-			upsert := true
+		menu.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = menu.Updated_at
 
-			opt := options.UpdateOptions{
-				Upsert: &upsert,
-			}
-			result, err := menuCollection.UpdateOne(ctx, filter, bson.D{
-				{"set", updateObj},
-			},
-				&opt,
-			)
-			if err != nil {
-				msg := fmt.Sprintf("Menu update failed")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
-			}
-			defer cancel()
-			c.JSON(http.StatusOK, result)
+		found, err := mc.menus.Update(ctx, menuID, update)
+		if err != nil {
+			msg := fmt.Sprintf("Menu update failed")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("menu was not found"))
+			return
+		}
+
+		updatedMenu, err := mc.menus.FindByID(ctx, menuID)
+		if err != nil {
+			msg := fmt.Sprintf("error occured while fetching the menu items")
+			c.Error(apierror.Internal(msg, err))
+			return
 		}
+		c.JSON(http.StatusOK, updatedMenu)
 	}
 }
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNoteSubjectQuery = errors.New("subject_type and subject_id go together")
+var errNoteNotAuthor = errors.New("only the author or a manager can change this note")
+
+type NoteController struct {
+	notes        repository.NoteRepository
+	foods        repository.FoodRepository
+	orders       repository.OrderRepository
+	orderItems   repository.OrderItemRepository
+	tables       repository.TableRepository
+	reservations repository.ReservationRepository
+	broker       *helpers.KitchenBroker
+
+	timeout time.Duration
+}
+
+func NewNoteController(notes repository.NoteRepository, foods repository.FoodRepository, orders repository.OrderRepository, orderItems repository.OrderItemRepository, tables repository.TableRepository, reservations repository.ReservationRepository, broker *helpers.KitchenBroker, timeout time.Duration) *NoteController {
+	return &NoteController{notes: notes, foods: foods, orders: orders, orderItems: orderItems, tables: tables, reservations: reservations, broker: broker, timeout: timeout}
+}
+
+var noteListSpec = listSpec{
+	fields: map[string]listField{
+		"subject_type": {path: "subject_type", kind: textField, filter: true},
+		"subject_id":   {path: "subject_id", kind: textField, filter: true},
+		"author_id":    {path: "author_id", kind: textField, filter: true},
+		"created_at":   {path: "created_at", kind: timeField, sort: true},
+		"updated_at":   {path: "updated_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "created_at",
+}
+
+// GetNotes lists every note, or with ?subject_type=&subject_id= the notes of
+// one order, order item, table or reservation
+func (nc *NoteController) GetNotes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), nc.timeout)
+		defer cancel()
+
+		if (c.Query("subject_type") == "") != (c.Query("subject_id") == "") {
+			c.Error(apierror.Wrap(http.StatusBadRequest, errNoteSubjectQuery))
+			return
+		}
+		query, err := listQuery(c, noteListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := nc.notes.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing notes", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (nc *NoteController) GetNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), nc.timeout)
+		defer cancel()
+
+		note, err := nc.notes.FindByID(ctx, c.Param("note_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("note was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the note", err))
+			return
+		}
+		c.JSON(http.StatusOK, note)
+	}
+}
+
+// CreateNote attaches a note to its subject, the author is the caller
+func (nc *NoteController) CreateNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), nc.timeout)
+		defer cancel()
+		var note models.Note
+
+		if err := c.ShouldBindJSON(&note); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(note); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		exists, err := nc.subjectExists(ctx, *note.Subject_type, *note.Subject_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while checking the subject of the note", err))
+			return
+		}
+		if !exists {
+			c.Error(apierror.NotFound("the " + *note.Subject_type + " of the note was not found"))
+			return
+		}
+
+		note.ID = primitive.NewObjectID()
+		note.Note_id = note.ID.Hex()
+		note.Author_id = c.GetString("uid")
+		note.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		note.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+		if err := nc.notes.Insert(ctx, note); err != nil {
+			c.Error(apierror.Internal("Note item was not created", err))
+			return
+		}
+		nc.publishTickets(ctx, note)
+		if note.Allergy_warnings, err = nc.noteAllergyWarnings(ctx, note); err != nil {
+			c.Error(apierror.Internal("error occured while checking the order for allergens", err))
+			return
+		}
+		c.JSON(http.StatusOK, note)
+	}
+}
+
+// UpdateNote changes the title and text, a note cannot move to another subject
+func (nc *NoteController) UpdateNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), nc.timeout)
+		defer cancel()
+		var note models.Note
+
+		if err := c.ShouldBindJSON(&note); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if note.Subject_type != nil || note.Subject_id != nil {
+			c.Error(apierror.BadRequest("the subject of a note cannot be changed"))
+			return
+		}
+		if validationErr := validate.StructPartial(note, "Title"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		var update repository.NoteUpdate
+		if note.Text != "" {
+			if validationErr := validate.StructPartial(note, "Text"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Text = &note.Text
+		}
+		if note.Title != "" {
+			update.Title = &note.Title
+		}
+		if update.Text == nil && update.Title == nil {
+			c.Error(apierror.BadRequest("please provide text or title"))
+			return
+		}
+
+		stored, ok := nc.loadOwnNote(ctx, c)
+		if !ok {
+			return
+		}
+
+		note.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = note.Updated_at
+
+		found, err := nc.notes.Update(ctx, stored.Note_id, update)
+		if err != nil {
+			c.Error(apierror.Internal("note update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("note was not found"))
+			return
+		}
+
+		updated, err := nc.notes.FindByID(ctx, stored.Note_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the note", err))
+			return
+		}
+		nc.publishTickets(ctx, updated)
+		if updated.Allergy_warnings, err = nc.noteAllergyWarnings(ctx, updated); err != nil {
+			c.Error(apierror.Internal("error occured while checking the order for allergens", err))
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+type DeletedNote struct {
+	Note_id string `json:"note_id"`
+	Deleted bool   `json:"deleted"`
+}
+
+func (nc *NoteController) DeleteNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), nc.timeout)
+		defer cancel()
+
+		stored, ok := nc.loadOwnNote(ctx, c)
+		if !ok {
+			return
+		}
+		deleted, err := nc.notes.Delete(ctx, stored.Note_id)
+		if err != nil {
+			c.Error(apierror.Internal("note was not deleted", err))
+			return
+		}
+		if !deleted {
+			c.Error(apierror.NotFound("note was not found"))
+			return
+		}
+		nc.publishTickets(ctx, stored)
+		c.JSON(http.StatusOK, DeletedNote{Note_id: stored.Note_id, Deleted: true})
+	}
+}
+
+// loadOwnNote writes the error response itself and returns false when the
+// note is missing or the caller is neither its author nor a manager
+func (nc *NoteController) loadOwnNote(ctx context.Context, c *gin.Context) (models.Note, bool) {
+	note, err := nc.notes.FindByID(ctx, c.Param("note_id"))
+	if err == repository.ErrNotFound {
+		c.Error(apierror.NotFound("note was not found"))
+		return note, false
+	}
+	if err != nil {
+		c.Error(apierror.Internal("error occured while fetching the note", err))
+		return note, false
+	}
+	role := c.GetString("role")
+	if note.Author_id != c.GetString("uid") && role != models.RoleManager && role != models.RoleAdmin {
+		c.Error(apierror.Wrap(http.StatusForbidden, errNoteNotAuthor))
+		return note, false
+	}
+	return note, true
+}
+
+func (nc *NoteController) subjectExists(ctx context.Context, subjectType string, subjectId string) (bool, error) {
+	var err error
+	switch subjectType {
+	case models.NoteOrder:
+		_, err = nc.orders.FindByID(ctx, subjectId)
+	case models.NoteOrderItem:
+		_, err = nc.orderItems.FindByID(ctx, subjectId)
+	case models.NoteTable:
+		_, err = nc.tables.FindByID(ctx, subjectId)
+	case models.NoteReservation:
+		_, err = nc.reservations.FindByID(ctx, subjectId)
+	}
+	if err == repository.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// publishTickets sends the tickets the note is printed on to the kitchen
+// displays again, a failed lookup only means they refresh on their next sync
+func (nc *NoteController) publishTickets(ctx context.Context, note models.Note) {
+	switch *note.Subject_type {
+	case models.NoteOrderItem:
+		if orderItem, err := nc.orderItems.FindByID(ctx, *note.Subject_id); err == nil {
+			nc.broker.Publish(orderItem)
+		}
+	case models.NoteOrder:
+		if orderItems, err := nc.orderItems.ListByOrder(ctx, *note.Subject_id); err == nil {
+			nc.broker.Publish(orderItems...)
+		}
+	}
+}
+
+// allergyWarnings checks food against the allergy notes of the order item
+// and of its order
+func allergyWarnings(ctx context.Context, notes repository.NoteRepository, food models.Food, orderItemId string, orderId string) ([]string, error) {
+	itemNotes, err := notes.ListBySubject(ctx, models.NoteOrderItem, orderItemId)
+	if err != nil {
+		return nil, err
+	}
+	orderNotes, err := notes.ListBySubject(ctx, models.NoteOrder, orderId)
+	if err != nil {
+		return nil, err
+	}
+	return food.AllergyWarnings(append(itemNotes, orderNotes...)), nil
+}
+
+// noteAllergyWarnings lists the items of the note's order, or its order
+// item, that the note warns about
+func (nc *NoteController) noteAllergyWarnings(ctx context.Context, note models.Note) ([]string, error) {
+	var orderItems []models.OrderItem
+	switch *note.Subject_type {
+	case models.NoteOrderItem:
+		orderItem, err := nc.orderItems.FindByID(ctx, *note.Subject_id)
+		if err != nil {
+			return nil, err
+		}
+		orderItems = append(orderItems, orderItem)
+	case models.NoteOrder:
+		var err error
+		if orderItems, err = nc.orderItems.ListByOrder(ctx, *note.Subject_id); err != nil {
+			return nil, err
+		}
+	}
+
+	warnings := []string{}
+	for _, orderItem := range orderItems {
+		if orderItem.Food_id == nil {
+			continue
+		}
+		food, err := nc.foods.FindByID(ctx, *orderItem.Food_id)
+		if err == repository.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, food.AllergyWarnings([]models.Note{note})...)
+	}
+	return warnings, nil
+}
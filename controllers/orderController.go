@@ -2,53 +2,79 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	database "restaurant-management/database"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
-var orderCollection *mongo.Collection = database.OpenCollection(database.Client, "order")
+type OrderController struct {
+	orders      repository.OrderRepository
+	tables      repository.TableRepository
+	orderItems  repository.OrderItemRepository
+	ingredients repository.IngredientRepository
+	invoices    repository.InvoiceRepository
+	zReports    repository.ZReportRepository
+	broker      *helpers.KitchenBroker
 
-func GetOrders() gin.HandlerFunc {
+	timeout time.Duration
+}
+
+func NewOrderController(orders repository.OrderRepository, tables repository.TableRepository, orderItems repository.OrderItemRepository, ingredients repository.IngredientRepository, invoices repository.InvoiceRepository, zReports repository.ZReportRepository, broker *helpers.KitchenBroker, timeout time.Duration) *OrderController {
+	return &OrderController{orders: orders, tables: tables, orderItems: orderItems, ingredients: ingredients, invoices: invoices, zReports: zReports, broker: broker, timeout: timeout}
+}
+
+var orderListSpec = listSpec{
+	fields: map[string]listField{
+		"table_id":   {path: "table_id", kind: textField, filter: true, sort: true},
+		"status":     {path: "status", kind: textField, filter: true, sort: true},
+		"order_date": {path: "order_date", kind: timeField, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "-created_at",
+}
+
+func (oc *OrderController) GetOrders() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
-		cursor, err := orderCollection.Find(context.TODO(), bson.M{})
+		ctx, cancel := context.WithTimeout(context.Background(), oc.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, orderListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing order items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allOrder []bson.M
-		if err := cursor.All(ctx, &allOrder); err != nil {
-			log.Fatal(err)
+		page, err := oc.orders.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing order items", err))
+			return
 		}
-
-		c.JSON(http.StatusOK, allOrder)
+		c.JSON(http.StatusOK, page)
 	}
 }
-func GetOrder() gin.HandlerFunc {
+func (oc *OrderController) GetOrder() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		var ctx, cancel = context.WithTimeout(context.Background(), oc.timeout)
+		defer cancel()
 
 		orderId := c.Param("order_id")
-		var order models.Order
 
-		err := orderCollection.FindOne(ctx, bson.M{"order_id": orderId}).Decode(&order)
-		defer cancel()
+		order, err := oc.orders.FindByID(ctx, orderId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("order was not found"))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("error cooured while fetching orders")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
 
@@ -56,107 +82,320 @@ func GetOrder() gin.HandlerFunc {
 
 	}
 }
-func CreateOrder() gin.HandlerFunc {
+func (oc *OrderController) CreateOrder() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		var ctx, cancel = context.WithTimeout(context.Background(), oc.timeout)
+		defer cancel()
 		var order models.Order
-		var table models.Table
 
-		if err := c.BindJSON(&order); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&order); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		if validationErr := validate.Struct(order); validationErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
 			return
 		}
-		if order.Table_id != nil {
-			err := tableCollection.FindOne(ctx, bson.M{"table_id": order.Table_id}).Decode(&table)
-			defer cancel()
-			if err != nil {
-				msg := fmt.Sprintf("message:Table was not found")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
-				return
-			}
-		}
-		order.ID = primitive.NewObjectID()
-		order.Order_id = order.ID.Hex()
-		order.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		order.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
-		result, insertErr := orderCollection.InsertOne(ctx, order)
-		if insertErr != nil {
+		createdOrder, err := placeOrder(ctx, oc.orders, oc.tables, order, c.GetString("uid"))
+		if err == errTableNotFound {
+			c.Error(apierror.InvalidField("table_id", "exists", "table was not found"))
+			return
+		}
+		if err != nil {
 			msg := fmt.Sprintf("Order item was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, createdOrder)
 	}
 }
-func OrderUpdate() gin.HandlerFunc {
+func (oc *OrderController) OrderUpdate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		var ctx, cancel = context.WithTimeout(context.Background(), oc.timeout)
+		defer cancel()
 
-		var table models.Table
 		var order models.Order
-		var updateObj primitive.D
+		var update repository.OrderUpdate
 
 		orderID := c.Param("order_id")
-		if err := c.BindJSON(&order); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&order); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		if order.Table_id != nil {
-			err := tableCollection.FindOne(ctx, bson.M{"table_id": order.Table_id}).Decode(&table) // menuCollection.FindOne(ctx, bson.M{"table_id":order.Table_id}).Decode(&table)
-			defer cancel()
+			if _, err := oc.tables.FindByID(ctx, *order.Table_id); err == repository.ErrNotFound {
+				c.Error(apierror.InvalidField("table_id", "exists", "table was not found"))
+				return
+			} else if err != nil {
+				c.Error(apierror.Internal("error occured while fetching the table", err))
+				return
+			}
+			//a closed order stays at the table it was served at
+			storedOrder, err := oc.orders.FindByID(ctx, orderID)
+			if err == repository.ErrNotFound {
+				c.Error(apierror.NotFound("order was not found"))
+				return
+			}
 			if err != nil {
-				msg := fmt.Sprintf("Menu was not found")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+				c.Error(apierror.Internal("error cooured while fetching orders", err))
 				return
 			}
-			updateObj = append(updateObj, bson.E{"table_id", order.Table_id}) //  bson.E{"menu", order.Table_id}
+			if storedOrder.IsClosed() {
+				c.Error(apierror.Conflict(fmt.Sprintf("order is %s, its table can no longer be changed", storedOrder.CurrentStatus())))
+				return
+			}
+			update.Table_id = order.Table_id
+		}
+		if order.Covers != nil {
+			if validationErr := validate.StructPartial(order, "Covers"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			update.Covers = order.Covers
 		}
 		order.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		updateObj = append(updateObj, bson.E{"updated_at", order.Updated_at})
+		update.Updated_at = order.Updated_at
+
+		found, err := oc.orders.Update(ctx, orderID, update)
+		if err != nil {
+			msg := fmt.Sprintf("Order item update failed")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("order was not found"))
+			return
+		}
+
+		updatedOrder, err := oc.orders.FindByID(ctx, orderID)
+		if err != nil {
+			msg := fmt.Sprintf("error cooured while fetching orders")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedOrder)
+
+	}
+}
 
-		upsert := true
+// TransitionOrder moves the order to status, rejecting anything the order
+// lifecycle does not allow from its current status.
+func (oc *OrderController) TransitionOrder(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), oc.timeout)
+		defer cancel()
 
-		filter := bson.M{"order_id": orderID}
+		orderID := c.Param("order_id")
 
-		opt := options.UpdateOptions{
-			Upsert: &upsert,
+		order, err := oc.orders.FindByID(ctx, orderID)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("order was not found"))
+			return
+		}
+		if err != nil {
+			msg := fmt.Sprintf("error cooured while fetching orders")
+			c.Error(apierror.Internal(msg, err))
+			return
 		}
-		result, err := orderCollection.UpdateOne(
-			ctx,
-			filter,
-			bson.D{
-				{"$set", updateObj},
-			},
-			&opt,
-		)
 
+		//voiding an invoiced order takes it out of the sales of its day
+		closing := status == models.OrderCancelled || status == models.OrderVoided
+		if closing {
+			invoice, err := oc.invoices.FindByOrderID(ctx, orderID)
+			if err != nil && err != repository.ErrNotFound {
+				c.Error(apierror.Internal("error occured while fetching the invoice", err))
+				return
+			}
+			if err == nil && !checkInvoiceOpen(ctx, c, oc.zReports, invoice) {
+				return
+			}
+		}
+		if !models.CanTransitionOrder(order.CurrentStatus(), status) {
+			c.Error(apierror.Conflict(fmt.Sprintf("order cannot go from %s to %s", order.CurrentStatus(), status)))
+			return
+		}
+
+		//cancelled and voided orders give their ingredients back before the
+		//status is stored, so a request failing halfway can be sent again
+		var orderItems []models.OrderItem
+		if closing {
+			orderItems, err = oc.orderItems.ListByOrder(ctx, orderID)
+			if err != nil {
+				c.Error(apierror.Internal("error occured while listing order items", err))
+				return
+			}
+			if err := oc.returnOrderStock(ctx, orderItems); err != nil {
+				c.Error(apierror.Internal("error occured while returning ingredients to stock", err))
+				return
+			}
+		}
+
+		updatedOrder, err := changeOrderStatus(ctx, oc.orders, order, status, c.GetString("uid"))
 		if err != nil {
-			msg := fmt.Sprintf("Order item update failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			//the order keeps its status, and so its ingredients
+			if takeErr := oc.takeOrderStock(ctx, orderItems); takeErr != nil {
+				c.Error(apierror.Internal("order status was not changed and its ingredients could not be taken from stock again", fmt.Errorf("%v, taking stock: %w", err, takeErr)))
+				return
+			}
+		}
+		if err == errInvalidTransition || err == errOrderChanged {
+			c.Error(apierror.Conflict(fmt.Sprintf("order cannot go from %s to %s", order.CurrentStatus(), status)))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		if err != nil {
+			c.Error(apierror.Internal("order status update failed", err))
+			return
+		}
+
+		//and leave the kitchen displays
+		for _, orderItem := range orderItems {
+			if err := oc.closeKitchenItem(ctx, orderItem); err != nil {
+				c.Error(apierror.Internal("error occured while closing kitchen items", err))
+				return
+			}
+		}
+		c.JSON(http.StatusOK, updatedOrder)
+	}
+}
 
+// returnOrderStock gives the ingredients of orderItems back to stock and
+// has each item forget them, so they are never given back twice. When it
+// fails the items given back so far take theirs again.
+func (oc *OrderController) returnOrderStock(ctx context.Context, orderItems []models.OrderItem) error {
+	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	for i, orderItem := range orderItems {
+		if len(orderItem.Stock_used) == 0 {
+			continue
+		}
+		err := returnStock(ctx, oc.ingredients, orderItem.Stock_used)
+		if err == nil {
+			_, err = oc.orderItems.UpdateStockUsed(ctx, orderItem.Order_item_id, nil, updatedAt)
+		}
+		if err != nil {
+			if takeErr := oc.takeOrderStock(ctx, orderItems[:i]); takeErr != nil {
+				return fmt.Errorf("%v, taking stock again: %w", err, takeErr)
+			}
+			return err
+		}
 	}
+	return nil
 }
-func OrderItemOrderCreator(order models.Order) string {
-	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+
+// takeOrderStock takes back what returnOrderStock gave back for orderItems
+func (oc *OrderController) takeOrderStock(ctx context.Context, orderItems []models.OrderItem) error {
+	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	for _, orderItem := range orderItems {
+		if len(orderItem.Stock_used) == 0 {
+			continue
+		}
+		if err := takeStock(ctx, oc.ingredients, orderItem.Stock_used); err != nil {
+			return err
+		}
+		if _, err := oc.orderItems.UpdateStockUsed(ctx, orderItem.Order_item_id, orderItem.Stock_used, updatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeKitchenItem cancels an item the kitchen has not finished and tells
+// every display, an item a cook bumps meanwhile is left as the cook left it
+func (oc *OrderController) closeKitchenItem(ctx context.Context, orderItem models.OrderItem) error {
+	if orderItem.Kitchen_status != nil && *orderItem.Kitchen_status != models.KitchenPending && *orderItem.Kitchen_status != models.KitchenPreparing {
+		return nil
+	}
+
+	status := models.KitchenCancelled
+	updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	updated, err := oc.orderItems.UpdateKitchenStatus(ctx, orderItem.Order_item_id, orderItem.Kitchen_status, status, updatedAt)
+	if err != nil || !updated {
+		return err
+	}
+
+	orderItem.Kitchen_status = &status
+	orderItem.Updated_at = updatedAt
+	oc.broker.Publish(orderItem)
+	return nil
+}
+
+var errTableNotFound = errors.New("table was not found")
+var errInvalidTransition = errors.New("invalid order status transition")
+var errOrderChanged = errors.New("order status was changed concurrently")
+
+// changeOrderStatus records who moved the order to which status and when.
+// The write only succeeds while the stored status is the one that was
+// checked, so two concurrent transitions cannot both win.
+func changeOrderStatus(ctx context.Context, orders repository.OrderRepository, order models.Order, status string, changedBy string) (models.Order, error) {
+	from := order.CurrentStatus()
+	if !models.CanTransitionOrder(from, status) {
+		return order, errInvalidTransition
+	}
+
+	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	history := append(order.Status_history, models.OrderStatusChange{
+		From:       from,
+		To:         status,
+		Changed_by: changedBy,
+		Changed_at: now,
+	})
+
+	update := repository.OrderUpdate{
+		Status:         &status,
+		Status_history: history,
+		Updated_at:     now,
+	}
+
+	updated, err := orders.UpdateStatus(ctx, order.Order_id, order.Status, update)
+	if err != nil {
+		return order, err
+	}
+	if !updated {
+		return order, errOrderChanged
+	}
+
+	order.Status = &status
+	order.Status_history = history
+	order.Updated_at = now
+	return order, nil
+}
+
+// placeOrder opens a new order on an existing table, CreateOrder and seating
+// a reservation or a walk-in party all go through it.
+func placeOrder(ctx context.Context, orders repository.OrderRepository, tables repository.TableRepository, order models.Order, placedBy string) (models.Order, error) {
+	if order.Table_id != nil {
+		if _, err := tables.FindByID(ctx, *order.Table_id); err == repository.ErrNotFound {
+			return order, errTableNotFound
+		} else if err != nil {
+			return order, err
+		}
+	}
+
+	orderId, err := OrderItemOrderCreator(ctx, orders, order, placedBy)
+	if err != nil {
+		return order, err
+	}
+	return orders.FindByID(ctx, orderId)
+}
+
+// OrderItemOrderCreator stores a new order and returns its order_id, it is
+// the single place orders get created so every order starts out placed.
+func OrderItemOrderCreator(ctx context.Context, orders repository.OrderRepository, order models.Order, placedBy string) (string, error) {
 	order.ID = primitive.NewObjectID()
 	order.Order_id = order.ID.Hex()
 	order.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 	order.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-	//	_, err :=
-	orderCollection.InsertOne(ctx, order)
-	defer cancel()
-	// if err != nil {
-	// 	return
-	// }
-	return order.Order_id
+
+	status := models.OrderPlaced
+	order.Status = &status
+	order.Status_history = []models.OrderStatusChange{{
+		To:         models.OrderPlaced,
+		Changed_by: placedBy,
+		Changed_at: order.Created_at,
+	}}
+
+	if err := orders.Insert(ctx, order); err != nil {
+		return "", err
+	}
+	return order.Order_id, nil
 }
@@ -3,220 +3,375 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
-	database "restaurant-management/database"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// OrderItemPack is a new order for Covers guests. Menu_override lets a
+// manager order foods whose menu is not being served right now.
 type OrderItemPack struct {
-	Table_id    *string
-	Order_items []models.OrderItem
+	Table_id      *string            `json:"table_id"`
+	Covers        *int               `json:"covers" validate:"omitempty,min=1,max=100"`
+	Order_items   []models.OrderItem `json:"order_items"`
+	Menu_override bool               `json:"menu_override"`
 }
 
-var orderItemCollection *mongo.Collection = database.OpenCollection(database.Client, "orderItem")
+type OrderItemController struct {
+	orderItems  repository.OrderItemRepository
+	orders      repository.OrderRepository
+	invoices    repository.InvoiceRepository
+	foods       repository.FoodRepository
+	menus       repository.MenuRepository
+	ingredients repository.IngredientRepository
+	notes       repository.NoteRepository
+	broker      *helpers.KitchenBroker
 
-func GetOrderItems() gin.HandlerFunc {
+	location *time.Location
+	timeout  time.Duration
+}
+
+func NewOrderItemController(orderItems repository.OrderItemRepository, orders repository.OrderRepository, invoices repository.InvoiceRepository, foods repository.FoodRepository, menus repository.MenuRepository, ingredients repository.IngredientRepository, notes repository.NoteRepository, broker *helpers.KitchenBroker, location *time.Location, timeout time.Duration) *OrderItemController {
+	return &OrderItemController{orderItems: orderItems, orders: orders, invoices: invoices, foods: foods, menus: menus, ingredients: ingredients, notes: notes, broker: broker, location: location, timeout: timeout}
+}
+
+var orderItemListSpec = listSpec{
+	fields: map[string]listField{
+		"order_id":       {path: "order_id", kind: textField, filter: true, sort: true},
+		"food_id":        {path: "food_id", kind: textField, filter: true},
+		"station":        {path: "station", kind: textField, filter: true, sort: true},
+		"kitchen_status": {path: "kitchen_status", kind: textField, filter: true, sort: true},
+		"created_at":     {path: "created_at", kind: timeField, sort: true},
+		"updated_at":     {path: "updated_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "-created_at",
+}
+
+func (oic *OrderItemController) GetOrderItems() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		cursor, err := orderCollection.Find(context.TODO(), bson.M{})
+		ctx, cancel := context.WithTimeout(context.Background(), oic.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, orderItemListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing the order items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allOrderItems []bson.M
-		if err := cursor.All(ctx, &allOrderItems); err != nil {
-			log.Fatal(err)
+		page, err := oic.orderItems.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the order items", err))
 			return
 		}
-		c.JSON(http.StatusOK, allOrderItems)
+		c.JSON(http.StatusOK, page)
 	}
 }
 
-func GetOrderItemsByOrderID() gin.HandlerFunc {
+func (oic *OrderItemController) GetOrderItemsByOrderID() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), oic.timeout)
+		defer cancel()
 		orderId := c.Param("order_id")
-		allOrderItems, err := AlltheItemsInAnOrder(orderId)
+		allOrderItems, err := oic.orderItems.AlltheItemsInAnOrder(ctx, orderId)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while listing order items by order ID"})
+			c.Error(apierror.Internal("error occured while listing order items by order ID", err))
 			return
 		}
-		c.JSON(http.StatusOK, allOrderItems)
-	}
-}
-
-func AlltheItemsInAnOrder(id string) (OrderItems []primitive.M, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-
-	matchStage := bson.D{{Key: "$match", Value: bson.D{{"order_id", id}}}}
-	lookupStage := bson.D{{Key: "$lookup", Value: bson.D{{"from", "food"}, {"localField", "food_id"}, {"foreignField", "food_id"}, {"as", "food"}}}}
-	unwindStage := bson.D{{"$unwind", bson.D{{"path", "$food"}, {"preserveNullAndEmptyArrays", true}}}}
-
-	lookupOrderStage := bson.D{{"$lookup", bson.D{{"from", "order"}, {"localField", "order_id"}, {"foreignField", "order_id"}, {"as", "order"}}}}
-	unwindOrderStage := bson.D{{"$unwind", bson.D{{"path", "$order"}, {"preserveNullAndEmptyArrays", true}}}}
-
-	lookupTableStage := bson.D{{"$lookup", bson.D{{"from", "table"}, {"localField", "order.table_id"}, {"foreignField", "order.table_id"}, {"as", "table"}}}}
-	unwindTableStage := bson.D{{"$unwind", bson.D{{"path", "$table"}, {"preserveNullAndEmptyArrays", true}}}}
-
-	projectStage := bson.D{
-		{"$project", bson.D{
-			{"id", 0},
-			{"amount", "$food.price"},
-			{"total_count", 1},
-			{"food_name", "$food.name"},
-			{"food_image", "$food.food_image"},
-			{"table_number", "$table.table_number"},
-			{"table_id", "$table.table_id"},
-			{"order_id", "$order.order_id"},
-			{"price", "$food.price"},
-			{"quantity", 1},
-		}}}
-
-	groupStage := bson.D{{"$group", bson.D{{"_id", bson.D{{"order_id", "$order_id"}, {"table_id", "$table_id"}, {"table_number", "$table_number"}}},
-		{"payment_due", bson.D{{"$sum", "$amount"}}}, {"total_count", bson.D{{"$sum", 1}}}, {"order_items", bson.D{{"$push", "$$ROOT"}}}}}}
-
-	projectStage2 := bson.D{
-		{"$project", bson.D{
-			{"id", 0},
-			{"payment_due", 1},
-			{"total_count", 1},
-			{"table_number", "$_id.table_number"},
-			{"order_items", 1},
-		}}}
-
-	cursor, err := orderItemCollection.Aggregate(ctx, mongo.Pipeline{
-		matchStage,
-		lookupStage,
-		unwindStage,
-		lookupOrderStage,
-		unwindOrderStage,
-		lookupTableStage,
-		unwindTableStage,
-		projectStage,
-		groupStage,
-		projectStage2,
-	})
-
-	if err != nil {
-		panic(err)
-
-	}
 
-	if err := cursor.All(ctx, &OrderItems); err != nil {
-		panic(err)
+		//the notes are already joined in, only the allergens of the foods are missing
+		for i, detail := range allOrderItems.Order_items {
+			allOrderItems.Order_items[i].Allergy_warnings = []string{}
+			if detail.Food_id == nil {
+				continue
+			}
+			food, err := oic.foods.FindByID(ctx, *detail.Food_id)
+			if err == repository.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				c.Error(apierror.Internal("error occured while checking the order for allergens", err))
+				return
+			}
+			allOrderItems.Order_items[i].Allergy_warnings = food.AllergyWarnings(append(detail.Notes, allOrderItems.Notes...))
+		}
+		c.JSON(http.StatusOK, allOrderItems)
 	}
-	defer cancel()
-
-	return OrderItems, err
-
 }
 
-func GetOrderItem() gin.HandlerFunc {
+func (oic *OrderItemController) GetOrderItem() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		var orderItem models.OrderItem
-		orderitemId := c.Param("order_item_id")
-		err := orderItemCollection.FindOne(ctx, bson.M{"order_item_id": orderitemId}).Decode(&orderItem)
+		ctx, cancel := context.WithTimeout(context.Background(), oic.timeout)
 		defer cancel()
+		orderitemId := c.Param("orderItem_id")
+		orderItem, err := oic.orderItems.FindByID(ctx, orderitemId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("order item was not found"))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("error occured while listing the Order Items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
 		c.JSON(http.StatusOK, orderItem)
 	}
 }
-func UpdateOrderItem() gin.HandlerFunc {
+func (oic *OrderItemController) UpdateOrderItem() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), oic.timeout)
+		defer cancel()
 
 		var orderItem models.OrderItem
 
-		orderItemId := c.Param("order_item_id")
-
-		filter := bson.M{"order_item_id": orderItemId}
-
-		var updateObj primitive.D
+		orderItemId := c.Param("orderItem_id")
 
-		if orderItem.Unit_price != nil {
-			updateObj = append(updateObj, bson.E{Key: "unit_price", Value: *orderItem.Unit_price})
+		if err := c.ShouldBindJSON(&orderItem); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
 		}
 
+		//prices follow the food, variant and modifiers, so any change to them
+		//prices the stored item again
+		storedOrderItem, err := oic.orderItems.FindByID(ctx, orderItemId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("order item was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the Order Items", err))
+			return
+		}
+		if !oic.checkOrderOpen(ctx, c, storedOrderItem.Order_id) {
+			return
+		}
 		if orderItem.Food_id != nil {
-			updateObj = append(updateObj, bson.E{Key: "food_id", Value: *orderItem.Food_id})
+			storedOrderItem.Food_id = orderItem.Food_id
+			//the old food's choices do not carry over unless given again
+			storedOrderItem.Variant = nil
+			storedOrderItem.Modifiers = nil
 		}
-
 		if orderItem.Quantity != nil {
-			updateObj = append(updateObj, bson.E{Key: "quantity", Value: *orderItem.Quantity})
+			storedOrderItem.Quantity = orderItem.Quantity
+		}
+		if orderItem.Variant != nil {
+			storedOrderItem.Variant = orderItem.Variant
+		}
+		if orderItem.Modifiers != nil {
+			storedOrderItem.Modifiers = orderItem.Modifiers
+		}
+		if validationErr := validate.StructPartial(storedOrderItem, "Quantity", "Variant", "Modifiers"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		food, err := oic.foods.FindByID(ctx, *storedOrderItem.Food_id)
+		if err != nil {
+			c.Error(apierror.BadRequest("food was not found"))
+			return
+		}
+		pricedOrderItem, err := food.PriceOrderItem(storedOrderItem)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		//what the item took before goes back and the new recipe is taken
+		stockUsed := food.StockUses(*pricedOrderItem.Quantity)
+		if err := returnStock(ctx, oic.ingredients, storedOrderItem.Stock_used); err != nil {
+			c.Error(apierror.Internal("error occured while returning ingredients to stock", err))
+			return
+		}
+		if err := takeStock(ctx, oic.ingredients, stockUsed); err != nil {
+			if restoreErr := takeStock(ctx, oic.ingredients, storedOrderItem.Stock_used); restoreErr != nil {
+				c.Error(apierror.Internal("order item was not updated and its ingredients could not be taken from stock again", fmt.Errorf("%v, restoring: %w", err, restoreErr)))
+				return
+			}
+			if _, ok := err.(outOfStockError); ok {
+				c.Error(apierror.Wrap(http.StatusConflict, err))
+				return
+			}
+			c.Error(apierror.Internal("error occured while taking ingredients from stock", err))
+			return
 		}
 
 		orderItem.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: orderItem.Updated_at})
-
-		upsert := true
-		opt := options.UpdateOptions{
-			Upsert: &upsert,
-		}
-		result, err := orderItemCollection.UpdateOne(
-			ctx,
-			filter,
-			bson.D{
-				{Key: "$set", Value: updateObj},
-			},
-			&opt,
-		)
-		defer cancel()
+		update := repository.OrderItemUpdate{
+			Food_id:    pricedOrderItem.Food_id,
+			Station:    food.Station,
+			Quantity:   pricedOrderItem.Quantity,
+			Variant:    pricedOrderItem.Variant,
+			Modifiers:  pricedOrderItem.Modifiers,
+			Unit_price: pricedOrderItem.Unit_price,
+			Line_price: pricedOrderItem.Line_price,
+			Stock_used: stockUsed,
+			Updated_at: orderItem.Updated_at,
+		}
+
+		found, err := oic.orderItems.Update(ctx, orderItemId, update)
 		if err != nil {
-			//	msg := fmt.Sprintf("Order item update failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Order item update failed"})
+			c.Error(apierror.Internal("Order item update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("order item was not found"))
 			return
 		}
 
-		c.JSON(http.StatusOK, result)
+		updatedOrderItem, err := oic.orderItems.FindByID(ctx, orderItemId)
+		if err != nil {
+			msg := fmt.Sprintf("error occured while listing the Order Items")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		updatedOrderItem.Allergy_warnings, err = allergyWarnings(ctx, oic.notes, food, orderItemId, updatedOrderItem.Order_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while checking the order for allergens", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedOrderItem)
 	}
 }
-func CreateOrderItem() gin.HandlerFunc {
+
+// checkOrderOpen writes a 409 and returns false once the order is closed or
+// invoiced, the invoice and its payments charge the items as they were then
+func (oic *OrderItemController) checkOrderOpen(ctx context.Context, c *gin.Context, orderId string) bool {
+	order, err := oic.orders.FindByID(ctx, orderId)
+	if err != nil && err != repository.ErrNotFound {
+		c.Error(apierror.Internal("error occured while fetching the order", err))
+		return false
+	}
+	if err == nil && order.IsClosed() {
+		c.Error(apierror.Conflict(fmt.Sprintf("order is %s, its items can no longer be changed", order.CurrentStatus())))
+		return false
+	}
+
+	_, err = oic.invoices.FindByOrderID(ctx, orderId)
+	if err == nil {
+		c.Error(apierror.Conflict("order has been invoiced, its items can no longer be changed"))
+		return false
+	}
+	if err != repository.ErrNotFound {
+		c.Error(apierror.Internal("error occured while fetching the invoice", err))
+		return false
+	}
+	return true
+}
+func (oic *OrderItemController) CreateOrderItem() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), oic.timeout)
+		defer cancel()
 		var orderItemPack OrderItemPack
 		var order models.Order
-		if err := c.BindJSON(&orderItemPack); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&orderItemPack); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		order.Order_date, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		orderItemsToBeInserted := []interface{}{}
-		order.Table_id = orderItemPack.Table_id
-		order_id := OrderItemOrderCreator(order)
+
+		if validationErr := validate.StructPartial(orderItemPack, "Covers"); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if len(orderItemPack.Order_items) == 0 {
+			c.Error(apierror.BadRequest("please provide order items"))
+			return
+		}
+
+		role := c.GetString("role")
+		if orderItemPack.Menu_override && role != models.RoleManager && role != models.RoleAdmin {
+			c.Error(apierror.Forbidden("only a manager can order from a menu that is not active"))
+			return
+		}
+
+		//the order does not exist yet, so its id is the one field not checked here
+		menus := map[string]models.Menu{}
+		now := time.Now()
+		pricedOrderItems := []models.OrderItem{}
 		for _, orderItem := range orderItemPack.Order_items {
-			orderItem.Order_id = order_id
+			if validationErr := validate.StructExcept(orderItem, "Order_id"); validationErr != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+				return
+			}
+			food, err := oic.foods.FindByID(ctx, *orderItem.Food_id)
+			if err != nil {
+				c.Error(apierror.BadRequest(fmt.Sprintf("food %s was not found", *orderItem.Food_id)))
+				return
+			}
+			orderItem, err = food.PriceOrderItem(orderItem)
+			if err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
+			}
+			orderItem.Station = food.Station
+			orderItem.Stock_used = food.StockUses(*orderItem.Quantity)
+			pricedOrderItems = append(pricedOrderItems, orderItem)
 
-			if validationErr := validate.Struct(orderItem); validationErr != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			if orderItemPack.Menu_override || food.Menu_id == nil {
+				continue
+			}
+			menu, ok := menus[*food.Menu_id]
+			if !ok {
+				menu, err = oic.menus.FindByID(ctx, *food.Menu_id)
+				if err != nil {
+					c.Error(apierror.BadRequest(fmt.Sprintf("menu of food %s was not found", food.Food_id)))
+					return
+				}
+				menus[*food.Menu_id] = menu
+			}
+			if !menu.IsActive(now, oic.location) {
+				c.Error(apierror.Conflict(fmt.Sprintf("%s is not available, the %s menu is not being served", food.Namme, menu.Name)))
 				return
 			}
+		}
+
+		//the recipes come out of stock before anything is stored, a sold out
+		//ingredient turns the whole order away
+		var stockUsed []models.StockUse
+		for _, orderItem := range pricedOrderItems {
+			stockUsed = append(stockUsed, orderItem.Stock_used...)
+		}
+		stockUsed = combineStockUses(stockUsed)
+		if err := takeStock(ctx, oic.ingredients, stockUsed); err != nil {
+			if _, ok := err.(outOfStockError); ok {
+				c.Error(apierror.Wrap(http.StatusConflict, err))
+				return
+			}
+			c.Error(apierror.Internal("error occured while taking ingredients from stock", err))
+			return
+		}
+
+		order.Order_date, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		order.Table_id = orderItemPack.Table_id
+		order.Covers = orderItemPack.Covers
+		order_id, err := OrderItemOrderCreator(ctx, oic.orders, order, c.GetString("uid"))
+		if err != nil {
+			returnStock(ctx, oic.ingredients, stockUsed)
+			c.Error(apierror.Internal("Order was not created", err))
+			return
+		}
+
+		orderItemsToBeInserted := []models.OrderItem{}
+		for _, orderItem := range pricedOrderItems {
+			orderItem.Order_id = order_id
 			orderItem.ID = primitive.NewObjectID()
 			orderItem.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 			orderItem.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 			orderItem.Order_item_id = orderItem.ID.Hex()
-			var num = toFixed(*orderItem.Unit_price, 2)
-			orderItem.Unit_price = &num
+			kitchenStatus := models.KitchenPending
+			orderItem.Kitchen_status = &kitchenStatus
 			orderItemsToBeInserted = append(orderItemsToBeInserted, orderItem)
 		}
-		insertedOrderItems, err := orderItemCollection.InsertMany(ctx, orderItemsToBeInserted)
-		if err != nil {
-			log.Fatal(err)
+		if err := oic.orderItems.InsertMany(ctx, orderItemsToBeInserted); err != nil {
+			returnStock(ctx, oic.ingredients, stockUsed)
+			c.Error(apierror.Internal("Order items were not created", err))
+			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, insertedOrderItems)
+		oic.broker.Publish(orderItemsToBeInserted...)
+		c.JSON(http.StatusOK, orderItemsToBeInserted)
 	}
 }
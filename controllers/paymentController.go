@@ -0,0 +1,460 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errInvoicePaid = errors.New("invoice is already paid")
+var errInvoiceChanged = errors.New("invoice was paid by someone else at the same time, try again")
+var errOrderNotPayable = errors.New("the order of this invoice cannot be paid")
+var errAmountRequired = errors.New("amount or order_item_ids is required")
+var errAmountMismatch = errors.New("amount does not match the order items")
+var errOverpayment = errors.New("only cash payments can exceed the balance due")
+var errTenderedTooLow = errors.New("tendered must be at least the amount")
+var errTenderedNotCash = errors.New("tendered is only used for cash payments")
+
+type PaymentController struct {
+	payments   repository.PaymentRepository
+	invoices   repository.InvoiceRepository
+	orders     repository.OrderRepository
+	orderItems repository.OrderItemRepository
+
+	timeout time.Duration
+}
+
+func NewPaymentController(payments repository.PaymentRepository, invoices repository.InvoiceRepository, orders repository.OrderRepository, orderItems repository.OrderItemRepository, timeout time.Duration) *PaymentController {
+	return &PaymentController{payments: payments, invoices: invoices, orders: orders, orderItems: orderItems, timeout: timeout}
+}
+
+var paymentListSpec = listSpec{
+	fields: map[string]listField{
+		"method":     {path: "method", kind: textField, filter: true, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "created_at",
+}
+
+func (pc *PaymentController) GetPayments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, paymentListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		invoiceId := c.Param("invoice_id")
+		if _, err := pc.invoices.FindByID(ctx, invoiceId); err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("invoice was not found"))
+			return
+		} else if err != nil {
+			c.Error(apierror.Internal("error occoured while listing invoice item", err))
+			return
+		}
+
+		page, err := pc.payments.PageByInvoice(ctx, invoiceId, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing payments", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+// PaymentReceipt is a new payment and the invoice it was made towards
+type PaymentReceipt struct {
+	Payment models.Payment `json:"payment"`
+	Invoice models.Invoice `json:"invoice"`
+}
+
+// CreatePayment records one payment towards the invoice. The amount is
+// either given or, with order_item_ids, the price of those items.
+func (pc *PaymentController) CreatePayment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+		var payment models.Payment
+
+		if err := c.ShouldBindJSON(&payment); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		invoice, err := pc.invoices.FindByID(ctx, c.Param("invoice_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("invoice was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occoured while listing invoice item", err))
+			return
+		}
+		orderDetails, err := pc.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+			return
+		}
+
+		payment, invoice, err = applyPayment(ctx, pc.payments, pc.invoices, pc.orders, invoice, orderDetails, payment, c.GetString("uid"))
+		if err != nil {
+			c.Error(apierror.Wrap(paymentErrorStatus(err), err))
+			return
+		}
+		c.JSON(http.StatusOK, PaymentReceipt{Payment: payment, Invoice: invoice})
+	}
+}
+
+type EvenSplit struct {
+	Invoice_id   string         `json:"invoice_id"`
+	Total_amount models.Money   `json:"total_amount"`
+	Balance_due  models.Money   `json:"balance_due"`
+	Guests       int            `json:"guests"`
+	Shares       []models.Money `json:"shares"`
+}
+
+// GetEvenSplit divides the balance due between guests, the cents that do
+// not divide evenly go to the first guests.
+func (pc *PaymentController) GetEvenSplit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		guests, err := strconv.Atoi(c.Query("guests"))
+		if err != nil || guests < 1 || guests > 100 {
+			c.Error(apierror.BadRequest("guests must be a number between 1 and 100"))
+			return
+		}
+
+		invoice, err := pc.invoices.FindByID(ctx, c.Param("invoice_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("invoice was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occoured while listing invoice item", err))
+			return
+		}
+		orderDetails, err := pc.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+			return
+		}
+
+		total := invoiceTotal(invoice, orderDetails)
+		balance := total.Sub(invoice.Amount_paid)
+		shares := balance.Split(guests)
+
+		c.JSON(http.StatusOK, EvenSplit{Invoice_id: invoice.Invoice_id, Total_amount: total, Balance_due: balance, Guests: guests, Shares: shares})
+	}
+}
+
+type ItemSplitRequest struct {
+	Guests []struct {
+		Order_item_ids []string `json:"order_item_ids" validate:"required,min=1"`
+	} `json:"guests" validate:"required,min=1,dive"`
+}
+
+// ItemSplit is what each guest owes for their order items, and what is
+// left when some items were given to nobody
+type ItemSplit struct {
+	Invoice_id        string       `json:"invoice_id"`
+	Balance_due       models.Money `json:"balance_due"`
+	Shares            []GuestShare `json:"shares"`
+	Unassigned_amount models.Money `json:"unassigned_amount"`
+}
+
+type GuestShare struct {
+	Guest          int          `json:"guest"`
+	Order_item_ids []string     `json:"order_item_ids"`
+	Amount         models.Money `json:"amount"`
+}
+
+// SplitByItems prices the order items each guest had, every guest then
+// pays their share by posting a payment with the same order_item_ids.
+func (pc *PaymentController) SplitByItems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+		var split ItemSplitRequest
+
+		if err := c.ShouldBindJSON(&split); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(split); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		invoice, err := pc.invoices.FindByID(ctx, c.Param("invoice_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("invoice was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occoured while listing invoice item", err))
+			return
+		}
+		orderDetails, err := pc.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+			return
+		}
+		payments, err := pc.payments.ListByInvoice(ctx, invoice.Invoice_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing payments", err))
+			return
+		}
+
+		paid := paidOrderItems(payments)
+		assigned := map[string]bool{}
+		shares := []GuestShare{}
+		assignedTotal := models.Cents(0)
+		for i, guest := range split.Guests {
+			amount, err := orderItemsAmount(invoice, orderDetails, guest.Order_item_ids, paid)
+			if err == nil {
+				for _, orderItemId := range guest.Order_item_ids {
+					if assigned[orderItemId] {
+						err = fmt.Errorf("order item %s is given to more than one guest", orderItemId)
+						break
+					}
+					assigned[orderItemId] = true
+				}
+			}
+			if err != nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, err))
+				return
+			}
+			assignedTotal = assignedTotal.Add(amount)
+			shares = append(shares, GuestShare{Guest: i + 1, Order_item_ids: guest.Order_item_ids, Amount: amount})
+		}
+
+		balance := invoiceTotal(invoice, orderDetails).Sub(invoice.Amount_paid)
+		c.JSON(http.StatusOK, ItemSplit{Invoice_id: invoice.Invoice_id, Balance_due: balance, Shares: shares, Unassigned_amount: balance.Sub(assignedTotal)})
+	}
+}
+
+// applyPayment checks payment against what is still owed, moves the invoice
+// balance and stores the payment. The invoice becomes PAID, and its order is
+// closed, once the balance reaches zero. Cash beyond the balance is given
+// back as change, other methods cannot overpay.
+func applyPayment(ctx context.Context, payments repository.PaymentRepository, invoices repository.InvoiceRepository, orders repository.OrderRepository, invoice models.Invoice, orderDetails models.OrderDetails, payment models.Payment, paidBy string) (models.Payment, models.Invoice, error) {
+	if validationErr := validate.Struct(payment); validationErr != nil {
+		return payment, invoice, validationErr
+	}
+	if invoice.Payment_status != nil && *invoice.Payment_status == models.PaymentPaid {
+		return payment, invoice, errInvoicePaid
+	}
+
+	order, err := orders.FindByID(ctx, invoice.Order_id)
+	if err != nil {
+		return payment, invoice, err
+	}
+	if order.CurrentStatus() != models.OrderServed && order.CurrentStatus() != models.OrderPaid {
+		return payment, invoice, errOrderNotPayable
+	}
+
+	earlierPayments, err := payments.ListByInvoice(ctx, invoice.Invoice_id)
+	if err != nil {
+		return payment, invoice, err
+	}
+
+	if len(payment.Order_item_ids) > 0 {
+		paid := paidOrderItems(earlierPayments)
+		itemsAmount, err := orderItemsAmount(invoice, orderDetails, payment.Order_item_ids, paid)
+		if err != nil {
+			return payment, invoice, err
+		}
+		//the last items pay what is left, so rounding each share leaves no cent behind
+		if len(paid)+len(payment.Order_item_ids) == len(orderDetails.Order_items) && invoice.Amount_paid.IsPositive() {
+			itemsAmount = invoiceTotal(invoice, orderDetails).Sub(invoice.Amount_paid)
+		}
+		if payment.Amount != nil && payment.Amount.Cmp(itemsAmount) != 0 {
+			return payment, invoice, errAmountMismatch
+		}
+		payment.Amount = &itemsAmount
+	}
+	if payment.Amount == nil {
+		return payment, invoice, errAmountRequired
+	}
+
+	method := *payment.Method
+	amount := *payment.Amount
+	tendered := amount
+	if payment.Tendered != nil {
+		if method != models.PaymentCash {
+			return payment, invoice, errTenderedNotCash
+		}
+		if payment.Tendered.Cmp(amount) < 0 {
+			return payment, invoice, errTenderedTooLow
+		}
+		tendered = *payment.Tendered
+	}
+
+	total := invoiceTotal(invoice, orderDetails)
+	balance := total.Sub(invoice.Amount_paid)
+	if amount.Cmp(balance) > 0 {
+		if method != models.PaymentCash {
+			return payment, invoice, errOverpayment
+		}
+		amount = balance
+	}
+
+	now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	payment.ID = primitive.NewObjectID()
+	payment.Payment_id = payment.ID.Hex()
+	payment.Invoice_id = invoice.Invoice_id
+	payment.Amount = &amount
+	payment.Change_due = tendered.Sub(amount)
+	if method == models.PaymentCash {
+		payment.Tendered = &tendered
+	}
+	payment.Created_by = paidBy
+	payment.Created_at = now
+
+	paidAmount := invoice.Amount_paid.Add(amount)
+	balance = total.Sub(paidAmount)
+	status := models.PaymentPartiallyPaid
+	if !balance.IsPositive() {
+		status = models.PaymentPaid
+	}
+	for _, earlier := range earlierPayments {
+		if earlier.Method != nil && *earlier.Method != method {
+			method = models.PaymentMixed
+		}
+	}
+	changeDue := invoice.Change_due.Add(payment.Change_due)
+
+	update := repository.InvoiceUpdate{
+		Total_amount:   &total,
+		Amount_paid:    &paidAmount,
+		Balance_due:    &balance,
+		Change_due:     &changeDue,
+		Payment_status: &status,
+		Payment_method: &method,
+		Updated_at:     now,
+	}
+	//the payment is stored first so that a balance is never paid without
+	//a payment behind it, it is taken back when the balance moved meanwhile
+	if err := payments.Insert(ctx, payment); err != nil {
+		return payment, invoice, err
+	}
+	updated, err := invoices.UpdateBalance(ctx, invoice.Invoice_id, invoice.Amount_paid, update)
+	if err == nil && !updated {
+		err = errInvoiceChanged
+	}
+	if err != nil {
+		if deleteErr := payments.Delete(ctx, payment.Payment_id); deleteErr != nil {
+			log.Printf("payment %s was not applied to invoice %s and not deleted: %v", payment.Payment_id, invoice.Invoice_id, deleteErr)
+		}
+		return payment, invoice, err
+	}
+
+	invoice.Total_amount = &total
+	invoice.Amount_paid = paidAmount
+	invoice.Balance_due = &balance
+	invoice.Change_due = changeDue
+	invoice.Payment_status = &status
+	invoice.Payment_method = &method
+	invoice.Updated_at = now
+
+	//the payment is taken, the order moving on to paid is best effort and
+	//a failure is not reported as a failed payment
+	if status == models.PaymentPaid && order.CurrentStatus() == models.OrderServed {
+		if _, err := changeOrderStatus(ctx, orders, order, models.OrderPaid, paidBy); err != nil {
+			log.Printf("order %s was not marked paid after invoice %s was paid: %v", order.Order_id, invoice.Invoice_id, err)
+		}
+	}
+	return payment, invoice, nil
+}
+
+func paymentErrorStatus(err error) int {
+	switch err {
+	case errInvoicePaid, errInvoiceChanged, errOrderNotPayable:
+		return http.StatusConflict
+	case errAmountRequired, errAmountMismatch, errOverpayment, errTenderedTooLow, errTenderedNotCash:
+		return http.StatusBadRequest
+	}
+	var itemErr orderItemPaymentError
+	if errors.As(err, &itemErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// invoiceTotal is the amount stored when the invoice was created, invoices
+// older than that are priced from their order
+func invoiceTotal(invoice models.Invoice, orderDetails models.OrderDetails) models.Money {
+	if invoice.Total_amount != nil {
+		return *invoice.Total_amount
+	}
+	return orderDetails.Payment_due
+}
+
+func paidOrderItems(payments []models.Payment) map[string]bool {
+	paid := map[string]bool{}
+	for _, payment := range payments {
+		for _, orderItemId := range payment.Order_item_ids {
+			paid[orderItemId] = true
+		}
+	}
+	return paid
+}
+
+type orderItemPaymentError struct {
+	msg string
+}
+
+func (e orderItemPaymentError) Error() string {
+	return e.msg
+}
+
+// orderItemsAmount prices order items of the invoice that are not paid yet
+// after their discounts, including their share of any tax added on top of
+// the menu prices
+func orderItemsAmount(invoice models.Invoice, orderDetails models.OrderDetails, orderItemIds []string, paid map[string]bool) (models.Money, error) {
+	prices := map[string]models.Money{}
+	discounted := models.Cents(0)
+	for _, item := range helpers.RemainingPrices(discountItems(orderDetails), invoice.Discounts) {
+		prices[item.Order_item_id] = item.Amount
+		discounted = discounted.Add(item.Amount)
+	}
+
+	seen := map[string]bool{}
+	amount := models.Cents(0)
+	for _, orderItemId := range orderItemIds {
+		price, ok := prices[orderItemId]
+		if !ok {
+			return models.Money{}, orderItemPaymentError{fmt.Sprintf("order item %s is not on this invoice", orderItemId)}
+		}
+		if paid[orderItemId] {
+			return models.Money{}, orderItemPaymentError{fmt.Sprintf("order item %s is already paid", orderItemId)}
+		}
+		if seen[orderItemId] {
+			return models.Money{}, orderItemPaymentError{fmt.Sprintf("order item %s is listed twice", orderItemId)}
+		}
+		seen[orderItemId] = true
+		amount = amount.Add(price)
+	}
+	if discounted.IsPositive() {
+		//the items' share of the total, rounded to the nearest cent
+		total := invoiceTotal(invoice, orderDetails).Amount
+		amount.Amount = (amount.Amount*total + discounted.Amount/2) / discounted.Amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errDiscountAfterPayment = errors.New("discounts can only be applied before the invoice is paid")
+var errCouponUnavailable = errors.New("coupon is not active, has expired or is used up")
+var errCouponApplied = errors.New("coupon is already applied to this invoice")
+var errPromotionNotApplicable = errors.New("promotion does not apply to any item on this invoice")
+var errCouponTaken = errors.New("coupon code is already used by another promotion")
+var errUsageLimitWithoutCoupon = errors.New("usage_limit needs a coupon_code")
+
+type PromotionController struct {
+	promotions repository.PromotionRepository
+	invoices   repository.InvoiceRepository
+	orderItems repository.OrderItemRepository
+	taxRates   repository.TaxRateRepository
+	taxRules   repository.TaxRuleRepository
+	zReports   repository.ZReportRepository
+
+	jurisdiction string
+	timeout      time.Duration
+}
+
+func NewPromotionController(promotions repository.PromotionRepository, invoices repository.InvoiceRepository, orderItems repository.OrderItemRepository, taxRates repository.TaxRateRepository, taxRules repository.TaxRuleRepository, zReports repository.ZReportRepository, jurisdiction string, timeout time.Duration) *PromotionController {
+	return &PromotionController{promotions: promotions, invoices: invoices, orderItems: orderItems, taxRates: taxRates, taxRules: taxRules, zReports: zReports, jurisdiction: jurisdiction, timeout: timeout}
+}
+
+var promotionListSpec = listSpec{
+	fields: map[string]listField{
+		"type":        {path: "type", kind: textField, filter: true, sort: true},
+		"active":      {path: "active", kind: boolField, filter: true},
+		"coupon_code": {path: "coupon_code", kind: textField, filter: true},
+		"name":        {path: "name", kind: textField, sort: true},
+		"expires_at":  {path: "expires_at", kind: timeField, sort: true},
+		"created_at":  {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "-created_at",
+}
+
+func (pc *PromotionController) GetPromotions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, promotionListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := pc.promotions.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing promotions", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (pc *PromotionController) GetPromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		promotion, err := pc.promotions.FindByID(ctx, c.Param("promotion_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("promotion was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the promotion", err))
+			return
+		}
+		c.JSON(http.StatusOK, promotion)
+	}
+}
+
+func (pc *PromotionController) CreatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		var promotion models.Promotion
+		if err := c.ShouldBindJSON(&promotion); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(promotion); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if promotion.Coupon_code != nil {
+			couponCode := strings.ToUpper(*promotion.Coupon_code)
+			promotion.Coupon_code = &couponCode
+			if _, err := pc.promotions.FindByCouponCode(ctx, couponCode); err == nil {
+				c.Error(apierror.Wrap(http.StatusConflict, errCouponTaken))
+				return
+			} else if err != repository.ErrNotFound {
+				c.Error(apierror.Internal("error occured while checking the coupon code", err))
+				return
+			}
+		} else if promotion.Usage_limit != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, errUsageLimitWithoutCoupon))
+			return
+		}
+
+		if promotion.Active == nil {
+			active := true
+			promotion.Active = &active
+		}
+		promotion.Times_used = 0
+		promotion.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		promotion.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		promotion.ID = primitive.NewObjectID()
+		promotion.Promotion_id = promotion.ID.Hex()
+
+		if err := pc.promotions.Insert(ctx, promotion); err != nil {
+			c.Error(apierror.Internal("promotion was not created", err))
+			return
+		}
+		c.JSON(http.StatusOK, promotion)
+	}
+}
+
+// UpdatePromotion changes the fields sent, the type and coupon code of a
+// promotion stay as they were created. Discounts already on invoices keep
+// the amounts they were applied with.
+func (pc *PromotionController) UpdatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		var promotion models.Promotion
+		if err := c.ShouldBindJSON(&promotion); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		promotionId := c.Param("promotion_id")
+
+		existing, err := pc.promotions.FindByID(ctx, promotionId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("promotion was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the promotion", err))
+			return
+		}
+
+		var update repository.PromotionUpdate
+		if promotion.Name != nil {
+			existing.Name = promotion.Name
+			update.Name = promotion.Name
+		}
+		if promotion.Percent != nil {
+			existing.Percent = promotion.Percent
+			update.Percent = promotion.Percent
+		}
+		if promotion.Amount != nil {
+			existing.Amount = promotion.Amount
+			update.Amount = promotion.Amount
+		}
+		if promotion.Food_ids != nil {
+			existing.Food_ids = promotion.Food_ids
+			update.Food_ids = promotion.Food_ids
+		}
+		if promotion.Buy_quantity != nil {
+			existing.Buy_quantity = promotion.Buy_quantity
+			update.Buy_quantity = promotion.Buy_quantity
+		}
+		if promotion.Get_quantity != nil {
+			existing.Get_quantity = promotion.Get_quantity
+			update.Get_quantity = promotion.Get_quantity
+		}
+		if promotion.Usage_limit != nil {
+			if existing.Coupon_code == nil {
+				c.Error(apierror.Wrap(http.StatusBadRequest, errUsageLimitWithoutCoupon))
+				return
+			}
+			existing.Usage_limit = promotion.Usage_limit
+			update.Usage_limit = promotion.Usage_limit
+		}
+		if promotion.Starts_at != nil {
+			update.Starts_at = promotion.Starts_at
+		}
+		if promotion.Expires_at != nil {
+			update.Expires_at = promotion.Expires_at
+		}
+		if promotion.Active != nil {
+			update.Active = promotion.Active
+		}
+		if validationErr := validate.Struct(existing); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		promotion.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = promotion.Updated_at
+
+		found, err := pc.promotions.Update(ctx, promotionId, update)
+		if err != nil {
+			c.Error(apierror.Internal("promotion update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("promotion was not found"))
+			return
+		}
+
+		updatedPromotion, err := pc.promotions.FindByID(ctx, promotionId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the promotion", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedPromotion)
+	}
+}
+
+type CouponRedemption struct {
+	Coupon_code *string `json:"coupon_code" validate:"required"`
+}
+
+// ApplyCoupon takes a coupon's promotion off an unpaid invoice and uses up
+// one of the coupon's uses
+func (pc *PromotionController) ApplyCoupon() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		var redemption CouponRedemption
+		if err := c.ShouldBindJSON(&redemption); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(redemption); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		promotion, err := pc.promotions.FindByCouponCode(ctx, strings.ToUpper(*redemption.Coupon_code))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("coupon was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the coupon", err))
+			return
+		}
+		if !promotion.InEffect(time.Now()) {
+			c.Error(apierror.Wrap(http.StatusConflict, errCouponUnavailable))
+			return
+		}
+
+		invoice, allOrderItems, ok := pc.loadUnpaidInvoice(ctx, c)
+		if !ok {
+			return
+		}
+		for _, discount := range invoice.Discounts {
+			if discount.Promotion_id == promotion.Promotion_id {
+				c.Error(apierror.Wrap(http.StatusConflict, errCouponApplied))
+				return
+			}
+		}
+
+		remaining := helpers.RemainingPrices(discountItems(allOrderItems), invoice.Discounts)
+		lines := helpers.PromotionDiscount(promotion, remaining)
+		if len(lines) == 0 {
+			c.Error(apierror.Wrap(http.StatusBadRequest, errPromotionNotApplicable))
+			return
+		}
+		discount := appliedPromotion(promotion, lines)
+
+		//take one use of the coupon first, so it cannot be used more often than allowed
+		now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		used, err := pc.promotions.UpdateTimesUsed(ctx, promotion.Promotion_id, promotion.Times_used, promotion.Times_used+1, now)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while redeeming the coupon", err))
+			return
+		}
+		if !used {
+			c.Error(apierror.Conflict("coupon was redeemed by someone else at the same time, try again"))
+			return
+		}
+
+		invoice, err = pc.addDiscount(ctx, invoice, allOrderItems, discount)
+		if err != nil {
+			//give the use back, the invoice did not get the discount
+			restored, restoreErr := pc.promotions.UpdateTimesUsed(ctx, promotion.Promotion_id, promotion.Times_used+1, promotion.Times_used, promotion.Updated_at)
+			if restoreErr != nil || !restored {
+				log.Printf("use of coupon %s was not given back after invoice %s was not discounted: %v", promotion.Promotion_id, invoice.Invoice_id, restoreErr)
+			}
+			c.Error(apierror.Wrap(paymentErrorStatus(err), err))
+			return
+		}
+		c.JSON(http.StatusOK, invoice)
+	}
+}
+
+type CompRequest struct {
+	Order_item_ids []string      `json:"order_item_ids"`
+	Amount         *models.Money `json:"amount" validate:"omitempty,gt=0"`
+	Reason         *string       `json:"reason" validate:"required,min=3,max=200"`
+}
+
+// CompInvoice lets a manager give away order items, or an amount spread
+// over the whole order, with the reason recorded on the invoice
+func (pc *PromotionController) CompInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+		defer cancel()
+
+		var request CompRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(request); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if (len(request.Order_item_ids) == 0) == (request.Amount == nil) {
+			c.Error(apierror.BadRequest("give either order_item_ids or amount"))
+			return
+		}
+
+		invoice, allOrderItems, ok := pc.loadUnpaidInvoice(ctx, c)
+		if !ok {
+			return
+		}
+
+		remaining := helpers.RemainingPrices(discountItems(allOrderItems), invoice.Discounts)
+		var lines []models.DiscountLine
+		if request.Amount != nil {
+			lines = helpers.AllocateDiscount(*request.Amount, remaining)
+		} else {
+			byId := map[string]helpers.DiscountItem{}
+			for _, item := range remaining {
+				byId[item.Order_item_id] = item
+			}
+			for _, orderItemId := range request.Order_item_ids {
+				item, found := byId[orderItemId]
+				if !found {
+					c.Error(apierror.BadRequest("order item " + orderItemId + " is not on this invoice"))
+					return
+				}
+				delete(byId, orderItemId)
+				if item.Amount.IsPositive() {
+					lines = append(lines, models.DiscountLine{Order_item_id: item.Order_item_id, Amount: item.Amount})
+				}
+			}
+		}
+		if len(lines) == 0 {
+			c.Error(apierror.BadRequest("nothing is left to comp"))
+			return
+		}
+
+		now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		discount := models.AppliedDiscount{
+			Name:        "Comp",
+			Type:        models.PromotionComp,
+			Reason:      *request.Reason,
+			Approved_by: c.GetString("uid"),
+			Amount:      helpers.DiscountTotal(lines),
+			Order_items: lines,
+			Applied_at:  now,
+		}
+		invoice, err := pc.addDiscount(ctx, invoice, allOrderItems, discount)
+		if err != nil {
+			c.Error(apierror.Wrap(paymentErrorStatus(err), err))
+			return
+		}
+		c.JSON(http.StatusOK, invoice)
+	}
+}
+
+// loadUnpaidInvoice writes the error response itself and returns false when
+// the invoice is missing, already has payments or its day is closed
+func (pc *PromotionController) loadUnpaidInvoice(ctx context.Context, c *gin.Context) (models.Invoice, models.OrderDetails, bool) {
+	invoice, err := pc.invoices.FindByID(ctx, c.Param("invoice_id"))
+	if err == repository.ErrNotFound {
+		c.Error(apierror.NotFound("invoice was not found"))
+		return invoice, models.OrderDetails{}, false
+	}
+	if err != nil {
+		c.Error(apierror.Internal("error occoured while listing invoice item", err))
+		return invoice, models.OrderDetails{}, false
+	}
+	if !invoice.Amount_paid.IsZero() || (invoice.Payment_status != nil && *invoice.Payment_status == models.PaymentPaid) {
+		c.Error(apierror.Wrap(http.StatusConflict, errDiscountAfterPayment))
+		return invoice, models.OrderDetails{}, false
+	}
+	if !checkInvoiceOpen(ctx, c, pc.zReports, invoice) {
+		return invoice, models.OrderDetails{}, false
+	}
+
+	allOrderItems, err := pc.orderItems.AlltheItemsInAnOrder(ctx, invoice.Order_id)
+	if err != nil {
+		c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+		return invoice, allOrderItems, false
+	}
+	return invoice, allOrderItems, true
+}
+
+// addDiscount reprices the invoice with one more discount. It fails with
+// errInvoiceChanged when a payment was recorded in the meantime.
+func (pc *PromotionController) addDiscount(ctx context.Context, invoice models.Invoice, allOrderItems models.OrderDetails, discount models.AppliedDiscount) (models.Invoice, error) {
+	discounts := append(append([]models.AppliedDiscount{}, invoice.Discounts...), discount)
+	taxes, err := priceOrder(ctx, pc.taxRates, pc.taxRules, pc.jurisdiction, allOrderItems, discounts)
+	if err != nil {
+		return invoice, err
+	}
+	setInvoicePrice(&invoice, taxes, discounts)
+	invoice.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+	update := repository.InvoiceUpdate{
+		Discounts:       invoice.Discounts,
+		Discount_amount: invoice.Discount_amount,
+		Net_amount:      invoice.Net_amount,
+		Tax_amount:      invoice.Tax_amount,
+		Tax_breakdown:   invoice.Tax_breakdown,
+		Total_amount:    invoice.Total_amount,
+		Balance_due:     invoice.Balance_due,
+		Updated_at:      invoice.Updated_at,
+	}
+	updated, err := pc.invoices.UpdateBalance(ctx, invoice.Invoice_id, invoice.Amount_paid, update)
+	if err != nil {
+		return invoice, err
+	}
+	if !updated {
+		return invoice, errInvoiceChanged
+	}
+	return invoice, nil
+}
+
+// automaticDiscounts applies every promotion in effect that needs no coupon,
+// oldest first, each to what the ones before it left
+func automaticDiscounts(ctx context.Context, promotions repository.PromotionRepository, allOrderItems models.OrderDetails) ([]models.AppliedDiscount, error) {
+	allPromotions, err := promotions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(allPromotions, func(i, j int) bool { return allPromotions[i].Created_at.Before(allPromotions[j].Created_at) })
+
+	discounts := []models.AppliedDiscount{}
+	items := discountItems(allOrderItems)
+	now := time.Now()
+	for _, promotion := range allPromotions {
+		if promotion.Coupon_code != nil || !promotion.InEffect(now) {
+			continue
+		}
+		lines := helpers.PromotionDiscount(promotion, helpers.RemainingPrices(items, discounts))
+		if len(lines) > 0 {
+			discounts = append(discounts, appliedPromotion(promotion, lines))
+		}
+	}
+	return discounts, nil
+}
+
+func appliedPromotion(promotion models.Promotion, lines []models.DiscountLine) models.AppliedDiscount {
+	discount := models.AppliedDiscount{
+		Promotion_id: promotion.Promotion_id,
+		Name:         *promotion.Name,
+		Type:         *promotion.Type,
+		Amount:       helpers.DiscountTotal(lines),
+		Order_items:  lines,
+	}
+	if promotion.Coupon_code != nil {
+		discount.Coupon_code = *promotion.Coupon_code
+	}
+	discount.Applied_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	return discount
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reports cover at most a year and the last 30 days when no range is given
+const maxReportRange = 366 * 24 * time.Hour
+const defaultReportDays = 30
+
+var errReportRange = errors.New("from must be before to and the range at most 366 days")
+
+type ReportController struct {
+	reports repository.ReportRepository
+
+	location *time.Location
+	timeout  time.Duration
+}
+
+func NewReportController(reports repository.ReportRepository, location *time.Location, timeout time.Duration) *ReportController {
+	return &ReportController{reports: reports, location: location, timeout: timeout}
+}
+
+func (rpc *ReportController) RevenueByDay() gin.HandlerFunc {
+	return rpc.report("revenue_by_day", revenueColumns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		rows, err := rpc.reports.RevenueByDay(ctx, period)
+		return rows, revenueRecords(rows), err
+	})
+}
+
+func (rpc *ReportController) RevenueByHour() gin.HandlerFunc {
+	return rpc.report("revenue_by_hour", revenueColumns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		rows, err := rpc.reports.RevenueByHour(ctx, period)
+		return rows, revenueRecords(rows), err
+	})
+}
+
+// TopFoods ranks foods by the quantity sold, ?limit= of them, 10 by default
+func (rpc *ReportController) TopFoods() gin.HandlerFunc {
+	columns := []string{"food_id", "food_name", "category", "quantity", "revenue", "currency"}
+	return rpc.report("top_foods", columns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 || limit > 100 {
+			return nil, nil, reportParamError{"limit must be between 1 and 100"}
+		}
+		rows, err := rpc.reports.TopFoods(ctx, period, limit)
+		records := [][]string{}
+		for _, row := range rows {
+			records = append(records, []string{row.Food_id, row.Food_name, row.Category, strconv.Itoa(row.Quantity), row.Revenue.String(), row.Revenue.Currency})
+		}
+		return rows, records, err
+	})
+}
+
+func (rpc *ReportController) RevenueByCategory() gin.HandlerFunc {
+	columns := []string{"category", "quantity", "revenue", "currency"}
+	return rpc.report("revenue_by_category", columns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		rows, err := rpc.reports.RevenueByCategory(ctx, period)
+		records := [][]string{}
+		for _, row := range rows {
+			records = append(records, []string{row.Category, strconv.Itoa(row.Quantity), row.Revenue.String(), row.Revenue.Currency})
+		}
+		return rows, records, err
+	})
+}
+
+func (rpc *ReportController) AverageTicket() gin.HandlerFunc {
+	columns := []string{"invoices", "gross", "average_ticket", "covers", "average_per_cover", "currency"}
+	return rpc.report("average_ticket", columns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		summary, err := rpc.reports.TicketSummary(ctx, period)
+		records := [][]string{{
+			strconv.Itoa(summary.Invoices), summary.Gross.String(), summary.Average_ticket.String(),
+			strconv.Itoa(summary.Covers), summary.Average_per_cover.String(), summary.Gross.Currency,
+		}}
+		return summary, records, err
+	})
+}
+
+func (rpc *ReportController) CoversByTable() gin.HandlerFunc {
+	columns := []string{"table_id", "table_number", "orders", "covers", "revenue", "currency"}
+	return rpc.report("covers_by_table", columns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		rows, err := rpc.reports.CoversByTable(ctx, period)
+		records := [][]string{}
+		for _, row := range rows {
+			tableNumber := ""
+			if row.Table_number != nil {
+				tableNumber = strconv.Itoa(*row.Table_number)
+			}
+			records = append(records, []string{row.Table_id, tableNumber, strconv.Itoa(row.Orders), strconv.Itoa(row.Covers), row.Revenue.String(), row.Revenue.Currency})
+		}
+		return rows, records, err
+	})
+}
+
+func (rpc *ReportController) PaymentMix() gin.HandlerFunc {
+	columns := []string{"method", "payments", "amount", "share", "currency"}
+	return rpc.report("payment_mix", columns, func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error) {
+		rows, err := rpc.reports.PaymentMix(ctx, period)
+		records := [][]string{}
+		for _, row := range rows {
+			records = append(records, []string{row.Method, strconv.Itoa(row.Payments), row.Amount.String(), strconv.FormatFloat(row.Share, 'f', 2, 64), row.Amount.Currency})
+		}
+		return rows, records, err
+	})
+}
+
+// Report is a report as JSON, Rows is what the report lists
+type Report[T any] struct {
+	Report   string    `json:"report"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Timezone string    `json:"timezone"`
+	Rows     T         `json:"rows"`
+}
+
+type reportParamError struct {
+	msg string
+}
+
+func (e reportParamError) Error() string {
+	return e.msg
+}
+
+// report answers with the rows run returns for the requested range, as
+// JSON or with ?format=csv as a CSV download
+func (rpc *ReportController) report(name string, columns []string, run func(ctx context.Context, c *gin.Context, period repository.ReportRange) (interface{}, [][]string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rpc.timeout)
+		defer cancel()
+
+		format := strings.ToLower(c.DefaultQuery("format", "json"))
+		if format != "json" && format != "csv" {
+			c.Error(apierror.BadRequest("format must be json or csv"))
+			return
+		}
+		period, err := rpc.reportRange(c.Query("from"), c.Query("to"))
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		rows, records, err := run(ctx, c, period)
+		if _, ok := err.(reportParamError); ok {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while building the report", err))
+			return
+		}
+
+		if format == "csv" {
+			var out strings.Builder
+			writer := csv.NewWriter(&out)
+			writer.Write(columns)
+			writer.WriteAll(records)
+			filename := fmt.Sprintf("%s_%s_%s.csv", name, period.From.In(rpc.location).Format("2006-01-02"), period.To.In(rpc.location).Format("2006-01-02"))
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(out.String()))
+			return
+		}
+		c.JSON(http.StatusOK, Report[interface{}]{Report: name, From: period.From, To: period.To, Timezone: rpc.location.String(), Rows: rows})
+	}
+}
+
+// reportRange reads from and to as dates in the restaurant timezone, to
+// included, or as RFC 3339 times, to excluded
+func (rpc *ReportController) reportRange(from string, to string) (repository.ReportRange, error) {
+	period := repository.ReportRange{Location: rpc.location}
+
+	now := time.Now().In(rpc.location)
+	period.To = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, rpc.location)
+	if to != "" {
+		parsed, err := rpc.reportTime(to, true)
+		if err != nil {
+			return period, reportParamError{"to must be a date like 2006-01-02 or an RFC 3339 time"}
+		}
+		period.To = parsed
+	}
+	period.From = period.To.AddDate(0, 0, -defaultReportDays)
+	if from != "" {
+		parsed, err := rpc.reportTime(from, false)
+		if err != nil {
+			return period, reportParamError{"from must be a date like 2006-01-02 or an RFC 3339 time"}
+		}
+		period.From = parsed
+	}
+
+	if !period.From.Before(period.To) || period.To.Sub(period.From) > maxReportRange {
+		return period, errReportRange
+	}
+	return period, nil
+}
+
+// reportTime parses a date as the start of that day, or of the next one
+// when end is set so that the day is included
+func (rpc *ReportController) reportTime(value string, end bool) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, rpc.location); err == nil {
+		if end {
+			return day.AddDate(0, 0, 1), nil
+		}
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+var revenueColumns = []string{"period", "invoices", "gross", "net", "tax", "discounts", "currency"}
+
+func revenueRecords(rows []models.RevenueRow) [][]string {
+	records := [][]string{}
+	for _, row := range rows {
+		records = append(records, []string{row.Period, strconv.Itoa(row.Invoices), row.Gross.String(), row.Net.String(), row.Tax.String(), row.Discounts.String(), row.Gross.Currency})
+	}
+	return records
+}
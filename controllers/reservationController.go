@@ -0,0 +1,569 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errTableTooSmall = errors.New("table is too small for the party")
+var errTableTaken = errors.New("table is already taken")
+var errNoTableFree = errors.New("no table is free for the party at that time")
+var errTableBusy = errors.New("table is being booked by someone else, try again")
+
+type ReservationController struct {
+	reservations repository.ReservationRepository
+	tables       repository.TableRepository
+	orders       repository.OrderRepository
+
+	defaultDuration time.Duration
+	turnTime        time.Duration
+	timeout         time.Duration
+}
+
+func NewReservationController(reservations repository.ReservationRepository, tables repository.TableRepository, orders repository.OrderRepository, defaultDuration time.Duration, turnTime time.Duration, timeout time.Duration) *ReservationController {
+	return &ReservationController{reservations: reservations, tables: tables, orders: orders, defaultDuration: defaultDuration, turnTime: turnTime, timeout: timeout}
+}
+
+// claimWait is how long a booking waits for another host to finish with
+// a table before it gives up with a conflict
+const claimWait = 3 * time.Second
+
+var reservationListSpec = listSpec{
+	fields: map[string]listField{
+		"status":           {path: "status", kind: textField, filter: true, sort: true},
+		"table_id":         {path: "table_id", kind: textField, filter: true},
+		"phone":            {path: "phone", kind: textField, filter: true},
+		"party_size":       {path: "party_size", kind: numberField, filter: true, sort: true},
+		"reservation_time": {path: "reservation_time", kind: timeField, sort: true},
+		"created_at":       {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "reservation_time",
+	defaultSort: "reservation_time",
+}
+
+func (rc *ReservationController) GetReservations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, reservationListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := rc.reservations.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing reservations", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (rc *ReservationController) GetReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+
+		reservation, err := rc.reservations.FindByID(ctx, c.Param("reservation_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("reservation was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the reservation", err))
+			return
+		}
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+type Availability struct {
+	Party_size int            `json:"party_size"`
+	Time       time.Time      `json:"time"`
+	Ends_at    time.Time      `json:"ends_at"`
+	Tables     []models.Table `json:"tables"`
+}
+
+// GetAvailability lists the tables that can take party_size guests at time
+// for duration_minutes, smallest tables first.
+func (rc *ReservationController) GetAvailability() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+
+		partySize, err := strconv.Atoi(c.Query("party_size"))
+		if err != nil || partySize < 1 {
+			c.Error(apierror.BadRequest("party_size must be a positive number"))
+			return
+		}
+		from, err := time.Parse(time.RFC3339, c.Query("time"))
+		if err != nil {
+			c.Error(apierror.BadRequest("time must be an RFC3339 timestamp"))
+			return
+		}
+		duration := rc.defaultDuration
+		if minutes := c.Query("duration_minutes"); minutes != "" {
+			value, err := strconv.Atoi(minutes)
+			if err != nil || value < 15 {
+				c.Error(apierror.BadRequest("duration_minutes must be at least 15"))
+				return
+			}
+			duration = time.Duration(value) * time.Minute
+		}
+
+		tables, err := freeTables(ctx, rc.tables, rc.reservations, rc.orders, partySize, from, from.Add(duration), time.Now().Add(rc.turnTime), "")
+		if err != nil {
+			c.Error(apierror.Internal("error occured while searching for tables", err))
+			return
+		}
+		c.JSON(http.StatusOK, Availability{Party_size: partySize, Time: from, Ends_at: from.Add(duration), Tables: tables})
+	}
+}
+
+func (rc *ReservationController) CreateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+		var reservation models.Reservation
+
+		if err := c.ShouldBindJSON(&reservation); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(reservation); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if reservation.Reservation_time.Before(time.Now()) {
+			c.Error(apierror.BadRequest("reservation_time must be in the future"))
+			return
+		}
+		if reservation.Duration_minutes == nil {
+			minutes := int(rc.defaultDuration / time.Minute)
+			reservation.Duration_minutes = &minutes
+		}
+		reservation.Ends_at = reservation.Reservation_time.Add(time.Duration(*reservation.Duration_minutes) * time.Minute)
+
+		tableId, release, err := rc.bookTable(ctx, reservation)
+		if err != nil {
+			c.Error(apierror.Wrap(statusForBooking(err), err))
+			return
+		}
+		defer release()
+
+		status := models.ReservationBooked
+		reservation.Table_id = &tableId
+		reservation.Status = &status
+		reservation.Order_id = nil
+		reservation.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		reservation.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		reservation.ID = primitive.NewObjectID()
+		reservation.Reservation_id = reservation.ID.Hex()
+
+		if err := rc.reservations.Insert(ctx, reservation); err != nil {
+			c.Error(apierror.Internal("reservation was not created", err))
+			return
+		}
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+// UpdateReservation changes a booked reservation, a new time, length, party
+// size or table is checked for availability again.
+func (rc *ReservationController) UpdateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+		var changes models.Reservation
+
+		reservationId := c.Param("reservation_id")
+		if err := c.ShouldBindJSON(&changes); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		reservation, err := rc.reservations.FindByID(ctx, reservationId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("reservation was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the reservation", err))
+			return
+		}
+		if !equalStatus(reservation.Status, models.ReservationBooked) {
+			c.Error(apierror.Conflict("only booked reservations can be changed"))
+			return
+		}
+
+		update := repository.ReservationUpdate{Guest_name: changes.Guest_name, Phone: changes.Phone}
+		rebook := false
+
+		if changes.Party_size != nil {
+			if *changes.Party_size < 1 {
+				c.Error(apierror.BadRequest("party_size must be a positive number"))
+				return
+			}
+			reservation.Party_size = changes.Party_size
+			rebook = true
+		}
+		if changes.Reservation_time != nil {
+			if changes.Reservation_time.Before(time.Now()) {
+				c.Error(apierror.BadRequest("reservation_time must be in the future"))
+				return
+			}
+			reservation.Reservation_time = changes.Reservation_time
+			rebook = true
+		}
+		if changes.Duration_minutes != nil {
+			if *changes.Duration_minutes < 15 || *changes.Duration_minutes > 720 {
+				c.Error(apierror.BadRequest("duration_minutes must be between 15 and 720"))
+				return
+			}
+			reservation.Duration_minutes = changes.Duration_minutes
+			rebook = true
+		}
+		if changes.Table_id != nil {
+			reservation.Table_id = changes.Table_id
+			rebook = true
+		}
+
+		if rebook {
+			reservation.Ends_at = reservation.Reservation_time.Add(time.Duration(*reservation.Duration_minutes) * time.Minute)
+			tableId, release, err := rc.bookTable(ctx, reservation)
+			if err != nil {
+				c.Error(apierror.Wrap(statusForBooking(err), err))
+				return
+			}
+			defer release()
+			update.Party_size = reservation.Party_size
+			update.Reservation_time = reservation.Reservation_time
+			update.Duration_minutes = reservation.Duration_minutes
+			update.Ends_at = &reservation.Ends_at
+			update.Table_id = &tableId
+		}
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = updatedAt
+
+		updated, err := rc.reservations.UpdateStatus(ctx, reservationId, reservation.Status, update)
+		if err != nil {
+			c.Error(apierror.Internal("reservation update failed", err))
+			return
+		}
+		if !updated {
+			c.Error(apierror.Conflict("reservation was changed by someone else"))
+			return
+		}
+
+		updatedReservation, err := rc.reservations.FindByID(ctx, reservationId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the reservation", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedReservation)
+	}
+}
+
+type SeatedReservation struct {
+	Reservation models.Reservation `json:"reservation"`
+	Order       models.Order       `json:"order"`
+}
+
+// SeatReservation opens the order for the reserved table the same way
+// CreateOrder does and marks the reservation seated.
+func (rc *ReservationController) SeatReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+
+		reservationId := c.Param("reservation_id")
+
+		reservation, err := rc.reservations.FindByID(ctx, reservationId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("reservation was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the reservation", err))
+			return
+		}
+		if !equalStatus(reservation.Status, models.ReservationBooked) {
+			c.Error(apierror.Conflict(fmt.Sprintf("reservation is %s, only booked reservations can be seated", *reservation.Status)))
+			return
+		}
+
+		release, err := claimTable(ctx, rc.tables, *reservation.Table_id, rc.timeout)
+		if err == errTableNotFound {
+			c.Error(apierror.Conflict("the reserved table no longer exists"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Wrap(statusForBooking(err), err))
+			return
+		}
+		defer release()
+
+		occupied, err := tableOccupied(ctx, rc.orders, *reservation.Table_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while checking the table", err))
+			return
+		}
+		if occupied {
+			c.Error(apierror.Conflict("the reserved table still has an open order"))
+			return
+		}
+
+		now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		order, err := placeOrder(ctx, rc.orders, rc.tables, models.Order{Order_date: now, Table_id: reservation.Table_id, Covers: reservation.Party_size}, c.GetString("uid"))
+		if err == errTableNotFound {
+			c.Error(apierror.Conflict("the reserved table no longer exists"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("order was not created", err))
+			return
+		}
+
+		status := models.ReservationSeated
+		update := repository.ReservationUpdate{Status: &status, Order_id: &order.Order_id, Updated_at: now}
+		updated, err := rc.reservations.UpdateStatus(ctx, reservationId, reservation.Status, update)
+		if err != nil {
+			c.Error(apierror.Internal("reservation update failed", err))
+			return
+		}
+		if !updated {
+			//the reservation was closed meanwhile, nobody sits at the order
+			if _, err := changeOrderStatus(ctx, rc.orders, order, models.OrderCancelled, c.GetString("uid")); err != nil {
+				c.Error(apierror.Internal("error occured while cancelling the unused order", err))
+				return
+			}
+			c.Error(apierror.Conflict("reservation was changed by someone else"))
+			return
+		}
+
+		reservation.Status = &status
+		reservation.Order_id = &order.Order_id
+		reservation.Updated_at = now
+		c.JSON(http.StatusOK, SeatedReservation{Reservation: reservation, Order: order})
+	}
+}
+
+// CloseReservation releases the table of a booked reservation, status is
+// either cancelled or no-show.
+func (rc *ReservationController) CloseReservation(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+		defer cancel()
+
+		reservationId := c.Param("reservation_id")
+		from := models.ReservationBooked
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update := repository.ReservationUpdate{Status: &status, Updated_at: updatedAt}
+		updated, err := rc.reservations.UpdateStatus(ctx, reservationId, &from, update)
+		if err != nil {
+			c.Error(apierror.Internal("reservation update failed", err))
+			return
+		}
+
+		reservation, err := rc.reservations.FindByID(ctx, reservationId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("reservation was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the reservation", err))
+			return
+		}
+		if !updated {
+			c.Error(apierror.Conflict(fmt.Sprintf("reservation is %s, only booked reservations can be closed", *reservation.Status)))
+			return
+		}
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+// bookTable picks a table for the reservation and claims it. The pick is
+// checked again under the claim, another host may have booked the table
+// in between. release gives the claim back once the booking is written.
+func (rc *ReservationController) bookTable(ctx context.Context, reservation models.Reservation) (tableId string, release func(), err error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		tableId, err = rc.pickTable(ctx, reservation)
+		if err != nil {
+			return "", nil, err
+		}
+		release, err = claimTable(ctx, rc.tables, tableId, rc.timeout)
+		if err != nil {
+			return "", nil, err
+		}
+
+		claimed := reservation
+		claimed.Table_id = &tableId
+		if _, err = rc.pickTable(ctx, claimed); err == nil {
+			return tableId, release, nil
+		}
+		release()
+		if err != errTableTaken || reservation.Table_id != nil {
+			return "", nil, err
+		}
+	}
+	return "", nil, errNoTableFree
+}
+
+// claimTable holds the claim on a table while a booking or seating is
+// checked and written, so two hosts cannot take the same table at once,
+// also when they reach different servers. It waits up to claimWait while
+// someone else holds the claim, the claim of a server that died runs out
+// after lease.
+func claimTable(ctx context.Context, tables repository.TableRepository, tableId string, lease time.Duration) (func(), error) {
+	giveUp := time.After(claimWait)
+	for {
+		now := time.Now()
+		until := now.Add(lease).Truncate(time.Millisecond)
+		claimed, err := tables.Claim(ctx, tableId, now, until)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return func() {
+				//the request may have timed out already
+				ctx, cancel := context.WithTimeout(context.Background(), lease)
+				defer cancel()
+				if err := tables.Release(ctx, tableId, until); err != nil {
+					log.Printf("claim on table %s was not released: %v", tableId, err)
+				}
+			}, nil
+		}
+
+		if _, err := tables.FindByID(ctx, tableId); err == repository.ErrNotFound {
+			return nil, errTableNotFound
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errTableBusy
+		case <-giveUp:
+			return nil, errTableBusy
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// pickTable checks the requested table or, when there is none, takes the
+// smallest free table that seats the party
+func (rc *ReservationController) pickTable(ctx context.Context, reservation models.Reservation) (string, error) {
+	free, err := freeTables(ctx, rc.tables, rc.reservations, rc.orders, *reservation.Party_size, *reservation.Reservation_time, reservation.Ends_at, time.Now().Add(rc.turnTime), reservation.Reservation_id)
+	if err != nil {
+		return "", err
+	}
+
+	if reservation.Table_id == nil {
+		if len(free) == 0 {
+			return "", errNoTableFree
+		}
+		return free[0].Table_id, nil
+	}
+
+	for _, table := range free {
+		if table.Table_id == *reservation.Table_id {
+			return table.Table_id, nil
+		}
+	}
+	table, err := rc.tables.FindByID(ctx, *reservation.Table_id)
+	if err == repository.ErrNotFound {
+		return "", errTableNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if table.Number_of_guests == nil || *table.Number_of_guests < *reservation.Party_size {
+		return "", errTableTooSmall
+	}
+	return "", errTableTaken
+}
+
+func statusForBooking(err error) int {
+	switch err {
+	case errTableNotFound, errTableTooSmall:
+		return http.StatusBadRequest
+	case errTableTaken, errNoTableFree, errTableBusy:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// freeTables returns the tables seating partySize that no booked or seated
+// reservation holds between from and to, smallest first so large tables
+// stay open for large parties. A table with an open order is taken by its
+// guests until walkInsUntil, it is not free for a slot starting before
+// then. ignoreReservation is left out of the check, it is the reservation
+// being moved.
+func freeTables(ctx context.Context, tables repository.TableRepository, reservations repository.ReservationRepository, orders repository.OrderRepository, partySize int, from time.Time, to time.Time, walkInsUntil time.Time, ignoreReservation string) ([]models.Table, error) {
+	allTables, err := tables.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overlapping, err := reservations.ListOverlapping(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	held := map[string]bool{}
+	for _, reservation := range overlapping {
+		if reservation.Reservation_id != ignoreReservation && reservation.Table_id != nil {
+			held[*reservation.Table_id] = true
+		}
+	}
+	if from.Before(walkInsUntil) {
+		openOrders, err := orders.ListOpen(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range openOrders {
+			if order.Table_id != nil {
+				held[*order.Table_id] = true
+			}
+		}
+	}
+
+	free := []models.Table{}
+	for _, table := range allTables {
+		if table.Number_of_guests != nil && *table.Number_of_guests >= partySize && !held[table.Table_id] {
+			free = append(free, table)
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool { return *free[i].Number_of_guests < *free[j].Number_of_guests })
+	return free, nil
+}
+
+// tableOccupied reports whether the table still has an order that is not
+// paid, cancelled or voided
+func tableOccupied(ctx context.Context, orders repository.OrderRepository, tableId string) (bool, error) {
+	openOrders, err := orders.ListOpen(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, order := range openOrders {
+		if order.Table_id != nil && *order.Table_id == tableId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func equalStatus(status *string, value string) bool {
+	return status != nil && *status == value
+}
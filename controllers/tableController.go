@@ -3,68 +3,86 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
-	database "restaurant-management/database"
+	"restaurant-management/apierror"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var tableCollection *mongo.Collection = database.OpenCollection(database.Client, "table")
+type TableController struct {
+	tables repository.TableRepository
 
-func GetTables() gin.HandlerFunc {
+	timeout time.Duration
+}
+
+func NewTableController(tables repository.TableRepository, timeout time.Duration) *TableController {
+	return &TableController{tables: tables, timeout: timeout}
+}
+
+var tableListSpec = listSpec{
+	fields: map[string]listField{
+		"table_number":     {path: "table_number", kind: numberField, filter: true, sort: true},
+		"number_of_guests": {path: "number_of_guests", kind: numberField, filter: true, sort: true},
+		"created_at":       {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "table_number",
+}
+
+func (tc *TableController) GetTables() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		cursor, err := tableCollection.Find(context.TODO(), bson.M{})
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
 		defer cancel()
+
+		query, err := listQuery(c, tableListSpec)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing table items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
-		var allTables []bson.M
-		if err := cursor.All(ctx, &allTables); err != nil {
-			log.Fatal(err)
+		page, err := tc.tables.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing table items", err))
+			return
 		}
-
-		c.JSON(http.StatusOK, allTables)
+		c.JSON(http.StatusOK, page)
 	}
 }
-func GetTable() gin.HandlerFunc {
+func (tc *TableController) GetTable() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
 
-		tableId := c.Param("order_id")
-		var table models.Table
+		tableId := c.Param("table_id")
 
-		err := tableCollection.FindOne(ctx, bson.M{"order_id": tableId}).Decode(&table)
-		defer cancel()
+		table, err := tc.tables.FindByID(ctx, tableId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("table was not found"))
+			return
+		}
 		if err != nil {
 			msg := fmt.Sprintf("error cooured while fetching tables")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
 
 		c.JSON(http.StatusOK, table)
 	}
 }
-func CreateTable() gin.HandlerFunc {
+func (tc *TableController) CreateTable() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
 		var table models.Table
 
-		if err := c.BindJSON(&table); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&table); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 		if validationErr := validate.Struct(table); validationErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
 			return
 		}
 
@@ -73,68 +91,60 @@ func CreateTable() gin.HandlerFunc {
 		table.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 		table.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
-		result, insertErr := tableCollection.InsertOne(ctx, table)
-
-		if insertErr != nil {
+		if insertErr := tc.tables.Insert(ctx, table); insertErr != nil {
 			msg := fmt.Sprintf("Table item was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal(msg, insertErr))
 			return
 		}
 
-		defer cancel()
-
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, table)
 	}
 }
-func UpdateTable() gin.HandlerFunc {
+func (tc *TableController) UpdateTable() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
 
 		var table models.Table
 
-		var updateObj primitive.D
+		var update repository.TableUpdate
 
 		tableId := c.Param("table_id")
 
-		filter := bson.M{"table_id": tableId}
-
-		if err := c.BindJSON(&table); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&table); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
 			return
 		}
 
 		if table.Number_of_guests != nil {
-			updateObj = append(updateObj, bson.E{"number_of_guests", table.Number_of_guests})
+			update.Number_of_guests = table.Number_of_guests
 		}
 
 		if table.Table_number != nil {
-			updateObj = append(updateObj, bson.E{"table_number", table.Table_number})
+			update.Table_number = table.Table_number
 		}
 
 		table.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		updateObj = append(updateObj, bson.E{"updated_at", table.Updated_at})
+		update.Updated_at = table.Updated_at
 
-		upsert := true
-		opt := options.UpdateOptions{
-			Upsert: &upsert,
+		found, err := tc.tables.Update(ctx, tableId, update)
+		if err != nil {
+			msg := fmt.Sprintf("Table item update failed")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("table was not found"))
+			return
 		}
 
-		result, err := orderCollection.UpdateOne(
-			ctx,
-			filter,
-			bson.D{
-				{"$set", updateObj},
-			},
-			&opt,
-		)
-
+		updatedTable, err := tc.tables.FindByID(ctx, tableId)
 		if err != nil {
-			msg := fmt.Sprintf("Table item update failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			msg := fmt.Sprintf("error cooured while fetching tables")
+			c.Error(apierror.Internal(msg, err))
 			return
 		}
-		defer cancel()
-		c.JSON(http.StatusOK, result)
+		c.JSON(http.StatusOK, updatedTable)
 
 	}
 }
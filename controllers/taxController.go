@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errTaxRuleTarget = errors.New("a tax rule applies to a food_id or a category, not both")
+var errTaxRateNotFound = errors.New("tax rate was not found")
+var errFoodNotFound = errors.New("food was not found")
+
+type TaxController struct {
+	taxRates repository.TaxRateRepository
+	taxRules repository.TaxRuleRepository
+	foods    repository.FoodRepository
+
+	timeout time.Duration
+}
+
+func NewTaxController(taxRates repository.TaxRateRepository, taxRules repository.TaxRuleRepository, foods repository.FoodRepository, timeout time.Duration) *TaxController {
+	return &TaxController{taxRates: taxRates, taxRules: taxRules, foods: foods, timeout: timeout}
+}
+
+var taxRateListSpec = listSpec{
+	fields: map[string]listField{
+		"jurisdiction": {path: "jurisdiction", kind: textField, filter: true, sort: true},
+		"active":       {path: "active", kind: boolField, filter: true},
+		"name":         {path: "name", kind: textField, sort: true},
+		"rate":         {path: "rate", kind: numberField, sort: true},
+		"created_at":   {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "name",
+}
+
+func (tc *TaxController) GetTaxRates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, taxRateListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := tc.taxRates.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing tax rates", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (tc *TaxController) GetTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		taxRate, err := tc.taxRates.FindByID(ctx, c.Param("tax_rate_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("tax rate was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the tax rate", err))
+			return
+		}
+		c.JSON(http.StatusOK, taxRate)
+	}
+}
+
+func (tc *TaxController) CreateTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		var taxRate models.TaxRate
+		if err := c.ShouldBindJSON(&taxRate); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(taxRate); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		if taxRate.Active == nil {
+			active := true
+			taxRate.Active = &active
+		}
+		taxRate.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		taxRate.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		taxRate.ID = primitive.NewObjectID()
+		taxRate.Tax_rate_id = taxRate.ID.Hex()
+
+		if err := tc.taxRates.Insert(ctx, taxRate); err != nil {
+			c.Error(apierror.Internal("tax rate was not created", err))
+			return
+		}
+		c.JSON(http.StatusOK, taxRate)
+	}
+}
+
+// UpdateTaxRate changes the fields sent, invoices already issued keep the
+// tax they were created with. Rates are deactivated rather than deleted.
+func (tc *TaxController) UpdateTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		var taxRate models.TaxRate
+		if err := c.ShouldBindJSON(&taxRate); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		taxRateId := c.Param("tax_rate_id")
+
+		var update repository.TaxRateUpdate
+		if taxRate.Name != nil {
+			if len(*taxRate.Name) < 2 || len(*taxRate.Name) > 50 {
+				c.Error(apierror.BadRequest("name must be between 2 and 50 characters"))
+				return
+			}
+			update.Name = taxRate.Name
+		}
+		if taxRate.Rate != nil {
+			if *taxRate.Rate < 0 || *taxRate.Rate > 100 {
+				c.Error(apierror.BadRequest("rate must be between 0 and 100"))
+				return
+			}
+			update.Rate = taxRate.Rate
+		}
+		if taxRate.Inclusive != nil {
+			update.Inclusive = taxRate.Inclusive
+		}
+		if taxRate.Jurisdiction != nil {
+			update.Jurisdiction = taxRate.Jurisdiction
+		}
+		if taxRate.Active != nil {
+			update.Active = taxRate.Active
+		}
+
+		taxRate.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = taxRate.Updated_at
+
+		found, err := tc.taxRates.Update(ctx, taxRateId, update)
+		if err != nil {
+			c.Error(apierror.Internal("tax rate update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("tax rate was not found"))
+			return
+		}
+
+		updatedTaxRate, err := tc.taxRates.FindByID(ctx, taxRateId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the tax rate", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedTaxRate)
+	}
+}
+
+var taxRuleListSpec = listSpec{
+	fields: map[string]listField{
+		"tax_rate_id": {path: "tax_rate_id", kind: textField, filter: true},
+		"category":    {path: "category", kind: textField, filter: true, sort: true},
+		"food_id":     {path: "food_id", kind: textField, filter: true},
+		"active":      {path: "active", kind: boolField, filter: true},
+		"created_at":  {path: "created_at", kind: timeField, sort: true},
+	},
+	defaultSort: "created_at",
+}
+
+func (tc *TaxController) GetTaxRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, taxRuleListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := tc.taxRules.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing tax rules", err))
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (tc *TaxController) GetTaxRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		taxRule, err := tc.taxRules.FindByID(ctx, c.Param("tax_rule_id"))
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("tax rule was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the tax rule", err))
+			return
+		}
+		c.JSON(http.StatusOK, taxRule)
+	}
+}
+
+// CreateTaxRule charges a rate on one food, on a menu category, or when
+// neither is given on every food without a more specific rule
+func (tc *TaxController) CreateTaxRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		var taxRule models.TaxRule
+		if err := c.ShouldBindJSON(&taxRule); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(taxRule); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+		if status, err := tc.checkTaxRule(ctx, taxRule); err != nil {
+			c.Error(apierror.Wrap(status, err))
+			return
+		}
+
+		if taxRule.Active == nil {
+			active := true
+			taxRule.Active = &active
+		}
+		taxRule.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		taxRule.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		taxRule.ID = primitive.NewObjectID()
+		taxRule.Tax_rule_id = taxRule.ID.Hex()
+
+		if err := tc.taxRules.Insert(ctx, taxRule); err != nil {
+			c.Error(apierror.Internal("tax rule was not created", err))
+			return
+		}
+		c.JSON(http.StatusOK, taxRule)
+	}
+}
+
+func (tc *TaxController) UpdateTaxRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		var taxRule models.TaxRule
+		if err := c.ShouldBindJSON(&taxRule); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		taxRuleId := c.Param("tax_rule_id")
+
+		existing, err := tc.taxRules.FindByID(ctx, taxRuleId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("tax rule was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the tax rule", err))
+			return
+		}
+
+		var update repository.TaxRuleUpdate
+		if taxRule.Tax_rate_id != nil {
+			existing.Tax_rate_id = taxRule.Tax_rate_id
+			update.Tax_rate_id = taxRule.Tax_rate_id
+		}
+		if taxRule.Category != nil {
+			existing.Category = taxRule.Category
+			update.Category = taxRule.Category
+		}
+		if taxRule.Food_id != nil {
+			existing.Food_id = taxRule.Food_id
+			update.Food_id = taxRule.Food_id
+		}
+		if taxRule.Active != nil {
+			update.Active = taxRule.Active
+		}
+		if status, err := tc.checkTaxRule(ctx, existing); err != nil {
+			c.Error(apierror.Wrap(status, err))
+			return
+		}
+
+		taxRule.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update.Updated_at = taxRule.Updated_at
+
+		found, err := tc.taxRules.Update(ctx, taxRuleId, update)
+		if err != nil {
+			c.Error(apierror.Internal("tax rule update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("tax rule was not found"))
+			return
+		}
+
+		updatedTaxRule, err := tc.taxRules.FindByID(ctx, taxRuleId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the tax rule", err))
+			return
+		}
+		c.JSON(http.StatusOK, updatedTaxRule)
+	}
+}
+
+// checkTaxRule makes sure the rate and the food a rule names exist and that
+// it does not name both a food and a category
+func (tc *TaxController) checkTaxRule(ctx context.Context, taxRule models.TaxRule) (int, error) {
+	hasFood := taxRule.Food_id != nil && *taxRule.Food_id != ""
+	hasCategory := taxRule.Category != nil && strings.TrimSpace(*taxRule.Category) != ""
+	if hasFood && hasCategory {
+		return http.StatusBadRequest, errTaxRuleTarget
+	}
+
+	if _, err := tc.taxRates.FindByID(ctx, *taxRule.Tax_rate_id); err == repository.ErrNotFound {
+		return http.StatusBadRequest, errTaxRateNotFound
+	} else if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if hasFood {
+		if _, err := tc.foods.FindByID(ctx, *taxRule.Food_id); err == repository.ErrNotFound {
+			return http.StatusBadRequest, errFoodNotFound
+		} else if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+	return http.StatusOK, nil
+}
@@ -5,205 +5,392 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	database "restaurant-management/database"
+	"restaurant-management/apierror"
 	"restaurant-management/helpers"
 	"restaurant-management/models"
-	"strconv"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
+type UserController struct {
+	users repository.UserRepository
 
-func GetUsers() gin.HandlerFunc {
+	bcryptCost int
+	timeout    time.Duration
+}
+
+func NewUserController(users repository.UserRepository, bcryptCost int, timeout time.Duration) *UserController {
+	return &UserController{users: users, bcryptCost: bcryptCost, timeout: timeout}
+}
+
+var userListSpec = listSpec{
+	fields: map[string]listField{
+		"role":       {path: "role", kind: textField, filter: true, sort: true},
+		"email":      {path: "email", kind: textField, filter: true, sort: true},
+		"first_name": {path: "first_name", kind: textField, sort: true},
+		"last_name":  {path: "last_name", kind: textField, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "created_at",
+}
+
+func (uc *UserController) GetUsers() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+		defer cancel()
 
-		recordePerPage, err := strconv.Atoi(c.Query("recordePerPage"))
-		if err != nil || recordePerPage < 1 {
-			recordePerPage = 10
+		query, err := listQuery(c, userListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
 		}
+		page, err := uc.users.Page(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing user items", err))
+			return
+		}
+		c.JSON(http.StatusOK, repository.MapPage(page, models.User.Profile))
+	}
+}
+func (uc *UserController) GetUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+		defer cancel()
+
+		userId := c.Param("user_id")
 
-		page, err1 := strconv.Atoi(c.Query("page"))
-		if err1 != nil || page < 1 {
-			page = 1
+		user, err := uc.users.FindByID(ctx, userId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("user was not found"))
+			return
 		}
-		startIndex := (page - 1) * recordePerPage
+		if err != nil {
+			msg := fmt.Sprintf("error occured while listing user items")
+			c.Error(apierror.Internal(msg, err))
+			return
+		}
+		c.JSON(http.StatusOK, user.Profile())
+	}
+}
+
+// SignupResult is the ID of the new user
+type SignupResult struct {
+	InsertedID primitive.ObjectID `json:"InsertedID"`
+}
 
-		startIndex, err = strconv.Atoi(c.Query("startIndex"))
+func (uc *UserController) Sugnup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
 		defer cancel()
+		var user models.User
+
+		//convert the JSON data coming from postman to something that golang understands
+		if err := c.ShouldBindJSON(&user); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+
+		//validate the data based on user struct
+		if valivatorErr := validate.Struct(&user); valivatorErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, valivatorErr))
+			return
+		}
+
+		//you'll check if the email or the phone no. has already been used by another user
+		count, err := uc.users.CountByEmailOrPhone(ctx, *user.Email, *user.Phone)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(apierror.Internal("error occured while checking for the email", err))
 			return
 		}
 
-		matchStage := bson.D{{Key: "$match", Value: bson.D{{}}}}
+		if count > 0 {
+			c.Error(apierror.Conflict("this email or phone number alresdy exist").WithCode(apierror.CodeAlreadyExists))
+			return
+		}
 
-		projectStage := bson.D{
-			{Key: "$project", Value: bson.D{
-				{Key: "_id", Value: 0},
-				{Key: "total_count", Value: 1},
-				{Key: "user_items", Value: bson.D{{Key: "$slice", Value: []interface{}{"$data", startIndex, recordePerPage}}}},
-			}}}
+		//hash password
+		password := HashPassword(*user.Password, uc.bcryptCost)
+		user.Password = &password
 
-		cursor, err := userCollection.Aggregate(ctx, mongo.Pipeline{
-			matchStage, projectStage,
-		})
-		defer cancel()
+		//only an admin can hand out roles, anybody else signs up without one
+		//and can do nothing until an admin gives them one. The very first
+		//user becomes the admin so the system can be bootstrapped, when two
+		//sign up at once only one of them is stored as the first
+		if c.GetString("role") != models.RoleAdmin {
+			user.Role = nil
+		}
+		total, err := uc.users.Count(ctx)
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing user items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal("error occured while counting users", err))
 			return
 		}
-
-		var allUsers []bson.M
-		if err := cursor.All(ctx, &allUsers); err != nil {
-			log.Fatal(err)
+		if total == 0 {
+			admin := models.RoleAdmin
+			user.Role = &admin
+			user, err = uc.insertUser(ctx, user, uc.users.InsertFirst)
+			if err == repository.ErrDuplicate {
+				user.Role = nil
+				user, err = uc.insertUser(ctx, user, uc.users.Insert)
+			}
+		} else {
+			user, err = uc.insertUser(ctx, user, uc.users.Insert)
+		}
+		if err != nil {
+			msg := fmt.Sprintf("User item was not created")
+			c.Error(apierror.Internal(msg, err))
+			return
 		}
-		c.JSON(http.StatusOK, allUsers[0])
+
+		//return status OK and send the result back
+		c.JSON(http.StatusOK, SignupResult{InsertedID: user.ID})
 
 	}
 }
-func GetUser() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 
-		var user models.User
+// insertUser gives user its ID and tokens and stores it with insert
+func (uc *UserController) insertUser(ctx context.Context, user models.User, insert func(context.Context, models.User) error) (models.User, error) {
+	//create some extra details for the user object - created_at, updated_at, ID
+	user.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	user.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	user.ID = primitive.NewObjectID()
+	user.User_id = user.ID.Hex()
 
-		userId := c.Param("user_id")
+	//generate token and refersh token (generate all tokens function from helper)
+	token, refreshToken, err := helpers.GenerateAllTokens(*user.Email, *user.First_name, *user.Last_name, user.User_id, userRole(user))
+	if err != nil {
+		return user, err
+	}
+	user.Token = &token
+	user.Refresh_Token = &refreshToken
 
-		err := userCollection.FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+	return user, insert(ctx, user)
+}
+
+type LoginRequest struct {
+	Email    *string `json:"email" validate:"required"`
+	Password *string `json:"password" validate:"required"`
+}
 
+// Login answers with a new token pair, the tokens of an earlier login stop
+// working since a user has one session at a time
+func (uc *UserController) Login() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
 		defer cancel()
+		var user LoginRequest
 
+		//convert the login data from postman which is in JSON to golang readable format
+		if err := c.ShouldBindJSON(&user); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if user.Email == nil || user.Password == nil {
+			c.Error(apierror.BadRequest("please provide email and password"))
+			return
+		}
+
+		//find a user with that email and see if that user even exists
+		//an unknown email and a wrong password get the same answer, so
+		//nobody can probe which emails have accounts
+		foundUser, err := uc.users.FindByEmail(ctx, *user.Email)
+		if err == repository.ErrNotFound {
+			c.Error(errInvalidCredentials())
+			return
+		}
 		if err != nil {
-			msg := fmt.Sprintf("error occured while listing user items")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+			c.Error(apierror.Internal("error occured while fetching the user", err))
 			return
 		}
-		c.JSON(http.StatusOK, user)
+
+		//then you will verify the password
+		if passwordIsValid, _ := VerifyPassword(*user.Password, *foundUser.Password); !passwordIsValid {
+			c.Error(errInvalidCredentials())
+			return
+		}
+
+		//if all goes well, then you'll generate tokens
+		token, refreshToken, err := helpers.GenerateAllTokens(*foundUser.Email, *foundUser.First_name, *foundUser.Last_name, foundUser.User_id, userRole(foundUser))
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
+			return
+		}
+
+		//update tokens - token and refersh token
+		if err := helpers.UpdateAllTokens(ctx, uc.users, token, refreshToken, foundUser.User_id); err != nil {
+			c.Error(apierror.Internal("error occured while storing tokens", err))
+			return
+		}
+
+		//return statusOK, only the new tokens are sent back
+		c.JSON(http.StatusOK, TokenPair{Token: token, Refresh_token: refreshToken})
+
 	}
 }
-func Sugnup() gin.HandlerFunc {
+
+type RefreshRequest struct {
+	Refresh_token string `json:"refresh_token" validate:"required"`
+}
+
+type TokenPair struct {
+	Token         string `json:"token"`
+	Refresh_token string `json:"refresh_token"`
+}
+
+// Message says what was done when there is nothing else to answer with
+type Message struct {
+	Message string `json:"message"`
+}
+
+func (uc *UserController) RefreshToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-		var user models.User
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+		defer cancel()
 
-		//convert the JSON data coming from postman to something that golang understands
-		if err := c.BindJSON(&user); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var request RefreshRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(request); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
 			return
 		}
 
-		//validate the data based on user struct
-		if valivatorErr := validate.Struct(&user); valivatorErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": valivatorErr.Error()})
+		claims, msg := helpers.ValidateRefreshToken(request.Refresh_token)
+		if msg != "" {
+			c.Error(apierror.Unauthorized(msg))
 			return
 		}
 
-		//you'll check if the email has already been used by another user
-		count, err := userCollection.CountDocuments(ctx, bson.M{"email": user.Email})
-		defer cancel()
+		foundUser, err := uc.users.FindByID(ctx, claims.Uid)
 		if err != nil {
-			log.Panic(err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while checking for the email"})
+			c.Error(apierror.Unauthorized("user was not found"))
 			return
 		}
 
-		//hash password
-		password := HashPassword(*user.Password)
-		user.Password = &password
-
-		//you'll also check if the phone no. has already been used by another user
-		count, err = userCollection.CountDocuments(ctx, bson.M{"phone": user.Phone})
-		defer cancel()
+		token, refreshToken, err := helpers.GenerateAllTokens(*foundUser.Email, *foundUser.First_name, *foundUser.Last_name, foundUser.User_id, userRole(foundUser))
 		if err != nil {
-			log.Panic(err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while checking for the phone number"})
+			c.Error(apierror.Wrap(http.StatusInternalServerError, err))
 			return
 		}
 
-		if count > 0 {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "this email or phone number alresdy exist"})
+		//the old refresh token is swapped out, using it a second time fails
+		rotated, err := helpers.RotateAllTokens(ctx, uc.users, token, refreshToken, foundUser.User_id, request.Refresh_token)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while rotating tokens", err))
+			return
+		}
+		if !rotated {
+			c.Error(apierror.Unauthorized("refresh token has been revoked or already used"))
 			return
 		}
 
-		//create some extra details for the user object - created_at, updated_at, ID
-		user.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		user.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-		user.ID = primitive.NewObjectID()
-		user.User_id = user.ID.Hex()
+		c.JSON(http.StatusOK, TokenPair{Token: token, Refresh_token: refreshToken})
+	}
+}
 
-		//generate token and refersh token (generate all tokens function from helper)
-		token, refreshToken, _ := helpers.GenerateAllTokens(*user.Email, *user.First_name, *user.Last_name, *user.Phone)
-		user.Token = &token
-		user.Refresh_Token = &refreshToken
+func (uc *UserController) Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+		defer cancel()
 
-		//if all ok, then you insert this new user into the user collection
-		resultInsertionNumber, insertErr := userCollection.InsertOne(ctx, user)
-		if insertErr != nil {
-			msg := fmt.Sprintf("User item was not created")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+		if _, err := helpers.RevokeAllTokens(ctx, uc.users, c.GetString("uid")); err != nil {
+			c.Error(apierror.Internal("error occured while logging out", err))
 			return
 		}
+		c.JSON(http.StatusOK, Message{Message: "logged out"})
+	}
+}
+
+func (uc *UserController) RevokeUserSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
 		defer cancel()
 
-		//return status OK and send the result back
-		c.JSON(http.StatusOK, resultInsertionNumber)
+		userId := c.Param("user_id")
 
+		found, err := helpers.RevokeAllTokens(ctx, uc.users, userId)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while revoking sessions", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("user was not found"))
+			return
+		}
+		c.JSON(http.StatusOK, Message{Message: "all sessions revoked"})
 	}
 }
-func Login() gin.HandlerFunc {
+
+func (uc *UserController) UpdateUserRole() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+		defer cancel()
+
 		var user models.User
-		var foundUser models.User
+		userId := c.Param("user_id")
 
-		//convert the login data from postman which is in JSON to golang readable format
-		defer cancel()
-		if err := c.BindJSON(user); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := c.ShouldBindJSON(&user); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if user.Role == nil {
+			c.Error(apierror.BadRequest("please provide role"))
+			return
+		}
+		if validationErr := validate.Var(*user.Role, "eq=ADMIN|eq=MANAGER|eq=WAITER|eq=COOK|eq=CASHIER"); validationErr != nil {
+			c.Error(apierror.BadRequest("role must be one of ADMIN, MANAGER, WAITER, COOK or CASHIER"))
 			return
 		}
 
-		//find a user with that email and see if that user even exists
-		err := userCollection.FindOne(ctx, bson.M{"email": user.Email}).Decode(&foundUser)
-		defer cancel()
+		user.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		found, err := uc.users.UpdateRole(ctx, userId, *user.Role, user.Updated_at)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "This email id not correct"})
+			c.Error(apierror.Internal("user role update failed", err))
+			return
+		}
+		if !found {
+			c.Error(apierror.NotFound("user was not found"))
 			return
 		}
 
-		//then you will verify the password
-		passwordIsValid, msg := VerifyPassword(*user.Password, *foundUser.Password)
-		if !passwordIsValid {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+		//tokens carry the role, the old ones must not outlive it
+		if _, err := helpers.RevokeAllTokens(ctx, uc.users, userId); err != nil {
+			c.Error(apierror.Internal("error occured while revoking sessions", err))
 			return
 		}
 
-		//if all goes well, then you'll generate tokens
-		token, refreshToken, err := helpers.GenerateAllTokens(*foundUser.Email, *foundUser.First_name, *foundUser.Last_name, foundUser.User_id)
+		updatedUser, err := uc.users.FindByID(ctx, userId)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(apierror.Internal("error occured while listing user items", err))
 			return
 		}
+		c.JSON(http.StatusOK, updatedUser.Profile())
+	}
+}
 
-		//update tokens - token and refersh token
-		helpers.UpdateAllTokens(token, refreshToken, foundUser.User_id)
-
-		//return statusOK
-		c.JSON(http.StatusOK, foundUser)
+// errInvalidCredentials is the one answer login gives for a bad email or password
+func errInvalidCredentials() *apierror.Error {
+	return apierror.Unauthorized("email or password is incorrect").WithCode(apierror.CodeInvalidCredentials)
+}
 
+// userRole returns the role stored on the user, users created before
+// roles existed have none and get no permissions beyond authentication
+func userRole(user models.User) string {
+	if user.Role == nil {
+		return ""
 	}
+	return *user.Role
 }
 
-func HashPassword(password string) string {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+func HashPassword(password string, cost int) string {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -0,0 +1,359 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WaitlistController struct {
+	waitlist     repository.WaitlistRepository
+	reservations repository.ReservationRepository
+	tables       repository.TableRepository
+	orders       repository.OrderRepository
+
+	turnTime time.Duration
+	timeout  time.Duration
+}
+
+func NewWaitlistController(waitlist repository.WaitlistRepository, reservations repository.ReservationRepository, tables repository.TableRepository, orders repository.OrderRepository, turnTime time.Duration, timeout time.Duration) *WaitlistController {
+	return &WaitlistController{waitlist: waitlist, reservations: reservations, tables: tables, orders: orders, turnTime: turnTime, timeout: timeout}
+}
+
+var waitlistListSpec = listSpec{
+	fields: map[string]listField{
+		"party_size": {path: "party_size", kind: numberField, filter: true, sort: true},
+		"created_at": {path: "created_at", kind: timeField, sort: true},
+	},
+	dateField:   "created_at",
+	defaultSort: "created_at",
+}
+
+// GetWaitlist returns the waiting parties, first come first by default,
+// with a fresh wait estimate from their place in the whole queue
+func (wc *WaitlistController) GetWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), wc.timeout)
+		defer cancel()
+
+		query, err := listQuery(c, waitlistListSpec)
+		if err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		page, err := wc.waitlist.PageWaiting(ctx, query)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the waitlist", err))
+			return
+		}
+
+		waiting, err := wc.waitlist.ListWaiting(ctx)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the waitlist", err))
+			return
+		}
+		waits, err := wc.estimateWaits(ctx, waiting)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while estimating wait times", err))
+			return
+		}
+		estimates := map[string]*int{}
+		for i, entry := range waiting {
+			estimates[entry.Waitlist_id] = waits[i]
+		}
+		for i := range page.Items {
+			page.Items[i].Estimated_wait_minutes = estimates[page.Items[i].Waitlist_id]
+		}
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+func (wc *WaitlistController) AddToWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), wc.timeout)
+		defer cancel()
+		var entry models.WaitlistEntry
+
+		if err := c.ShouldBindJSON(&entry); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(entry); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		entry.Created_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		entry.Updated_at, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+		waiting, err := wc.waitlist.ListWaiting(ctx)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while listing the waitlist", err))
+			return
+		}
+		waits, err := wc.estimateWaits(ctx, append(waiting, entry))
+		if err != nil {
+			c.Error(apierror.Internal("error occured while estimating wait times", err))
+			return
+		}
+		quoted := waits[len(waits)-1]
+		if quoted == nil {
+			c.Error(apierror.BadRequest(fmt.Sprintf("no table seats a party of %d", *entry.Party_size)))
+			return
+		}
+
+		status := models.WaitlistWaiting
+		entry.Status = &status
+		entry.Quoted_wait_minutes = *quoted
+		entry.Estimated_wait_minutes = quoted
+		entry.Table_id = nil
+		entry.Order_id = nil
+		entry.Seated_at = nil
+		entry.ID = primitive.NewObjectID()
+		entry.Waitlist_id = entry.ID.Hex()
+
+		if err := wc.waitlist.Insert(ctx, entry); err != nil {
+			c.Error(apierror.Internal("waitlist entry was not created", err))
+			return
+		}
+		c.JSON(http.StatusOK, entry)
+	}
+}
+
+type WaitlistSeating struct {
+	Table_id *string `json:"table_id" validate:"required"`
+}
+
+type SeatedWaitlistEntry struct {
+	Waitlist_entry models.WaitlistEntry `json:"waitlist_entry"`
+	Order          models.Order         `json:"order"`
+}
+
+// SeatWaitlistEntry puts a waiting party on the given table, the table must
+// be big enough, have no open order and not be reserved for the next turn.
+func (wc *WaitlistController) SeatWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), wc.timeout)
+		defer cancel()
+		var seating WaitlistSeating
+
+		waitlistId := c.Param("waitlist_id")
+		if err := c.ShouldBindJSON(&seating); err != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, err))
+			return
+		}
+		if validationErr := validate.Struct(seating); validationErr != nil {
+			c.Error(apierror.Wrap(http.StatusBadRequest, validationErr))
+			return
+		}
+
+		entry, err := wc.waitlist.FindByID(ctx, waitlistId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("waitlist entry was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the waitlist entry", err))
+			return
+		}
+		if !equalStatus(entry.Status, models.WaitlistWaiting) {
+			c.Error(apierror.Conflict("only waiting parties can be seated"))
+			return
+		}
+
+		release, err := claimTable(ctx, wc.tables, *seating.Table_id, wc.timeout)
+		if err != nil {
+			c.Error(apierror.Wrap(statusForBooking(err), err))
+			return
+		}
+		defer release()
+
+		now, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		occupied, err := tableOccupied(ctx, wc.orders, *seating.Table_id)
+		if err != nil {
+			c.Error(apierror.Internal("error occured while checking the table", err))
+			return
+		}
+		if occupied {
+			c.Error(apierror.Conflict("the table still has an open order"))
+			return
+		}
+		free, err := freeTables(ctx, wc.tables, wc.reservations, wc.orders, *entry.Party_size, now, now.Add(wc.turnTime), now.Add(wc.turnTime), "")
+		if err != nil {
+			c.Error(apierror.Internal("error occured while checking the table", err))
+			return
+		}
+		if !containsTable(free, *seating.Table_id) {
+			c.Error(apierror.Conflict("the table is too small or reserved"))
+			return
+		}
+
+		order, err := placeOrder(ctx, wc.orders, wc.tables, models.Order{Order_date: now, Table_id: seating.Table_id, Covers: entry.Party_size}, c.GetString("uid"))
+		if err != nil {
+			c.Error(apierror.Internal("order was not created", err))
+			return
+		}
+
+		status := models.WaitlistSeated
+		update := repository.WaitlistUpdate{
+			Status:     &status,
+			Table_id:   seating.Table_id,
+			Order_id:   &order.Order_id,
+			Seated_at:  &now,
+			Updated_at: now,
+		}
+		updated, err := wc.waitlist.UpdateStatus(ctx, waitlistId, entry.Status, update)
+		if err != nil {
+			c.Error(apierror.Internal("waitlist entry update failed", err))
+			return
+		}
+		if !updated {
+			//the party was seated elsewhere or left meanwhile, nobody sits
+			//at the order
+			if _, err := changeOrderStatus(ctx, wc.orders, order, models.OrderCancelled, c.GetString("uid")); err != nil {
+				c.Error(apierror.Internal("error occured while cancelling the unused order", err))
+				return
+			}
+			c.Error(apierror.Conflict("waitlist entry was changed by someone else"))
+			return
+		}
+
+		entry.Status = &status
+		entry.Table_id = seating.Table_id
+		entry.Order_id = &order.Order_id
+		entry.Seated_at = &now
+		entry.Updated_at = now
+		c.JSON(http.StatusOK, SeatedWaitlistEntry{Waitlist_entry: entry, Order: order})
+	}
+}
+
+// LeaveWaitlist takes a party that walked away off the waitlist
+func (wc *WaitlistController) LeaveWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), wc.timeout)
+		defer cancel()
+
+		waitlistId := c.Param("waitlist_id")
+		from := models.WaitlistWaiting
+		status := models.WaitlistLeft
+
+		updatedAt, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+		update := repository.WaitlistUpdate{Status: &status, Updated_at: updatedAt}
+		updated, err := wc.waitlist.UpdateStatus(ctx, waitlistId, &from, update)
+		if err != nil {
+			c.Error(apierror.Internal("waitlist entry update failed", err))
+			return
+		}
+
+		entry, err := wc.waitlist.FindByID(ctx, waitlistId)
+		if err == repository.ErrNotFound {
+			c.Error(apierror.NotFound("waitlist entry was not found"))
+			return
+		}
+		if err != nil {
+			c.Error(apierror.Internal("error occured while fetching the waitlist entry", err))
+			return
+		}
+		if !updated {
+			c.Error(apierror.Conflict("only waiting parties can leave the waitlist"))
+			return
+		}
+		c.JSON(http.StatusOK, entry)
+	}
+}
+
+// estimateWaits walks the waitlist in order and gives each party the fitting
+// table that frees up first, assuming every seating lasts turnTime. A table
+// with an open order frees up turnTime after the order was placed, and is
+// skipped past any reservation that would overlap the party's turn. Parties
+// no table can seat get nil.
+func (wc *WaitlistController) estimateWaits(ctx context.Context, waiting []models.WaitlistEntry) ([]*int, error) {
+	now := time.Now()
+
+	allTables, err := wc.tables.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	openOrders, err := wc.orders.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	upcoming, err := wc.reservations.ListOverlapping(ctx, now, now.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	freeAt := map[string]time.Time{}
+	for _, table := range allTables {
+		freeAt[table.Table_id] = now
+	}
+	for _, order := range openOrders {
+		if order.Table_id == nil {
+			continue
+		}
+		if expected := order.Created_at.Add(wc.turnTime); expected.After(freeAt[*order.Table_id]) {
+			freeAt[*order.Table_id] = expected
+		}
+	}
+	reserved := map[string][]models.Reservation{}
+	for _, reservation := range upcoming {
+		if reservation.Table_id != nil {
+			reserved[*reservation.Table_id] = append(reserved[*reservation.Table_id], reservation)
+		}
+	}
+
+	waits := make([]*int, len(waiting))
+	for i, entry := range waiting {
+		var bestTable string
+		var bestStart time.Time
+		for _, table := range allTables {
+			if table.Number_of_guests == nil || *table.Number_of_guests < *entry.Party_size {
+				continue
+			}
+			start := clearOfReservations(freeAt[table.Table_id], wc.turnTime, reserved[table.Table_id])
+			if bestTable == "" || start.Before(bestStart) {
+				bestTable, bestStart = table.Table_id, start
+			}
+		}
+		if bestTable == "" {
+			continue
+		}
+
+		freeAt[bestTable] = bestStart.Add(wc.turnTime)
+		minutes := int(math.Ceil(bestStart.Sub(now).Minutes()))
+		waits[i] = &minutes
+	}
+	return waits, nil
+}
+
+// clearOfReservations moves start past every reservation that overlaps
+// start to start+turnTime
+func clearOfReservations(start time.Time, turnTime time.Duration, reservations []models.Reservation) time.Time {
+	for moved := true; moved; {
+		moved = false
+		for _, reservation := range reservations {
+			if reservation.Reservation_time.Before(start.Add(turnTime)) && reservation.Ends_at.After(start) {
+				start = reservation.Ends_at
+				moved = true
+			}
+		}
+	}
+	return start
+}
+
+func containsTable(tables []models.Table, tableId string) bool {
+	for _, table := range tables {
+		if table.Table_id == tableId {
+			return true
+		}
+	}
+	return false
+}
@@ -4,20 +4,27 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
+	"restaurant-management/config"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func DBinstance() *mongo.Client {
-	MongoDb := "mongodb://localhost:27017"
-	fmt.Print(MongoDb)
-	client, err := mongo.NewClient(options.Client().ApplyURI(MongoDb))
+func DBinstance(cfg config.MongoConfig) *mongo.Client {
+	clientOptions := options.Client().ApplyURI(cfg.Uri)
+	if cfg.Username != "" {
+		clientOptions.SetAuth(options.Credential{
+			AuthSource: cfg.Auth_source,
+			Username:   cfg.Username,
+			Password:   cfg.Password,
+		})
+	}
+
+	client, err := mongo.NewClient(clientOptions)
 	if err != nil {
 		log.Fatal(err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Connect_timeout.Duration)
 	defer cancel()
 	err = client.Connect(ctx)
 	if err != nil {
@@ -27,10 +34,12 @@ func DBinstance() *mongo.Client {
 	return client
 }
 
-var Client *mongo.Client = DBinstance()
+func OpenDatabase(client *mongo.Client, databaseName string) *mongo.Database {
+	return client.Database(databaseName)
+}
 
-func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collection {
-	var collection *mongo.Collection = client.Database("restautant").Collection(collectionName)
+func OpenCollection(database *mongo.Database, collectionName string) *mongo.Collection {
+	var collection *mongo.Collection = database.Collection(collectionName)
 	return collection
 
 }
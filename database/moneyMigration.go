@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// moneyFields are the amounts that used to be stored as doubles
+var moneyFields = map[string][]string{
+	"food":      {"price"},
+	"orderItem": {"unit_price"},
+	"invoice":   {"net_amount", "tax_amount", "total_amount", "amount_paid", "balance_due", "change_due"},
+	"payment":   {"amount", "tendered", "change_due"},
+}
+
+// MigrateMoney rewrites amounts stored as plain numbers into
+// {amount: <cents>, currency: <currency>} documents. Only numeric fields
+// are touched, so running it again after a partial run is safe.
+func MigrateMoney(ctx context.Context, db *mongo.Database, currency string) error {
+	for collectionName, fields := range moneyFields {
+		collection := OpenCollection(db, collectionName)
+		for _, field := range fields {
+			filter := bson.M{field: bson.M{"$type": "number"}}
+			update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: moneyExpression("$"+field, currency)}}}}}
+			result, err := collection.UpdateMany(ctx, filter, update)
+			if err != nil {
+				return fmt.Errorf("migrating %s.%s: %w", collectionName, field, err)
+			}
+			log.Printf("migrated %d %s.%s amounts", result.ModifiedCount, collectionName, field)
+		}
+	}
+
+	//the tax breakdown keeps its amounts inside an array
+	filter := bson.M{"tax_breakdown": bson.M{"$elemMatch": bson.M{"tax_amount": bson.M{"$type": "number"}}}}
+	update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{{Key: "tax_breakdown", Value: bson.D{{Key: "$map", Value: bson.D{
+		{Key: "input", Value: "$tax_breakdown"},
+		{Key: "in", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{"$$this", bson.D{
+			{Key: "taxable_amount", Value: moneyExpression("$$this.taxable_amount", currency)},
+			{Key: "tax_amount", Value: moneyExpression("$$this.tax_amount", currency)},
+		}}}}},
+	}}}}}}}}
+	result, err := OpenCollection(db, "invoice").UpdateMany(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("migrating invoice.tax_breakdown: %w", err)
+	}
+	log.Printf("migrated %d invoice.tax_breakdown amounts", result.ModifiedCount)
+	return nil
+}
+
+// moneyExpression converts the number at path to cents, documents already
+// converted are left alone
+func moneyExpression(path string, currency string) bson.D {
+	return bson.D{{Key: "$cond", Value: bson.D{
+		{Key: "if", Value: bson.D{{Key: "$isNumber", Value: path}}},
+		{Key: "then", Value: bson.D{
+			{Key: "amount", Value: bson.D{{Key: "$toLong", Value: bson.D{{Key: "$round", Value: bson.A{bson.D{{Key: "$multiply", Value: bson.A{path, 100}}}, 0}}}}}},
+			{Key: "currency", Value: currency},
+		}},
+		{Key: "else", Value: path},
+	}}}
+}
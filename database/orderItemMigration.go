@@ -0,0 +1,27 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrateOrderItems moves the size older versions kept in quantity ("S",
+// "M" or "L") into variant and sets quantity to 1. Items without a line
+// price keep being charged the food price, as they were before.
+func MigrateOrderItems(ctx context.Context, db *mongo.Database) error {
+	filter := bson.M{"quantity": bson.M{"$type": "string"}}
+	update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{
+		{Key: "variant", Value: "$quantity"},
+		{Key: "quantity", Value: 1},
+	}}}}
+	result, err := OpenCollection(db, "orderItem").UpdateMany(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("migrating orderItem.quantity: %w", err)
+	}
+	log.Printf("migrated %d orderItem.quantity sizes", result.ModifiedCount)
+	return nil
+}
@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// ImageTypes are the uploads accepted, by the content type sniffed from
+// their first bytes, with the extension they are stored under
+var ImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// MaxImagePixels keeps a small file from decoding into a huge bitmap
+const MaxImagePixels = 40_000_000
+
+var ErrImageType = errors.New("image must be a JPEG, PNG or GIF")
+
+// DecodeImage sniffs the content type of data and decodes it, GIFs give
+// their first frame
+func DecodeImage(data []byte) (image.Image, string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := ImageTypes[contentType]; !ok {
+		return nil, contentType, ErrImageType
+	}
+	size, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, contentType, errors.New("image could not be read")
+	}
+	if size.Width <= 0 || size.Height <= 0 || size.Width*size.Height > MaxImagePixels {
+		return nil, contentType, errors.New("image has too many pixels")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, contentType, errors.New("image could not be read")
+	}
+	return img, contentType, nil
+}
+
+// Thumbnail scales img down to width keeping its aspect ratio, every pixel
+// is the average of the pixels it covers. Images already narrower are
+// returned as they are.
+func Thumbnail(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Dx() <= width {
+		return img
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*src.Rect.Dy()/height, (y+1)*src.Rect.Dy()/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*src.Rect.Dx()/width, (x+1)*src.Rect.Dx()/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row[sx*4 : sx*4+4]
+					//weighted by alpha so transparent pixels do not darken edges
+					alpha := int(pixel[3])
+					r += int(pixel[0]) * alpha
+					g += int(pixel[1]) * alpha
+					b += int(pixel[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+			out := dst.Pix[y*dst.Stride+x*4:]
+			if a > 0 {
+				out[0], out[1], out[2] = uint8(r/a), uint8(g/a), uint8(b/a)
+			}
+			out[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// EncodeThumbnail writes JPEG thumbnails of JPEG uploads and PNG ones of
+// everything else, which keeps transparency
+func EncodeThumbnail(img image.Image, contentType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), "image/png", err
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"fmt"
+	"restaurant-management/config"
+	"restaurant-management/models"
+	"strconv"
+	"time"
+)
+
+// InvoiceDocument is everything printed on an invoice, the PDF and the
+// thermal receipt render the same document.
+type InvoiceDocument struct {
+	Restaurant     config.RestaurantConfig
+	Invoice_id     string
+	Order_id       string
+	Table_number   *int
+	Lines          []InvoiceLine
+	Items_total    models.Money
+	Discounts      []InvoiceDiscount
+	Subtotal       models.Money
+	Tax            models.Money
+	Taxes          []models.TaxLine
+	Total          models.Money
+	Amount_paid    models.Money
+	Balance_due    models.Money
+	Change_due     models.Money
+	Payments       []InvoicePayment
+	Payment_method string
+	Payment_status string
+	Issued_at      time.Time
+	Due_date       time.Time
+}
+
+type InvoiceLine struct {
+	Name      string
+	Quantity  int
+	Variant   string
+	Modifiers []string
+	Amount    models.Money
+}
+
+type InvoiceDiscount struct {
+	Name   string
+	Amount models.Money
+}
+
+type InvoicePayment struct {
+	Method string
+	Amount models.Money
+}
+
+// Description is the item name with its quantity and variant,
+// "2 x Burger (M)", the modifiers are printed below it
+func (line InvoiceLine) Description() string {
+	description := line.Name
+	if line.Variant != "" {
+		description = fmt.Sprintf("%s (%s)", description, line.Variant)
+	}
+	if line.Quantity > 1 {
+		description = fmt.Sprintf("%d x %s", line.Quantity, description)
+	}
+	return description
+}
+
+func (doc InvoiceDocument) table() string {
+	if doc.Table_number == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *doc.Table_number)
+}
+
+// TaxLabel names a rate with its percentage, "VAT 20%", marking rates that
+// are already in the item prices
+func TaxLabel(line models.TaxLine) string {
+	label := fmt.Sprintf("%s %s%%", line.Name, strconv.FormatFloat(line.Rate, 'f', -1, 64))
+	if line.Inclusive {
+		label += " incl."
+	}
+	return label
+}
+
+func formatAmount(amount models.Money) string {
+	return amount.String()
+}
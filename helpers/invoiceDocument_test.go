@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"restaurant-management/config"
+	"restaurant-management/models"
+	"testing"
+	"time"
+)
+
+// go test ./helpers -update writes the golden files again after a change
+// to the layout, check the new ones before committing them
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func eur(cents int64) models.Money {
+	return models.Money{Amount: cents, Currency: "EUR"}
+}
+
+func testRestaurant() config.RestaurantConfig {
+	return config.RestaurantConfig{
+		Name:       "Trattoria Da Enzo",
+		Address:    "Via Roma 12, 00100 Roma",
+		Phone:      "+39 06 1234567",
+		Tax_number: "IT01234567890",
+		Footer:     "Grazie e arrivederci!",
+	}
+}
+
+// invoiceDocuments are the documents every renderer is checked against
+func invoiceDocuments() map[string]InvoiceDocument {
+	issued := time.Date(2024, 3, 14, 19, 30, 0, 0, time.UTC)
+	table := 7
+
+	return map[string]InvoiceDocument{
+		//one item, nothing paid yet
+		"unpaid": {
+			Restaurant:     config.RestaurantConfig{Name: "Burger Bar"},
+			Invoice_id:     "inv-1",
+			Order_id:       "ord-1",
+			Lines:          []InvoiceLine{{Name: "Burger", Quantity: 1, Amount: eur(999)}},
+			Items_total:    eur(999),
+			Subtotal:       eur(999),
+			Total:          eur(999),
+			Balance_due:    eur(999),
+			Payment_status: models.PaymentPending,
+			Issued_at:      issued,
+			Due_date:       issued.Add(24 * time.Hour),
+		},
+		//variants, modifiers, a discount, two tax rates, a split payment
+		//with change and names that need wrapping or are not ASCII
+		"paid": {
+			Restaurant:   testRestaurant(),
+			Invoice_id:   "inv-2",
+			Order_id:     "ord-2",
+			Table_number: &table,
+			Lines: []InvoiceLine{
+				{Name: "Pizza Margherita", Quantity: 2, Variant: "Large", Modifiers: []string{"Extra mozzarella", "No basil"}, Amount: eur(2600)},
+				{Name: "Crème brûlée", Quantity: 1, Amount: eur(650)},
+				{Name: "Tiramisù della casa con savoiardi fatti a mano e mascarpone fresco", Quantity: 1, Amount: eur(800)},
+				{Name: "Acqua frizzante", Quantity: 3, Amount: eur(750)},
+			},
+			Items_total: eur(4800),
+			Discounts:   []InvoiceDiscount{{Name: "Happy hour 10%", Amount: eur(480)}},
+			Subtotal:    eur(4320),
+			Tax:         eur(588),
+			Taxes: []models.TaxLine{
+				{Name: "IVA", Rate: 10, Inclusive: true, Taxable_amount: eur(3570), Tax_amount: eur(325)},
+				{Name: "Service", Rate: 5.5, Taxable_amount: eur(4320), Tax_amount: eur(263)},
+			},
+			Total:       eur(4583),
+			Amount_paid: eur(4583),
+			Change_due:  eur(417),
+			Payments: []InvoicePayment{
+				{Method: models.PaymentCard, Amount: eur(2583)},
+				{Method: models.PaymentCash, Amount: eur(2000)},
+			},
+			Balance_due:    eur(0),
+			Payment_method: models.PaymentMixed,
+			Payment_status: models.PaymentPaid,
+			Issued_at:      issued,
+			Due_date:       issued,
+		},
+	}
+}
+
+func TestRenderReceipt(t *testing.T) {
+	tests := []struct {
+		document string
+		width    int
+		escpos   bool
+		golden   string
+	}{
+		{"unpaid", 58, false, "receipt_unpaid_58.txt"},
+		{"unpaid", 80, false, "receipt_unpaid_80.txt"},
+		{"paid", 58, false, "receipt_paid_58.txt"},
+		{"paid", 80, false, "receipt_paid_80.txt"},
+		{"paid", 58, true, "receipt_paid_58.escpos"},
+		{"paid", 80, true, "receipt_paid_80.escpos"},
+	}
+	documents := invoiceDocuments()
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			columns, err := ReceiptColumnsFor(tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := RenderReceipt(&out, documents[tt.document], columns, tt.escpos); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, out.Bytes())
+		})
+	}
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	tests := []struct {
+		document string
+		golden   string
+	}{
+		{"unpaid", "invoice_unpaid.pdf"},
+		{"paid", "invoice_paid.pdf"},
+	}
+	documents := invoiceDocuments()
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := RenderInvoicePDF(&out, documents[tt.document]); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, out.Bytes())
+		})
+	}
+}
+
+func TestReceiptColumnsFor(t *testing.T) {
+	tests := []struct {
+		width   int
+		columns int
+		wantErr bool
+	}{
+		{58, 32, false},
+		{80, 48, false},
+		{76, 0, true},
+	}
+	for _, tt := range tests {
+		columns, err := ReceiptColumnsFor(tt.width)
+		if (err != nil) != tt.wantErr || columns != tt.columns {
+			t.Errorf("ReceiptColumnsFor(%d) = %d, %v, want %d, error %v", tt.width, columns, err, tt.columns, tt.wantErr)
+		}
+	}
+}
+
+// checkGolden compares got with testdata/name, or writes it there with
+// -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run go test -update to write it", err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	if filepath.Ext(name) == ".pdf" {
+		t.Errorf("%s differs from the rendered output (%d bytes, want %d), run go test -update if the change is intended", path, len(got), len(want))
+		return
+	}
+	t.Errorf("%s differs from the rendered output, run go test -update if the change is intended\ngot:\n%s\nwant:\n%s", path, got, want)
+}
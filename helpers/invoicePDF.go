@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// brand colour of the header bar and the item table
+var brandRed, brandGreen, brandBlue = 34, 49, 63
+
+// RenderInvoicePDF writes doc as a single A4 invoice. The creation date is
+// the invoice date and the fonts are written in order, so the same invoice
+// always renders the same bytes.
+func RenderInvoicePDF(w io.Writer, doc InvoiceDocument) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(doc.Issued_at)
+	pdf.SetModificationDate(doc.Issued_at)
+	pdf.SetTitle("Invoice "+doc.Invoice_id, true)
+	pdf.SetAuthor(doc.Restaurant.Name, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, tr(doc.Restaurant.Footer), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 30
+
+	//header bar with the restaurant on the left and the title on the right
+	pdf.SetFillColor(brandRed, brandGreen, brandBlue)
+	pdf.Rect(0, 0, pageWidth, 38, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetXY(15, 10)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(contentWidth/2, 9, tr(doc.Restaurant.Name), "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 24)
+	pdf.CellFormat(contentWidth/2, 9, "INVOICE", "", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{doc.Restaurant.Address, doc.Restaurant.Phone} {
+		if line != "" {
+			pdf.SetX(15)
+			pdf.CellFormat(contentWidth/2, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+	}
+
+	//invoice facts
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetY(48)
+	facts := [][2]string{
+		{"Invoice", doc.Invoice_id},
+		{"Order", doc.Order_id},
+		{"Table", doc.table()},
+		{"Issued", doc.Issued_at.Format("02 Jan 2006 15:04")},
+		{"Due", doc.Due_date.Format("02 Jan 2006 15:04")},
+		{"Payment", doc.Payment_method + " / " + doc.Payment_status},
+	}
+	for _, fact := range facts {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(30, 6, fact[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(contentWidth-30, 6, tr(fact[1]), "", 1, "L", false, 0, "")
+	}
+
+	//line items
+	pdf.Ln(6)
+	amountWidth := 40.0
+	pdf.SetFillColor(brandRed, brandGreen, brandBlue)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(contentWidth-amountWidth, 8, "Item", "", 0, "L", true, 0, "")
+	pdf.CellFormat(amountWidth, 8, "Amount", "", 1, "R", true, 0, "")
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFillColor(242, 244, 246)
+	for i, line := range doc.Lines {
+		fill := i%2 == 1
+		pdf.CellFormat(contentWidth-amountWidth, 7, tr(line.Description()), "", 0, "L", fill, 0, "")
+		pdf.CellFormat(amountWidth, 7, formatAmount(line.Amount), "", 1, "R", fill, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		for _, modifier := range line.Modifiers {
+			pdf.CellFormat(contentWidth-amountWidth, 5, tr("    + "+modifier), "", 0, "L", fill, 0, "")
+			pdf.CellFormat(amountWidth, 5, "", "", 1, "R", fill, 0, "")
+		}
+		pdf.SetFont("Helvetica", "", 10)
+	}
+
+	//totals
+	pdf.Ln(4)
+	labelX := 15 + contentWidth - amountWidth - 40
+	totals := [][2]string{}
+	if len(doc.Discounts) > 0 {
+		totals = append(totals, [2]string{"Items", formatAmount(doc.Items_total)})
+		for _, discount := range doc.Discounts {
+			totals = append(totals, [2]string{discount.Name, "-" + formatAmount(discount.Amount)})
+		}
+	}
+	totals = append(totals, [2]string{"Net", formatAmount(doc.Subtotal)})
+	for _, tax := range doc.Taxes {
+		totals = append(totals, [2]string{TaxLabel(tax), formatAmount(tax.Tax_amount)})
+	}
+	totals = append(totals, [2]string{"Tax", formatAmount(doc.Tax)})
+	for _, total := range totals {
+		pdf.SetX(labelX)
+		pdf.CellFormat(40, 6, tr(total[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(amountWidth, 6, total[1], "", 1, "R", false, 0, "")
+	}
+	pdf.SetX(labelX)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(40, 8, strings.TrimSpace("Total "+doc.Total.Currency), "T", 0, "L", false, 0, "")
+	pdf.CellFormat(amountWidth, 8, formatAmount(doc.Total), "T", 1, "R", false, 0, "")
+
+	//payments made so far and what is left
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.Ln(2)
+	payments := [][2]string{}
+	for _, payment := range doc.Payments {
+		payments = append(payments, [2]string{"Paid by " + payment.Method, formatAmount(payment.Amount)})
+	}
+	payments = append(payments, [2]string{"Balance due", formatAmount(doc.Balance_due)})
+	if doc.Change_due.IsPositive() {
+		payments = append(payments, [2]string{"Change", formatAmount(doc.Change_due)})
+	}
+	for _, payment := range payments {
+		pdf.SetX(labelX)
+		pdf.CellFormat(40, 6, tr(payment[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(amountWidth, 6, payment[1], "", 1, "R", false, 0, "")
+	}
+
+	if doc.Restaurant.Tax_number != "" {
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(0, 5, tr("Tax number: "+doc.Restaurant.Tax_number), "", 1, "L", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
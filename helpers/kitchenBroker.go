@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"restaurant-management/models"
+	"sync"
+)
+
+// KitchenBroker fans order item changes out to the kitchen display streams
+// of this process. A subscriber that falls behind is dropped, its stream
+// ends and the display resyncs when it reconnects.
+type KitchenBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan models.OrderItem]string
+}
+
+func NewKitchenBroker() *KitchenBroker {
+	return &KitchenBroker{subscribers: map[chan models.OrderItem]string{}}
+}
+
+// Subscribe receives the items of one station, or of every station when
+// station is empty, until the returned func is called.
+func (b *KitchenBroker) Subscribe(station string) (<-chan models.OrderItem, func()) {
+	events := make(chan models.OrderItem, 64)
+
+	b.mu.Lock()
+	b.subscribers[events] = station
+	b.mu.Unlock()
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[events]; ok {
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+func (b *KitchenBroker) Publish(orderItems ...models.OrderItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, orderItem := range orderItems {
+		for events, station := range b.subscribers {
+			if station != "" && (orderItem.Station == nil || *orderItem.Station != station) {
+				continue
+			}
+			select {
+			case events <- orderItem:
+			default:
+				delete(b.subscribers, events)
+				close(events)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"math"
+	"restaurant-management/models"
+	"sort"
+)
+
+// DiscountItem is an order item with what is left of its price after the
+// discounts already applied. Quantity is how many of the food it is, zero
+// is taken as one.
+type DiscountItem struct {
+	Order_item_id string
+	Food_id       string
+	Quantity      int
+	Amount        models.Money
+}
+
+// RemainingPrices takes the discounts in discounts off items
+func RemainingPrices(items []DiscountItem, discounts []models.AppliedDiscount) []DiscountItem {
+	taken := map[string]models.Money{}
+	for _, discount := range discounts {
+		for _, line := range discount.Order_items {
+			taken[line.Order_item_id] = taken[line.Order_item_id].Add(line.Amount)
+		}
+	}
+	remaining := make([]DiscountItem, len(items))
+	for i, item := range items {
+		remaining[i] = item
+		remaining[i].Amount = item.Amount.Sub(taken[item.Order_item_id])
+		if remaining[i].Amount.Amount < 0 {
+			remaining[i].Amount.Amount = 0
+		}
+	}
+	return remaining
+}
+
+// PromotionDiscount works out what promotion takes off each item, nothing
+// when it does not apply to any of them
+func PromotionDiscount(promotion models.Promotion, items []DiscountItem) []models.DiscountLine {
+	eligible := items
+	if len(promotion.Food_ids) > 0 {
+		foods := map[string]bool{}
+		for _, foodId := range promotion.Food_ids {
+			foods[foodId] = true
+		}
+		eligible = nil
+		for _, item := range items {
+			if foods[item.Food_id] {
+				eligible = append(eligible, item)
+			}
+		}
+	}
+
+	var lines []models.DiscountLine
+	switch *promotion.Type {
+	case models.PromotionPercent:
+		for _, item := range eligible {
+			cents := int64(math.Round(float64(item.Amount.Amount) * *promotion.Percent / 100))
+			lines = append(lines, models.DiscountLine{Order_item_id: item.Order_item_id, Amount: models.Money{Amount: cents, Currency: item.Amount.Currency}})
+		}
+	case models.PromotionFixed:
+		lines = AllocateDiscount(*promotion.Amount, eligible)
+	case models.PromotionBuyXGetY:
+		//the cheapest units of every group of buy+get are free, an item of
+		//several units counts each of them
+		var units []DiscountItem
+		for _, item := range eligible {
+			quantity := item.Quantity
+			if quantity < 1 {
+				quantity = 1
+			}
+			for _, share := range item.Amount.Split(quantity) {
+				units = append(units, DiscountItem{Order_item_id: item.Order_item_id, Food_id: item.Food_id, Quantity: 1, Amount: share})
+			}
+		}
+		sort.SliceStable(units, func(i, j int) bool { return units[i].Amount.Cmp(units[j].Amount) > 0 })
+		group := *promotion.Buy_quantity + *promotion.Get_quantity
+		free := map[string]models.Money{}
+		var order []string
+		for i, unit := range units {
+			if i%group < *promotion.Buy_quantity {
+				continue
+			}
+			if _, ok := free[unit.Order_item_id]; !ok {
+				order = append(order, unit.Order_item_id)
+			}
+			free[unit.Order_item_id] = free[unit.Order_item_id].Add(unit.Amount)
+		}
+		for _, orderItemId := range order {
+			lines = append(lines, models.DiscountLine{Order_item_id: orderItemId, Amount: free[orderItemId]})
+		}
+	}
+	return withoutZeroLines(lines)
+}
+
+// AllocateDiscount spreads amount over items in proportion to their price,
+// the cents left by rounding go to the largest remainders. No item is
+// discounted by more than it costs.
+func AllocateDiscount(amount models.Money, items []DiscountItem) []models.DiscountLine {
+	var total int64
+	for _, item := range items {
+		total += item.Amount.Amount
+	}
+	if total <= 0 || !amount.IsPositive() {
+		return nil
+	}
+	if amount.Amount > total {
+		amount.Amount = total
+	}
+
+	lines := make([]models.DiscountLine, len(items))
+	remainders := make([]int64, len(items))
+	var allocated int64
+	for i, item := range items {
+		share := amount.Amount * item.Amount.Amount
+		lines[i] = models.DiscountLine{Order_item_id: item.Order_item_id, Amount: models.Money{Amount: share / total, Currency: amount.Currency}}
+		remainders[i] = share % total
+		allocated += share / total
+	}
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for _, i := range order[:amount.Amount-allocated] {
+		lines[i].Amount.Amount++
+	}
+	return withoutZeroLines(lines)
+}
+
+// DiscountTotal is the sum of lines
+func DiscountTotal(lines []models.DiscountLine) models.Money {
+	total := models.Cents(0)
+	for _, line := range lines {
+		total = total.Add(line.Amount)
+	}
+	return total
+}
+
+func withoutZeroLines(lines []models.DiscountLine) []models.DiscountLine {
+	kept := []models.DiscountLine{}
+	for _, line := range lines {
+		if line.Amount.IsPositive() {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
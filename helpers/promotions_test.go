@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"reflect"
+	"restaurant-management/models"
+	"testing"
+)
+
+func discountLines(lines ...models.DiscountLine) []models.DiscountLine {
+	return append([]models.DiscountLine{}, lines...)
+}
+
+func line(orderItemId string, cents int64) models.DiscountLine {
+	return models.DiscountLine{Order_item_id: orderItemId, Amount: models.Cents(cents)}
+}
+
+func promotion(kind string) models.Promotion {
+	return models.Promotion{Type: &kind}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	items := []DiscountItem{
+		{Order_item_id: "a", Food_id: "burger", Quantity: 1, Amount: models.Cents(1000)},
+		{Order_item_id: "b", Food_id: "cola", Quantity: 2, Amount: models.Cents(600)},
+		{Order_item_id: "c", Food_id: "burger", Quantity: 3, Amount: models.Cents(900)},
+	}
+
+	percent := func(percent float64, foodIds ...string) models.Promotion {
+		p := promotion(models.PromotionPercent)
+		p.Percent, p.Food_ids = &percent, foodIds
+		return p
+	}
+	fixed := func(cents int64, foodIds ...string) models.Promotion {
+		p := promotion(models.PromotionFixed)
+		amount := models.Cents(cents)
+		p.Amount, p.Food_ids = &amount, foodIds
+		return p
+	}
+	buyGet := func(buy int, get int, foodIds ...string) models.Promotion {
+		p := promotion(models.PromotionBuyXGetY)
+		p.Buy_quantity, p.Get_quantity, p.Food_ids = &buy, &get, foodIds
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		promotion models.Promotion
+		items     []DiscountItem
+		want      []models.DiscountLine
+	}{
+		{"percent off everything", percent(10), items, discountLines(line("a", 100), line("b", 60), line("c", 90))},
+		{"percent off some foods", percent(15, "burger"), items, discountLines(line("a", 150), line("c", 135))},
+		{"percent rounds each item", percent(12.5), items, discountLines(line("a", 125), line("b", 75), line("c", 113))},
+		{"percent of nothing", percent(10, "pizza"), items, discountLines()},
+		{"fixed in proportion", fixed(500), items, discountLines(line("a", 200), line("b", 120), line("c", 180))},
+		{"fixed on some foods", fixed(380, "burger"), items, discountLines(line("a", 200), line("c", 180))},
+		{
+			name:      "fixed cents left by rounding",
+			promotion: fixed(100),
+			items: []DiscountItem{
+				{Order_item_id: "a", Amount: models.Cents(100)},
+				{Order_item_id: "b", Amount: models.Cents(100)},
+				{Order_item_id: "c", Amount: models.Cents(100)},
+			},
+			want: discountLines(line("a", 34), line("b", 33), line("c", 33)),
+		},
+		{"fixed above the price", fixed(5000, "cola"), items, discountLines(line("b", 600))},
+		{"fixed of nothing", fixed(500, "pizza"), items, discountLines()},
+		//units cost 10.00, 3.00, 3.00 and 3.00, the third is free
+		{"buy 2 get 1", buyGet(2, 1, "burger"), items, discountLines(line("c", 300))},
+		//units are 10.00, 3.00 (b), 3.00 (b), 3.00 (c) ... every second is free
+		{"buy 1 get 1 counts units", buyGet(1, 1), items, discountLines(line("b", 300), line("c", 600))},
+		{"buy 3 get 1 too few", buyGet(3, 1, "cola"), items, discountLines()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PromotionDiscount(tt.promotion, tt.items)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PromotionDiscount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemainingPrices(t *testing.T) {
+	items := []DiscountItem{
+		{Order_item_id: "a", Amount: models.Cents(1000)},
+		{Order_item_id: "b", Amount: models.Cents(600)},
+	}
+	discounts := []models.AppliedDiscount{
+		{Order_items: discountLines(line("a", 100), line("b", 500))},
+		{Order_items: discountLines(line("a", 50), line("b", 500))},
+	}
+
+	got := RemainingPrices(items, discounts)
+	want := []DiscountItem{
+		{Order_item_id: "a", Amount: models.Cents(850)},
+		//never below zero
+		{Order_item_id: "b", Amount: models.Cents(0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemainingPrices() = %v, want %v", got, want)
+	}
+	if items[0].Amount.Amount != 1000 {
+		t.Errorf("items were changed")
+	}
+	if total := DiscountTotal(discountLines(line("a", 100), line("b", 250))); total != models.Cents(350) {
+		t.Errorf("DiscountTotal() = %v, want 3.50", total)
+	}
+}
@@ -0,0 +1,230 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReceiptColumns is how many characters of the default font fit on a line
+// of each supported paper width in millimetres
+var ReceiptColumns = map[int]int{
+	58: 32,
+	80: 48,
+}
+
+// ESC/POS commands
+var (
+	escInit        = []byte{0x1b, 0x40}
+	escAlignLeft   = []byte{0x1b, 0x61, 0x00}
+	escAlignCenter = []byte{0x1b, 0x61, 0x01}
+	escBoldOn      = []byte{0x1b, 0x45, 0x01}
+	escBoldOff     = []byte{0x1b, 0x45, 0x00}
+	escDoubleOn    = []byte{0x1d, 0x21, 0x01}
+	escDoubleOff   = []byte{0x1d, 0x21, 0x00}
+	escFeedAndCut  = []byte{0x1d, 0x56, 0x42, 0x03}
+)
+
+type receiptLine struct {
+	text   string
+	center bool
+	bold   bool
+	large  bool
+}
+
+// RenderReceipt writes doc for a thermal printer with the given number of
+// columns, as plain text or, when escpos is set, with ESC/POS commands for
+// alignment, emphasis and the paper cut.
+func RenderReceipt(w io.Writer, doc InvoiceDocument, columns int, escpos bool) error {
+	lines := receiptLines(doc, columns)
+
+	var out bytes.Buffer
+	if !escpos {
+		for _, line := range lines {
+			text := line.text
+			if line.center {
+				text = strings.Repeat(" ", (columns-utf8.RuneCountInString(text))/2) + text
+			}
+			out.WriteString(strings.TrimRight(text, " ") + "\n")
+		}
+		_, err := w.Write(out.Bytes())
+		return err
+	}
+
+	out.Write(escInit)
+	for _, line := range lines {
+		if line.center {
+			out.Write(escAlignCenter)
+		}
+		if line.bold {
+			out.Write(escBoldOn)
+		}
+		if line.large {
+			out.Write(escDoubleOn)
+		}
+		out.WriteString(asciiOnly(strings.TrimRight(line.text, " ")))
+		out.WriteByte('\n')
+		if line.large {
+			out.Write(escDoubleOff)
+		}
+		if line.bold {
+			out.Write(escBoldOff)
+		}
+		if line.center {
+			out.Write(escAlignLeft)
+		}
+	}
+	out.Write(escFeedAndCut)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func receiptLines(doc InvoiceDocument, columns int) []receiptLine {
+	rule := receiptLine{text: strings.Repeat("-", columns)}
+	var lines []receiptLine
+
+	for _, text := range wrap(doc.Restaurant.Name, columns) {
+		lines = append(lines, receiptLine{text: text, center: true, bold: true, large: true})
+	}
+	for _, header := range []string{doc.Restaurant.Address, doc.Restaurant.Phone} {
+		for _, text := range wrap(header, columns) {
+			lines = append(lines, receiptLine{text: text, center: true})
+		}
+	}
+	lines = append(lines, rule)
+
+	lines = append(lines, columnsLine("Invoice", doc.Invoice_id, columns)...)
+	lines = append(lines, columnsLine("Order", doc.Order_id, columns)...)
+	lines = append(lines, columnsLine("Table", doc.table(), columns)...)
+	lines = append(lines, columnsLine("Date", doc.Issued_at.Format("2006-01-02 15:04"), columns)...)
+	lines = append(lines, rule)
+
+	for _, item := range doc.Lines {
+		lines = append(lines, columnsLine(item.Description(), formatAmount(item.Amount), columns)...)
+		for _, modifier := range item.Modifiers {
+			for _, text := range wrap("+ "+modifier, columns-2) {
+				lines = append(lines, receiptLine{text: "  " + text})
+			}
+		}
+	}
+	lines = append(lines, rule)
+
+	if len(doc.Discounts) > 0 {
+		lines = append(lines, columnsLine("Items", formatAmount(doc.Items_total), columns)...)
+		for _, discount := range doc.Discounts {
+			lines = append(lines, columnsLine(discount.Name, "-"+formatAmount(discount.Amount), columns)...)
+		}
+	}
+	lines = append(lines, columnsLine("Net", formatAmount(doc.Subtotal), columns)...)
+	for _, tax := range doc.Taxes {
+		lines = append(lines, columnsLine(TaxLabel(tax), formatAmount(tax.Tax_amount), columns)...)
+	}
+	lines = append(lines, columnsLine("Tax", formatAmount(doc.Tax), columns)...)
+	for _, total := range columnsLine(strings.TrimSpace("TOTAL "+doc.Total.Currency), formatAmount(doc.Total), columns) {
+		total.bold = true
+		lines = append(lines, total)
+	}
+	lines = append(lines, rule)
+
+	for _, payment := range doc.Payments {
+		lines = append(lines, columnsLine(payment.Method, formatAmount(payment.Amount), columns)...)
+	}
+	if len(doc.Payments) > 0 {
+		lines = append(lines, columnsLine("Paid", formatAmount(doc.Amount_paid), columns)...)
+	}
+	lines = append(lines, columnsLine("Balance due", formatAmount(doc.Balance_due), columns)...)
+	if doc.Change_due.IsPositive() {
+		lines = append(lines, columnsLine("Change", formatAmount(doc.Change_due), columns)...)
+	}
+	lines = append(lines, columnsLine("Status", doc.Payment_status, columns)...)
+	if doc.Restaurant.Tax_number != "" {
+		lines = append(lines, columnsLine("Tax no.", doc.Restaurant.Tax_number, columns)...)
+	}
+	if doc.Restaurant.Footer != "" {
+		lines = append(lines, receiptLine{})
+		for _, text := range wrap(doc.Restaurant.Footer, columns) {
+			lines = append(lines, receiptLine{text: text, center: true})
+		}
+	}
+	return lines
+}
+
+// columnsLine puts left and right on one line with right aligned to the
+// edge, a left side too long for that wraps and right goes on the last line
+// when it fits, otherwise on a line of its own.
+func columnsLine(left string, right string, columns int) []receiptLine {
+	rightWidth := utf8.RuneCountInString(right)
+	wrapped := wrap(left, columns)
+	if len(wrapped) == 0 {
+		wrapped = []string{""}
+	}
+
+	var lines []receiptLine
+	for _, text := range wrapped[:len(wrapped)-1] {
+		lines = append(lines, receiptLine{text: text})
+	}
+	last := wrapped[len(wrapped)-1]
+	gap := columns - utf8.RuneCountInString(last) - rightWidth
+	if gap < 1 {
+		lines = append(lines, receiptLine{text: last})
+		last, gap = "", columns-rightWidth
+		if gap < 0 {
+			gap = 0
+		}
+	}
+	return append(lines, receiptLine{text: last + strings.Repeat(" ", gap) + right})
+}
+
+// wrap breaks text on spaces into lines of at most columns characters,
+// words longer than a line are split
+func wrap(text string, columns int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > columns {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= columns:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// asciiOnly keeps receipts readable on printers left on their default code
+// page, anything outside ASCII prints as '?'
+func asciiOnly(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e {
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+// ReceiptColumnsFor returns the columns for paper width or an error naming
+// the supported widths
+func ReceiptColumnsFor(width int) (int, error) {
+	columns, ok := ReceiptColumns[width]
+	if !ok {
+		return 0, fmt.Errorf("paper width must be 58 or 80, got %d", width)
+	}
+	return columns, nil
+}
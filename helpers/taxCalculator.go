@@ -0,0 +1,154 @@
+package helpers
+
+import (
+	"math"
+	"restaurant-management/models"
+	"sort"
+	"strings"
+)
+
+// TaxItem is one priced line of an order to be taxed
+type TaxItem struct {
+	Food_id  string
+	Category string
+	Amount   models.Money
+}
+
+// TaxResult is what an order costs before and after tax. Net plus Tax is
+// always exactly Gross, the rounding difference goes into Net.
+type TaxResult struct {
+	Net       models.Money
+	Tax       models.Money
+	Gross     models.Money
+	Breakdown []models.TaxLine
+}
+
+// taxSum adds up one rate in fractions of a cent, only the totals are
+// rounded
+type taxSum struct {
+	line    models.TaxLine
+	taxable float64
+	tax     float64
+}
+
+// CalculateTax taxes items with the active rules whose rate applies in
+// jurisdiction. Each item is taxed by the rules naming its food, if there
+// are none by the rules for its category, otherwise by the default rules.
+// Inclusive rates are backed out of the price, exclusive rates are charged
+// on the net price and added to it.
+func CalculateTax(items []TaxItem, rates []models.TaxRate, rules []models.TaxRule, jurisdiction string) TaxResult {
+	usable := map[string]models.TaxRate{}
+	for _, rate := range rates {
+		if !isActive(rate.Active) || rate.Rate == nil || rate.Inclusive == nil {
+			continue
+		}
+		if rate.Jurisdiction != nil && *rate.Jurisdiction != "" && !strings.EqualFold(*rate.Jurisdiction, jurisdiction) {
+			continue
+		}
+		usable[rate.Tax_rate_id] = rate
+	}
+
+	sums := map[string]*taxSum{}
+	amount := models.Cents(0)
+	for _, item := range items {
+		amount = amount.Add(item.Amount)
+		itemRates := ratesFor(item, rules, usable)
+
+		var inclusiveRate float64
+		for _, rate := range itemRates {
+			if *rate.Inclusive {
+				inclusiveRate += *rate.Rate / 100
+			}
+		}
+		net := float64(item.Amount.Amount) / (1 + inclusiveRate)
+
+		for _, rate := range itemRates {
+			sum, ok := sums[rate.Tax_rate_id]
+			if !ok {
+				sum = &taxSum{line: models.TaxLine{Tax_rate_id: rate.Tax_rate_id, Name: *rate.Name, Rate: *rate.Rate, Inclusive: *rate.Inclusive}}
+				sums[rate.Tax_rate_id] = sum
+			}
+			sum.taxable += net
+			sum.tax += net * *rate.Rate / 100
+		}
+	}
+
+	result := TaxResult{Breakdown: []models.TaxLine{}}
+	tax := models.Cents(0)
+	exclusive := models.Cents(0)
+	for _, sum := range sums {
+		sum.line.Taxable_amount = toCents(sum.taxable)
+		sum.line.Tax_amount = toCents(sum.tax)
+		tax = tax.Add(sum.line.Tax_amount)
+		if !sum.line.Inclusive {
+			exclusive = exclusive.Add(sum.line.Tax_amount)
+		}
+		result.Breakdown = append(result.Breakdown, sum.line)
+	}
+	sort.Slice(result.Breakdown, func(i, j int) bool {
+		if result.Breakdown[i].Name != result.Breakdown[j].Name {
+			return result.Breakdown[i].Name < result.Breakdown[j].Name
+		}
+		return result.Breakdown[i].Tax_rate_id < result.Breakdown[j].Tax_rate_id
+	})
+
+	result.Gross = amount.Add(exclusive)
+	result.Tax = tax
+	result.Net = result.Gross.Sub(tax)
+	return result
+}
+
+// ratesFor picks the most specific rules matching item, a rate named by
+// more than one of them is only charged once
+func ratesFor(item TaxItem, rules []models.TaxRule, usable map[string]models.TaxRate) []models.TaxRate {
+	var byFood, byCategory, byDefault []models.TaxRate
+	for _, rule := range rules {
+		if !isActive(rule.Active) || rule.Tax_rate_id == nil {
+			continue
+		}
+		rate, ok := usable[*rule.Tax_rate_id]
+		if !ok {
+			continue
+		}
+		hasFood := rule.Food_id != nil && *rule.Food_id != ""
+		hasCategory := rule.Category != nil && *rule.Category != ""
+		switch {
+		case hasFood:
+			if *rule.Food_id == item.Food_id {
+				byFood = appendRate(byFood, rate)
+			}
+		case hasCategory:
+			if strings.EqualFold(*rule.Category, item.Category) {
+				byCategory = appendRate(byCategory, rate)
+			}
+		default:
+			byDefault = appendRate(byDefault, rate)
+		}
+	}
+	if len(byFood) > 0 {
+		return byFood
+	}
+	if len(byCategory) > 0 {
+		return byCategory
+	}
+	return byDefault
+}
+
+func appendRate(rates []models.TaxRate, rate models.TaxRate) []models.TaxRate {
+	for _, existing := range rates {
+		if existing.Tax_rate_id == rate.Tax_rate_id {
+			return rates
+		}
+	}
+	return append(rates, rate)
+}
+
+// isActive treats a missing flag as active
+func isActive(active *bool) bool {
+	return active == nil || *active
+}
+
+// toCents rounds a fractional number of cents to the nearest cent
+func toCents(cents float64) models.Money {
+	return models.Cents(int64(math.Round(cents)))
+}
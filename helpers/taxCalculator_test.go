@@ -0,0 +1,118 @@
+package helpers
+
+import (
+	"reflect"
+	"restaurant-management/models"
+	"testing"
+)
+
+func taxRate(id string, name string, rate float64, inclusive bool) models.TaxRate {
+	return models.TaxRate{Tax_rate_id: id, Name: &name, Rate: &rate, Inclusive: &inclusive}
+}
+
+// taxRule names a food, a category or, with both empty, everything
+func taxRule(rateId string, foodId string, category string) models.TaxRule {
+	return models.TaxRule{Tax_rate_id: &rateId, Food_id: &foodId, Category: &category}
+}
+
+func taxLine(rate models.TaxRate, taxable int64, tax int64) models.TaxLine {
+	return models.TaxLine{Tax_rate_id: rate.Tax_rate_id, Name: *rate.Name, Rate: *rate.Rate, Inclusive: *rate.Inclusive, Taxable_amount: models.Cents(taxable), Tax_amount: models.Cents(tax)}
+}
+
+func TestCalculateTax(t *testing.T) {
+	sales := taxRate("sales", "Sales tax", 8.25, false)
+	vat := taxRate("vat", "VAT", 20, true)
+	reduced := taxRate("reduced", "Reduced", 10, false)
+
+	retired := taxRate("retired", "Retired", 50, false)
+	inactive := false
+	retired.Active = &inactive
+	california := taxRate("california", "California", 7.25, false)
+	ca := "CA"
+	california.Jurisdiction = &ca
+	newYork := taxRate("newyork", "New York", 4, false)
+	ny := "ny"
+	newYork.Jurisdiction = &ny
+
+	burger := TaxItem{Food_id: "burger", Category: "Mains", Amount: models.Cents(1000)}
+
+	tests := []struct {
+		name  string
+		items []TaxItem
+		rates []models.TaxRate
+		rules []models.TaxRule
+		want  TaxResult
+	}{
+		{
+			name:  "no rules",
+			items: []TaxItem{burger},
+			rates: []models.TaxRate{sales},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(0), Gross: models.Cents(1000), Breakdown: []models.TaxLine{}},
+		},
+		{
+			name:  "exclusive is added",
+			items: []TaxItem{burger},
+			rates: []models.TaxRate{sales},
+			rules: []models.TaxRule{taxRule("sales", "", "")},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(83), Gross: models.Cents(1083), Breakdown: []models.TaxLine{taxLine(sales, 1000, 83)}},
+		},
+		{
+			name:  "inclusive is backed out",
+			items: []TaxItem{{Food_id: "burger", Amount: models.Cents(1200)}},
+			rates: []models.TaxRate{vat},
+			rules: []models.TaxRule{taxRule("vat", "", "")},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(200), Gross: models.Cents(1200), Breakdown: []models.TaxLine{taxLine(vat, 1000, 200)}},
+		},
+		{
+			//the exclusive rate is charged on the price without the inclusive one
+			name:  "inclusive and exclusive",
+			items: []TaxItem{{Food_id: "burger", Amount: models.Cents(1200)}},
+			rates: []models.TaxRate{vat, sales},
+			rules: []models.TaxRule{taxRule("vat", "", ""), taxRule("sales", "", "")},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(283), Gross: models.Cents(1283), Breakdown: []models.TaxLine{taxLine(sales, 1000, 83), taxLine(vat, 1000, 200)}},
+		},
+		{
+			name: "food before category before default",
+			items: []TaxItem{
+				burger,
+				{Food_id: "cola", Category: "Drinks", Amount: models.Cents(600)},
+				{Food_id: "water", Category: "Drinks", Amount: models.Cents(500)},
+			},
+			rates: []models.TaxRate{sales, vat, reduced},
+			rules: []models.TaxRule{taxRule("sales", "", ""), taxRule("vat", "", "drinks"), taxRule("reduced", "water", "")},
+			want: TaxResult{Net: models.Cents(2000), Tax: models.Cents(233), Gross: models.Cents(2233), Breakdown: []models.TaxLine{
+				taxLine(reduced, 500, 50), taxLine(sales, 1000, 83), taxLine(vat, 500, 100),
+			}},
+		},
+		{
+			name:  "a rate named twice is charged once",
+			items: []TaxItem{burger},
+			rates: []models.TaxRate{sales},
+			rules: []models.TaxRule{taxRule("sales", "burger", ""), taxRule("sales", "burger", "")},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(83), Gross: models.Cents(1083), Breakdown: []models.TaxLine{taxLine(sales, 1000, 83)}},
+		},
+		{
+			name:  "inactive rates and other jurisdictions are left out",
+			items: []TaxItem{burger},
+			rates: []models.TaxRate{retired, california, newYork},
+			rules: []models.TaxRule{taxRule("retired", "", ""), taxRule("california", "", ""), taxRule("newyork", "", "")},
+			want:  TaxResult{Net: models.Cents(1000), Tax: models.Cents(40), Gross: models.Cents(1040), Breakdown: []models.TaxLine{taxLine(newYork, 1000, 40)}},
+		},
+		{
+			//8.6625 cents on each, only the total is rounded
+			name:  "rounded once per rate",
+			items: []TaxItem{{Food_id: "fries", Amount: models.Cents(105)}, {Food_id: "fries", Amount: models.Cents(105)}},
+			rates: []models.TaxRate{sales},
+			rules: []models.TaxRule{taxRule("sales", "", "")},
+			want:  TaxResult{Net: models.Cents(210), Tax: models.Cents(17), Gross: models.Cents(227), Breakdown: []models.TaxLine{taxLine(sales, 210, 17)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateTax(tt.items, tt.rates, tt.rules, "NY")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalculateTax() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,16 +3,11 @@ package helpers
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"restaurant-management/database"
+	"restaurant-management/repository"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type SignedDetails struct {
@@ -20,81 +15,115 @@ type SignedDetails struct {
 	First_name string
 	Last_name  string
 	Uid        string
+	Role       string
 	jwt.StandardClaims
 }
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
+// set once at startup from config.AuthConfig by Configure
+var SECRET_KEY string
+var TOKEN_LIFETIME = 30 * time.Minute
+var REFRESH_TOKEN_LIFETIME = 24 * time.Hour
 
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
+func Configure(secretKey string, tokenLifetime time.Duration, refreshTokenLifetime time.Duration) {
+	SECRET_KEY = secretKey
+	TOKEN_LIFETIME = tokenLifetime
+	REFRESH_TOKEN_LIFETIME = refreshTokenLifetime
+}
 
-func GenerateAllTokens(email string, firstName string, lastname string, uid string) (signedToken string, signedRefreshToken string, err error) {
+func GenerateAllTokens(email string, firstName string, lastname string, uid string, role string) (signedToken string, signedRefreshToken string, err error) {
 	claims := &SignedDetails{
 		Email:      email,
 		First_name: firstName,
 		Last_name:  lastname,
 		Uid:        uid,
+		Role:       role,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(time.Minute * time.Duration(30)).Unix(),
+			Id:        primitive.NewObjectID().Hex(),
+			ExpiresAt: time.Now().Local().Add(TOKEN_LIFETIME).Unix(),
 		},
 	}
 
 	refreshClims := &SignedDetails{
+		Uid: uid,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(time.Hour * time.Duration(24)).Unix(),
+			Id:        primitive.NewObjectID().Hex(),
+			ExpiresAt: time.Now().Local().Add(REFRESH_TOKEN_LIFETIME).Unix(),
 		},
 	}
 
 	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
 	if err != nil {
-		log.Panic(err)
-		return
+		return "", "", err
 	}
 
 	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClims).SignedString([]byte(SECRET_KEY))
 	if err != nil {
-		log.Panic(err)
-		return
+		return "", "", err
 	}
 
-	return token, refreshToken, err
+	return token, refreshToken, nil
 }
 
-func UpdateAllTokens(signedToken string, signedRefreshToken string, userId string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+func UpdateAllTokens(ctx context.Context, users repository.UserRepository, signedToken string, signedRefreshToken string, userId string) error {
+	Updated_at, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 
-	var updateObj primitive.D
+	_, err := users.SetTokens(ctx, userId, &signedToken, &signedRefreshToken, Updated_at)
+	return err
+}
 
-	updateObj = append(updateObj, bson.E{Key: "token", Value: signedToken})
-	updateObj = append(updateObj, bson.E{Key: "refresh_Token", Value: signedRefreshToken})
+// RotateAllTokens swaps in a new token pair only if oldRefreshToken is still
+// the one stored on the user, so a refresh token can be exchanged once.
+func RotateAllTokens(ctx context.Context, users repository.UserRepository, signedToken string, signedRefreshToken string, userId string, oldRefreshToken string) (bool, error) {
+	Updated_at, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	return users.RotateTokens(ctx, userId, oldRefreshToken, signedToken, signedRefreshToken, Updated_at)
+}
 
+// RevokeAllTokens clears the stored token pair, every token issued to the
+// user so far stops validating. It reports whether the user exists.
+func RevokeAllTokens(ctx context.Context, users repository.UserRepository, userId string) (bool, error) {
 	Updated_at, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-	updateObj = append(updateObj, bson.E{Key: "updated_at", Value: Updated_at})
+	return users.SetTokens(ctx, userId, nil, nil, Updated_at)
+}
 
-	upsert := true
-	filter := bson.M{"user_id": userId}
-	opt := options.UpdateOptions{
-		Upsert: &upsert,
+// ValidateToken checks an access token, it must also still be the token
+// stored on the user, so rotated and revoked tokens are rejected. A user
+// holds one token pair, signing in again ends the previous session. msg
+// tells the client why the token was rejected, err is a storage failure.
+func ValidateToken(ctx context.Context, users repository.UserRepository, signedToken string) (claims *SignedDetails, msg string, err error) {
+	claims, msg = parseToken(signedToken)
+	if msg != "" {
+		return
 	}
 
-	_, err := userCollection.UpdateOne(
-		ctx,
-		filter,
-		bson.D{
-			{Key: "$set", Value: updateObj},
-		},
-		&opt,
-	)
-	defer cancel()
-
+	current, err := users.HasToken(ctx, claims.Uid, signedToken)
 	if err != nil {
-		log.Panic(err)
+		return claims, msg, err
+	}
+	if !current {
+		msg = fmt.Sprintf("token has been revoked")
 		return
 	}
 
-	return
+	return claims, msg, nil
 }
 
-func ValidateToken(signedToken string) (claims *SignedDetails, msg string) {
+// ValidateRefreshToken only checks signature and expiry, whether the refresh
+// token is still current is decided atomically by RotateAllTokens.
+func ValidateRefreshToken(signedToken string) (claims *SignedDetails, msg string) {
+	claims, msg = parseToken(signedToken)
+	if msg != "" {
+		return
+	}
+
+	if claims.Uid == "" {
+		msg = fmt.Sprintf("the refresh token is invalid")
+		return
+	}
+
+	return claims, msg
+}
+
+func parseToken(signedToken string) (claims *SignedDetails, msg string) {
 	token, err := jwt.ParseWithClaims(
 		signedToken,
 		&SignedDetails{},
@@ -109,15 +138,13 @@ func ValidateToken(signedToken string) (claims *SignedDetails, msg string) {
 
 	claims, ok := token.Claims.(*SignedDetails)
 
-	if !ok {
+	if !ok || !token.Valid {
 		msg = fmt.Sprintf("the token is invalid")
-		msg = err.Error()
 		return
 	}
 
 	if claims.ExpiresAt < time.Now().Local().Unix() {
 		msg = fmt.Sprintf("token is expired")
-		msg = err.Error()
 		return
 	}
 
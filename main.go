@@ -1,33 +1,104 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
 	"os"
-	middleware "restaurant-management/middleware"
+	"restaurant-management/blobstore"
+	"restaurant-management/config"
+	database "restaurant-management/database"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"restaurant-management/repository/memory"
+	"restaurant-management/repository/mongodb"
 	routes "restaurant-management/routes"
-
-	"github.com/gin-gonic/gin"
+	_ "time/tzdata"
 )
 
-//var foodCollection *mongo.Collection = database.OpenCollection(database.Client, "food")
-
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
+	//"openapi" writes the document of the API, docs/openapi.json unless
+	//another file is given, and exits. It needs no configuration or database.
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		path := "docs/openapi.json"
+		if len(os.Args) > 2 {
+			path = os.Args[2]
+		}
+		router := routes.NewRouter(memory.NewRepositories(), nil, config.Default())
+		data, err := json.MarshalIndent(routes.Document(router), "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helpers.Configure(cfg.Auth.Secret_key, cfg.Auth.Token_lifetime.Duration, cfg.Auth.Refresh_token_lifetime.Duration)
+	models.DefaultCurrency = cfg.Restaurant.Currency
+
+	//"migrate-money" converts amounts stored as doubles by older versions,
+	//"migrate-order-items" moves sizes out of quantity, both exit when done
+	if len(os.Args) > 1 && (os.Args[1] == "migrate-money" || os.Args[1] == "migrate-order-items") {
+		if cfg.Storage != config.StorageMongo {
+			log.Fatalf("%s only runs against mongodb storage", os.Args[1])
+		}
+		client := database.DBinstance(cfg.Mongo)
+		db := database.OpenDatabase(client, cfg.Mongo.Database)
+		if os.Args[1] == "migrate-money" {
+			err = database.MigrateMoney(context.Background(), db, cfg.Restaurant.Currency)
+		} else {
+			err = database.MigrateOrderItems(context.Background(), db)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	//storage: memory runs the whole API without a database, data is lost on restart
+	var repos repository.Repositories
+	if cfg.Storage == config.StorageMemory {
+		repos = memory.NewRepositories()
+	} else {
+		client := database.DBinstance(cfg.Mongo)
+		repos = mongodb.NewRepositories(database.OpenDatabase(client, cfg.Mongo.Database))
 	}
 
-	router := gin.New()
-	router.Use(gin.Logger())
-	router.Use(middleware.Authentication())
+	//uploaded images are kept on disk unless an S3-compatible bucket is configured
+	var images blobstore.Store
+	if cfg.Images.Store == config.ImageStoreS3 {
+		images, err = blobstore.NewS3(blobstore.S3Options{
+			Endpoint:   cfg.Images.S3.Endpoint,
+			Region:     cfg.Images.S3.Region,
+			Bucket:     cfg.Images.S3.Bucket,
+			Access_key: cfg.Images.S3.Access_key,
+			Secret_key: cfg.Images.S3.Secret_key,
+			Path_style: cfg.Images.S3.Path_style,
+		})
+	} else {
+		images, err = blobstore.NewLocal(cfg.Images.Directory)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	routes.UserRoutes(router)
-	routes.FoodRoutes(router)
-	routes.InvoiceRoutes(router)
-	routes.MenuRoutes(router)
-	routes.OrderItemRoutes(router)
-	routes.TableRoutes(router)
-	routes.OrderRoutes(router)
+	router := routes.NewRouter(repos, images, cfg)
 
-	router.Run(": " + port)
+	server := &http.Server{
+		Addr:         cfg.Server.Listen_address,
+		Handler:      router,
+		ReadTimeout:  cfg.Server.Read_timeout.Duration,
+		WriteTimeout: cfg.Server.Write_timeout.Duration,
+	}
+	log.Fatal(server.ListenAndServe())
 
 }
@@ -1,35 +1,74 @@
 package middleware
 
 import (
-	"fmt"
-	"net/http"
+	"context"
+	"restaurant-management/apierror"
 	"restaurant-management/helpers"
+	"restaurant-management/repository"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Authentication() gin.HandlerFunc {
+func Authentication(users repository.UserRepository, timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		clientToken := c.Request.Header.Get("token")
 		if clientToken == "" {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("No authorization header provided")})
+			c.Error(apierror.Unauthorized("No authorization header provided"))
 			c.Abort()
 			return
 		}
 
-		claims, err := helpers.ValidateToken(clientToken)
-		if err != "" {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		claims, msg, err := helpers.ValidateToken(ctx, users, clientToken)
+		if err != nil {
+			c.Error(apierror.Internal("token could not be checked", err))
+			c.Abort()
+			return
+		}
+		if msg != "" {
+			c.Error(apierror.Unauthorized(msg))
 			c.Abort()
 			return
 		}
 
-		c.Set("email", claims.Email)
-		c.Set("first_name", claims.First_name)
-		c.Set("last_name", claims.Last_name)
-		c.Set("uid", claims.Uid)
+		setClaims(c, claims)
 
 		c.Next()
 	}
 
 }
+
+// OptionalAuthentication is used on public routes, a valid token still
+// identifies the caller but a missing or invalid one is not an error
+func OptionalAuthentication(users repository.UserRepository, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientToken := c.Request.Header.Get("token")
+		if clientToken != "" {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+			defer cancel()
+
+			claims, msg, err := helpers.ValidateToken(ctx, users, clientToken)
+			if err != nil {
+				c.Error(apierror.Internal("token could not be checked", err))
+				c.Abort()
+				return
+			}
+			if msg == "" {
+				setClaims(c, claims)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func setClaims(c *gin.Context, claims *helpers.SignedDetails) {
+	c.Set("email", claims.Email)
+	c.Set("first_name", claims.First_name)
+	c.Set("last_name", claims.Last_name)
+	c.Set("uid", claims.Uid)
+	c.Set("role", claims.Role)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorization only lets the request through when the role stored by
+// Authentication is one of the given roles. Admins are always allowed.
+func Authorization(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == models.RoleAdmin {
+			c.Next()
+			return
+		}
+
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.Error(apierror.Forbidden("you are not allowed to access this resource"))
+		c.Abort()
+	}
+}
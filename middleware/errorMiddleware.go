@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"restaurant-management/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// a request ID from the client is kept when it is safe to log and echo
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ErrorHandler gives every request an ID and renders the last error a
+// handler or middleware added with c.Error, so they only report errors and
+// return. Panics and errors that are not an apierror.Error become a 500
+// whose cause is logged with the request ID.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("request %s panicked: %v", requestID, recovered)
+				c.Errors = c.Errors[:0]
+				c.Error(apierror.Internal("internal server error", nil))
+				c.Abort()
+				renderError(c, requestID)
+			}
+		}()
+
+		c.Next()
+		renderError(c, requestID)
+	}
+}
+
+func renderError(c *gin.Context, requestID string) {
+	last := c.Errors.Last()
+	if last == nil || c.Writer.Written() {
+		return
+	}
+
+	var apiErr *apierror.Error
+	if !errors.As(last.Err, &apiErr) {
+		apiErr = apierror.Internal("internal server error", last.Err)
+	}
+	if apiErr.Cause != nil && apiErr.Status >= http.StatusInternalServerError {
+		log.Printf("request %s: %v", requestID, apiErr)
+	}
+
+	c.JSON(apiErr.Status, apiErr.Body(requestID))
+}
+
+func newRequestID() string {
+	id := make([]byte, 12)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"restaurant-management/apierror"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdempotencyKeyHeader lets a client retry a POST without doing it twice,
+// answers replayed from the store carry IdempotentReplayedHeader
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// maxStoredAnswer is the largest answer kept for replay, a request with a
+// larger one can be retried as if it had failed
+const maxStoredAnswer = 1 << 20
+
+// Idempotency answers a POST sent again with the same Idempotency-Key with
+// the answer to the first one, for lifetime. Keys belong to the user who
+// sent them. The same key with another request is refused, and so is a
+// repeat while the first request is still running, for up to lease when
+// the server stops without answering. Requests that fail with a server
+// error free their key. Bodies are read for their fingerprint up to
+// maxBody bytes, larger ones are refused. It goes after the authentication.
+func Idempotency(records repository.IdempotencyRepository, lifetime time.Duration, lease time.Duration, timeout time.Duration, maxBody int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if !idempotencyKeyPattern.MatchString(key) {
+			c.Error(apierror.BadRequest("Idempotency-Key must be 1 to 255 printable characters without spaces"))
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody)
+		body, err := io.ReadAll(c.Request.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.Error(apierror.New(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must be at most %d bytes", maxBody)))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Error(apierror.BadRequest("request body could not be read"))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := models.IdempotencyRecord{
+			ID:          primitive.NewObjectID(),
+			Key:         c.GetString("uid") + " " + key,
+			Fingerprint: fingerprint(c.Request, body),
+			Created_at:  now,
+			Expires_at:  now.Add(lease),
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		stored, reserved, err := records.Reserve(ctx, record)
+		cancel()
+		if err != nil {
+			c.Error(apierror.Internal("idempotency key could not be checked", err))
+			c.Abort()
+			return
+		}
+		if !reserved {
+			replay(c, stored, record.Fingerprint)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		answered := false
+		defer func() {
+			//also runs when the handler panics
+			if answered {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := records.Release(ctx, record); err != nil {
+				log.Printf("idempotency key %q was not released: %v", key, err)
+			}
+		}()
+
+		c.Next()
+
+		//errors are rendered here rather than by ErrorHandler so that they
+		//are stored and replayed too
+		renderError(c, c.GetString("request_id"))
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || recorder.overflow {
+			return
+		}
+		record.Status = status
+		record.Content_type = recorder.Header().Get("Content-Type")
+		record.Body = recorder.body.Bytes()
+		record.Expires_at = time.Now().Add(lifetime)
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := records.Complete(ctx, record); err != nil {
+			log.Printf("answer to idempotency key %q was not stored: %v", key, err)
+			return
+		}
+		answered = true
+	}
+}
+
+func replay(c *gin.Context, stored models.IdempotencyRecord, fingerprint string) {
+	switch {
+	case stored.Fingerprint != fingerprint:
+		c.Error(apierror.Conflict("Idempotency-Key was already used for a different request").WithCode(apierror.CodeIdempotencyReused))
+		c.Abort()
+	case !stored.Answered():
+		c.Error(apierror.Conflict("the first request with this Idempotency-Key is still running").WithCode(apierror.CodeIdempotencyPending))
+		c.Abort()
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		if stored.Content_type != "" {
+			c.Header("Content-Type", stored.Content_type)
+		}
+		c.Status(stored.Status)
+		c.Writer.WriteHeaderNow()
+		c.Writer.Write(stored.Body)
+		c.Abort()
+	}
+}
+
+// fingerprint tells requests apart, the same key with another route or
+// body is another request
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// bodyRecorder keeps a copy of the answer while it is written
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(data string) (int, error) {
+	r.record([]byte(data))
+	return r.ResponseWriter.WriteString(data)
+}
+
+func (r *bodyRecorder) record(data []byte) {
+	if r.overflow || r.body.Len()+len(data) > maxStoredAnswer {
+		r.overflow = true
+		return
+	}
+	r.body.Write(data)
+}
@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"restaurant-management/apierror"
+	"restaurant-management/repository/memory"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// counter answers POST /count with how many times it ran, so a replay is
+// told apart from a second run
+type counter struct {
+	mu   sync.Mutex
+	runs map[string]int
+	//block holds the next run of /slow until it is closed
+	block chan struct{}
+}
+
+func (ct *counter) run(name string) int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.runs[name]++
+	return ct.runs[name]
+}
+
+func idempotentRouter(lease time.Duration) (*gin.Engine, *counter) {
+	gin.SetMode(gin.TestMode)
+	ct := &counter{runs: map[string]int{}, block: make(chan struct{})}
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	//the user comes from the token in the API, a header is enough here
+	router.Use(func(c *gin.Context) { c.Set("uid", c.GetHeader("X-User")) })
+	router.Use(Idempotency(memory.NewIdempotencyRepository(), time.Hour, lease, time.Second, 1<<10))
+
+	router.POST("/count", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"runs": ct.run("count")})
+	})
+	router.GET("/count", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"runs": ct.run("count")})
+	})
+	router.POST("/refused", func(c *gin.Context) {
+		ct.run("refused")
+		c.Error(apierror.BadRequest("refused"))
+	})
+	router.POST("/flaky", func(c *gin.Context) {
+		n := ct.run("flaky")
+		if n == 1 {
+			c.Error(apierror.Internal("down", nil))
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"runs": n})
+	})
+	router.POST("/panics", func(c *gin.Context) {
+		n := ct.run("panics")
+		if n == 1 {
+			panic("first run")
+		}
+		c.JSON(http.StatusCreated, gin.H{"runs": n})
+	})
+	router.POST("/slow", func(c *gin.Context) {
+		n := ct.run("slow")
+		if n == 1 {
+			<-ct.block
+		}
+		c.JSON(http.StatusCreated, gin.H{"runs": n})
+	})
+	return router, ct
+}
+
+type call struct {
+	method string
+	path   string
+	user   string
+	key    string
+	body   string
+}
+
+func send(router *gin.Engine, cl call) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(cl.method, cl.path, strings.NewReader(cl.body))
+	req.Header.Set("Content-Type", "application/json")
+	if cl.user == "" {
+		cl.user = "u1"
+	}
+	req.Header.Set("X-User", cl.user)
+	if cl.key != "" {
+		req.Header.Set(IdempotencyKeyHeader, cl.key)
+	}
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	return res
+}
+
+// runs reads the run count of an answer, or the error code of a failed one
+func runs(t *testing.T, res *httptest.ResponseRecorder) (int, string) {
+	t.Helper()
+	var body struct {
+		Runs int    `json:"runs"`
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.Fatalf("answer %q is not JSON: %v", res.Body.String(), err)
+	}
+	return body.Runs, body.Code
+}
+
+func TestIdempotency(t *testing.T) {
+	type want struct {
+		status   int
+		runs     int
+		code     apierror.Code
+		replayed bool
+	}
+	tests := []struct {
+		name  string
+		calls []call
+		wants []want
+	}{
+		{
+			name:  "retry is replayed",
+			calls: []call{{method: "POST", path: "/count", key: "k1", body: `{"a":1}`}, {method: "POST", path: "/count", key: "k1", body: `{"a":1}`}},
+			wants: []want{{status: 201, runs: 1}, {status: 201, runs: 1, replayed: true}},
+		},
+		{
+			name:  "no key runs twice",
+			calls: []call{{method: "POST", path: "/count"}, {method: "POST", path: "/count"}},
+			wants: []want{{status: 201, runs: 1}, {status: 201, runs: 2}},
+		},
+		{
+			name:  "other keys run",
+			calls: []call{{method: "POST", path: "/count", key: "k1"}, {method: "POST", path: "/count", key: "k2"}},
+			wants: []want{{status: 201, runs: 1}, {status: 201, runs: 2}},
+		},
+		{
+			name:  "keys belong to their user",
+			calls: []call{{method: "POST", path: "/count", key: "k1"}, {method: "POST", path: "/count", key: "k1", user: "u2"}},
+			wants: []want{{status: 201, runs: 1}, {status: 201, runs: 2}},
+		},
+		{
+			name:  "GET is not stored",
+			calls: []call{{method: "GET", path: "/count", key: "k1"}, {method: "GET", path: "/count", key: "k1"}},
+			wants: []want{{status: 200, runs: 1}, {status: 200, runs: 2}},
+		},
+		{
+			name:  "another body reuses the key",
+			calls: []call{{method: "POST", path: "/count", key: "k1", body: `{"a":1}`}, {method: "POST", path: "/count", key: "k1", body: `{"a":2}`}},
+			wants: []want{{status: 201, runs: 1}, {status: 409, code: apierror.CodeIdempotencyReused}},
+		},
+		{
+			name:  "another route reuses the key",
+			calls: []call{{method: "POST", path: "/count", key: "k1"}, {method: "POST", path: "/flaky", key: "k1"}},
+			wants: []want{{status: 201, runs: 1}, {status: 409, code: apierror.CodeIdempotencyReused}},
+		},
+		{
+			name:  "client errors are replayed",
+			calls: []call{{method: "POST", path: "/refused", key: "k1"}, {method: "POST", path: "/refused", key: "k1"}},
+			wants: []want{{status: 400, code: apierror.CodeBadRequest}, {status: 400, code: apierror.CodeBadRequest, replayed: true}},
+		},
+		{
+			name:  "server errors free the key",
+			calls: []call{{method: "POST", path: "/flaky", key: "k1"}, {method: "POST", path: "/flaky", key: "k1"}, {method: "POST", path: "/flaky", key: "k1"}},
+			wants: []want{{status: 500, code: apierror.CodeInternal}, {status: 201, runs: 2}, {status: 201, runs: 2, replayed: true}},
+		},
+		{
+			name:  "panics free the key",
+			calls: []call{{method: "POST", path: "/panics", key: "k1"}, {method: "POST", path: "/panics", key: "k1"}},
+			wants: []want{{status: 500, code: apierror.CodeInternal}, {status: 201, runs: 2}},
+		},
+		{
+			name:  "body over the limit",
+			calls: []call{{method: "POST", path: "/count", key: "k1", body: strings.Repeat("a", 2<<10)}, {method: "POST", path: "/count", body: strings.Repeat("a", 2<<10)}},
+			wants: []want{{status: 413, code: apierror.CodePayloadTooLarge}, {status: 201, runs: 1}},
+		},
+		{
+			name:  "invalid key",
+			calls: []call{{method: "POST", path: "/count", key: "with space"}},
+			wants: []want{{status: 400, code: apierror.CodeBadRequest}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := idempotentRouter(time.Minute)
+			for i, cl := range tt.calls {
+				res := send(router, cl)
+				gotRuns, gotCode := runs(t, res)
+				w := tt.wants[i]
+				replayed := res.Header().Get(IdempotentReplayedHeader) == "true"
+				if res.Code != w.status || gotRuns != w.runs || gotCode != string(w.code) || replayed != w.replayed {
+					t.Errorf("call %d: status %d, runs %d, code %q, replayed %v, want %d, %d, %q, %v",
+						i+1, res.Code, gotRuns, gotCode, replayed, w.status, w.runs, w.code, w.replayed)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyPending(t *testing.T) {
+	router, ct := idempotentRouter(time.Minute)
+	slow := call{method: "POST", path: "/slow", key: "k1"}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- send(router, slow) }()
+	waitForRun(t, ct, "slow")
+
+	res := send(router, slow)
+	if _, code := runs(t, res); res.Code != http.StatusConflict || code != string(apierror.CodeIdempotencyPending) {
+		t.Errorf("repeat while running: status %d, code %q, want 409 %q", res.Code, code, apierror.CodeIdempotencyPending)
+	}
+
+	close(ct.block)
+	if res := <-first; res.Code != http.StatusCreated {
+		t.Fatalf("first request: status %d, want 201", res.Code)
+	}
+	res = send(router, slow)
+	if n, _ := runs(t, res); res.Code != http.StatusCreated || n != 1 || res.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("repeat once answered: status %d, runs %d, want the first answer replayed", res.Code, n)
+	}
+}
+
+func TestIdempotencyLeaseExpires(t *testing.T) {
+	lease := 20 * time.Millisecond
+	router, ct := idempotentRouter(lease)
+	slow := call{method: "POST", path: "/slow", key: "k1"}
+
+	//the first request stands for one whose server stopped, it holds the
+	//key only until the lease runs out
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- send(router, slow) }()
+	waitForRun(t, ct, "slow")
+	time.Sleep(2 * lease)
+
+	res := send(router, slow)
+	if n, _ := runs(t, res); res.Code != http.StatusCreated || n != 2 {
+		t.Errorf("retry after the lease: status %d, runs %d, want 201 from a second run", res.Code, n)
+	}
+	close(ct.block)
+	<-first
+
+	//the answer of the retry is the one kept
+	res = send(router, slow)
+	if n, _ := runs(t, res); n != 2 || res.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("repeat: runs %d, replayed %q, want the retry replayed", n, res.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func waitForRun(t *testing.T, ct *counter, name string) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		ct.mu.Lock()
+		n := ct.runs[name]
+		ct.mu.Unlock()
+		if n > 0 {
+			return
+		}
+	}
+	t.Fatalf("%s never ran", name)
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Allergens are the 14 allergens EU food law requires to be declared
+var Allergens = []string{
+	"celery", "gluten", "crustaceans", "eggs", "fish", "lupin", "milk",
+	"molluscs", "mustard", "peanuts", "sesame", "soy", "sulphites", "tree_nuts",
+}
+
+// dietary tags a food can carry
+const (
+	DietVegan      = "vegan"
+	DietVegetarian = "vegetarian"
+	DietHalal      = "halal"
+	DietGlutenFree = "gluten_free"
+)
+
+var Diets = []string{DietVegan, DietVegetarian, DietHalal, DietGlutenFree}
+
+// dietAllergens are the allergens a food tagged with the diet cannot contain
+var dietAllergens = map[string][]string{
+	DietVegan:      {"eggs", "milk", "fish", "crustaceans", "molluscs"},
+	DietVegetarian: {"fish", "crustaceans", "molluscs"},
+	DietGlutenFree: {"gluten"},
+}
+
+// allergenWords maps the words guests and staff use in notes to allergens
+var allergenWords = map[string][]string{
+	"celery": {"celery"}, "celeriac": {"celery"},
+	"gluten": {"gluten"}, "wheat": {"gluten"}, "barley": {"gluten"}, "rye": {"gluten"}, "coeliac": {"gluten"}, "celiac": {"gluten"},
+	"crustacean": {"crustaceans"}, "crustaceans": {"crustaceans"}, "shrimp": {"crustaceans"}, "prawn": {"crustaceans"}, "prawns": {"crustaceans"}, "crab": {"crustaceans"}, "lobster": {"crustaceans"},
+	"shellfish": {"crustaceans", "molluscs"},
+	"egg":       {"eggs"}, "eggs": {"eggs"},
+	"fish":  {"fish"},
+	"lupin": {"lupin"}, "lupine": {"lupin"},
+	"milk": {"milk"}, "dairy": {"milk"}, "lactose": {"milk"},
+	"mollusc": {"molluscs"}, "molluscs": {"molluscs"}, "mussels": {"molluscs"}, "oysters": {"molluscs"}, "squid": {"molluscs"},
+	"mustard": {"mustard"},
+	"peanut":  {"peanuts"}, "peanuts": {"peanuts"},
+	"sesame": {"sesame"},
+	"soy":    {"soy"}, "soya": {"soy"}, "soybeans": {"soy"},
+	"sulphites": {"sulphites"}, "sulphite": {"sulphites"}, "sulfites": {"sulphites"}, "sulfite": {"sulphites"},
+	"nut": {"tree_nuts"}, "nuts": {"tree_nuts"}, "almond": {"tree_nuts"}, "almonds": {"tree_nuts"}, "hazelnut": {"tree_nuts"}, "hazelnuts": {"tree_nuts"},
+	"walnut": {"tree_nuts"}, "walnuts": {"tree_nuts"}, "cashew": {"tree_nuts"}, "cashews": {"tree_nuts"}, "pistachio": {"tree_nuts"}, "pistachios": {"tree_nuts"},
+}
+
+// Nutrition is per serving, Serving_size in grams
+type Nutrition struct {
+	Serving_size  *float64 `json:"serving_size" bson:"serving_size" validate:"omitempty,gt=0"`
+	Calories      *float64 `json:"calories" bson:"calories" validate:"omitempty,min=0"`
+	Protein       *float64 `json:"protein" bson:"protein" validate:"omitempty,min=0"`
+	Carbohydrates *float64 `json:"carbohydrates" bson:"carbohydrates" validate:"omitempty,min=0"`
+	Sugars        *float64 `json:"sugars" bson:"sugars" validate:"omitempty,min=0"`
+	Fat           *float64 `json:"fat" bson:"fat" validate:"omitempty,min=0"`
+	Saturated_fat *float64 `json:"saturated_fat" bson:"saturated_fat" validate:"omitempty,min=0"`
+	Fibre         *float64 `json:"fibre" bson:"fibre" validate:"omitempty,min=0"`
+	Salt          *float64 `json:"salt" bson:"salt" validate:"omitempty,min=0"`
+}
+
+// SatisfyingDiets are the tags that meet diet, a vegan food is vegetarian too
+func SatisfyingDiets(diet string) []string {
+	if diet == DietVegetarian {
+		return []string{DietVegetarian, DietVegan}
+	}
+	return []string{diet}
+}
+
+// CheckDiet catches tags that contradict the declared allergens, a vegan
+// food containing milk
+func (food Food) CheckDiet() error {
+	for _, diet := range food.Dietary_tags {
+		for _, allergen := range dietAllergens[diet] {
+			if food.Contains(allergen) {
+				return fmt.Errorf("a %s food cannot contain %s", diet, allergen)
+			}
+		}
+	}
+	return nil
+}
+
+func (food Food) Contains(allergen string) bool {
+	for _, declared := range food.Allergens {
+		if declared == allergen {
+			return true
+		}
+	}
+	return false
+}
+
+// Allergies returns the allergens a note names when it is about an allergy,
+// "Allergy: peanuts and shellfish"
+func (note Note) Allergies() []string {
+	text := strings.ToLower(note.Label())
+	if !strings.Contains(text, "allerg") && !strings.Contains(text, "intoleran") {
+		return nil
+	}
+	found := map[string]bool{}
+	var allergies []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for _, allergen := range allergenWords[word] {
+			if !found[allergen] {
+				found[allergen] = true
+				allergies = append(allergies, allergen)
+			}
+		}
+	}
+	return allergies
+}
+
+// AllergyWarnings lists the allergens of food that an allergy note in notes
+// warns about
+func (food Food) AllergyWarnings(notes []Note) []string {
+	warnings := []string{}
+	warned := map[string]bool{}
+	for _, note := range notes {
+		for _, allergen := range note.Allergies() {
+			if food.Contains(allergen) && !warned[allergen] {
+				warned[allergen] = true
+				warnings = append(warnings, fmt.Sprintf("%s contains %s, see note %q", food.Namme, strings.ReplaceAll(allergen, "_", " "), note.Label()))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// a drawer is opened with a float and closed once with the counted cash,
+// only one can be open at a time
+const (
+	DrawerOpen   = "OPEN"
+	DrawerClosed = "CLOSED"
+)
+
+// drops move cash from the drawer to the safe, payouts pay for something
+// out of it, both take cash out of the drawer
+const (
+	DrawerDrop   = "DROP"
+	DrawerPayout = "PAYOUT"
+)
+
+// CashDrawer is the cash of one shift. Expected_cash is the float plus the
+// CASH payments of the period less drops and payouts, Variance is how far
+// the counted cash is off from it.
+type CashDrawer struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Drawer_id     string             `json:"drawer_id"`
+	Status        *string            `json:"status"`
+	Opening_float *Money             `json:"opening_float" validate:"omitempty,gte=0"`
+	Movements     []DrawerMovement   `json:"movements"`
+	Counted_cash  *Money             `json:"counted_cash" validate:"omitempty,gte=0"`
+	Expected_cash *Money             `json:"expected_cash"`
+	Variance      *Money             `json:"variance"`
+	Z_report_id   *string            `json:"z_report_id"`
+	Opened_by     string             `json:"opened_by"`
+	Closed_by     string             `json:"closed_by"`
+	Opened_at     time.Time          `json:"opened_at"`
+	Closed_at     *time.Time         `json:"closed_at"`
+}
+
+type DrawerMovement struct {
+	Type       *string   `json:"type" validate:"required,eq=DROP|eq=PAYOUT"`
+	Amount     *Money    `json:"amount" validate:"required,gt=0"`
+	Reason     *string   `json:"reason" validate:"required_if=Type PAYOUT,omitempty,max=200"`
+	Created_by string    `json:"created_by"`
+	Created_at time.Time `json:"created_at"`
+}
+
+// CashOut is what left the drawer through drops and payouts
+func (drawer CashDrawer) CashOut() (drops Money, payouts Money) {
+	drops, payouts = Cents(0), Cents(0)
+	for _, movement := range drawer.Movements {
+		if movement.Amount == nil || movement.Type == nil {
+			continue
+		}
+		if *movement.Type == DrawerDrop {
+			drops = drops.Add(*movement.Amount)
+		} else {
+			payouts = payouts.Add(*movement.Amount)
+		}
+	}
+	return drops, payouts
+}
+
+// ZReport is written once when a drawer is closed and never changed. It
+// covers everything since the previous Z report, invoices issued before
+// Period_end can no longer be edited.
+type ZReport struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Z_report_id   string             `json:"z_report_id"`
+	Number        int                `json:"number"`
+	Drawer_id     string             `json:"drawer_id"`
+	Period_start  time.Time          `json:"period_start"`
+	Period_end    time.Time          `json:"period_end"`
+	Invoices      int                `json:"invoices"`
+	Gross         Money              `json:"gross"`
+	Net           Money              `json:"net"`
+	Tax           Money              `json:"tax"`
+	Discounts     Money              `json:"discounts"`
+	Payments      []PaymentMixRow    `json:"payments"`
+	Voids         VoidSummary        `json:"voids"`
+	Opening_float Money              `json:"opening_float"`
+	Cash_payments Money              `json:"cash_payments"`
+	Drops         Money              `json:"drops"`
+	Payouts       Money              `json:"payouts"`
+	Expected_cash Money              `json:"expected_cash"`
+	Counted_cash  Money              `json:"counted_cash"`
+	Variance      Money              `json:"variance"`
+	Closed_by     string             `json:"closed_by"`
+	Created_at    time.Time          `json:"created_at"`
+}
+
+// VoidSummary is what the orders voided in a period had on them
+type VoidSummary struct {
+	Orders int   `json:"orders" bson:"orders"`
+	Items  int   `json:"items" bson:"items"`
+	Amount Money `json:"amount" bson:"amount"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,11 +10,188 @@ import (
 
 type Food struct {
 	ID         primitive.ObjectID `bson:"_id"`
-	Namme      string             `json:"name" validate:"required,min=2,max=100"`
-	Price      *float64           `json:"price" validate:"required"`
-	Food_image *string            `json:"food_image" validate:"required"`
+	Namme      string             `json:"name" bson:"name" validate:"required,min=2,max=100"`
+	Price      *Money             `json:"price" validate:"required,gt=0"`
+	Food_image *string            `json:"food_image"`
 	Created_at time.Time          `json:"created_at"`
 	Updated_at time.Time          `json:"updated_at"`
-	Food_id    string             `json:"food_id" validate:"required"`
+	Food_id    string             `json:"food_id"`
 	Menu_id    *string            `json:"menu_id" validate:"required"`
+	Station    *string            `json:"station" validate:"omitempty,max=30"`
+
+	Variants        []FoodVariant   `json:"variants" validate:"omitempty,dive"`
+	Modifier_groups []ModifierGroup `json:"modifier_groups" validate:"omitempty,dive"`
+	Recipe          []RecipeLine    `json:"recipe" validate:"omitempty,dive"`
+
+	// Allergens are declared from the 14 EU allergens, foods without a
+	// declaration are never offered when a guest excludes allergens
+	Allergens    []string   `json:"allergens" validate:"omitempty,unique,dive,oneof=celery gluten crustaceans eggs fish lupin milk molluscs mustard peanuts sesame soy sulphites tree_nuts"`
+	Dietary_tags []string   `json:"dietary_tags" validate:"omitempty,unique,dive,oneof=vegan vegetarian halal gluten_free"`
+	Nutrition    *Nutrition `json:"nutrition"`
+
+	// Image is set by uploading one, Food_image then links to it too
+	Image *FoodImage `json:"image,omitempty"`
+
+	// Available is false once an ingredient of the recipe runs out, the
+	// food is "86" until it is restocked
+	Available *bool `json:"available,omitempty" bson:"-"`
+}
+
+// FoodImage is an uploaded image and the thumbnails made of it, narrowest
+// first. Keys are where they are stored, removed when it is replaced.
+type FoodImage struct {
+	Url          string           `json:"url" bson:"url"`
+	Content_type string           `json:"content_type" bson:"content_type"`
+	Width        int              `json:"width" bson:"width"`
+	Height       int              `json:"height" bson:"height"`
+	Thumbnails   []ImageThumbnail `json:"thumbnails" bson:"thumbnails"`
+	Keys         []string         `json:"-" bson:"keys"`
+}
+
+type ImageThumbnail struct {
+	Url    string `json:"url" bson:"url"`
+	Width  int    `json:"width" bson:"width"`
+	Height int    `json:"height" bson:"height"`
+}
+
+// FoodVariant is a size or version of a food sold at its own price instead
+// of Price
+type FoodVariant struct {
+	Name  string `json:"name" bson:"name" validate:"required,max=30"`
+	Price Money  `json:"price" bson:"price" validate:"gt=0"`
+}
+
+// ModifierGroup is a choice made for a food, "choose 1 of 3 sides" is
+// Min_select 1 and Max_select 1, "extras" is Min_select 0. Every chosen
+// option adds its Price_delta to the price, which may be negative or zero.
+type ModifierGroup struct {
+	Name       string           `json:"name" bson:"name" validate:"required,max=50"`
+	Min_select int              `json:"min_select" bson:"min_select" validate:"min=0"`
+	Max_select int              `json:"max_select" bson:"max_select" validate:"min=1,gtefield=Min_select"`
+	Options    []ModifierOption `json:"options" bson:"options" validate:"required,min=1,dive"`
+}
+
+type ModifierOption struct {
+	Name        string `json:"name" bson:"name" validate:"required,max=50"`
+	Price_delta Money  `json:"price_delta" bson:"price_delta"`
+}
+
+// CheckOptions catches what the validate tags cannot, repeated names and
+// groups asking for more options than they offer
+func (food Food) CheckOptions() error {
+	variants := map[string]bool{}
+	for _, variant := range food.Variants {
+		if variants[strings.ToUpper(variant.Name)] {
+			return fmt.Errorf("variant %s is listed twice", variant.Name)
+		}
+		variants[strings.ToUpper(variant.Name)] = true
+	}
+	groups := map[string]bool{}
+	for _, group := range food.Modifier_groups {
+		if groups[strings.ToUpper(group.Name)] {
+			return fmt.Errorf("modifier group %s is listed twice", group.Name)
+		}
+		groups[strings.ToUpper(group.Name)] = true
+		if group.Min_select > len(group.Options) {
+			return fmt.Errorf("modifier group %s needs %d options but has %d", group.Name, group.Min_select, len(group.Options))
+		}
+		options := map[string]bool{}
+		for _, option := range group.Options {
+			if options[strings.ToUpper(option.Name)] {
+				return fmt.Errorf("option %s is listed twice in modifier group %s", option.Name, group.Name)
+			}
+			options[strings.ToUpper(option.Name)] = true
+		}
+	}
+	return nil
+}
+
+// StockUses is what quantity of the food takes from stock
+func (food Food) StockUses(quantity int) []StockUse {
+	uses := []StockUse{}
+	for _, line := range food.Recipe {
+		uses = append(uses, StockUse{Ingredient_id: line.Ingredient_id, Quantity: RoundStock(line.Quantity * float64(quantity))})
+	}
+	return uses
+}
+
+// PriceOrderItem fills in the unit and line price of orderItem from the
+// variant and modifiers chosen, which must satisfy every modifier group.
+// The variant and modifiers are stored as the food names them.
+func (food Food) PriceOrderItem(orderItem OrderItem) (OrderItem, error) {
+	var price Money
+	if food.Price != nil {
+		price = *food.Price
+	}
+	if orderItem.Variant != nil && *orderItem.Variant != "" {
+		found := false
+		for _, variant := range food.Variants {
+			if strings.EqualFold(variant.Name, *orderItem.Variant) {
+				name := variant.Name
+				orderItem.Variant = &name
+				price, found = variant.Price, true
+				break
+			}
+		}
+		if !found {
+			return orderItem, fmt.Errorf("%s has no variant %s", food.Namme, *orderItem.Variant)
+		}
+	} else if len(food.Variants) > 0 {
+		return orderItem, fmt.Errorf("please choose a variant of %s", food.Namme)
+	} else {
+		orderItem.Variant = nil
+	}
+
+	chosen := []OrderItemModifier{}
+	counts := map[string]int{}
+	for _, modifier := range orderItem.Modifiers {
+		option, group, ok := food.modifierOption(modifier.Group, modifier.Name)
+		if !ok {
+			return orderItem, fmt.Errorf("%s has no modifier %s in %s", food.Namme, modifier.Name, modifier.Group)
+		}
+		for _, previous := range chosen {
+			if previous.Group == group.Name && previous.Name == option.Name {
+				return orderItem, fmt.Errorf("modifier %s is chosen twice", option.Name)
+			}
+		}
+		counts[group.Name]++
+		chosen = append(chosen, OrderItemModifier{Group: group.Name, Name: option.Name, Price_delta: option.Price_delta})
+		price = price.Add(option.Price_delta)
+	}
+	for _, group := range food.Modifier_groups {
+		if counts[group.Name] < group.Min_select || counts[group.Name] > group.Max_select {
+			if group.Min_select == group.Max_select {
+				return orderItem, fmt.Errorf("please choose %d of %s", group.Min_select, group.Name)
+			}
+			return orderItem, fmt.Errorf("please choose between %d and %d of %s", group.Min_select, group.Max_select, group.Name)
+		}
+	}
+	if price.Amount < 0 {
+		return orderItem, fmt.Errorf("the modifiers take the price of %s below zero", food.Namme)
+	}
+
+	quantity := 1
+	if orderItem.Quantity != nil {
+		quantity = *orderItem.Quantity
+	}
+	linePrice := price.Times(quantity)
+	orderItem.Quantity = &quantity
+	orderItem.Modifiers = chosen
+	orderItem.Unit_price = &price
+	orderItem.Line_price = &linePrice
+	return orderItem, nil
+}
+
+func (food Food) modifierOption(groupName string, optionName string) (ModifierOption, ModifierGroup, bool) {
+	for _, group := range food.Modifier_groups {
+		if !strings.EqualFold(group.Name, groupName) {
+			continue
+		}
+		for _, option := range group.Options {
+			if strings.EqualFold(option.Name, optionName) {
+				return option, group, true
+			}
+		}
+	}
+	return ModifierOption{}, ModifierGroup{}, false
 }
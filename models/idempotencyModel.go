@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdempotencyRecord is the first answer to a POST sent with an
+// Idempotency-Key. Key is the header scoped to the user who sent it and
+// Fingerprint is a hash of the request, a repeat with another fingerprint
+// is a different request reusing the key. Status stays 0 until the first
+// request is answered, the record is dropped at Expires_at, which is short
+// while the request runs so a key is not held by a server that stopped.
+type IdempotencyRecord struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Key          string             `json:"key"`
+	Fingerprint  string             `json:"fingerprint"`
+	Status       int                `json:"status"`
+	Content_type string             `json:"content_type"`
+	Body         []byte             `json:"body"`
+	Created_at   time.Time          `json:"created_at"`
+	Expires_at   time.Time          `json:"expires_at"`
+}
+
+// Answered tells whether the first request got its response
+func (record IdempotencyRecord) Answered() bool {
+	return record.Status != 0
+}
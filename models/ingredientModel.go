@@ -0,0 +1,46 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ingredient is something the kitchen keeps in stock, counted in Unit.
+// It is low on stock once Stock falls to Low_stock_threshold.
+type Ingredient struct {
+	ID                  primitive.ObjectID `bson:"_id"`
+	Name                *string            `json:"name" validate:"required,min=2,max=100"`
+	Unit                *string            `json:"unit" validate:"required,eq=g|eq=kg|eq=ml|eq=l|eq=pcs"`
+	Stock               *float64           `json:"stock" validate:"required,gte=0"`
+	Low_stock_threshold *float64           `json:"low_stock_threshold" validate:"omitempty,gte=0"`
+	Low_stock           bool               `json:"low_stock" bson:"-"`
+	Created_at          time.Time          `json:"created_at"`
+	Updated_at          time.Time          `json:"updated_at"`
+	Ingredient_id       string             `json:"ingredient_id"`
+}
+
+// RecipeLine is how much of an ingredient one of a food takes
+type RecipeLine struct {
+	Ingredient_id string  `json:"ingredient_id" bson:"ingredient_id" validate:"required"`
+	Quantity      float64 `json:"quantity" bson:"quantity" validate:"gt=0"`
+}
+
+// StockUse is how much of an ingredient an order item took from stock, it
+// is given back exactly when the order is cancelled or voided
+type StockUse struct {
+	Ingredient_id string  `json:"ingredient_id" bson:"ingredient_id"`
+	Quantity      float64 `json:"quantity" bson:"quantity"`
+}
+
+// IsLow is whether the stock has fallen to the threshold
+func (ingredient Ingredient) IsLow() bool {
+	return ingredient.Low_stock_threshold != nil && ingredient.Stock != nil && *ingredient.Stock <= *ingredient.Low_stock_threshold
+}
+
+// RoundStock keeps stock levels to six decimals so repeated fractions of a
+// unit add up exactly
+func RoundStock(quantity float64) float64 {
+	return math.Round(quantity*1e6) / 1e6
+}
@@ -6,13 +6,39 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// an invoice is paid by one or more payments, it is PAID once they cover
+// the total
+const (
+	PaymentPending       = "PENDING"
+	PaymentPartiallyPaid = "PARTIALLY_PAID"
+	PaymentPaid          = "PAID"
+)
+
+const (
+	PaymentCard    = "CARD"
+	PaymentCash    = "CASH"
+	PaymentVoucher = "VOUCHER"
+	// PaymentMixed is the invoice payment method once it was paid with
+	// more than one method
+	PaymentMixed = "MIXED"
+)
+
 type Invoice struct {
 	ID               primitive.ObjectID `bson:"_id"`
 	Invoice_id       string             `json:"invoice_id"`
 	Order_id         string             `json:"order_id"`
-	Payment_method   *string            `json:"payment_method" validate:"eq=CARD|eq=CASAH|eq="`
-	Payment_status   *string            `json:"payment_ststus" validate:"required,eq=PENDING|eq=PAID"`
+	Payment_method   *string            `json:"payment_method" validate:"omitempty,eq=CARD|eq=CASH|eq=VOUCHER|eq=MIXED"`
+	Payment_status   *string            `json:"payment_status" validate:"required,eq=PENDING|eq=PARTIALLY_PAID|eq=PAID" openapi:"readonly"`
+	Discounts        []AppliedDiscount  `json:"discounts"`
+	Discount_amount  *Money             `json:"discount_amount"`
+	Net_amount       *Money             `json:"net_amount"`
+	Tax_amount       *Money             `json:"tax_amount"`
+	Tax_breakdown    []TaxLine          `json:"tax_breakdown"`
+	Total_amount     *Money             `json:"total_amount"`
+	Amount_paid      Money              `json:"amount_paid"`
+	Balance_due      *Money             `json:"balance_due"`
+	Change_due       Money              `json:"change_due"`
 	Payment_due_date time.Time          `json:"payment_due_date"`
-	Created_at       time.Time          `json:"create_at"`
-	Updated_at       time.Time          `json:"update_at"`
+	Created_at       time.Time          `json:"created_at"`
+	Updated_at       time.Time          `json:"updated_at"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// KitchenTicket is what the kitchen display shows for one order item
+type KitchenTicket struct {
+	Order_item_id string   `json:"order_item_id"`
+	Order_id      string   `json:"order_id"`
+	Food_id       *string  `json:"food_id"`
+	Food_name     string   `json:"food_name"`
+	Quantity      *int     `json:"quantity"`
+	Variant       *string  `json:"variant"`
+	Modifiers     []string `json:"modifiers"`
+	Notes         []string `json:"notes"`
+	// Allergy_warnings name the allergens of the food that the notes warn about
+	Allergy_warnings []string  `json:"allergy_warnings"`
+	Station          *string   `json:"station"`
+	Table_number     *int      `json:"table_number"`
+	Kitchen_status   *string   `json:"kitchen_status"`
+	Created_at       time.Time `json:"created_at"`
+	Updated_at       time.Time `json:"updated_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,7 +13,92 @@ type Menu struct {
 	Category   string             `json:"category" validate:"required"`
 	Start_Date *time.Time         `json:"start_date"`
 	End_Date   *time.Time         `json:"end_date"`
+	Timezone   *string            `json:"timezone"`
+	Schedules  []MenuSchedule     `json:"schedules" validate:"omitempty,dive"`
 	Created_at time.Time          `json:"created_at"`
-	Updated_at time.Time          `json:"update_at"`
+	Updated_at time.Time          `json:"updated_at"`
 	Menu_id    string             `json:"menu_id"`
 }
+
+// MenuSchedule is a weekly window in which a menu can be ordered from,
+// "07:00" to "11:00" on MON to FRI. A window ending before it starts runs
+// past midnight and belongs to the day it starts on.
+type MenuSchedule struct {
+	Days  []string `json:"days" bson:"days" validate:"required,min=1,dive,oneof=MON TUE WED THU FRI SAT SUN"`
+	Start string   `json:"start" bson:"start" validate:"required,datetime=15:04"`
+	End   string   `json:"end" bson:"end" validate:"required,datetime=15:04,nefield=Start"`
+}
+
+var weekdays = [...]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// Location is the menu's timezone, or fallback when it has none
+func (menu Menu) Location(fallback *time.Location) (*time.Location, error) {
+	if menu.Timezone == nil || *menu.Timezone == "" {
+		return fallback, nil
+	}
+	location, err := time.LoadLocation(*menu.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", *menu.Timezone)
+	}
+	return location, nil
+}
+
+// IsActive is whether the menu can be ordered from at t. Outside its start
+// and end dates it never is, within them a menu without schedules always
+// is and one with schedules only during them, in its own timezone.
+func (menu Menu) IsActive(t time.Time, fallback *time.Location) bool {
+	if menu.Start_Date != nil && t.Before(*menu.Start_Date) {
+		return false
+	}
+	if menu.End_Date != nil && !t.Before(*menu.End_Date) {
+		return false
+	}
+	if len(menu.Schedules) == 0 {
+		return true
+	}
+
+	location, err := menu.Location(fallback)
+	if err != nil {
+		return false
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := weekdays[local.Weekday()]
+	yesterday := weekdays[(local.Weekday()+6)%7]
+
+	for _, schedule := range menu.Schedules {
+		start, startErr := minuteOfDay(schedule.Start)
+		end, endErr := minuteOfDay(schedule.End)
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		if start < end {
+			if hasDay(schedule.Days, today) && minute >= start && minute < end {
+				return true
+			}
+			continue
+		}
+		//past midnight: the evening of a scheduled day or the early hours after it
+		if (hasDay(schedule.Days, today) && minute >= start) || (hasDay(schedule.Days, yesterday) && minute < end) {
+			return true
+		}
+	}
+	return false
+}
+
+func minuteOfDay(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func hasDay(days []string, day string) bool {
+	for _, scheduled := range days {
+		if scheduled == day {
+			return true
+		}
+	}
+	return false
+}
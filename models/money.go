@@ -0,0 +1,144 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// DefaultCurrency is the ISO 4217 code given to amounts read from requests,
+// main sets it from the restaurant configuration
+var DefaultCurrency = "USD"
+
+// minorUnits is how many minor units make one major unit. Every amount is
+// kept in cents, currencies without two decimals are not supported.
+const minorUnits = 100
+
+// Money is an exact amount in minor units of Currency. It is stored as
+// {amount: 999, currency: "USD"} so totals summed in the database never
+// drift, and written in JSON as the plain decimal 9.99.
+type Money struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+}
+
+// Cents is amount minor units of the default currency
+func Cents(amount int64) Money {
+	return Money{Amount: amount, Currency: DefaultCurrency}
+}
+
+// ParseMoney reads a decimal like "9.99" exactly, digits past the cents are
+// rounded half away from zero
+func ParseMoney(text string) (Money, error) {
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return Money{}, fmt.Errorf("%q is not an amount", text)
+	}
+	value.Mul(value, big.NewRat(minorUnits, 1))
+
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	if !quotient.IsInt64() {
+		return Money{}, fmt.Errorf("%q is too large", text)
+	}
+	return Cents(quotient.Int64()), nil
+}
+
+func (m Money) currency(other Money) string {
+	if m.Currency == "" {
+		return other.Currency
+	}
+	return m.Currency
+}
+
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.currency(other)}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.currency(other)}
+}
+
+// Cmp is -1, 0 or +1 as m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Times is n of m
+func (m Money) Times(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.Currency}
+}
+
+// Split divides m into n shares differing by at most one cent, the first
+// shares take the remainder
+func (m Money) Split(n int) []Money {
+	shares := make([]Money, n)
+	share, remainder := m.Amount/int64(n), m.Amount%int64(n)
+	for i := range shares {
+		shares[i] = Money{Amount: share, Currency: m.Currency}
+		if int64(i) < remainder {
+			shares[i].Amount++
+		}
+	}
+	return shares
+}
+
+// Float64 is only for display and for ratios, never add the results
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / minorUnits
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnits, amount%minorUnits)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON takes a number or a string holding one, both are read as
+// decimals, not floats
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(bytes.TrimSpace(data))
+	if text == "null" {
+		return nil
+	}
+	if len(text) > 0 && text[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	//big.Rat also reads fractions like 1/3, amounts are decimals only
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return fmt.Errorf("%q is not an amount", text)
+	}
+	money, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
@@ -6,11 +6,36 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// what a note can be attached to, Subject_id is the id of one of these
+const (
+	NoteOrder       = "ORDER"
+	NoteOrderItem   = "ORDER_ITEM"
+	NoteTable       = "TABLE"
+	NoteReservation = "RESERVATION"
+)
+
+// Note is free text attached to an order, order item, table or reservation.
+// Notes of order items and their order are printed on the kitchen tickets.
 type Note struct {
-	ID         primitive.ObjectID `bson:"_id"`
-	Text       string             `json:"text"`
-	Title      string             `json:"title"`
-	Created_at time.Time          `json:"created_at"`
-	Updated_at time.Time          `json:"updated_at"`
-	Note_id    string             `json:"note_id"`
+	ID           primitive.ObjectID `bson:"_id"`
+	Text         string             `json:"text" validate:"required,max=1000"`
+	Title        string             `json:"title" validate:"max=100"`
+	Subject_type *string            `json:"subject_type" validate:"required,eq=ORDER|eq=ORDER_ITEM|eq=TABLE|eq=RESERVATION"`
+	Subject_id   *string            `json:"subject_id" validate:"required"`
+	Author_id    string             `json:"author_id"`
+	Created_at   time.Time          `json:"created_at"`
+	Updated_at   time.Time          `json:"updated_at"`
+	Note_id      string             `json:"note_id"`
+
+	// Allergy_warnings lists the items already ordered that an allergy note
+	// conflicts with, it is only worked out when the note is written
+	Allergy_warnings []string `json:"allergy_warnings,omitempty" bson:"-"`
+}
+
+// Label is how a kitchen ticket shows the note, "Allergy: peanuts"
+func (note Note) Label() string {
+	if note.Title == "" {
+		return note.Text
+	}
+	return note.Title + ": " + note.Text
 }
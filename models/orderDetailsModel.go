@@ -0,0 +1,31 @@
+package models
+
+// OrderDetails is the joined view of an order built by AlltheItemsInAnOrder,
+// Notes are those attached to the order itself
+type OrderDetails struct {
+	Payment_due  Money             `json:"payment_due" bson:"payment_due"`
+	Total_count  int               `json:"total_count" bson:"total_count"`
+	Table_number *int              `json:"table_number" bson:"table_number"`
+	Order_items  []OrderItemDetail `json:"order_items" bson:"order_items"`
+	Notes        []Note            `json:"notes" bson:"notes"`
+}
+
+type OrderItemDetail struct {
+	Order_item_id string  `json:"order_item_id" bson:"order_item_id"`
+	Food_id       *string `json:"food_id" bson:"food_id"`
+	Category      *string `json:"category" bson:"category"`
+	Amount        *Money  `json:"amount" bson:"amount"`
+	Food_name     *string `json:"food_name" bson:"food_name"`
+	Food_image    *string `json:"food_image" bson:"food_image"`
+	Table_number  *int    `json:"table_number" bson:"table_number"`
+	Table_id      *string `json:"table_id" bson:"table_id"`
+	Order_id      string  `json:"order_id" bson:"order_id"`
+	Price         *Money  `json:"price" bson:"price"`
+	Quantity      *int    `json:"quantity" bson:"quantity"`
+
+	Variant   *string             `json:"variant" bson:"variant"`
+	Modifiers []OrderItemModifier `json:"modifiers" bson:"modifiers"`
+	Notes     []Note              `json:"notes" bson:"notes"`
+
+	Allergy_warnings []string `json:"allergy_warnings" bson:"-"`
+}
@@ -6,13 +6,47 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// kitchen progress of a single order item, cooks bump items forward.
+// Items of a cancelled or voided order that were not done are CANCELLED.
+const (
+	KitchenPending   = "PENDING"
+	KitchenPreparing = "PREPARING"
+	KitchenDone      = "DONE"
+	KitchenCancelled = "CANCELLED"
+)
+
+// OrderItem is Quantity of one food. Unit_price is the price of the chosen
+// variant plus the price deltas of the modifiers, Line_price is that times
+// Quantity. Both are worked out when the item is ordered.
 type OrderItem struct {
-	ID            primitive.ObjectID `bson:"_id"`
-	Quantity      *string            `json:"quantity" validate:"required,eq=S|eq=M|eq=L"`
-	Unit_price    *float64           `json:"unit_price" validate:"required"`
-	Created_at    time.Time          `json:"created_at"`
-	Updated_at    time.Time          `json:"updated_at"`
-	Food_id       *string            `json:"food_id" validate:"required"`
-	Order_item_id string             `json:"order_item_id"`
-	Order_id      string             `json:"order_id" validate:"required"`
+	ID             primitive.ObjectID  `bson:"_id"`
+	Quantity       *int                `json:"quantity" validate:"required,min=1,max=99"`
+	Variant        *string             `json:"variant" validate:"omitempty,max=30"`
+	Modifiers      []OrderItemModifier `json:"modifiers" validate:"omitempty,dive"`
+	Unit_price     *Money              `json:"unit_price"`
+	Line_price     *Money              `json:"line_price"`
+	Created_at     time.Time           `json:"created_at"`
+	Updated_at     time.Time           `json:"updated_at"`
+	Food_id        *string             `json:"food_id" validate:"required"`
+	Order_item_id  string              `json:"order_item_id"`
+	Order_id       string              `json:"order_id" validate:"required" openapi:"readonly"`
+	Station        *string             `json:"station"`
+	Kitchen_status *string             `json:"kitchen_status"`
+	Stock_used     []StockUse          `json:"stock_used"`
+
+	// Allergy_warnings is only worked out for the response to a change
+	Allergy_warnings []string `json:"allergy_warnings,omitempty" bson:"-"`
+}
+
+// OrderItemModifier is an option chosen from one of the food's modifier
+// groups, "Sides: Fries"
+type OrderItemModifier struct {
+	Group       string `json:"group" bson:"group" validate:"required"`
+	Name        string `json:"name" bson:"name" validate:"required"`
+	Price_delta Money  `json:"price_delta" bson:"price_delta"`
+}
+
+// Label is how the kitchen and the invoice show the modifier
+func (modifier OrderItemModifier) Label() string {
+	return modifier.Group + ": " + modifier.Name
 }
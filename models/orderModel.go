@@ -6,11 +6,73 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// order lifecycle, an order moves forward through these one step at a time
+const (
+	OrderPlaced        = "PLACED"
+	OrderAccepted      = "ACCEPTED"
+	OrderInPreparation = "IN_PREPARATION"
+	OrderReady         = "READY"
+	OrderServed        = "SERVED"
+	OrderPaid          = "PAID"
+	OrderCancelled     = "CANCELLED"
+	OrderVoided        = "VOIDED"
+)
+
+// orderTransitions lists where each status may go next. Orders are cancelled
+// before the kitchen starts and voided after, paid, cancelled and voided
+// orders are closed.
+var orderTransitions = map[string][]string{
+	OrderPlaced:        {OrderAccepted, OrderCancelled},
+	OrderAccepted:      {OrderInPreparation, OrderCancelled},
+	OrderInPreparation: {OrderReady, OrderVoided},
+	OrderReady:         {OrderServed, OrderVoided},
+	OrderServed:        {OrderPaid, OrderVoided},
+}
+
+// ClosedOrderStatuses are the statuses an order never leaves
+var ClosedOrderStatuses = []string{OrderPaid, OrderCancelled, OrderVoided}
+
 type Order struct {
-	ID         primitive.ObjectID `bson:"_id"`
-	Order_date time.Time          `json:"order_date" validate:"required"`
-	Created_at time.Time          `json:"created_at"`
-	Updated_at time.Time          `json:"update_at"`
-	Order_id   string             `json:"order_id"`
-	Table_id   *string            `json:"table_id" validate:"required"`
+	ID             primitive.ObjectID  `bson:"_id"`
+	Order_date     time.Time           `json:"order_date" validate:"required"`
+	Created_at     time.Time           `json:"created_at"`
+	Updated_at     time.Time           `json:"updated_at"`
+	Order_id       string              `json:"order_id"`
+	Table_id       *string             `json:"table_id" validate:"required"`
+	Covers         *int                `json:"covers" validate:"omitempty,min=1,max=100"`
+	Status         *string             `json:"status"`
+	Status_history []OrderStatusChange `json:"status_history"`
+}
+
+type OrderStatusChange struct {
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Changed_by string    `json:"changed_by"`
+	Changed_at time.Time `json:"changed_at"`
+}
+
+// CurrentStatus treats orders stored before statuses existed as placed
+func (order Order) CurrentStatus() string {
+	if order.Status == nil {
+		return OrderPlaced
+	}
+	return *order.Status
+}
+
+func (order Order) IsClosed() bool {
+	for _, closed := range ClosedOrderStatuses {
+		if order.CurrentStatus() == closed {
+			return true
+		}
+	}
+	return false
+}
+
+func CanTransitionOrder(from string, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payment is one guest's share of an invoice. Amount is what went towards
+// the bill, for cash Tendered is what was handed over and Change_due the
+// difference given back.
+type Payment struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	Payment_id     string             `json:"payment_id"`
+	Invoice_id     string             `json:"invoice_id"`
+	Method         *string            `json:"method" validate:"required,eq=CARD|eq=CASH|eq=VOUCHER"`
+	Amount         *Money             `json:"amount" validate:"omitempty,gt=0"`
+	Tendered       *Money             `json:"tendered" validate:"omitempty,gt=0"`
+	Change_due     Money              `json:"change_due"`
+	Voucher_code   *string            `json:"voucher_code" validate:"required_if=Method VOUCHER"`
+	Order_item_ids []string           `json:"order_item_ids"`
+	Created_by     string             `json:"created_by"`
+	Created_at     time.Time          `json:"created_at"`
+}
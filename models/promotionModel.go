@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PromotionPercent  = "PERCENT"
+	PromotionFixed    = "FIXED"
+	PromotionBuyXGetY = "BUY_X_GET_Y"
+	// PromotionComp is a discount a manager gave by hand, there is no
+	// promotion document behind it
+	PromotionComp = "COMP"
+)
+
+// Promotion takes Percent or Amount off the foods in Food_ids, or off the
+// whole order when there are none. BUY_X_GET_Y makes every Get_quantity
+// cheapest items free for each Buy_quantity bought among Food_ids.
+// Promotions with a coupon code only apply when the code is given and at
+// most Usage_limit times, the others apply to every invoice while active.
+type Promotion struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Name         *string            `json:"name" validate:"required,min=2,max=100"`
+	Type         *string            `json:"type" validate:"required,eq=PERCENT|eq=FIXED|eq=BUY_X_GET_Y"`
+	Percent      *float64           `json:"percent" validate:"required_if=Type PERCENT,omitempty,gt=0,lte=100"`
+	Amount       *Money             `json:"amount" validate:"required_if=Type FIXED,omitempty,gt=0"`
+	Food_ids     []string           `json:"food_ids" validate:"required_if=Type BUY_X_GET_Y"`
+	Buy_quantity *int               `json:"buy_quantity" validate:"required_if=Type BUY_X_GET_Y,omitempty,min=1"`
+	Get_quantity *int               `json:"get_quantity" validate:"required_if=Type BUY_X_GET_Y,omitempty,min=1"`
+	Coupon_code  *string            `json:"coupon_code" validate:"omitempty,min=3,max=30,alphanum"`
+	Usage_limit  *int               `json:"usage_limit" validate:"omitempty,min=1"`
+	Times_used   int                `json:"times_used"`
+	Starts_at    *time.Time         `json:"starts_at"`
+	Expires_at   *time.Time         `json:"expires_at"`
+	Active       *bool              `json:"active"`
+	Created_at   time.Time          `json:"created_at"`
+	Updated_at   time.Time          `json:"updated_at"`
+	Promotion_id string             `json:"promotion_id"`
+}
+
+// InEffect is whether the promotion can be used at t
+func (promotion Promotion) InEffect(t time.Time) bool {
+	if promotion.Active != nil && !*promotion.Active {
+		return false
+	}
+	if promotion.Starts_at != nil && t.Before(*promotion.Starts_at) {
+		return false
+	}
+	if promotion.Expires_at != nil && !t.Before(*promotion.Expires_at) {
+		return false
+	}
+	return promotion.Usage_limit == nil || promotion.Times_used < *promotion.Usage_limit
+}
+
+// AppliedDiscount is a promotion or comp as it was applied to an invoice,
+// with what it took off each order item so reports can attribute it
+type AppliedDiscount struct {
+	Promotion_id string         `json:"promotion_id,omitempty" bson:"promotion_id,omitempty"`
+	Name         string         `json:"name" bson:"name"`
+	Type         string         `json:"type" bson:"type"`
+	Coupon_code  string         `json:"coupon_code,omitempty" bson:"coupon_code,omitempty"`
+	Reason       string         `json:"reason,omitempty" bson:"reason,omitempty"`
+	Approved_by  string         `json:"approved_by,omitempty" bson:"approved_by,omitempty"`
+	Amount       Money          `json:"amount" bson:"amount"`
+	Order_items  []DiscountLine `json:"order_items" bson:"order_items"`
+	Applied_at   time.Time      `json:"applied_at" bson:"applied_at"`
+}
+
+type DiscountLine struct {
+	Order_item_id string `json:"order_item_id" bson:"order_item_id"`
+	Amount        Money  `json:"amount" bson:"amount"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"math"
+)
+
+// RevenueRow is what the invoices of one period came to. Invoices of
+// cancelled and voided orders are left out.
+type RevenueRow struct {
+	Period    string `json:"period" bson:"period"`
+	Invoices  int    `json:"invoices" bson:"invoices"`
+	Gross     Money  `json:"gross" bson:"gross"`
+	Net       Money  `json:"net" bson:"net"`
+	Tax       Money  `json:"tax" bson:"tax"`
+	Discounts Money  `json:"discounts" bson:"discounts"`
+}
+
+// FoodSalesRow is how many of a food were ordered and what their line
+// prices came to, before discounts and exclusive tax
+type FoodSalesRow struct {
+	Food_id   string `json:"food_id" bson:"food_id"`
+	Food_name string `json:"food_name" bson:"food_name"`
+	Category  string `json:"category" bson:"category"`
+	Quantity  int    `json:"quantity" bson:"quantity"`
+	Revenue   Money  `json:"revenue" bson:"revenue"`
+}
+
+type CategorySalesRow struct {
+	Category string `json:"category" bson:"category"`
+	Quantity int    `json:"quantity" bson:"quantity"`
+	Revenue  Money  `json:"revenue" bson:"revenue"`
+}
+
+// TicketSummary is the average invoice, and the average spend of a guest
+// over the orders that recorded their covers
+type TicketSummary struct {
+	Invoices          int   `json:"invoices"`
+	Gross             Money `json:"gross"`
+	Average_ticket    Money `json:"average_ticket"`
+	Covers            int   `json:"covers"`
+	Average_per_cover Money `json:"average_per_cover"`
+}
+
+// NewTicketSummary works out the averages, coveredGross is what the
+// invoices of orders with covers came to
+func NewTicketSummary(invoices int, gross Money, covers int, coveredGross Money) TicketSummary {
+	summary := TicketSummary{Invoices: invoices, Gross: gross, Covers: covers, Average_ticket: Money{Currency: gross.Currency}, Average_per_cover: Money{Currency: gross.Currency}}
+	if invoices > 0 {
+		summary.Average_ticket.Amount = int64(math.Round(float64(gross.Amount) / float64(invoices)))
+	}
+	if covers > 0 {
+		summary.Average_per_cover.Amount = int64(math.Round(float64(coveredGross.Amount) / float64(covers)))
+	}
+	return summary
+}
+
+// TableCoversRow is how many orders and guests a table served and what
+// their invoices came to
+type TableCoversRow struct {
+	Table_id     string `json:"table_id" bson:"table_id"`
+	Table_number *int   `json:"table_number" bson:"table_number"`
+	Orders       int    `json:"orders" bson:"orders"`
+	Covers       int    `json:"covers" bson:"covers"`
+	Revenue      Money  `json:"revenue" bson:"revenue"`
+}
+
+// PaymentMixRow is what was paid with one method, Share is its percentage
+// of everything paid
+type PaymentMixRow struct {
+	Method   string  `json:"method" bson:"method"`
+	Payments int     `json:"payments" bson:"payments"`
+	Amount   Money   `json:"amount" bson:"amount"`
+	Share    float64 `json:"share" bson:"share"`
+}
+
+// SetPaymentShares fills in Share on every row
+func SetPaymentShares(rows []PaymentMixRow) {
+	var total int64
+	for _, row := range rows {
+		total += row.Amount.Amount
+	}
+	for i := range rows {
+		if total > 0 {
+			rows[i].Share = math.Round(float64(rows[i].Amount.Amount)*10000/float64(total)) / 100
+		}
+	}
+}
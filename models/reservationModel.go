@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// a reservation holds its table from Reservation_time until Ends_at while it
+// is booked or seated
+const (
+	ReservationBooked    = "BOOKED"
+	ReservationSeated    = "SEATED"
+	ReservationCancelled = "CANCELLED"
+	ReservationNoShow    = "NO_SHOW"
+)
+
+type Reservation struct {
+	ID               primitive.ObjectID `bson:"_id"`
+	Guest_name       *string            `json:"guest_name" validate:"required,min=2,max=100"`
+	Party_size       *int               `json:"party_size" validate:"required,min=1"`
+	Phone            *string            `json:"phone" validate:"required"`
+	Reservation_time *time.Time         `json:"reservation_time" validate:"required"`
+	Duration_minutes *int               `json:"duration_minutes" validate:"omitempty,min=15,max=720"`
+	Ends_at          time.Time          `json:"ends_at"`
+	Table_id         *string            `json:"table_id"`
+	Status           *string            `json:"status"`
+	Order_id         *string            `json:"order_id"`
+	Created_at       time.Time          `json:"created_at"`
+	Updated_at       time.Time          `json:"updated_at"`
+	Reservation_id   string             `json:"reservation_id"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaxRate is a named percentage. An inclusive rate is already part of the
+// food price, an exclusive one is added on top. A rate with a jurisdiction
+// only applies when the restaurant is configured for that jurisdiction.
+type TaxRate struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Name         *string            `json:"name" validate:"required,min=2,max=50"`
+	Rate         *float64           `json:"rate" validate:"required,gte=0,lte=100"`
+	Inclusive    *bool              `json:"inclusive" validate:"required"`
+	Jurisdiction *string            `json:"jurisdiction" validate:"omitempty,max=50"`
+	Active       *bool              `json:"active"`
+	Created_at   time.Time          `json:"created_at"`
+	Updated_at   time.Time          `json:"updated_at"`
+	Tax_rate_id  string             `json:"tax_rate_id"`
+}
+
+// TaxRule charges a rate on the foods of a menu category or on one food. A
+// food is taxed by the rules naming it, if there are none by the rules of
+// its category, and if there are none of those by the rules naming
+// neither, which apply to everything.
+type TaxRule struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Tax_rate_id *string            `json:"tax_rate_id" validate:"required"`
+	Category    *string            `json:"category"`
+	Food_id     *string            `json:"food_id"`
+	Active      *bool              `json:"active"`
+	Created_at  time.Time          `json:"created_at"`
+	Updated_at  time.Time          `json:"updated_at"`
+	Tax_rule_id string             `json:"tax_rule_id"`
+}
+
+// TaxLine is the part of an invoice's tax charged at one rate
+type TaxLine struct {
+	Tax_rate_id    string  `json:"tax_rate_id" bson:"tax_rate_id"`
+	Name           string  `json:"name" bson:"name"`
+	Rate           float64 `json:"rate" bson:"rate"`
+	Inclusive      bool    `json:"inclusive" bson:"inclusive"`
+	Taxable_amount Money   `json:"taxable_amount" bson:"taxable_amount"`
+	Tax_amount     Money   `json:"tax_amount" bson:"tax_amount"`
+}
@@ -6,6 +6,19 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// roles a staff member can hold, checked by middleware.Authorization
+const (
+	RoleAdmin   = "ADMIN"
+	RoleManager = "MANAGER"
+	RoleWaiter  = "WAITER"
+	RoleCook    = "COOK"
+	RoleCashier = "CASHIER"
+)
+
+// User is read from signup bodies, so Password is the plain password there
+// and the bcrypt hash once stored. Users are only ever answered with as a
+// UserProfile, the hash and the tokens never leave the server. First_user
+// marks the one user the system was bootstrapped with.
 type User struct {
 	ID            primitive.ObjectID `bson:"_id"`
 	First_name    *string            `json:"first_name" validate:"required,min=2,max=100"`
@@ -14,9 +27,38 @@ type User struct {
 	Email         *string            `json:"email" validate:"email,required"`
 	Avatar        *string            `json:"avatar"`
 	Phone         *string            `json:"phone" validate:"required"`
-	Token         *string            `json:"token"`
-	Refresh_Token *string            `json:"refresh_token"`
+	Role          *string            `json:"role" validate:"omitempty,eq=ADMIN|eq=MANAGER|eq=WAITER|eq=COOK|eq=CASHIER"`
+	Token         *string            `json:"-"`
+	Refresh_Token *string            `json:"-"`
 	Created_at    time.Time          `json:"created_at"`
 	Updated_at    time.Time          `json:"updated_at"`
 	User_id       string             `json:"user_id"`
+	First_user    bool               `json:"-" bson:"first_user,omitempty"`
+}
+
+// UserProfile is what the API shows of a user
+type UserProfile struct {
+	User_id    string    `json:"user_id"`
+	First_name *string   `json:"first_name"`
+	Last_name  *string   `json:"last_name"`
+	Email      *string   `json:"email"`
+	Avatar     *string   `json:"avatar"`
+	Phone      *string   `json:"phone"`
+	Role       *string   `json:"role"`
+	Created_at time.Time `json:"created_at"`
+	Updated_at time.Time `json:"updated_at"`
+}
+
+func (user User) Profile() UserProfile {
+	return UserProfile{
+		User_id:    user.User_id,
+		First_name: user.First_name,
+		Last_name:  user.Last_name,
+		Email:      user.Email,
+		Avatar:     user.Avatar,
+		Phone:      user.Phone,
+		Role:       user.Role,
+		Created_at: user.Created_at,
+		Updated_at: user.Updated_at,
+	}
 }
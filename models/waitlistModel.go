@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WaitlistWaiting = "WAITING"
+	WaitlistSeated  = "SEATED"
+	WaitlistLeft    = "LEFT"
+)
+
+// WaitlistEntry is a walk-in party waiting for a table. Quoted_wait_minutes
+// is the estimate given when they joined, Estimated_wait_minutes is worked
+// out again every time the waitlist is read.
+type WaitlistEntry struct {
+	ID                     primitive.ObjectID `bson:"_id"`
+	Guest_name             *string            `json:"guest_name" validate:"required,min=2,max=100"`
+	Party_size             *int               `json:"party_size" validate:"required,min=1"`
+	Phone                  *string            `json:"phone"`
+	Status                 *string            `json:"status"`
+	Quoted_wait_minutes    int                `json:"quoted_wait_minutes"`
+	Estimated_wait_minutes *int               `json:"estimated_wait_minutes,omitempty" bson:"-"`
+	Table_id               *string            `json:"table_id"`
+	Order_id               *string            `json:"order_id"`
+	Seated_at              *time.Time         `json:"seated_at"`
+	Created_at             time.Time          `json:"created_at"`
+	Updated_at             time.Time          `json:"updated_at"`
+	Waitlist_id            string             `json:"waitlist_id"`
+}
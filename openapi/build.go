@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenScheme is the security scheme of routes that need a token
+const TokenScheme = "token"
+
+// Route documents one route the router registers. Body and Response are
+// zero values of what the handler reads and writes, or a *Schema.
+type Route struct {
+	Method      string
+	Path        string
+	Id          string
+	Summary     string
+	Description string
+	Tag         string
+
+	// Public routes need no token, the others are open to Roles and
+	// admins, or to anyone signed in when Roles is empty
+	Public bool
+	Roles  []string
+
+	Query []Parameter
+	Body  interface{}
+	// Body_type is the content type of a body that is not JSON
+	Body_type string
+
+	Response interface{}
+	// Response_type is the content type of a response that is not JSON
+	Response_type string
+
+	// Errors are statuses the route answers with besides those every
+	// route of its kind can, 409 for instance
+	Errors []int
+}
+
+// Options are what a document needs besides its routes. Types are the
+// schemas of types with their own JSON encoding and Error is the body of
+// every error response. Every operation of a method that needs a token
+// takes its Headers and may answer with its Errors, they are read by
+// middleware that runs after authentication.
+type Options struct {
+	Info    Info
+	Tags    []Tag
+	Types   map[reflect.Type]*Schema
+	Error   interface{}
+	Headers map[string][]Parameter
+	Errors  map[string][]int
+}
+
+// Build documents every route gin registered. It fails when a registered
+// route is not documented, or a documented one is not registered.
+func Build(options Options, registered gin.RoutesInfo, routes []Route) (*Document, error) {
+	doc := &Document{
+		Openapi: Version,
+		Info:    options.Info,
+		Tags:    options.Tags,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Security_schemes: map[string]*SecurityScheme{
+				TokenScheme: {Type: "apiKey", In: "header", Name: "token", Description: "the token returned by /user/login"},
+			},
+		},
+	}
+	schemas := newSchemas(options.Types)
+	errorSchema := schemas.of(options.Error)
+
+	documented := map[string]bool{}
+	ids := map[string]bool{}
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		if documented[key] {
+			return nil, fmt.Errorf("openapi: %s is documented twice", key)
+		}
+		if ids[route.Id] {
+			return nil, fmt.Errorf("openapi: operation id %s is used twice", route.Id)
+		}
+		documented[key], ids[route.Id] = true, true
+
+		path := OpenAPIPath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		if !route.Public {
+			route.Errors = append(route.Errors, options.Errors[route.Method]...)
+		}
+		op := operation(route, schemas, errorSchema)
+		for _, header := range options.Headers[route.Method] {
+			if route.Public {
+				break
+			}
+			header.In = "header"
+			op.Parameters = append(op.Parameters, header)
+		}
+		(*item)[lower(route.Method)] = op
+	}
+
+	missing := []string{}
+	for _, info := range registered {
+		if !documented[info.Method+" "+info.Path] {
+			missing = append(missing, info.Method+" "+info.Path)
+		}
+		delete(documented, info.Method+" "+info.Path)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("openapi: %s not documented", strings.Join(missing, ", "))
+	}
+	for key := range documented {
+		return nil, fmt.Errorf("openapi: %s is documented but not registered", key)
+	}
+
+	doc.Components.Schemas = schemas.components
+	return doc, nil
+}
+
+func operation(route Route, schemas *schemas, errorSchema *Schema) *Operation {
+	op := &Operation{
+		Operation_id: route.Id,
+		Summary:      route.Summary,
+		Description:  route.Description,
+		Responses:    map[string]*Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	for _, name := range pathParams(route.Path) {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, param := range route.Query {
+		param.In = "query"
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	if route.Body != nil {
+		bodyType := route.Body_type
+		if bodyType == "" {
+			bodyType = gin.MIMEJSON
+		}
+		op.Request_body = &RequestBody{Required: true, Content: map[string]*MediaType{bodyType: {Schema: schemas.of(route.Body)}}}
+		if route.Method == http.MethodPatch {
+			op.Request_body.Description = "fields left out are not changed"
+		}
+	}
+
+	success := &Response{Description: "OK"}
+	switch {
+	case route.Response_type != "":
+		success.Content = map[string]*MediaType{route.Response_type: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case route.Response != nil:
+		success.Content = map[string]*MediaType{gin.MIMEJSON: {Schema: schemas.of(route.Response)}}
+	}
+	op.Responses[strconv.Itoa(http.StatusOK)] = success
+
+	failures := []int{http.StatusInternalServerError}
+	if !route.Public {
+		op.Security = []map[string][]string{{TokenScheme: {}}}
+		failures = append(failures, http.StatusUnauthorized)
+		if len(route.Roles) > 0 {
+			failures = append(failures, http.StatusForbidden)
+			op.Description = strings.TrimSpace(op.Description + "\n\nAllowed roles: " + strings.Join(route.Roles, ", ") + ".")
+		}
+	}
+	if len(route.Query) > 0 || route.Body != nil {
+		failures = append(failures, http.StatusBadRequest)
+	}
+	if route.Body != nil {
+		failures = append(failures, http.StatusUnprocessableEntity)
+	}
+	if len(pathParams(route.Path)) > 0 {
+		failures = append(failures, http.StatusNotFound)
+	}
+	failures = append(failures, route.Errors...)
+	for _, status := range failures {
+		op.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]*MediaType{gin.MIMEJSON: {Schema: errorSchema}},
+		}
+	}
+	return op
+}
+
+// OpenAPIPath writes the :param and *param of a gin path as {param}
+func OpenAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(ginPath string) []string {
+	params := []string{}
+	for _, segment := range strings.Split(ginPath, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+		}
+	}
+	return params
+}
+
+func lower(method string) string {
+	return strings.ToLower(method)
+}
@@ -0,0 +1,141 @@
+// Package openapi describes the REST API as an OpenAPI 3 document. The
+// document is built from the routes the router registers and the Go types
+// their handlers read and write, so it cannot drift from the handlers
+// without the router refusing to start.
+package openapi
+
+// Version is the OpenAPI version the documents follow
+const Version = "3.0.3"
+
+type Document struct {
+	Openapi    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	Url         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of one path by lowercase method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Operation_id string                `json:"operationId"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	Parameters   []Parameter           `json:"parameters,omitempty"`
+	Request_body *RequestBody          `json:"requestBody,omitempty"`
+	Responses    map[string]*Response  `json:"responses"`
+	Security     []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas          map[string]*Schema         `json:"schemas"`
+	Responses        map[string]*Response       `json:"responses,omitempty"`
+	Security_schemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is the part of the OpenAPI schema object the API uses
+type Schema struct {
+	Ref                   string             `json:"$ref,omitempty"`
+	Type                  string             `json:"type,omitempty"`
+	Format                string             `json:"format,omitempty"`
+	Description           string             `json:"description,omitempty"`
+	Nullable              bool               `json:"nullable,omitempty"`
+	Read_only             bool               `json:"readOnly,omitempty"`
+	Enum                  []interface{}      `json:"enum,omitempty"`
+	Minimum               *float64           `json:"minimum,omitempty"`
+	Maximum               *float64           `json:"maximum,omitempty"`
+	Exclusive_minimum     bool               `json:"exclusiveMinimum,omitempty"`
+	Exclusive_maximum     bool               `json:"exclusiveMaximum,omitempty"`
+	Min_length            *int               `json:"minLength,omitempty"`
+	Max_length            *int               `json:"maxLength,omitempty"`
+	Pattern               string             `json:"pattern,omitempty"`
+	Min_items             *int               `json:"minItems,omitempty"`
+	Max_items             *int               `json:"maxItems,omitempty"`
+	Unique_items          bool               `json:"uniqueItems,omitempty"`
+	Items                 *Schema            `json:"items,omitempty"`
+	Properties            map[string]*Schema `json:"properties,omitempty"`
+	Required              []string           `json:"required,omitempty"`
+	Additional_properties *Schema            `json:"additionalProperties,omitempty"`
+	All_of                []*Schema          `json:"allOf,omitempty"`
+}
+
+// Ref points at a schema of the components
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// RefName is the component name a ref points at, "" for inline schemas
+func RefName(schema *Schema) string {
+	const prefix = "#/components/schemas/"
+	if schema == nil || len(schema.Ref) <= len(prefix) {
+		return ""
+	}
+	return schema.Ref[len(prefix):]
+}
+
+// Resolve follows a ref to the component it points at
+func (doc *Document) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = doc.Components.Schemas[RefName(schema)]
+	}
+	return schema
+}
+
+// Find is the operation of method on a path written the way gin writes
+// it, with :param and *param
+func (doc *Document) Find(method string, ginPath string) *Operation {
+	item := doc.Paths[OpenAPIPath(ginPath)]
+	if item == nil {
+		return nil
+	}
+	return (*item)[lower(method)]
+}
@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the document as JSON
+func Handler(doc *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// swaggerUIPage loads Swagger UI from a CDN, the API itself serves only the
+// document
+var swaggerUIPage = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.Url}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+// SwaggerUI serves a page to browse and try the document found at url
+func SwaggerUI(title string, url string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		swaggerUIPage.Execute(c.Writer, struct{ Title, Url string }{title, url})
+	}
+}
@@ -0,0 +1,296 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas turns Go types into schemas the way encoding/json writes them.
+// Named structs become components, types with their own JSON encoding
+// must be given a schema in known, anything else of theirs is left open.
+type schemas struct {
+	components map[string]*Schema
+	known      map[reflect.Type]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas(known map[reflect.Type]*Schema) *schemas {
+	s := &schemas{components: map[string]*Schema{}, known: map[reflect.Type]*Schema{timeType: {Type: "string", Format: "date-time"}}, names: map[reflect.Type]string{}}
+	for t, schema := range known {
+		s.known[t] = schema
+	}
+	return s
+}
+
+// of is the schema of the value v, nil for no value
+func (s *schemas) of(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if schema, ok := v.(*Schema); ok {
+		return schema
+	}
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *schemas) schema(t reflect.Type) *Schema {
+	if known, ok := s.known[t]; ok {
+		copied := *known
+		return &copied
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.schema(t.Elem())
+		return nullable(schema)
+	case reflect.Interface:
+		return &Schema{}
+	}
+	if t == rawMessageType || t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t.Kind() == reflect.Int32 || t.Kind() == reflect.Int64 {
+			return &Schema{Type: "integer", Format: t.Kind().String()}
+		}
+		return &Schema{Type: "integer"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		//nil slices are written as null
+		return &Schema{Type: "array", Items: s.schema(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", Additional_properties: s.schema(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := s.name(t)
+		if _, ok := s.components[name]; !ok {
+			//the placeholder stops types that refer to themselves
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.object(t)
+		}
+		return Ref(name)
+	}
+	return &Schema{}
+}
+
+// nullable marks schema as taking null, refs are wrapped since siblings of
+// $ref are ignored
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{Nullable: true, All_of: []*Schema{schema}}
+	}
+	schema.Nullable = true
+	return schema
+}
+
+// name is the component name of a named type, generic types are named
+// after their type argument, Page[models.Food] is FoodPage
+func (s *schemas) name(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if open := strings.Index(name, "["); open >= 0 {
+		args := strings.Split(strings.TrimSuffix(name[open+1:], "]"), ",")
+		base := name[:open]
+		name = ""
+		for _, arg := range args {
+			name += arg[strings.LastIndex(arg, ".")+1:]
+		}
+		name += base
+	}
+	for _, taken := range s.names {
+		if taken == name {
+			name += strconv.Itoa(len(s.names))
+			break
+		}
+	}
+	s.names[t] = name
+	return name
+}
+
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.fields(t, schema)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.fields(embedded, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.schema(field.Type)
+		required := false
+		//the validator checks struct values field by field, times aside
+		if field.Type.Kind() != reflect.Struct || field.Type.ConvertibleTo(timeType) {
+			required = applyValidation(property, field.Tag.Get("validate"))
+		}
+		if field.Tag.Get("openapi") == "readonly" {
+			//set by the server, clients may leave it out
+			property.Read_only = true
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+		if (required || options == "omitempty") && property.Nullable {
+			//never null, required fields cannot be and others are omitted
+			property.Nullable = false
+			if len(property.All_of) == 1 {
+				property = property.All_of[0]
+			}
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyValidation adds the rules of a validate tag that a schema can say,
+// it is true when the field is required
+func applyValidation(schema *Schema, tag string) bool {
+	required := false
+	target := schema
+	for _, rule := range splitRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		if target.Ref != "" || len(target.All_of) > 0 {
+			//rules on a component apply to every use of it
+			if name == "required" {
+				required = true
+			}
+			continue
+		}
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "min", "max", "len":
+			number, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			limit := int(number)
+			switch target.Type {
+			case "string":
+				if name != "max" {
+					target.Min_length = &limit
+				}
+				if name != "min" {
+					target.Max_length = &limit
+				}
+			case "array":
+				if name != "max" {
+					target.Min_items = &limit
+				}
+				if name != "min" {
+					target.Max_items = &limit
+				}
+			case "integer", "number":
+				if name != "max" {
+					target.Minimum = &number
+				}
+				if name != "min" {
+					target.Maximum = &number
+				}
+			}
+		case "gt", "gte", "lt", "lte":
+			number, err := strconv.ParseFloat(param, 64)
+			if err != nil || (target.Type != "integer" && target.Type != "number") {
+				continue
+			}
+			switch name {
+			case "gt", "gte":
+				target.Minimum, target.Exclusive_minimum = &number, name == "gt"
+			default:
+				target.Maximum, target.Exclusive_maximum = &number, name == "lt"
+			}
+		case "oneof":
+			target.Enum = enum(target, strings.Fields(param))
+		case "eq":
+			//eq=A|eq=B is how the models list allowed values
+			values := []string{}
+			for _, alternative := range strings.Split(rule, "|") {
+				values = append(values, strings.TrimPrefix(alternative, "eq="))
+			}
+			target.Enum = enum(target, values)
+		case "unique":
+			target.Unique_items = true
+		case "email":
+			target.Format = "email"
+		}
+	}
+	return required
+}
+
+// splitRules is the rules of a validate tag, alternatives stay together
+// as in "eq=A|eq=B"
+func splitRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+func enum(schema *Schema, values []string) []interface{} {
+	list := []interface{}{}
+	for _, value := range values {
+		if schema.Type == "integer" || schema.Type == "number" {
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				list = append(list, number)
+				continue
+			}
+		}
+		list = append(list, value)
+	}
+	return list
+}
@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"restaurant-management/apierror"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecordedResponse is how much of a response Validator keeps to check,
+// larger ones are not checked
+const maxRecordedResponse = 1 << 20
+
+// Validator checks requests and responses against doc, it is meant for
+// development. A JSON body that does not match is refused with a 422
+// before the handler runs, a response that does not match is logged.
+// It goes after the error handler, errors are rendered by it.
+func Validator(doc *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Find(c.Request.Method, c.FullPath())
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		if media := jsonMedia(op.Request_body); media != nil && c.ContentType() == gin.MIMEJSON {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.Error(apierror.BadRequest("request body could not be read"))
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			//bodies that are not JSON at all are left to the handler
+			if value, err := decodeJSON(body); err == nil {
+				problems := doc.check(media.Schema, value, "", true, c.Request.Method == http.MethodPatch)
+				if len(problems) > 0 {
+					e := apierror.New(http.StatusUnprocessableEntity, "request body does not match the API specification")
+					e.Fields = problems
+					c.Error(e)
+					c.Abort()
+					return
+				}
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if !recorder.Written() || status >= http.StatusBadRequest || status == http.StatusNotModified {
+			return
+		}
+		response := op.Responses[strconv.Itoa(status)]
+		if response == nil {
+			log.Printf("openapi: %s %s answered %d which is not documented", c.Request.Method, c.FullPath(), status)
+			return
+		}
+		media := response.Content[gin.MIMEJSON]
+		if media == nil || recorder.overflow || !strings.HasPrefix(recorder.Header().Get("Content-Type"), gin.MIMEJSON) {
+			return
+		}
+		value, err := decodeJSON(recorder.body.Bytes())
+		if err != nil {
+			log.Printf("openapi: %s %s answered invalid JSON: %v", c.Request.Method, c.FullPath(), err)
+			return
+		}
+		for _, problem := range doc.check(media.Schema, value, "", false, false) {
+			log.Printf("openapi: %s %s response does not match the specification: %s %s", c.Request.Method, c.FullPath(), problem.Field, problem.Message)
+		}
+	}
+}
+
+func jsonMedia(body *RequestBody) *MediaType {
+	if body == nil {
+		return nil
+	}
+	return body.Content[gin.MIMEJSON]
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// responseRecorder keeps a copy of what is written, streamed responses
+// still go out as they are written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.record([]byte(data))
+	return r.ResponseWriter.WriteString(data)
+}
+
+func (r *responseRecorder) record(data []byte) {
+	if r.overflow || r.body.Len()+len(data) > maxRecordedResponse {
+		r.overflow = true
+		return
+	}
+	r.body.Write(data)
+}
+
+// check lists how value breaks schema. Requests may leave out read only
+// fields, partial ones, those of PATCH, any required field at the top.
+func (doc *Document) check(schema *Schema, value interface{}, path string, request bool, partial bool) []apierror.FieldError {
+	var problems []apierror.FieldError
+	fail := func(rule string, format string, args ...interface{}) []apierror.FieldError {
+		return append(problems, apierror.FieldError{Field: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		return doc.check(doc.Resolve(schema), value, path, request, partial)
+	}
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.All_of) == 0) {
+			return nil
+		}
+		return fail("type", "must not be null")
+	}
+	for _, part := range schema.All_of {
+		problems = append(problems, doc.check(part, value, path, request, partial)...)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("type", "must be an object")
+		}
+		if !partial {
+			for _, name := range schema.Required {
+				if property := schema.Properties[name]; request && property != nil && property.Read_only {
+					continue
+				}
+				if _, ok := object[name]; !ok {
+					problems = append(problems, apierror.FieldError{Field: join(path, name), Rule: "required", Message: "is required"})
+				}
+			}
+		}
+		for name, property := range object {
+			if propertySchema, ok := schema.Properties[name]; ok {
+				problems = append(problems, doc.check(propertySchema, property, join(path, name), request, false)...)
+			} else if schema.Additional_properties != nil {
+				problems = append(problems, doc.check(schema.Additional_properties, property, join(path, name), request, false)...)
+			}
+		}
+	case "array":
+		list, ok := value.([]interface{})
+		if !ok {
+			return fail("type", "must be an array")
+		}
+		if schema.Min_items != nil && len(list) < *schema.Min_items {
+			problems = fail("minItems", "must have at least %d items", *schema.Min_items)
+		}
+		if schema.Max_items != nil && len(list) > *schema.Max_items {
+			problems = fail("maxItems", "must have at most %d items", *schema.Max_items)
+		}
+		if schema.Unique_items && !unique(list) {
+			problems = fail("uniqueItems", "must not repeat items")
+		}
+		for i, item := range list {
+			problems = append(problems, doc.check(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), request, false)...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fail("type", "must be a string")
+		}
+		length := len([]rune(text))
+		if schema.Min_length != nil && length < *schema.Min_length {
+			problems = fail("minLength", "must be at least %d characters long", *schema.Min_length)
+		}
+		if schema.Max_length != nil && length > *schema.Max_length {
+			problems = fail("maxLength", "must be at most %d characters long", *schema.Max_length)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				problems = fail("format", "must be an RFC 3339 time")
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fail("type", "must be a number")
+		}
+		parsed, err := number.Float64()
+		if err != nil {
+			return fail("type", "must be a number")
+		}
+		if schema.Type == "integer" && parsed != math.Trunc(parsed) {
+			return fail("type", "must be a whole number")
+		}
+		if minimum := schema.Minimum; minimum != nil && (parsed < *minimum || (schema.Exclusive_minimum && parsed == *minimum)) {
+			problems = fail("minimum", "must be %s %v", map[bool]string{true: "greater than", false: "at least"}[schema.Exclusive_minimum], *minimum)
+		}
+		if maximum := schema.Maximum; maximum != nil && (parsed > *maximum || (schema.Exclusive_maximum && parsed == *maximum)) {
+			problems = fail("maximum", "must be %s %v", map[bool]string{true: "less than", false: "at most"}[schema.Exclusive_maximum], *maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("type", "must be true or false")
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		values := []string{}
+		for _, allowed := range schema.Enum {
+			values = append(values, fmt.Sprint(allowed))
+		}
+		problems = fail("enum", "must be one of %s", strings.Join(values, ", "))
+	}
+	return problems
+}
+
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func unique(list []interface{}) bool {
+	seen := map[string]bool{}
+	for _, item := range list {
+		encoded, _ := json.Marshal(item)
+		if seen[string(encoded)] {
+			return false
+		}
+		seen[string(encoded)] = true
+	}
+	return true
+}
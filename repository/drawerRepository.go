@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DrawerRepository interface {
+	List(ctx context.Context) ([]models.CashDrawer, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.CashDrawer], error)
+	FindByID(ctx context.Context, drawerId string) (models.CashDrawer, error)
+	// FindOpen returns ErrNotFound when no drawer is open
+	FindOpen(ctx context.Context) (models.CashDrawer, error)
+	// Open inserts drawer unless another one is open already
+	Open(ctx context.Context, drawer models.CashDrawer) (bool, error)
+	// AddMovement and Close only apply while the drawer is still open
+	AddMovement(ctx context.Context, drawerId string, movement models.DrawerMovement) (bool, error)
+	Close(ctx context.Context, drawerId string, closing DrawerClose) (bool, error)
+	// Reopen undoes Close when the Z report could not be stored
+	Reopen(ctx context.Context, drawerId string) error
+}
+
+// DrawerClose is what closing a drawer stores on it
+type DrawerClose struct {
+	Counted_cash  models.Money
+	Expected_cash models.Money
+	Variance      models.Money
+	Z_report_id   string
+	Closed_by     string
+	Closed_at     time.Time
+}
+
+// Set is the closing as the fields to store, the drawer is closed with it
+func (closing DrawerClose) Set() primitive.D {
+	return primitive.D{
+		{Key: "status", Value: models.DrawerClosed},
+		{Key: "counted_cash", Value: closing.Counted_cash},
+		{Key: "expected_cash", Value: closing.Expected_cash},
+		{Key: "variance", Value: closing.Variance},
+		{Key: "z_report_id", Value: closing.Z_report_id},
+		{Key: "closed_by", Value: closing.Closed_by},
+		{Key: "closed_at", Value: closing.Closed_at},
+	}
+}
+
+// ZReportRepository has no update, a Z report is never changed once it is
+// written
+type ZReportRepository interface {
+	List(ctx context.Context) ([]models.ZReport, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.ZReport], error)
+	FindByID(ctx context.Context, zReportId string) (models.ZReport, error)
+	// Latest returns ErrNotFound before the first close
+	Latest(ctx context.Context) (models.ZReport, error)
+	Insert(ctx context.Context, report models.ZReport) error
+}
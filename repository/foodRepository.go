@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FoodFilter narrows Page to foods containing none of Exclude_allergens and
+// meeting every diet in Diets. Foods that declare no allergens at all are
+// left out once allergens are excluded.
+type FoodFilter struct {
+	Exclude_allergens []string
+	Diets             []string
+}
+
+type FoodRepository interface {
+	Page(ctx context.Context, filter FoodFilter, query ListQuery) (Page[models.Food], error)
+	FindByID(ctx context.Context, foodId string) (models.Food, error)
+	Insert(ctx context.Context, food models.Food) error
+	Update(ctx context.Context, foodId string, update FoodUpdate) (bool, error)
+}
+
+// FoodUpdate changes the fields of a food that are not nil, lists are
+// replaced as a whole. Linking a Food_image drops the uploaded Image
+// unless the upload sets both.
+type FoodUpdate struct {
+	Name            *string
+	Price           *models.Money
+	Food_image      *string
+	Image           *models.FoodImage
+	Menu_id         *string
+	Station         *string
+	Variants        []models.FoodVariant
+	Modifier_groups []models.ModifierGroup
+	Recipe          []models.RecipeLine
+	Allergens       []string
+	Dietary_tags    []string
+	Nutrition       *models.Nutrition
+	Updated_at      time.Time
+}
+
+// Set is the update as the fields to store
+func (update FoodUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "name", update.Name)
+	fields = setGiven(fields, "price", update.Price)
+	if update.Food_image != nil {
+		fields = append(fields, primitive.E{Key: "food_image", Value: update.Food_image}, primitive.E{Key: "image", Value: update.Image})
+	}
+	fields = setGiven(fields, "menu_id", update.Menu_id)
+	fields = setGiven(fields, "station", update.Station)
+	fields = setGiven(fields, "variants", update.Variants)
+	fields = setGiven(fields, "modifier_groups", update.Modifier_groups)
+	fields = setGiven(fields, "recipe", update.Recipe)
+	fields = setGiven(fields, "allergens", update.Allergens)
+	fields = setGiven(fields, "dietary_tags", update.Dietary_tags)
+	fields = setGiven(fields, "nutrition", update.Nutrition)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
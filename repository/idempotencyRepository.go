@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+)
+
+// IdempotencyRepository keeps the answers to requests sent with an
+// Idempotency-Key until they expire
+type IdempotencyRepository interface {
+	// Reserve stores record unless its key is taken by a record that has
+	// not expired, then it returns the record already stored and false
+	Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error)
+	// Complete stores the answer and expiry of record over the reserved
+	// record with its ID, a reservation that expired and was taken by
+	// another request is left alone
+	Complete(ctx context.Context, record models.IdempotencyRecord) error
+	// Release drops the reserved record with the ID of record so the
+	// request can be retried, when it failed
+	Release(ctx context.Context, record models.IdempotencyRecord) error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IngredientRepository interface {
+	List(ctx context.Context) ([]models.Ingredient, error)
+	// Page with lowStock only has the ingredients at or below their threshold
+	Page(ctx context.Context, lowStock bool, query ListQuery) (Page[models.Ingredient], error)
+	FindByID(ctx context.Context, ingredientId string) (models.Ingredient, error)
+	Insert(ctx context.Context, ingredient models.Ingredient) error
+	Update(ctx context.Context, ingredientId string, update IngredientUpdate) (bool, error)
+	// AdjustStock adds delta to the stock. Taking stock only succeeds while
+	// there is enough of it left, so two orders cannot both take the last.
+	AdjustStock(ctx context.Context, ingredientId string, delta float64, updatedAt time.Time) (bool, error)
+}
+
+// IngredientUpdate changes the fields of an ingredient that are not nil
+type IngredientUpdate struct {
+	Name                *string
+	Unit                *string
+	Stock               *float64
+	Low_stock_threshold *float64
+	Updated_at          time.Time
+}
+
+// Set is the update as the fields to store
+func (update IngredientUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "name", update.Name)
+	fields = setGiven(fields, "unit", update.Unit)
+	fields = setGiven(fields, "stock", update.Stock)
+	fields = setGiven(fields, "low_stock_threshold", update.Low_stock_threshold)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
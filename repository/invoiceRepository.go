@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InvoiceRepository interface {
+	List(ctx context.Context) ([]models.Invoice, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Invoice], error)
+	FindByID(ctx context.Context, invoiceId string) (models.Invoice, error)
+	FindByOrderID(ctx context.Context, orderId string) (models.Invoice, error)
+	// Insert returns ErrDuplicate when the order already has an invoice
+	Insert(ctx context.Context, invoice models.Invoice) error
+	Update(ctx context.Context, invoiceId string, update InvoiceUpdate) (bool, error)
+
+	// UpdateBalance only applies update while amount_paid is still
+	// paidBefore, so two payments cannot both spend the same balance.
+	UpdateBalance(ctx context.Context, invoiceId string, paidBefore models.Money, update InvoiceUpdate) (bool, error)
+}
+
+// InvoiceUpdate changes the fields of an invoice that are not nil. The
+// discounts are stored with Discount_amount and the tax breakdown with
+// Tax_amount, so that an empty list clears them.
+type InvoiceUpdate struct {
+	Payment_method  *string
+	Payment_status  *string
+	Discounts       []models.AppliedDiscount
+	Discount_amount *models.Money
+	Net_amount      *models.Money
+	Tax_amount      *models.Money
+	Tax_breakdown   []models.TaxLine
+	Total_amount    *models.Money
+	Amount_paid     *models.Money
+	Balance_due     *models.Money
+	Change_due      *models.Money
+	Updated_at      time.Time
+}
+
+// Set is the update as the fields to store
+func (update InvoiceUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "payment_method", update.Payment_method)
+	fields = setGiven(fields, "payment_status", update.Payment_status)
+	if update.Discount_amount != nil {
+		fields = append(fields, primitive.E{Key: "discounts", Value: update.Discounts}, primitive.E{Key: "discount_amount", Value: update.Discount_amount})
+	}
+	fields = setGiven(fields, "net_amount", update.Net_amount)
+	if update.Tax_amount != nil {
+		fields = append(fields, primitive.E{Key: "tax_amount", Value: update.Tax_amount}, primitive.E{Key: "tax_breakdown", Value: update.Tax_breakdown})
+	}
+	fields = setGiven(fields, "total_amount", update.Total_amount)
+	fields = setGiven(fields, "amount_paid", update.Amount_paid)
+	fields = setGiven(fields, "balance_due", update.Balance_due)
+	fields = setGiven(fields, "change_due", update.Change_due)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
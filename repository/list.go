@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ListQuery asks for one page of a collection: the documents matching every
+// filter, ordered by Sort and then by _id so that the order is total, from
+// the one after Cursor on.
+type ListQuery struct {
+	Filters []Filter
+	Sort    []Sort
+	Cursor  string
+	Limit   int
+}
+
+const (
+	FilterEq  = "eq"
+	FilterIn  = "in"
+	FilterGte = "gte"
+	FilterLt  = "lt"
+)
+
+// Filter compares a stored field with Value, FilterIn takes a
+// []interface{} of the values allowed
+type Filter struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// Page is the envelope every list endpoint answers with. Next_cursor is
+// only set when there are more documents, passing it back as the cursor
+// gives the next page.
+type Page[T any] struct {
+	Items       []T    `json:"items"`
+	Total_count int64  `json:"total_count"`
+	Limit       int    `json:"limit"`
+	Next_cursor string `json:"next_cursor,omitempty"`
+	Has_more    bool   `json:"has_more"`
+}
+
+// MapPage is page with every item converted, the cursor is kept
+func MapPage[T any, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return Page[U]{Items: items, Total_count: page.Total_count, Limit: page.Limit, Next_cursor: page.Next_cursor, Has_more: page.Has_more}
+}
+
+var ErrInvalidCursor = errors.New("cursor is invalid or was made for another sort")
+
+// cursor holds the sort values of the last document of a page, with the
+// sort they were taken for so it cannot be reused with another one
+type cursor struct {
+	Sort   string          `bson:"s"`
+	Values []bson.RawValue `bson:"v"`
+}
+
+// SortKeys is Sort followed by _id
+func (query ListQuery) SortKeys() []Sort {
+	return append(append([]Sort{}, query.Sort...), Sort{Field: "_id"})
+}
+
+func (query ListQuery) sortSignature() string {
+	keys := []string{}
+	for _, key := range query.SortKeys() {
+		if key.Descending {
+			keys = append(keys, "-"+key.Field)
+		} else {
+			keys = append(keys, key.Field)
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// After decodes Cursor into one value per sort key, nil without a cursor
+func (query ListQuery) After() ([]bson.RawValue, error) {
+	if query.Cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var decoded cursor
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Sort != query.sortSignature() || len(decoded.Values) != len(query.SortKeys()) {
+		return nil, ErrInvalidCursor
+	}
+	return decoded.Values, nil
+}
+
+// Value is the field at a dotted path of doc, missing fields are null the
+// way MongoDB compares them
+func Value(doc bson.Raw, path string) bson.RawValue {
+	value, err := doc.LookupErr(strings.Split(path, ".")...)
+	if err != nil || value.Type == bson.TypeUndefined {
+		return bson.RawValue{Type: bson.TypeNull}
+	}
+	return value
+}
+
+// NewPage decodes the documents a backend found for query, which asks for
+// one more than Limit to tell whether there is a next page
+func NewPage[T any](docs []bson.Raw, total int64, query ListQuery) (Page[T], error) {
+	page := Page[T]{Items: []T{}, Total_count: total, Limit: query.Limit}
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		page.Has_more = true
+	}
+	for _, doc := range docs {
+		var item T
+		if err := bson.Unmarshal(doc, &item); err != nil {
+			return page, err
+		}
+		page.Items = append(page.Items, item)
+	}
+
+	if page.Has_more && len(docs) > 0 {
+		last := cursor{Sort: query.sortSignature()}
+		for _, key := range query.SortKeys() {
+			last.Values = append(last.Values, Value(docs[len(docs)-1], key.Field))
+		}
+		data, err := bson.Marshal(last)
+		if err != nil {
+			return page, fmt.Errorf("encoding cursor: %w", err)
+		}
+		page.Next_cursor = base64.RawURLEncoding.EncodeToString(data)
+	}
+	return page, nil
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"sync"
+
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// collection keeps documents BSON encoded, the same way MongoDB would, so
+// callers never share memory with the store and updates can use the same
+// fields the typed updates of the repository package set with $set.
+type collection[T any] struct {
+	mu   sync.RWMutex
+	docs []bson.Raw
+}
+
+func (c *collection[T]) insert(v T) error {
+	doc, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.docs = append(c.docs, doc)
+	return nil
+}
+
+// insertUnique inserts v unless a document is taken, the way a unique
+// index would
+func (c *collection[T]) insertUnique(v T, taken func(T) bool) error {
+	doc, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, stored := range c.docs {
+		var existing T
+		if err := bson.Unmarshal(stored, &existing); err != nil {
+			return err
+		}
+		if taken(existing) {
+			return repository.ErrDuplicate
+		}
+	}
+	c.docs = append(c.docs, doc)
+	return nil
+}
+
+func (c *collection[T]) filter(match func(T) bool) ([]T, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := []T{}
+	for _, doc := range c.docs {
+		var v T
+		if err := bson.Unmarshal(doc, &v); err != nil {
+			return nil, err
+		}
+		if match == nil || match(v) {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func (c *collection[T]) find(match func(T) bool) (T, error) {
+	var zero T
+	found, err := c.filter(match)
+	if err != nil {
+		return zero, err
+	}
+	if len(found) == 0 {
+		return zero, repository.ErrNotFound
+	}
+	return found[0], nil
+}
+
+func (c *collection[T]) count(match func(T) bool) (int64, error) {
+	found, err := c.filter(match)
+	return int64(len(found)), err
+}
+
+// update applies updateObj like $set on the first matching document, only
+// top level keys are supported.
+func (c *collection[T]) update(match func(T) bool, updateObj primitive.D) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, doc := range c.docs {
+		var v T
+		if err := bson.Unmarshal(doc, &v); err != nil {
+			return false, err
+		}
+		if !match(v) {
+			continue
+		}
+
+		var fields bson.D
+		if err := bson.Unmarshal(doc, &fields); err != nil {
+			return false, err
+		}
+		for _, set := range updateObj {
+			fields = setField(fields, set)
+		}
+		updated, err := bson.Marshal(fields)
+		if err != nil {
+			return false, err
+		}
+		c.docs[i] = updated
+		return true, nil
+	}
+	return false, nil
+}
+
+// remove deletes the first matching document
+func (c *collection[T]) remove(match func(T) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, doc := range c.docs {
+		var v T
+		if err := bson.Unmarshal(doc, &v); err != nil {
+			return false, err
+		}
+		if match(v) {
+			c.docs = append(c.docs[:i], c.docs[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func setField(fields bson.D, set bson.E) bson.D {
+	for i := range fields {
+		if fields[i].Key == set.Key {
+			fields[i].Value = set.Value
+			return fields
+		}
+	}
+	return append(fields, set)
+}
+
+func equal(field *string, value string) bool {
+	return field != nil && *field == value
+}
+
+func equalPointer(field *string, value *string) bool {
+	if field == nil || value == nil {
+		return field == value
+	}
+	return *field == *value
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type drawerRepository struct {
+	drawers collection[models.CashDrawer]
+
+	//opening checks for an open drawer and movements are read and written
+	//back, the lock makes each of them one step
+	mu sync.Mutex
+}
+
+func NewDrawerRepository() repository.DrawerRepository {
+	return &drawerRepository{}
+}
+
+func (r *drawerRepository) List(ctx context.Context) ([]models.CashDrawer, error) {
+	drawers, err := r.drawers.filter(nil)
+	sort.SliceStable(drawers, func(i, j int) bool { return drawers[i].Opened_at.After(drawers[j].Opened_at) })
+	return drawers, err
+}
+
+func (r *drawerRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.CashDrawer], error) {
+	return r.drawers.list(nil, query)
+}
+
+func (r *drawerRepository) FindByID(ctx context.Context, drawerId string) (models.CashDrawer, error) {
+	return r.drawers.find(func(drawer models.CashDrawer) bool { return drawer.Drawer_id == drawerId })
+}
+
+func (r *drawerRepository) FindOpen(ctx context.Context) (models.CashDrawer, error) {
+	return r.drawers.find(func(drawer models.CashDrawer) bool { return equal(drawer.Status, models.DrawerOpen) })
+}
+
+func (r *drawerRepository) Open(ctx context.Context, drawer models.CashDrawer) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.FindOpen(ctx); err != repository.ErrNotFound {
+		return false, err
+	}
+	return true, r.drawers.insert(drawer)
+}
+
+func (r *drawerRepository) AddMovement(ctx context.Context, drawerId string, movement models.DrawerMovement) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	drawer, err := r.FindByID(ctx, drawerId)
+	if err == repository.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return r.drawers.update(func(drawer models.CashDrawer) bool {
+		return drawer.Drawer_id == drawerId && equal(drawer.Status, models.DrawerOpen)
+	}, primitive.D{{Key: "movements", Value: append(drawer.Movements, movement)}})
+}
+
+func (r *drawerRepository) Close(ctx context.Context, drawerId string, closing repository.DrawerClose) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.drawers.update(func(drawer models.CashDrawer) bool {
+		return drawer.Drawer_id == drawerId && equal(drawer.Status, models.DrawerOpen)
+	}, closing.Set())
+}
+
+func (r *drawerRepository) Reopen(ctx context.Context, drawerId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err := r.drawers.update(func(drawer models.CashDrawer) bool { return drawer.Drawer_id == drawerId }, reopenDrawer())
+	return err
+}
+
+type zReportRepository struct {
+	reports collection[models.ZReport]
+}
+
+func NewZReportRepository() repository.ZReportRepository {
+	return &zReportRepository{}
+}
+
+func (r *zReportRepository) List(ctx context.Context) ([]models.ZReport, error) {
+	reports, err := r.reports.filter(nil)
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Number > reports[j].Number })
+	return reports, err
+}
+
+func (r *zReportRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.ZReport], error) {
+	return r.reports.list(nil, query)
+}
+
+func (r *zReportRepository) FindByID(ctx context.Context, zReportId string) (models.ZReport, error) {
+	return r.reports.find(func(report models.ZReport) bool { return report.Z_report_id == zReportId })
+}
+
+func (r *zReportRepository) Latest(ctx context.Context) (models.ZReport, error) {
+	reports, err := r.List(ctx)
+	if err != nil {
+		return models.ZReport{}, err
+	}
+	if len(reports) == 0 {
+		return models.ZReport{}, repository.ErrNotFound
+	}
+	return reports[0], nil
+}
+
+func (r *zReportRepository) Insert(ctx context.Context, report models.ZReport) error {
+	return r.reports.insert(report)
+}
+
+func reopenDrawer() primitive.D {
+	return primitive.D{
+		{Key: "status", Value: models.DrawerOpen},
+		{Key: "counted_cash", Value: nil},
+		{Key: "expected_cash", Value: nil},
+		{Key: "variance", Value: nil},
+		{Key: "z_report_id", Value: nil},
+		{Key: "closed_by", Value: ""},
+		{Key: "closed_at", Value: nil},
+	}
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type foodRepository struct {
+	foods collection[models.Food]
+}
+
+func NewFoodRepository() repository.FoodRepository {
+	return &foodRepository{}
+}
+
+func (r *foodRepository) Page(ctx context.Context, filter repository.FoodFilter, query repository.ListQuery) (repository.Page[models.Food], error) {
+	return r.foods.list(func(food models.Food) bool {
+		if len(filter.Exclude_allergens) > 0 && food.Allergens == nil {
+			return false
+		}
+		for _, allergen := range filter.Exclude_allergens {
+			if food.Contains(allergen) {
+				return false
+			}
+		}
+		for _, diet := range filter.Diets {
+			if !meetsDiet(food, diet) {
+				return false
+			}
+		}
+		return true
+	}, query)
+}
+
+func (r *foodRepository) FindByID(ctx context.Context, foodId string) (models.Food, error) {
+	return r.foods.find(func(food models.Food) bool { return food.Food_id == foodId })
+}
+
+func (r *foodRepository) Insert(ctx context.Context, food models.Food) error {
+	return r.foods.insert(food)
+}
+
+func (r *foodRepository) Update(ctx context.Context, foodId string, update repository.FoodUpdate) (bool, error) {
+	return r.foods.update(func(food models.Food) bool { return food.Food_id == foodId }, update.Set())
+}
+
+func meetsDiet(food models.Food, diet string) bool {
+	for _, tag := range food.Dietary_tags {
+		for _, satisfying := range models.SatisfyingDiets(diet) {
+			if tag == satisfying {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type idempotencyRepository struct {
+	//reserve checks and inserts under one lock, like the unique index
+	mu      sync.Mutex
+	records collection[models.IdempotencyRecord]
+}
+
+func NewIdempotencyRepository() repository.IdempotencyRepository {
+	return &idempotencyRepository{}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	//expired records are dropped as the TTL index would
+	now := time.Now()
+	for {
+		removed, err := r.records.remove(func(stored models.IdempotencyRecord) bool { return !stored.Expires_at.After(now) })
+		if err != nil {
+			return models.IdempotencyRecord{}, false, err
+		}
+		if !removed {
+			break
+		}
+	}
+
+	stored, err := r.records.find(func(stored models.IdempotencyRecord) bool { return stored.Key == record.Key })
+	if err == nil {
+		return stored, false, nil
+	}
+	if err != repository.ErrNotFound {
+		return models.IdempotencyRecord{}, false, err
+	}
+	return record, true, r.records.insert(record)
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := r.records.update(func(stored models.IdempotencyRecord) bool { return stored.ID == record.ID }, primitive.D{
+		{Key: "status", Value: record.Status},
+		{Key: "content_type", Value: record.Content_type},
+		{Key: "body", Value: record.Body},
+		{Key: "expires_at", Value: record.Expires_at},
+	})
+	return err
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := r.records.remove(func(stored models.IdempotencyRecord) bool { return stored.ID == record.ID })
+	return err
+}
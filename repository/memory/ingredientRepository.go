@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ingredientRepository struct {
+	ingredients collection[models.Ingredient]
+
+	//stock is read and written back, the lock makes that one step
+	stock sync.Mutex
+}
+
+func NewIngredientRepository() repository.IngredientRepository {
+	return &ingredientRepository{}
+}
+
+func (r *ingredientRepository) List(ctx context.Context) ([]models.Ingredient, error) {
+	return r.ingredients.filter(nil)
+}
+
+func (r *ingredientRepository) Page(ctx context.Context, lowStock bool, query repository.ListQuery) (repository.Page[models.Ingredient], error) {
+	if !lowStock {
+		return r.ingredients.list(nil, query)
+	}
+	return r.ingredients.list(models.Ingredient.IsLow, query)
+}
+
+func (r *ingredientRepository) FindByID(ctx context.Context, ingredientId string) (models.Ingredient, error) {
+	return r.ingredients.find(func(ingredient models.Ingredient) bool { return ingredient.Ingredient_id == ingredientId })
+}
+
+func (r *ingredientRepository) Insert(ctx context.Context, ingredient models.Ingredient) error {
+	return r.ingredients.insert(ingredient)
+}
+
+func (r *ingredientRepository) Update(ctx context.Context, ingredientId string, update repository.IngredientUpdate) (bool, error) {
+	r.stock.Lock()
+	defer r.stock.Unlock()
+	return r.ingredients.update(func(ingredient models.Ingredient) bool { return ingredient.Ingredient_id == ingredientId }, update.Set())
+}
+
+func (r *ingredientRepository) AdjustStock(ctx context.Context, ingredientId string, delta float64, updatedAt time.Time) (bool, error) {
+	r.stock.Lock()
+	defer r.stock.Unlock()
+
+	ingredient, err := r.FindByID(ctx, ingredientId)
+	if err == repository.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var stock float64
+	if ingredient.Stock != nil {
+		stock = *ingredient.Stock
+	}
+	if delta < 0 && stock < -delta {
+		return false, nil
+	}
+	return r.ingredients.update(func(ingredient models.Ingredient) bool { return ingredient.Ingredient_id == ingredientId }, primitive.D{
+		{Key: "stock", Value: models.RoundStock(stock + delta)},
+		{Key: "updated_at", Value: updatedAt},
+	})
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type invoiceRepository struct {
+	invoices collection[models.Invoice]
+}
+
+func NewInvoiceRepository() repository.InvoiceRepository {
+	return &invoiceRepository{}
+}
+
+func (r *invoiceRepository) List(ctx context.Context) ([]models.Invoice, error) {
+	return r.invoices.filter(nil)
+}
+
+func (r *invoiceRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Invoice], error) {
+	return r.invoices.list(nil, query)
+}
+
+func (r *invoiceRepository) FindByID(ctx context.Context, invoiceId string) (models.Invoice, error) {
+	return r.invoices.find(func(invoice models.Invoice) bool { return invoice.Invoice_id == invoiceId })
+}
+
+func (r *invoiceRepository) FindByOrderID(ctx context.Context, orderId string) (models.Invoice, error) {
+	return r.invoices.find(func(invoice models.Invoice) bool { return invoice.Order_id == orderId })
+}
+
+func (r *invoiceRepository) Insert(ctx context.Context, invoice models.Invoice) error {
+	return r.invoices.insertUnique(invoice, func(stored models.Invoice) bool { return stored.Order_id == invoice.Order_id })
+}
+
+func (r *invoiceRepository) Update(ctx context.Context, invoiceId string, update repository.InvoiceUpdate) (bool, error) {
+	return r.invoices.update(func(invoice models.Invoice) bool { return invoice.Invoice_id == invoiceId }, update.Set())
+}
+
+func (r *invoiceRepository) UpdateBalance(ctx context.Context, invoiceId string, paidBefore models.Money, update repository.InvoiceUpdate) (bool, error) {
+	return r.invoices.update(func(invoice models.Invoice) bool {
+		return invoice.Invoice_id == invoiceId && invoice.Amount_paid.Amount == paidBefore.Amount
+	}, update.Set())
+}
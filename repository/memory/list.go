@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// list answers query over the documents match keeps, match may be nil. It
+// filters, sorts and pages the way the MongoDB backend does.
+func (c *collection[T]) list(match func(T) bool, query repository.ListQuery) (repository.Page[T], error) {
+	after, err := query.After()
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+	filters, err := rawFilters(query.Filters)
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+
+	c.mu.RLock()
+	docs := append([]bson.Raw{}, c.docs...)
+	c.mu.RUnlock()
+
+	matched := []bson.Raw{}
+	for _, doc := range docs {
+		if match != nil {
+			var v T
+			if err := bson.Unmarshal(doc, &v); err != nil {
+				return repository.Page[T]{}, err
+			}
+			if !match(v) {
+				continue
+			}
+		}
+		if matchesFilters(doc, filters) {
+			matched = append(matched, doc)
+		}
+	}
+	total := int64(len(matched))
+
+	keys := query.SortKeys()
+	sort.SliceStable(matched, func(i, j int) bool {
+		return compareDocs(matched[i], keys, sortValues(matched[j], keys)) < 0
+	})
+	if after != nil {
+		start := sort.Search(len(matched), func(i int) bool {
+			return compareDocs(matched[i], keys, after) > 0
+		})
+		matched = matched[start:]
+	}
+	if len(matched) > query.Limit+1 {
+		matched = matched[:query.Limit+1]
+	}
+	return repository.NewPage[T](matched, total, query)
+}
+
+type rawFilter struct {
+	field  string
+	op     string
+	values []bson.RawValue
+}
+
+func rawFilters(filters []repository.Filter) ([]rawFilter, error) {
+	result := []rawFilter{}
+	for _, filter := range filters {
+		values := []interface{}{filter.Value}
+		if filter.Op == repository.FilterIn {
+			values = filter.Value.([]interface{})
+		}
+		raw := rawFilter{field: filter.Field, op: filter.Op}
+		for _, value := range values {
+			t, data, err := bson.MarshalValue(value)
+			if err != nil {
+				return nil, err
+			}
+			raw.values = append(raw.values, bson.RawValue{Type: t, Value: data})
+		}
+		result = append(result, raw)
+	}
+	return result, nil
+}
+
+func matchesFilters(doc bson.Raw, filters []rawFilter) bool {
+	for _, filter := range filters {
+		value := repository.Value(doc, filter.field)
+		matched := false
+		for _, wanted := range filter.values {
+			//like MongoDB, ranges only match values of the same type
+			if typeRank(value.Type) != typeRank(wanted.Type) {
+				continue
+			}
+			switch filter.op {
+			case repository.FilterEq, repository.FilterIn:
+				matched = matched || compareValues(value, wanted) == 0
+			case repository.FilterGte:
+				matched = compareValues(value, wanted) >= 0
+			case repository.FilterLt:
+				matched = compareValues(value, wanted) < 0
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func sortValues(doc bson.Raw, keys []repository.Sort) []bson.RawValue {
+	values := []bson.RawValue{}
+	for _, key := range keys {
+		values = append(values, repository.Value(doc, key.Field))
+	}
+	return values
+}
+
+// compareDocs orders doc against the sort values of another document
+func compareDocs(doc bson.Raw, keys []repository.Sort, values []bson.RawValue) int {
+	for i, key := range keys {
+		result := compareValues(repository.Value(doc, key.Field), values[i])
+		if key.Descending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// typeRank is the order MongoDB sorts values of different types in
+func typeRank(t bsontype.Type) int {
+	switch t {
+	case bson.TypeNull, bson.TypeUndefined:
+		return 1
+	case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble:
+		return 2
+	case bson.TypeString, bson.TypeSymbol:
+		return 3
+	case bson.TypeEmbeddedDocument:
+		return 4
+	case bson.TypeArray:
+		return 5
+	case bson.TypeBinary:
+		return 6
+	case bson.TypeObjectID:
+		return 7
+	case bson.TypeBoolean:
+		return 8
+	case bson.TypeDateTime:
+		return 9
+	case bson.TypeTimestamp:
+		return 10
+	}
+	return 11
+}
+
+func compareValues(a bson.RawValue, b bson.RawValue) int {
+	if rankA, rankB := typeRank(a.Type), typeRank(b.Type); rankA != rankB {
+		return rankA - rankB
+	}
+	switch typeRank(a.Type) {
+	case 1:
+		return 0
+	case 2:
+		if a.Type != bson.TypeDouble && b.Type != bson.TypeDouble {
+			return compareInts(a.AsInt64(), b.AsInt64())
+		}
+		x, y := number(a), number(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case 3:
+		return strings.Compare(a.StringValue(), b.StringValue())
+	case 7:
+		x, y := a.ObjectID(), b.ObjectID()
+		return bytes.Compare(x[:], y[:])
+	case 8:
+		x, y := a.Boolean(), b.Boolean()
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case 9:
+		return compareInts(a.DateTime(), b.DateTime())
+	}
+	return bytes.Compare(a.Value, b.Value)
+}
+
+func compareInts(x int64, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func number(value bson.RawValue) float64 {
+	if value.Type == bson.TypeDouble {
+		return value.Double()
+	}
+	return float64(value.AsInt64())
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tablesWithGuests stores one table per entry, numbered from 1, so that
+// several share a guest count and only _id tells them apart
+func tablesWithGuests(t *testing.T, guests ...int) repository.TableRepository {
+	t.Helper()
+	tables := NewTableRepository()
+	for i := range guests {
+		id := primitive.NewObjectID()
+		number := i + 1
+		table := models.Table{ID: id, Table_id: id.Hex(), Table_number: &number, Number_of_guests: &guests[i]}
+		if err := tables.Insert(context.Background(), table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tables
+}
+
+// walk reads every page of query and returns the table numbers in order
+func walk(t *testing.T, tables repository.TableRepository, query repository.ListQuery) []int {
+	t.Helper()
+	numbers := []int{}
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("the cursor does not move on")
+		}
+		page, err := tables.Page(context.Background(), query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) > query.Limit {
+			t.Fatalf("page has %d items, limit is %d", len(page.Items), query.Limit)
+		}
+		for _, table := range page.Items {
+			numbers = append(numbers, *table.Table_number)
+		}
+		if page.Has_more != (page.Next_cursor != "") {
+			t.Fatalf("has_more is %v with next_cursor %q", page.Has_more, page.Next_cursor)
+		}
+		if !page.Has_more {
+			return numbers
+		}
+		query.Cursor = page.Next_cursor
+	}
+}
+
+func TestListPages(t *testing.T) {
+	tables := tablesWithGuests(t, 2, 4, 2, 4, 2, 4, 6)
+
+	tests := []struct {
+		name  string
+		query repository.ListQuery
+		want  []int
+	}{
+		{
+			name:  "insertion order",
+			query: repository.ListQuery{Limit: 3},
+			want:  []int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			//ties on the sort field are broken by _id, so no page repeats
+			//or skips a table whatever the limit
+			name:  "descending with ties",
+			query: repository.ListQuery{Sort: []repository.Sort{{Field: "number_of_guests", Descending: true}}, Limit: 2},
+			want:  []int{7, 2, 4, 6, 1, 3, 5},
+		},
+		{
+			name:  "ascending, one per page",
+			query: repository.ListQuery{Sort: []repository.Sort{{Field: "number_of_guests"}}, Limit: 1},
+			want:  []int{1, 3, 5, 2, 4, 6, 7},
+		},
+		{
+			name: "filtered",
+			query: repository.ListQuery{
+				Filters: []repository.Filter{{Field: "number_of_guests", Op: repository.FilterIn, Value: []interface{}{4, 6}}},
+				Sort:    []repository.Sort{{Field: "table_number", Descending: true}},
+				Limit:   3,
+			},
+			want: []int{7, 6, 4, 2},
+		},
+		{
+			name: "range",
+			query: repository.ListQuery{
+				Filters: []repository.Filter{{Field: "number_of_guests", Op: repository.FilterGte, Value: 4}, {Field: "number_of_guests", Op: repository.FilterLt, Value: 6}},
+				Limit:   20,
+			},
+			want: []int{2, 4, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := walk(t, tables, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tables %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListTotalCount(t *testing.T) {
+	tables := tablesWithGuests(t, 2, 4, 2, 4, 2)
+
+	query := repository.ListQuery{Filters: []repository.Filter{{Field: "number_of_guests", Op: repository.FilterEq, Value: 2}}, Limit: 2}
+	page, err := tables.Page(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total_count != 3 || len(page.Items) != 2 || !page.Has_more {
+		t.Errorf("first page has %d of %d tables, has_more %v, want 2 of 3 and more", len(page.Items), page.Total_count, page.Has_more)
+	}
+
+	query.Cursor = page.Next_cursor
+	page, err = tables.Page(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total_count != 3 || len(page.Items) != 1 || page.Has_more {
+		t.Errorf("last page has %d of %d tables, has_more %v, want 1 of 3 and no more", len(page.Items), page.Total_count, page.Has_more)
+	}
+}
+
+func TestListInvalidCursor(t *testing.T) {
+	tables := tablesWithGuests(t, 2, 4, 6)
+
+	byGuests := repository.ListQuery{Sort: []repository.Sort{{Field: "number_of_guests"}}, Limit: 1}
+	page, err := tables.Page(context.Background(), byGuests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query repository.ListQuery
+	}{
+		{"another field", repository.ListQuery{Sort: []repository.Sort{{Field: "table_number"}}, Cursor: page.Next_cursor, Limit: 1}},
+		{"another direction", repository.ListQuery{Sort: []repository.Sort{{Field: "number_of_guests", Descending: true}}, Cursor: page.Next_cursor, Limit: 1}},
+		{"no sort", repository.ListQuery{Cursor: page.Next_cursor, Limit: 1}},
+		{"not base64", repository.ListQuery{Sort: byGuests.Sort, Cursor: "not a cursor!", Limit: 1}},
+		{"not bson", repository.ListQuery{Sort: byGuests.Sort, Cursor: "bm90IGJzb24", Limit: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tables.Page(context.Background(), tt.query); err != repository.ErrInvalidCursor {
+				t.Errorf("error %v, want %v", err, repository.ErrInvalidCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type menuRepository struct {
+	menus collection[models.Menu]
+}
+
+func NewMenuRepository() repository.MenuRepository {
+	return &menuRepository{}
+}
+
+func (r *menuRepository) List(ctx context.Context) ([]models.Menu, error) {
+	return r.menus.filter(nil)
+}
+
+func (r *menuRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Menu], error) {
+	return r.menus.list(nil, query)
+}
+
+func (r *menuRepository) FindByID(ctx context.Context, menuId string) (models.Menu, error) {
+	return r.menus.find(func(menu models.Menu) bool { return menu.Menu_id == menuId })
+}
+
+func (r *menuRepository) Insert(ctx context.Context, menu models.Menu) error {
+	return r.menus.insert(menu)
+}
+
+func (r *menuRepository) Update(ctx context.Context, menuId string, update repository.MenuUpdate) (bool, error) {
+	return r.menus.update(func(menu models.Menu) bool { return menu.Menu_id == menuId }, update.Set())
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+)
+
+type noteRepository struct {
+	notes collection[models.Note]
+}
+
+func NewNoteRepository() repository.NoteRepository {
+	return &noteRepository{}
+}
+
+func (r *noteRepository) List(ctx context.Context) ([]models.Note, error) {
+	return r.notes.filter(nil)
+}
+
+func (r *noteRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Note], error) {
+	return r.notes.list(nil, query)
+}
+
+func (r *noteRepository) ListBySubject(ctx context.Context, subjectType string, subjectId string) ([]models.Note, error) {
+	notes, err := r.notes.filter(func(note models.Note) bool {
+		return equal(note.Subject_type, subjectType) && equal(note.Subject_id, subjectId)
+	})
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].Created_at.Before(notes[j].Created_at) })
+	return notes, err
+}
+
+func (r *noteRepository) FindByID(ctx context.Context, noteId string) (models.Note, error) {
+	return r.notes.find(func(note models.Note) bool { return note.Note_id == noteId })
+}
+
+func (r *noteRepository) Insert(ctx context.Context, note models.Note) error {
+	return r.notes.insert(note)
+}
+
+func (r *noteRepository) Update(ctx context.Context, noteId string, update repository.NoteUpdate) (bool, error) {
+	return r.notes.update(func(note models.Note) bool { return note.Note_id == noteId }, update.Set())
+}
+
+func (r *noteRepository) Delete(ctx context.Context, noteId string) (bool, error) {
+	return r.notes.remove(func(note models.Note) bool { return note.Note_id == noteId })
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type orderItemRepository struct {
+	orderItems collection[models.OrderItem]
+
+	foods  repository.FoodRepository
+	menus  repository.MenuRepository
+	orders repository.OrderRepository
+	tables repository.TableRepository
+	notes  repository.NoteRepository
+}
+
+// NewOrderItemRepository needs the other repositories to join order items
+// with their food, menu, order, table and notes the way the MongoDB $lookup
+// stages do.
+func NewOrderItemRepository(foods repository.FoodRepository, menus repository.MenuRepository, orders repository.OrderRepository, tables repository.TableRepository, notes repository.NoteRepository) repository.OrderItemRepository {
+	return &orderItemRepository{foods: foods, menus: menus, orders: orders, tables: tables, notes: notes}
+}
+
+func (r *orderItemRepository) List(ctx context.Context) ([]models.OrderItem, error) {
+	return r.orderItems.filter(nil)
+}
+
+func (r *orderItemRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.OrderItem], error) {
+	return r.orderItems.list(nil, query)
+}
+
+func (r *orderItemRepository) FindByID(ctx context.Context, orderItemId string) (models.OrderItem, error) {
+	return r.orderItems.find(func(orderItem models.OrderItem) bool { return orderItem.Order_item_id == orderItemId })
+}
+
+func (r *orderItemRepository) ListByOrder(ctx context.Context, orderId string) ([]models.OrderItem, error) {
+	return r.orderItems.filter(func(orderItem models.OrderItem) bool { return orderItem.Order_id == orderId })
+}
+
+func (r *orderItemRepository) InsertMany(ctx context.Context, orderItems []models.OrderItem) error {
+	for _, orderItem := range orderItems {
+		if err := r.orderItems.insert(orderItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *orderItemRepository) Update(ctx context.Context, orderItemId string, update repository.OrderItemUpdate) (bool, error) {
+	return r.orderItems.update(func(orderItem models.OrderItem) bool { return orderItem.Order_item_id == orderItemId }, update.Set())
+}
+
+func (r *orderItemRepository) ListOpenKitchenItems(ctx context.Context, station string) ([]models.OrderItem, error) {
+	openOrderItems, err := r.orderItems.filter(func(orderItem models.OrderItem) bool {
+		if !equal(orderItem.Kitchen_status, models.KitchenPending) && !equal(orderItem.Kitchen_status, models.KitchenPreparing) {
+			return false
+		}
+		return station == "" || equal(orderItem.Station, station)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(openOrderItems, func(i, j int) bool {
+		return openOrderItems[i].Created_at.Before(openOrderItems[j].Created_at)
+	})
+	return openOrderItems, nil
+}
+
+func (r *orderItemRepository) UpdateKitchenStatus(ctx context.Context, orderItemId string, from *string, status string, updatedAt time.Time) (bool, error) {
+	return r.orderItems.update(func(orderItem models.OrderItem) bool {
+		return orderItem.Order_item_id == orderItemId && equalPointer(orderItem.Kitchen_status, from)
+	}, primitive.D{{Key: "kitchen_status", Value: status}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *orderItemRepository) UpdateStockUsed(ctx context.Context, orderItemId string, stockUsed []models.StockUse, updatedAt time.Time) (bool, error) {
+	return r.orderItems.update(func(orderItem models.OrderItem) bool {
+		return orderItem.Order_item_id == orderItemId
+	}, primitive.D{{Key: "stock_used", Value: stockUsed}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *orderItemRepository) AlltheItemsInAnOrder(ctx context.Context, orderId string) (models.OrderDetails, error) {
+	details := models.OrderDetails{Payment_due: models.Cents(0), Order_items: []models.OrderItemDetail{}, Notes: []models.Note{}}
+
+	orderItems, err := r.orderItems.filter(func(orderItem models.OrderItem) bool { return orderItem.Order_id == orderId })
+	if err != nil {
+		return details, err
+	}
+
+	for _, orderItem := range orderItems {
+		var detail models.OrderItemDetail
+		detail.Order_item_id = orderItem.Order_item_id
+		detail.Quantity = orderItem.Quantity
+		detail.Variant = orderItem.Variant
+		detail.Modifiers = orderItem.Modifiers
+		detail.Notes, err = r.notes.ListBySubject(ctx, models.NoteOrderItem, orderItem.Order_item_id)
+		if err != nil {
+			return details, err
+		}
+
+		if orderItem.Food_id != nil {
+			food, err := r.foods.FindByID(ctx, *orderItem.Food_id)
+			if err != nil && err != repository.ErrNotFound {
+				return details, err
+			}
+			if err == nil {
+				detail.Amount = food.Price
+				detail.Price = food.Price
+				detail.Food_name = &food.Namme
+				detail.Food_image = food.Food_image
+				detail.Food_id = &food.Food_id
+			}
+			if err == nil && food.Menu_id != nil {
+				menu, err := r.menus.FindByID(ctx, *food.Menu_id)
+				if err != nil && err != repository.ErrNotFound {
+					return details, err
+				}
+				if err == nil {
+					detail.Category = &menu.Category
+				}
+			}
+		}
+
+		//items ordered before line prices were kept are charged the food price
+		if orderItem.Line_price != nil {
+			detail.Amount = orderItem.Line_price
+			detail.Price = orderItem.Unit_price
+		}
+
+		order, err := r.orders.FindByID(ctx, orderItem.Order_id)
+		if err != nil && err != repository.ErrNotFound {
+			return details, err
+		}
+		if err == nil {
+			detail.Order_id = order.Order_id
+			if order.Table_id != nil {
+				table, err := r.tables.FindByID(ctx, *order.Table_id)
+				if err != nil && err != repository.ErrNotFound {
+					return details, err
+				}
+				if err == nil {
+					detail.Table_id = &table.Table_id
+					detail.Table_number = table.Table_number
+				}
+			}
+		}
+
+		if detail.Amount != nil {
+			details.Payment_due = details.Payment_due.Add(*detail.Amount)
+		}
+		details.Table_number = detail.Table_number
+		details.Order_items = append(details.Order_items, detail)
+	}
+	details.Total_count = len(details.Order_items)
+	if len(orderItems) > 0 {
+		details.Notes, err = r.notes.ListBySubject(ctx, models.NoteOrder, orderId)
+	}
+
+	return details, err
+}
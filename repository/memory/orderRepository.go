@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type orderRepository struct {
+	orders collection[models.Order]
+}
+
+func NewOrderRepository() repository.OrderRepository {
+	return &orderRepository{}
+}
+
+func (r *orderRepository) List(ctx context.Context) ([]models.Order, error) {
+	return r.orders.filter(nil)
+}
+
+func (r *orderRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Order], error) {
+	return r.orders.list(nil, query)
+}
+
+func (r *orderRepository) ListOpen(ctx context.Context) ([]models.Order, error) {
+	return r.orders.filter(func(order models.Order) bool { return !order.IsClosed() })
+}
+
+func (r *orderRepository) FindByID(ctx context.Context, orderId string) (models.Order, error) {
+	return r.orders.find(func(order models.Order) bool { return order.Order_id == orderId })
+}
+
+func (r *orderRepository) Insert(ctx context.Context, order models.Order) error {
+	return r.orders.insert(order)
+}
+
+func (r *orderRepository) Update(ctx context.Context, orderId string, update repository.OrderUpdate) (bool, error) {
+	return r.orders.update(func(order models.Order) bool { return order.Order_id == orderId }, update.Set())
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, orderId string, from *string, update repository.OrderUpdate) (bool, error) {
+	return r.orders.update(func(order models.Order) bool {
+		return order.Order_id == orderId && equalPointer(order.Status, from)
+	}, update.Set())
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type paymentRepository struct {
+	payments collection[models.Payment]
+}
+
+func NewPaymentRepository() repository.PaymentRepository {
+	return &paymentRepository{}
+}
+
+func (r *paymentRepository) List(ctx context.Context) ([]models.Payment, error) {
+	return r.payments.filter(nil)
+}
+
+func (r *paymentRepository) ListByInvoice(ctx context.Context, invoiceId string) ([]models.Payment, error) {
+	return r.payments.filter(func(payment models.Payment) bool { return payment.Invoice_id == invoiceId })
+}
+
+func (r *paymentRepository) PageByInvoice(ctx context.Context, invoiceId string, query repository.ListQuery) (repository.Page[models.Payment], error) {
+	return r.payments.list(func(payment models.Payment) bool { return payment.Invoice_id == invoiceId }, query)
+}
+
+func (r *paymentRepository) Insert(ctx context.Context, payment models.Payment) error {
+	return r.payments.insert(payment)
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, paymentId string) error {
+	_, err := r.payments.remove(func(payment models.Payment) bool { return payment.Payment_id == paymentId })
+	return err
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type promotionRepository struct {
+	promotions collection[models.Promotion]
+}
+
+func NewPromotionRepository() repository.PromotionRepository {
+	return &promotionRepository{}
+}
+
+func (r *promotionRepository) List(ctx context.Context) ([]models.Promotion, error) {
+	return r.promotions.filter(nil)
+}
+
+func (r *promotionRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Promotion], error) {
+	return r.promotions.list(nil, query)
+}
+
+func (r *promotionRepository) FindByID(ctx context.Context, promotionId string) (models.Promotion, error) {
+	return r.promotions.find(func(promotion models.Promotion) bool { return promotion.Promotion_id == promotionId })
+}
+
+func (r *promotionRepository) FindByCouponCode(ctx context.Context, couponCode string) (models.Promotion, error) {
+	return r.promotions.find(func(promotion models.Promotion) bool { return equal(promotion.Coupon_code, couponCode) })
+}
+
+func (r *promotionRepository) Insert(ctx context.Context, promotion models.Promotion) error {
+	return r.promotions.insert(promotion)
+}
+
+func (r *promotionRepository) Update(ctx context.Context, promotionId string, update repository.PromotionUpdate) (bool, error) {
+	return r.promotions.update(func(promotion models.Promotion) bool { return promotion.Promotion_id == promotionId }, update.Set())
+}
+
+func (r *promotionRepository) UpdateTimesUsed(ctx context.Context, promotionId string, usedBefore int, timesUsed int, updatedAt time.Time) (bool, error) {
+	return r.promotions.update(func(promotion models.Promotion) bool {
+		return promotion.Promotion_id == promotionId && promotion.Times_used == usedBefore
+	}, primitive.D{{Key: "times_used", Value: timesUsed}, {Key: "updated_at", Value: updatedAt}})
+}
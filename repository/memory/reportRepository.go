@@ -0,0 +1,427 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+	"time"
+)
+
+type reportRepository struct {
+	invoices   repository.InvoiceRepository
+	orders     repository.OrderRepository
+	orderItems repository.OrderItemRepository
+	foods      repository.FoodRepository
+	menus      repository.MenuRepository
+	tables     repository.TableRepository
+	payments   repository.PaymentRepository
+}
+
+// NewReportRepository adds up the other repositories in Go the way the
+// MongoDB aggregation pipelines do.
+func NewReportRepository(invoices repository.InvoiceRepository, orders repository.OrderRepository, orderItems repository.OrderItemRepository, foods repository.FoodRepository, menus repository.MenuRepository, tables repository.TableRepository, payments repository.PaymentRepository) repository.ReportRepository {
+	return &reportRepository{invoices: invoices, orders: orders, orderItems: orderItems, foods: foods, menus: menus, tables: tables, payments: payments}
+}
+
+func (r *reportRepository) RevenueByDay(ctx context.Context, period repository.ReportRange) ([]models.RevenueRow, error) {
+	return r.revenue(ctx, period, func(t time.Time) string { return t.In(period.Location).Format("2006-01-02") })
+}
+
+func (r *reportRepository) RevenueByHour(ctx context.Context, period repository.ReportRange) ([]models.RevenueRow, error) {
+	return r.revenue(ctx, period, func(t time.Time) string { return t.In(period.Location).Format("15") + ":00" })
+}
+
+func (r *reportRepository) revenue(ctx context.Context, period repository.ReportRange, groupBy func(time.Time) string) ([]models.RevenueRow, error) {
+	invoices, _, err := r.periodInvoices(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]*models.RevenueRow{}
+	for _, invoice := range invoices {
+		key := groupBy(invoice.Created_at)
+		row, ok := rows[key]
+		if !ok {
+			row = &models.RevenueRow{Period: key, Gross: models.Cents(0), Net: models.Cents(0), Tax: models.Cents(0), Discounts: models.Cents(0)}
+			rows[key] = row
+		}
+		row.Invoices++
+		gross := amountOf(invoice.Total_amount)
+		row.Gross = row.Gross.Add(gross)
+		if invoice.Net_amount != nil {
+			row.Net = row.Net.Add(*invoice.Net_amount)
+		} else {
+			row.Net = row.Net.Add(gross)
+		}
+		row.Tax = row.Tax.Add(amountOf(invoice.Tax_amount))
+		row.Discounts = row.Discounts.Add(amountOf(invoice.Discount_amount))
+	}
+
+	result := []models.RevenueRow{}
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+	return result, nil
+}
+
+func (r *reportRepository) TopFoods(ctx context.Context, period repository.ReportRange, limit int) ([]models.FoodSalesRow, error) {
+	sales, err := r.periodSales(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]*models.FoodSalesRow{}
+	result := []*models.FoodSalesRow{}
+	for _, sale := range sales {
+		row, ok := rows[sale.foodId]
+		if !ok {
+			row = &models.FoodSalesRow{Food_id: sale.foodId, Food_name: sale.foodName, Category: sale.category, Revenue: models.Cents(0)}
+			rows[sale.foodId] = row
+			result = append(result, row)
+		}
+		row.Quantity += sale.quantity
+		row.Revenue = row.Revenue.Add(sale.revenue)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Quantity != result[j].Quantity {
+			return result[i].Quantity > result[j].Quantity
+		}
+		if result[i].Revenue.Cmp(result[j].Revenue) != 0 {
+			return result[i].Revenue.Cmp(result[j].Revenue) > 0
+		}
+		return result[i].Food_id < result[j].Food_id
+	})
+
+	top := []models.FoodSalesRow{}
+	for _, row := range result {
+		if len(top) == limit {
+			break
+		}
+		top = append(top, *row)
+	}
+	return top, nil
+}
+
+func (r *reportRepository) RevenueByCategory(ctx context.Context, period repository.ReportRange) ([]models.CategorySalesRow, error) {
+	sales, err := r.periodSales(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]*models.CategorySalesRow{}
+	result := []*models.CategorySalesRow{}
+	for _, sale := range sales {
+		row, ok := rows[sale.category]
+		if !ok {
+			row = &models.CategorySalesRow{Category: sale.category, Revenue: models.Cents(0)}
+			rows[sale.category] = row
+			result = append(result, row)
+		}
+		row.Quantity += sale.quantity
+		row.Revenue = row.Revenue.Add(sale.revenue)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Revenue.Cmp(result[j].Revenue) != 0 {
+			return result[i].Revenue.Cmp(result[j].Revenue) > 0
+		}
+		return result[i].Category < result[j].Category
+	})
+
+	categories := []models.CategorySalesRow{}
+	for _, row := range result {
+		categories = append(categories, *row)
+	}
+	return categories, nil
+}
+
+func (r *reportRepository) TicketSummary(ctx context.Context, period repository.ReportRange) (models.TicketSummary, error) {
+	invoices, orders, err := r.periodInvoices(ctx, period)
+	if err != nil {
+		return models.TicketSummary{}, err
+	}
+	gross, coveredGross := models.Cents(0), models.Cents(0)
+	covers := 0
+	for _, invoice := range invoices {
+		total := amountOf(invoice.Total_amount)
+		gross = gross.Add(total)
+		if order, ok := orders[invoice.Order_id]; ok && order.Covers != nil && *order.Covers > 0 {
+			covers += *order.Covers
+			coveredGross = coveredGross.Add(total)
+		}
+	}
+	return models.NewTicketSummary(len(invoices), gross, covers, coveredGross), nil
+}
+
+func (r *reportRepository) CoversByTable(ctx context.Context, period repository.ReportRange) ([]models.TableCoversRow, error) {
+	allOrders, err := r.orders.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allInvoices, err := r.invoices.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invoicesByOrder := map[string][]models.Invoice{}
+	for _, invoice := range allInvoices {
+		invoicesByOrder[invoice.Order_id] = append(invoicesByOrder[invoice.Order_id], invoice)
+	}
+
+	rows := map[string]*models.TableCoversRow{}
+	result := []*models.TableCoversRow{}
+	for _, order := range allOrders {
+		if !inPeriod(order.Created_at, period) || !countsAsSale(order) {
+			continue
+		}
+		tableId := ""
+		if order.Table_id != nil {
+			tableId = *order.Table_id
+		}
+		row, ok := rows[tableId]
+		if !ok {
+			row = &models.TableCoversRow{Table_id: tableId, Revenue: models.Cents(0)}
+			if table, err := r.tables.FindByID(ctx, tableId); err == nil {
+				row.Table_number = table.Table_number
+			} else if err != repository.ErrNotFound {
+				return nil, err
+			}
+			rows[tableId] = row
+			result = append(result, row)
+		}
+		row.Orders++
+		if order.Covers != nil {
+			row.Covers += *order.Covers
+		}
+		for _, invoice := range invoicesByOrder[order.Order_id] {
+			row.Revenue = row.Revenue.Add(amountOf(invoice.Total_amount))
+		}
+	}
+	//tables without a number come first, as MongoDB sorts null
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Table_number, result[j].Table_number
+		if a != nil && b != nil && *a != *b {
+			return *a < *b
+		}
+		if (a == nil) != (b == nil) {
+			return a == nil
+		}
+		return result[i].Table_id < result[j].Table_id
+	})
+
+	tables := []models.TableCoversRow{}
+	for _, row := range result {
+		tables = append(tables, *row)
+	}
+	return tables, nil
+}
+
+func (r *reportRepository) PaymentMix(ctx context.Context, period repository.ReportRange) ([]models.PaymentMixRow, error) {
+	allPayments, err := r.payments.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]*models.PaymentMixRow{}
+	result := []*models.PaymentMixRow{}
+	for _, payment := range allPayments {
+		if !inPeriod(payment.Created_at, period) {
+			continue
+		}
+		counts, err := r.invoiceCounts(ctx, payment.Invoice_id)
+		if err != nil {
+			return nil, err
+		}
+		if !counts {
+			continue
+		}
+		method := ""
+		if payment.Method != nil {
+			method = *payment.Method
+		}
+		row, ok := rows[method]
+		if !ok {
+			row = &models.PaymentMixRow{Method: method, Amount: models.Cents(0)}
+			rows[method] = row
+			result = append(result, row)
+		}
+		row.Payments++
+		row.Amount = row.Amount.Add(amountOf(payment.Amount))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount.Cmp(result[j].Amount) != 0 {
+			return result[i].Amount.Cmp(result[j].Amount) > 0
+		}
+		return result[i].Method < result[j].Method
+	})
+
+	mix := []models.PaymentMixRow{}
+	for _, row := range result {
+		mix = append(mix, *row)
+	}
+	models.SetPaymentShares(mix)
+	return mix, nil
+}
+
+func (r *reportRepository) Voids(ctx context.Context, period repository.ReportRange) (models.VoidSummary, error) {
+	allOrders, err := r.orders.List(ctx)
+	if err != nil {
+		return models.VoidSummary{}, err
+	}
+	voids := models.VoidSummary{Amount: models.Cents(0)}
+	for _, order := range allOrders {
+		if order.CurrentStatus() != models.OrderVoided || !voidedIn(order, period) {
+			continue
+		}
+		orderItems, err := r.orderItems.ListByOrder(ctx, order.Order_id)
+		if err != nil {
+			return voids, err
+		}
+		if len(orderItems) == 0 {
+			continue
+		}
+		voids.Orders++
+		for _, orderItem := range orderItems {
+			quantity := 1
+			if orderItem.Quantity != nil {
+				quantity = *orderItem.Quantity
+			}
+			voids.Items += quantity
+			if orderItem.Line_price != nil {
+				voids.Amount = voids.Amount.Add(*orderItem.Line_price)
+			} else if orderItem.Food_id != nil {
+				food, err := r.foods.FindByID(ctx, *orderItem.Food_id)
+				if err != nil && err != repository.ErrNotFound {
+					return voids, err
+				}
+				voids.Amount = voids.Amount.Add(amountOf(food.Price))
+			}
+		}
+	}
+	return voids, nil
+}
+
+// periodInvoices returns the invoices issued in period whose order counts
+// as a sale, with those orders by order_id
+func (r *reportRepository) periodInvoices(ctx context.Context, period repository.ReportRange) ([]models.Invoice, map[string]models.Order, error) {
+	allInvoices, err := r.invoices.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	invoices := []models.Invoice{}
+	orders := map[string]models.Order{}
+	for _, invoice := range allInvoices {
+		if !inPeriod(invoice.Created_at, period) {
+			continue
+		}
+		order, err := r.orders.FindByID(ctx, invoice.Order_id)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, nil, err
+		}
+		if err == nil {
+			if !countsAsSale(order) {
+				continue
+			}
+			orders[order.Order_id] = order
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, orders, nil
+}
+
+func (r *reportRepository) invoiceCounts(ctx context.Context, invoiceId string) (bool, error) {
+	invoice, err := r.invoices.FindByID(ctx, invoiceId)
+	if err == repository.ErrNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	order, err := r.orders.FindByID(ctx, invoice.Order_id)
+	if err == repository.ErrNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return countsAsSale(order), nil
+}
+
+type sale struct {
+	foodId   string
+	foodName string
+	category string
+	quantity int
+	revenue  models.Money
+}
+
+// periodSales joins the order items ordered in period with their food and
+// menu, leaving out cancelled and voided orders
+func (r *reportRepository) periodSales(ctx context.Context, period repository.ReportRange) ([]sale, error) {
+	allOrderItems, err := r.orderItems.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sales := []sale{}
+	for _, orderItem := range allOrderItems {
+		if !inPeriod(orderItem.Created_at, period) {
+			continue
+		}
+		order, err := r.orders.FindByID(ctx, orderItem.Order_id)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, err
+		}
+		if err == nil && !countsAsSale(order) {
+			continue
+		}
+
+		s := sale{quantity: 1, revenue: models.Cents(0)}
+		if orderItem.Quantity != nil {
+			s.quantity = *orderItem.Quantity
+		}
+		if orderItem.Food_id != nil {
+			s.foodId = *orderItem.Food_id
+			food, err := r.foods.FindByID(ctx, *orderItem.Food_id)
+			if err != nil && err != repository.ErrNotFound {
+				return nil, err
+			}
+			if err == nil {
+				s.foodName = food.Namme
+				s.revenue = amountOf(food.Price)
+				if food.Menu_id != nil {
+					menu, err := r.menus.FindByID(ctx, *food.Menu_id)
+					if err != nil && err != repository.ErrNotFound {
+						return nil, err
+					}
+					s.category = menu.Category
+				}
+			}
+		}
+		if orderItem.Line_price != nil {
+			s.revenue = *orderItem.Line_price
+		}
+		sales = append(sales, s)
+	}
+	return sales, nil
+}
+
+func inPeriod(t time.Time, period repository.ReportRange) bool {
+	return !t.Before(period.From) && t.Before(period.To)
+}
+
+func voidedIn(order models.Order, period repository.ReportRange) bool {
+	for _, change := range order.Status_history {
+		if change.To == models.OrderVoided && inPeriod(change.Changed_at, period) {
+			return true
+		}
+	}
+	return false
+}
+
+func countsAsSale(order models.Order) bool {
+	status := order.CurrentStatus()
+	return status != models.OrderCancelled && status != models.OrderVoided
+}
+
+func amountOf(amount *models.Money) models.Money {
+	if amount == nil {
+		return models.Cents(0)
+	}
+	return *amount
+}
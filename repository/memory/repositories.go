@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"restaurant-management/repository"
+)
+
+// NewRepositories keeps every collection in process memory, nothing
+// survives a restart. It is meant for tests and local development.
+func NewRepositories() repository.Repositories {
+	foods := NewFoodRepository()
+	menus := NewMenuRepository()
+	orders := NewOrderRepository()
+	tables := NewTableRepository()
+	notes := NewNoteRepository()
+	orderItems := NewOrderItemRepository(foods, menus, orders, tables, notes)
+	invoices := NewInvoiceRepository()
+	payments := NewPaymentRepository()
+
+	return repository.Repositories{
+		Users:        NewUserRepository(),
+		Foods:        foods,
+		Menus:        menus,
+		Tables:       tables,
+		Orders:       orders,
+		OrderItems:   orderItems,
+		Invoices:     invoices,
+		Payments:     payments,
+		Notes:        notes,
+		Reservations: NewReservationRepository(),
+		Waitlist:     NewWaitlistRepository(),
+		TaxRates:     NewTaxRateRepository(),
+		TaxRules:     NewTaxRuleRepository(),
+		Promotions:   NewPromotionRepository(),
+		Ingredients:  NewIngredientRepository(),
+		Reports:      NewReportRepository(invoices, orders, orderItems, foods, menus, tables, payments),
+		Drawers:      NewDrawerRepository(),
+		ZReports:     NewZReportRepository(),
+		Idempotency:  NewIdempotencyRepository(),
+	}
+}
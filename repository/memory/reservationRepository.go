@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+)
+
+type reservationRepository struct {
+	reservations collection[models.Reservation]
+}
+
+func NewReservationRepository() repository.ReservationRepository {
+	return &reservationRepository{}
+}
+
+func (r *reservationRepository) List(ctx context.Context) ([]models.Reservation, error) {
+	return r.reservations.filter(nil)
+}
+
+func (r *reservationRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Reservation], error) {
+	return r.reservations.list(nil, query)
+}
+
+func (r *reservationRepository) ListOverlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error) {
+	return r.reservations.filter(func(reservation models.Reservation) bool {
+		holdsTable := equal(reservation.Status, models.ReservationBooked) || equal(reservation.Status, models.ReservationSeated)
+		return holdsTable && reservation.Reservation_time != nil &&
+			reservation.Reservation_time.Before(to) && reservation.Ends_at.After(from)
+	})
+}
+
+func (r *reservationRepository) FindByID(ctx context.Context, reservationId string) (models.Reservation, error) {
+	return r.reservations.find(func(reservation models.Reservation) bool { return reservation.Reservation_id == reservationId })
+}
+
+func (r *reservationRepository) Insert(ctx context.Context, reservation models.Reservation) error {
+	return r.reservations.insert(reservation)
+}
+
+func (r *reservationRepository) Update(ctx context.Context, reservationId string, update repository.ReservationUpdate) (bool, error) {
+	return r.reservations.update(func(reservation models.Reservation) bool { return reservation.Reservation_id == reservationId }, update.Set())
+}
+
+func (r *reservationRepository) UpdateStatus(ctx context.Context, reservationId string, from *string, update repository.ReservationUpdate) (bool, error) {
+	return r.reservations.update(func(reservation models.Reservation) bool {
+		return reservation.Reservation_id == reservationId && equalPointer(reservation.Status, from)
+	}, update.Set())
+}
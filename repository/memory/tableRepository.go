@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tableRepository struct {
+	tables collection[models.Table]
+}
+
+func NewTableRepository() repository.TableRepository {
+	return &tableRepository{}
+}
+
+func (r *tableRepository) List(ctx context.Context) ([]models.Table, error) {
+	return r.tables.filter(nil)
+}
+
+func (r *tableRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Table], error) {
+	return r.tables.list(nil, query)
+}
+
+func (r *tableRepository) FindByID(ctx context.Context, tableId string) (models.Table, error) {
+	return r.tables.find(func(table models.Table) bool { return table.Table_id == tableId })
+}
+
+func (r *tableRepository) Insert(ctx context.Context, table models.Table) error {
+	return r.tables.insert(table)
+}
+
+func (r *tableRepository) Update(ctx context.Context, tableId string, update repository.TableUpdate) (bool, error) {
+	return r.tables.update(func(table models.Table) bool { return table.Table_id == tableId }, update.Set())
+}
+
+func (r *tableRepository) Claim(ctx context.Context, tableId string, now time.Time, until time.Time) (bool, error) {
+	return r.tables.update(func(table models.Table) bool {
+		return table.Table_id == tableId && (table.Claimed_until == nil || !table.Claimed_until.After(now))
+	}, primitive.D{{Key: "claimed_until", Value: until}})
+}
+
+func (r *tableRepository) Release(ctx context.Context, tableId string, until time.Time) error {
+	_, err := r.tables.update(func(table models.Table) bool {
+		return table.Table_id == tableId && table.Claimed_until != nil && table.Claimed_until.Equal(until)
+	}, primitive.D{{Key: "claimed_until", Value: nil}})
+	return err
+}
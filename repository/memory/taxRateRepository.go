@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type taxRateRepository struct {
+	taxRates collection[models.TaxRate]
+}
+
+func NewTaxRateRepository() repository.TaxRateRepository {
+	return &taxRateRepository{}
+}
+
+func (r *taxRateRepository) List(ctx context.Context) ([]models.TaxRate, error) {
+	return r.taxRates.filter(nil)
+}
+
+func (r *taxRateRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.TaxRate], error) {
+	return r.taxRates.list(nil, query)
+}
+
+func (r *taxRateRepository) FindByID(ctx context.Context, taxRateId string) (models.TaxRate, error) {
+	return r.taxRates.find(func(taxRate models.TaxRate) bool { return taxRate.Tax_rate_id == taxRateId })
+}
+
+func (r *taxRateRepository) Insert(ctx context.Context, taxRate models.TaxRate) error {
+	return r.taxRates.insert(taxRate)
+}
+
+func (r *taxRateRepository) Update(ctx context.Context, taxRateId string, update repository.TaxRateUpdate) (bool, error) {
+	return r.taxRates.update(func(taxRate models.TaxRate) bool { return taxRate.Tax_rate_id == taxRateId }, update.Set())
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+type taxRuleRepository struct {
+	taxRules collection[models.TaxRule]
+}
+
+func NewTaxRuleRepository() repository.TaxRuleRepository {
+	return &taxRuleRepository{}
+}
+
+func (r *taxRuleRepository) List(ctx context.Context) ([]models.TaxRule, error) {
+	return r.taxRules.filter(nil)
+}
+
+func (r *taxRuleRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.TaxRule], error) {
+	return r.taxRules.list(nil, query)
+}
+
+func (r *taxRuleRepository) FindByID(ctx context.Context, taxRuleId string) (models.TaxRule, error) {
+	return r.taxRules.find(func(taxRule models.TaxRule) bool { return taxRule.Tax_rule_id == taxRuleId })
+}
+
+func (r *taxRuleRepository) Insert(ctx context.Context, taxRule models.TaxRule) error {
+	return r.taxRules.insert(taxRule)
+}
+
+func (r *taxRuleRepository) Update(ctx context.Context, taxRuleId string, update repository.TaxRuleUpdate) (bool, error) {
+	return r.taxRules.update(func(taxRule models.TaxRule) bool { return taxRule.Tax_rule_id == taxRuleId }, update.Set())
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userRepository struct {
+	users collection[models.User]
+}
+
+func NewUserRepository() repository.UserRepository {
+	return &userRepository{}
+}
+
+func (r *userRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.User], error) {
+	return r.users.list(nil, query)
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	return r.users.find(func(user models.User) bool { return user.User_id == userId })
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.users.find(func(user models.User) bool { return equal(user.Email, email) })
+}
+
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	return r.users.count(nil)
+}
+
+func (r *userRepository) CountByEmailOrPhone(ctx context.Context, email string, phone string) (int64, error) {
+	return r.users.count(func(user models.User) bool {
+		return equal(user.Email, email) || equal(user.Phone, phone)
+	})
+}
+
+func (r *userRepository) Insert(ctx context.Context, user models.User) error {
+	return r.users.insert(user)
+}
+
+func (r *userRepository) InsertFirst(ctx context.Context, user models.User) error {
+	user.First_user = true
+	return r.users.insertUnique(user, func(models.User) bool { return true })
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userId string, role string, updatedAt time.Time) (bool, error) {
+	return r.users.update(func(user models.User) bool { return user.User_id == userId }, primitive.D{{Key: "role", Value: role}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *userRepository) SetTokens(ctx context.Context, userId string, token *string, refreshToken *string, updatedAt time.Time) (bool, error) {
+	return r.users.update(func(user models.User) bool { return user.User_id == userId }, tokens(token, refreshToken, updatedAt))
+}
+
+func (r *userRepository) HasToken(ctx context.Context, userId string, token string) (bool, error) {
+	count, err := r.users.count(func(user models.User) bool {
+		return user.User_id == userId && equal(user.Token, token)
+	})
+	return count > 0, err
+}
+
+func (r *userRepository) RotateTokens(ctx context.Context, userId string, oldRefreshToken string, token string, refreshToken string, updatedAt time.Time) (bool, error) {
+	return r.users.update(func(user models.User) bool {
+		return user.User_id == userId && equal(user.Refresh_Token, oldRefreshToken)
+	}, tokens(&token, &refreshToken, updatedAt))
+}
+
+func tokens(token *string, refreshToken *string, updatedAt time.Time) primitive.D {
+	return primitive.D{
+		{Key: "token", Value: token},
+		{Key: "refresh_token", Value: refreshToken},
+		{Key: "updated_at", Value: updatedAt},
+	}
+}
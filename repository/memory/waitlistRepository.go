@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"sort"
+)
+
+type waitlistRepository struct {
+	entries collection[models.WaitlistEntry]
+}
+
+func NewWaitlistRepository() repository.WaitlistRepository {
+	return &waitlistRepository{}
+}
+
+func (r *waitlistRepository) ListWaiting(ctx context.Context) ([]models.WaitlistEntry, error) {
+	waiting, err := r.entries.filter(func(entry models.WaitlistEntry) bool { return equal(entry.Status, models.WaitlistWaiting) })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(waiting, func(i, j int) bool { return waiting[i].Created_at.Before(waiting[j].Created_at) })
+	return waiting, nil
+}
+
+func (r *waitlistRepository) PageWaiting(ctx context.Context, query repository.ListQuery) (repository.Page[models.WaitlistEntry], error) {
+	return r.entries.list(func(entry models.WaitlistEntry) bool { return equal(entry.Status, models.WaitlistWaiting) }, query)
+}
+
+func (r *waitlistRepository) FindByID(ctx context.Context, waitlistId string) (models.WaitlistEntry, error) {
+	return r.entries.find(func(entry models.WaitlistEntry) bool { return entry.Waitlist_id == waitlistId })
+}
+
+func (r *waitlistRepository) Insert(ctx context.Context, entry models.WaitlistEntry) error {
+	return r.entries.insert(entry)
+}
+
+func (r *waitlistRepository) UpdateStatus(ctx context.Context, waitlistId string, from *string, update repository.WaitlistUpdate) (bool, error) {
+	return r.entries.update(func(entry models.WaitlistEntry) bool {
+		return entry.Waitlist_id == waitlistId && equalPointer(entry.Status, from)
+	}, update.Set())
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MenuRepository interface {
+	List(ctx context.Context) ([]models.Menu, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Menu], error)
+	FindByID(ctx context.Context, menuId string) (models.Menu, error)
+	Insert(ctx context.Context, menu models.Menu) error
+	Update(ctx context.Context, menuId string, update MenuUpdate) (bool, error)
+}
+
+// MenuUpdate changes the fields of a menu that are not nil
+type MenuUpdate struct {
+	Name       *string
+	Category   *string
+	Start_date *time.Time
+	End_date   *time.Time
+	Timezone   *string
+	Schedules  []models.MenuSchedule
+	Updated_at time.Time
+}
+
+// Set is the update as the fields to store
+func (update MenuUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "start_date", update.Start_date)
+	fields = setGiven(fields, "end_date", update.End_date)
+	fields = setGiven(fields, "schedules", update.Schedules)
+	fields = setGiven(fields, "timezone", update.Timezone)
+	fields = setGiven(fields, "name", update.Name)
+	fields = setGiven(fields, "category", update.Category)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
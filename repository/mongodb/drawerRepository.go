@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type drawerRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDrawerRepository(db *mongo.Database) repository.DrawerRepository {
+	return &drawerRepository{collection: database.OpenCollection(db, "drawer")}
+}
+
+func (r *drawerRepository) List(ctx context.Context) ([]models.CashDrawer, error) {
+	drawers := []models.CashDrawer{}
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "opened_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &drawers)
+	return drawers, err
+}
+
+func (r *drawerRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.CashDrawer], error) {
+	return list[models.CashDrawer](ctx, r.collection, nil, query)
+}
+
+func (r *drawerRepository) FindByID(ctx context.Context, drawerId string) (models.CashDrawer, error) {
+	var drawer models.CashDrawer
+	err := findOne(ctx, r.collection, bson.M{"drawer_id": drawerId}, &drawer)
+	return drawer, err
+}
+
+func (r *drawerRepository) FindOpen(ctx context.Context) (models.CashDrawer, error) {
+	var drawer models.CashDrawer
+	err := findOne(ctx, r.collection, bson.M{"status": models.DrawerOpen}, &drawer)
+	return drawer, err
+}
+
+// Open upserts on the open drawer, when there is one already nothing is
+// inserted
+func (r *drawerRepository) Open(ctx context.Context, drawer models.CashDrawer) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"status": models.DrawerOpen},
+		bson.D{{Key: "$setOnInsert", Value: drawer}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *drawerRepository) AddMovement(ctx context.Context, drawerId string, movement models.DrawerMovement) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"drawer_id": drawerId, "status": models.DrawerOpen},
+		bson.D{{Key: "$push", Value: bson.D{{Key: "movements", Value: movement}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *drawerRepository) Close(ctx context.Context, drawerId string, closing repository.DrawerClose) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"drawer_id": drawerId, "status": models.DrawerOpen}, closing.Set())
+}
+
+func (r *drawerRepository) Reopen(ctx context.Context, drawerId string) error {
+	_, err := updateOne(ctx, r.collection, bson.M{"drawer_id": drawerId}, primitive.D{
+		{Key: "status", Value: models.DrawerOpen},
+		{Key: "counted_cash", Value: nil},
+		{Key: "expected_cash", Value: nil},
+		{Key: "variance", Value: nil},
+		{Key: "z_report_id", Value: nil},
+		{Key: "closed_by", Value: ""},
+		{Key: "closed_at", Value: nil},
+	})
+	return err
+}
+
+type zReportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewZReportRepository(db *mongo.Database) repository.ZReportRepository {
+	return &zReportRepository{collection: database.OpenCollection(db, "zReport")}
+}
+
+func (r *zReportRepository) List(ctx context.Context) ([]models.ZReport, error) {
+	reports := []models.ZReport{}
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "number", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &reports)
+	return reports, err
+}
+
+func (r *zReportRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.ZReport], error) {
+	return list[models.ZReport](ctx, r.collection, nil, query)
+}
+
+func (r *zReportRepository) FindByID(ctx context.Context, zReportId string) (models.ZReport, error) {
+	var report models.ZReport
+	err := findOne(ctx, r.collection, bson.M{"z_report_id": zReportId}, &report)
+	return report, err
+}
+
+func (r *zReportRepository) Latest(ctx context.Context) (models.ZReport, error) {
+	var report models.ZReport
+	err := r.collection.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}})).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		return report, repository.ErrNotFound
+	}
+	return report, err
+}
+
+func (r *zReportRepository) Insert(ctx context.Context, report models.ZReport) error {
+	_, err := r.collection.InsertOne(ctx, report)
+	return err
+}
@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type foodRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFoodRepository(db *mongo.Database) repository.FoodRepository {
+	return &foodRepository{collection: database.OpenCollection(db, "food")}
+}
+
+func (r *foodRepository) Page(ctx context.Context, filter repository.FoodFilter, query repository.ListQuery) (repository.Page[models.Food], error) {
+	match := bson.D{}
+	if len(filter.Exclude_allergens) > 0 {
+		match = append(match, bson.E{Key: "allergens", Value: bson.D{{Key: "$type", Value: "array"}, {Key: "$nin", Value: filter.Exclude_allergens}}})
+	}
+	diets := bson.A{}
+	for _, diet := range filter.Diets {
+		diets = append(diets, bson.D{{Key: "dietary_tags", Value: bson.D{{Key: "$in", Value: models.SatisfyingDiets(diet)}}}})
+	}
+	if len(diets) > 0 {
+		match = append(match, bson.E{Key: "$and", Value: diets})
+	}
+	return list[models.Food](ctx, r.collection, match, query)
+}
+
+func (r *foodRepository) FindByID(ctx context.Context, foodId string) (models.Food, error) {
+	var food models.Food
+	err := findOne(ctx, r.collection, bson.M{"food_id": foodId}, &food)
+	return food, err
+}
+
+func (r *foodRepository) Insert(ctx context.Context, food models.Food) error {
+	_, err := r.collection.InsertOne(ctx, food)
+	return err
+}
+
+func (r *foodRepository) Update(ctx context.Context, foodId string, update repository.FoodUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"food_id": foodId}, update.Set())
+}
@@ -0,0 +1,73 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type idempotencyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewIdempotencyRepository makes sure keys are unique and that MongoDB
+// drops records once they reach expires_at
+func NewIdempotencyRepository(db *mongo.Database) repository.IdempotencyRepository {
+	r := &idempotencyRepository{collection: database.OpenCollection(db, "idempotency")}
+	ensureIndexes(r.collection,
+		mongo.IndexModel{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	)
+	return r
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error) {
+	_, err := r.collection.InsertOne(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	//the TTL monitor runs about once a minute, an expired record that is
+	//still there is dropped and the insert tried again, whoever inserts
+	//first gets the key
+	var stored models.IdempotencyRecord
+	err = findOne(ctx, r.collection, bson.M{"key": record.Key}, &stored)
+	if err == repository.ErrNotFound {
+		return r.Reserve(ctx, record)
+	}
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+	if stored.Expires_at.After(time.Now()) {
+		return stored, false, nil
+	}
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": stored.ID, "expires_at": stored.Expires_at}); err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+	return r.Reserve(ctx, record)
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := updateOne(ctx, r.collection, bson.M{"_id": record.ID}, primitive.D{
+		{Key: "status", Value: record.Status},
+		{Key: "content_type", Value: record.Content_type},
+		{Key: "body", Value: record.Body},
+		{Key: "expires_at", Value: record.Expires_at},
+	})
+	return err
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": record.ID})
+	return err
+}
@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ingredientRepository struct {
+	collection *mongo.Collection
+}
+
+func NewIngredientRepository(db *mongo.Database) repository.IngredientRepository {
+	return &ingredientRepository{collection: database.OpenCollection(db, "ingredient")}
+}
+
+func (r *ingredientRepository) List(ctx context.Context) ([]models.Ingredient, error) {
+	ingredients := []models.Ingredient{}
+	err := findAll(ctx, r.collection, bson.M{}, &ingredients)
+	return ingredients, err
+}
+
+func (r *ingredientRepository) Page(ctx context.Context, lowStock bool, query repository.ListQuery) (repository.Page[models.Ingredient], error) {
+	match := bson.D{}
+	if lowStock {
+		match = bson.D{
+			{Key: "low_stock_threshold", Value: bson.D{{Key: "$type", Value: "number"}}},
+			{Key: "$expr", Value: bson.D{{Key: "$lte", Value: bson.A{"$stock", "$low_stock_threshold"}}}},
+		}
+	}
+	return list[models.Ingredient](ctx, r.collection, match, query)
+}
+
+func (r *ingredientRepository) FindByID(ctx context.Context, ingredientId string) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := findOne(ctx, r.collection, bson.M{"ingredient_id": ingredientId}, &ingredient)
+	return ingredient, err
+}
+
+func (r *ingredientRepository) Insert(ctx context.Context, ingredient models.Ingredient) error {
+	_, err := r.collection.InsertOne(ctx, ingredient)
+	return err
+}
+
+func (r *ingredientRepository) Update(ctx context.Context, ingredientId string, update repository.IngredientUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"ingredient_id": ingredientId}, update.Set())
+}
+
+func (r *ingredientRepository) AdjustStock(ctx context.Context, ingredientId string, delta float64, updatedAt time.Time) (bool, error) {
+	filter := bson.M{"ingredient_id": ingredientId}
+	if delta < 0 {
+		filter["stock"] = bson.M{"$gte": -delta}
+	}
+	//rounded like models.RoundStock, fractions of a unit would drift otherwise
+	update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{
+		{Key: "stock", Value: bson.D{{Key: "$round", Value: bson.A{bson.D{{Key: "$add", Value: bson.A{"$stock", delta}}}, 6}}}},
+		{Key: "updated_at", Value: updatedAt},
+	}}}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type invoiceRepository struct {
+	collection *mongo.Collection
+}
+
+// NewInvoiceRepository makes sure an order has one invoice at most, the
+// server does not start while some order has several
+func NewInvoiceRepository(db *mongo.Database) repository.InvoiceRepository {
+	r := &invoiceRepository{collection: database.OpenCollection(db, "invoice")}
+	ensureIndexes(r.collection, mongo.IndexModel{Keys: bson.D{{Key: "order_id", Value: 1}}, Options: options.Index().SetUnique(true)})
+	return r
+}
+
+func (r *invoiceRepository) List(ctx context.Context) ([]models.Invoice, error) {
+	allInvoices := []models.Invoice{}
+	err := findAll(ctx, r.collection, bson.M{}, &allInvoices)
+	return allInvoices, err
+}
+
+func (r *invoiceRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Invoice], error) {
+	return list[models.Invoice](ctx, r.collection, nil, query)
+}
+
+func (r *invoiceRepository) FindByID(ctx context.Context, invoiceId string) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := findOne(ctx, r.collection, bson.M{"invoice_id": invoiceId}, &invoice)
+	return invoice, err
+}
+
+func (r *invoiceRepository) FindByOrderID(ctx context.Context, orderId string) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := findOne(ctx, r.collection, bson.M{"order_id": orderId}, &invoice)
+	return invoice, err
+}
+
+func (r *invoiceRepository) Insert(ctx context.Context, invoice models.Invoice) error {
+	_, err := r.collection.InsertOne(ctx, invoice)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *invoiceRepository) Update(ctx context.Context, invoiceId string, update repository.InvoiceUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"invoice_id": invoiceId}, update.Set())
+}
+
+func (r *invoiceRepository) UpdateBalance(ctx context.Context, invoiceId string, paidBefore models.Money, update repository.InvoiceUpdate) (bool, error) {
+	filter := bson.M{"invoice_id": invoiceId, "amount_paid.amount": paidBefore.Amount}
+	if paidBefore.IsZero() {
+		//invoices created before payments existed have no amount_paid yet
+		filter["amount_paid.amount"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	return updateOne(ctx, r.collection, filter, update.Set())
+}
@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type menuRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMenuRepository(db *mongo.Database) repository.MenuRepository {
+	return &menuRepository{collection: database.OpenCollection(db, "menu")}
+}
+
+func (r *menuRepository) List(ctx context.Context) ([]models.Menu, error) {
+	allMenus := []models.Menu{}
+	err := findAll(ctx, r.collection, bson.M{}, &allMenus)
+	return allMenus, err
+}
+
+func (r *menuRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Menu], error) {
+	return list[models.Menu](ctx, r.collection, nil, query)
+}
+
+func (r *menuRepository) FindByID(ctx context.Context, menuId string) (models.Menu, error) {
+	var menu models.Menu
+	err := findOne(ctx, r.collection, bson.M{"menu_id": menuId}, &menu)
+	return menu, err
+}
+
+func (r *menuRepository) Insert(ctx context.Context, menu models.Menu) error {
+	_, err := r.collection.InsertOne(ctx, menu)
+	return err
+}
+
+func (r *menuRepository) Update(ctx context.Context, menuId string, update repository.MenuUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"menu_id": menuId}, update.Set())
+}
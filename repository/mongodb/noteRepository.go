@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type noteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNoteRepository(db *mongo.Database) repository.NoteRepository {
+	return &noteRepository{collection: database.OpenCollection(db, "note")}
+}
+
+func (r *noteRepository) List(ctx context.Context) ([]models.Note, error) {
+	allNotes := []models.Note{}
+	err := findAll(ctx, r.collection, bson.M{}, &allNotes)
+	return allNotes, err
+}
+
+func (r *noteRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Note], error) {
+	return list[models.Note](ctx, r.collection, nil, query)
+}
+
+func (r *noteRepository) ListBySubject(ctx context.Context, subjectType string, subjectId string) ([]models.Note, error) {
+	notes := []models.Note{}
+	cursor, err := r.collection.Find(ctx, bson.M{"subject_type": subjectType, "subject_id": subjectId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &notes)
+	return notes, err
+}
+
+func (r *noteRepository) FindByID(ctx context.Context, noteId string) (models.Note, error) {
+	var note models.Note
+	err := findOne(ctx, r.collection, bson.M{"note_id": noteId}, &note)
+	return note, err
+}
+
+func (r *noteRepository) Insert(ctx context.Context, note models.Note) error {
+	_, err := r.collection.InsertOne(ctx, note)
+	return err
+}
+
+func (r *noteRepository) Update(ctx context.Context, noteId string, update repository.NoteUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"note_id": noteId}, update.Set())
+}
+
+func (r *noteRepository) Delete(ctx context.Context, noteId string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"note_id": noteId})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
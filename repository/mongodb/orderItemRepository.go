@@ -0,0 +1,179 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type orderItemRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrderItemRepository(db *mongo.Database) repository.OrderItemRepository {
+	return &orderItemRepository{collection: database.OpenCollection(db, "orderItem")}
+}
+
+func (r *orderItemRepository) List(ctx context.Context) ([]models.OrderItem, error) {
+	allOrderItems := []models.OrderItem{}
+	err := findAll(ctx, r.collection, bson.M{}, &allOrderItems)
+	return allOrderItems, err
+}
+
+func (r *orderItemRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.OrderItem], error) {
+	return list[models.OrderItem](ctx, r.collection, nil, query)
+}
+
+func (r *orderItemRepository) FindByID(ctx context.Context, orderItemId string) (models.OrderItem, error) {
+	var orderItem models.OrderItem
+	err := findOne(ctx, r.collection, bson.M{"order_item_id": orderItemId}, &orderItem)
+	return orderItem, err
+}
+
+func (r *orderItemRepository) ListByOrder(ctx context.Context, orderId string) ([]models.OrderItem, error) {
+	orderItems := []models.OrderItem{}
+	err := findAll(ctx, r.collection, bson.M{"order_id": orderId}, &orderItems)
+	return orderItems, err
+}
+
+func (r *orderItemRepository) InsertMany(ctx context.Context, orderItems []models.OrderItem) error {
+	orderItemsToBeInserted := []interface{}{}
+	for _, orderItem := range orderItems {
+		orderItemsToBeInserted = append(orderItemsToBeInserted, orderItem)
+	}
+	_, err := r.collection.InsertMany(ctx, orderItemsToBeInserted)
+	return err
+}
+
+func (r *orderItemRepository) Update(ctx context.Context, orderItemId string, update repository.OrderItemUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"order_item_id": orderItemId}, update.Set())
+}
+
+func (r *orderItemRepository) ListOpenKitchenItems(ctx context.Context, station string) ([]models.OrderItem, error) {
+	filter := bson.M{"kitchen_status": bson.M{"$in": bson.A{models.KitchenPending, models.KitchenPreparing}}}
+	if station != "" {
+		filter["station"] = station
+	}
+
+	openOrderItems := []models.OrderItem{}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &openOrderItems)
+	return openOrderItems, err
+}
+
+func (r *orderItemRepository) UpdateKitchenStatus(ctx context.Context, orderItemId string, from *string, status string, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"order_item_id": orderItemId, "kitchen_status": from}, primitive.D{{Key: "kitchen_status", Value: status}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *orderItemRepository) UpdateStockUsed(ctx context.Context, orderItemId string, stockUsed []models.StockUse, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"order_item_id": orderItemId}, primitive.D{{Key: "stock_used", Value: stockUsed}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *orderItemRepository) AlltheItemsInAnOrder(ctx context.Context, orderId string) (models.OrderDetails, error) {
+	matchStage := bson.D{{Key: "$match", Value: bson.D{{Key: "order_id", Value: orderId}}}}
+	lookupStage := bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "food"}, {Key: "localField", Value: "food_id"}, {Key: "foreignField", Value: "food_id"}, {Key: "as", Value: "food"}}}}
+	unwindStage := bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$food"}, {Key: "preserveNullAndEmptyArrays", Value: true}}}}
+
+	lookupMenuStage := bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "menu"}, {Key: "localField", Value: "food.menu_id"}, {Key: "foreignField", Value: "menu_id"}, {Key: "as", Value: "menu"}}}}
+	unwindMenuStage := bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$menu"}, {Key: "preserveNullAndEmptyArrays", Value: true}}}}
+
+	lookupOrderStage := bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "order"}, {Key: "localField", Value: "order_id"}, {Key: "foreignField", Value: "order_id"}, {Key: "as", Value: "order"}}}}
+	unwindOrderStage := bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$order"}, {Key: "preserveNullAndEmptyArrays", Value: true}}}}
+
+	lookupTableStage := bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: "table"}, {Key: "localField", Value: "order.table_id"}, {Key: "foreignField", Value: "table_id"}, {Key: "as", Value: "table"}}}}
+	unwindTableStage := bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$table"}, {Key: "preserveNullAndEmptyArrays", Value: true}}}}
+
+	lookupNotesStage := noteLookup("$order_item_id", models.NoteOrderItem)
+
+	projectStage := bson.D{
+		{Key: "$project", Value: bson.D{
+			{Key: "id", Value: 0},
+			{Key: "order_item_id", Value: 1},
+			{Key: "food_id", Value: "$food.food_id"},
+			{Key: "category", Value: "$menu.category"},
+			{Key: "amount", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$line_price", "$food.price"}}}},
+			{Key: "total_count", Value: 1},
+			{Key: "food_name", Value: "$food.name"},
+			{Key: "food_image", Value: "$food.food_image"},
+			{Key: "table_number", Value: "$table.table_number"},
+			{Key: "table_id", Value: "$table.table_id"},
+			{Key: "order_id", Value: "$order.order_id"},
+			{Key: "price", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$unit_price", "$food.price"}}}},
+			{Key: "quantity", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$quantity", 1}}}},
+			{Key: "variant", Value: 1},
+			{Key: "modifiers", Value: 1},
+			{Key: "notes", Value: 1},
+		}}}
+
+	groupStage := bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: bson.D{{Key: "order_id", Value: "$order_id"}, {Key: "table_id", Value: "$table_id"}, {Key: "table_number", Value: "$table_number"}}},
+		{Key: "payment_due", Value: bson.D{{Key: "$sum", Value: "$amount.amount"}}}, {Key: "currency", Value: bson.D{{Key: "$first", Value: "$amount.currency"}}}, {Key: "total_count", Value: bson.D{{Key: "$sum", Value: 1}}}, {Key: "order_items", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}}}}}
+
+	lookupOrderNotesStage := noteLookup("$_id.order_id", models.NoteOrder)
+
+	projectStage2 := bson.D{
+		{Key: "$project", Value: bson.D{
+			{Key: "id", Value: 0},
+			{Key: "payment_due", Value: bson.D{{Key: "amount", Value: "$payment_due"}, {Key: "currency", Value: "$currency"}}},
+			{Key: "total_count", Value: 1},
+			{Key: "table_number", Value: "$_id.table_number"},
+			{Key: "order_items", Value: 1},
+			{Key: "notes", Value: 1},
+		}}}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		matchStage,
+		lookupStage,
+		unwindStage,
+		lookupMenuStage,
+		unwindMenuStage,
+		lookupOrderStage,
+		unwindOrderStage,
+		lookupTableStage,
+		unwindTableStage,
+		lookupNotesStage,
+		projectStage,
+		groupStage,
+		lookupOrderNotesStage,
+		projectStage2,
+	})
+	if err != nil {
+		return models.OrderDetails{}, err
+	}
+
+	var OrderItems []models.OrderDetails
+	if err := cursor.All(ctx, &OrderItems); err != nil {
+		return models.OrderDetails{}, err
+	}
+	if len(OrderItems) == 0 {
+		return models.OrderDetails{Payment_due: models.Cents(0), Order_items: []models.OrderItemDetail{}, Notes: []models.Note{}}, nil
+	}
+
+	return OrderItems[0], nil
+}
+
+// noteLookup joins the notes of subjectType whose subject_id is the value of
+// localField, oldest first
+func noteLookup(localField string, subjectType string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: "note"},
+		{Key: "let", Value: bson.D{{Key: "subject_id", Value: localField}}},
+		{Key: "pipeline", Value: mongo.Pipeline{
+			bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{"$subject_id", "$$subject_id"}}},
+				bson.D{{Key: "$eq", Value: bson.A{"$subject_type", subjectType}}},
+			}}}}}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
+		}},
+		{Key: "as", Value: "notes"},
+	}}}
+}
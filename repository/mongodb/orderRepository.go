@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type orderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrderRepository(db *mongo.Database) repository.OrderRepository {
+	return &orderRepository{collection: database.OpenCollection(db, "order")}
+}
+
+func (r *orderRepository) List(ctx context.Context) ([]models.Order, error) {
+	allOrders := []models.Order{}
+	err := findAll(ctx, r.collection, bson.M{}, &allOrders)
+	return allOrders, err
+}
+
+func (r *orderRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Order], error) {
+	return list[models.Order](ctx, r.collection, nil, query)
+}
+
+func (r *orderRepository) ListOpen(ctx context.Context) ([]models.Order, error) {
+	openOrders := []models.Order{}
+	err := findAll(ctx, r.collection, bson.M{"status": bson.M{"$nin": models.ClosedOrderStatuses}}, &openOrders)
+	return openOrders, err
+}
+
+func (r *orderRepository) FindByID(ctx context.Context, orderId string) (models.Order, error) {
+	var order models.Order
+	err := findOne(ctx, r.collection, bson.M{"order_id": orderId}, &order)
+	return order, err
+}
+
+func (r *orderRepository) Insert(ctx context.Context, order models.Order) error {
+	_, err := r.collection.InsertOne(ctx, order)
+	return err
+}
+
+func (r *orderRepository) Update(ctx context.Context, orderId string, update repository.OrderUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"order_id": orderId}, update.Set())
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, orderId string, from *string, update repository.OrderUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"order_id": orderId, "status": from}, update.Set())
+}
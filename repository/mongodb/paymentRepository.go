@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type paymentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPaymentRepository(db *mongo.Database) repository.PaymentRepository {
+	return &paymentRepository{collection: database.OpenCollection(db, "payment")}
+}
+
+func (r *paymentRepository) List(ctx context.Context) ([]models.Payment, error) {
+	payments := []models.Payment{}
+	err := findAll(ctx, r.collection, bson.M{}, &payments)
+	return payments, err
+}
+
+func (r *paymentRepository) ListByInvoice(ctx context.Context, invoiceId string) ([]models.Payment, error) {
+	payments := []models.Payment{}
+	cursor, err := r.collection.Find(ctx, bson.M{"invoice_id": invoiceId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &payments)
+	return payments, err
+}
+
+func (r *paymentRepository) PageByInvoice(ctx context.Context, invoiceId string, query repository.ListQuery) (repository.Page[models.Payment], error) {
+	return list[models.Payment](ctx, r.collection, bson.D{{Key: "invoice_id", Value: invoiceId}}, query)
+}
+
+func (r *paymentRepository) Insert(ctx context.Context, payment models.Payment) error {
+	_, err := r.collection.InsertOne(ctx, payment)
+	return err
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, paymentId string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"payment_id": paymentId})
+	return err
+}
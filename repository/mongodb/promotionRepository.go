@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type promotionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPromotionRepository(db *mongo.Database) repository.PromotionRepository {
+	return &promotionRepository{collection: database.OpenCollection(db, "promotion")}
+}
+
+func (r *promotionRepository) List(ctx context.Context) ([]models.Promotion, error) {
+	promotions := []models.Promotion{}
+	err := findAll(ctx, r.collection, bson.M{}, &promotions)
+	return promotions, err
+}
+
+func (r *promotionRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Promotion], error) {
+	return list[models.Promotion](ctx, r.collection, nil, query)
+}
+
+func (r *promotionRepository) FindByID(ctx context.Context, promotionId string) (models.Promotion, error) {
+	var promotion models.Promotion
+	err := findOne(ctx, r.collection, bson.M{"promotion_id": promotionId}, &promotion)
+	return promotion, err
+}
+
+func (r *promotionRepository) FindByCouponCode(ctx context.Context, couponCode string) (models.Promotion, error) {
+	var promotion models.Promotion
+	err := findOne(ctx, r.collection, bson.M{"coupon_code": couponCode}, &promotion)
+	return promotion, err
+}
+
+func (r *promotionRepository) Insert(ctx context.Context, promotion models.Promotion) error {
+	_, err := r.collection.InsertOne(ctx, promotion)
+	return err
+}
+
+func (r *promotionRepository) Update(ctx context.Context, promotionId string, update repository.PromotionUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"promotion_id": promotionId}, update.Set())
+}
+
+func (r *promotionRepository) UpdateTimesUsed(ctx context.Context, promotionId string, usedBefore int, timesUsed int, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"promotion_id": promotionId, "times_used": usedBefore}, primitive.D{{Key: "times_used", Value: timesUsed}, {Key: "updated_at", Value: updatedAt}})
+}
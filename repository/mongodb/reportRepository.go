@@ -0,0 +1,316 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type reportRepository struct {
+	invoices   *mongo.Collection
+	orders     *mongo.Collection
+	orderItems *mongo.Collection
+	payments   *mongo.Collection
+}
+
+func NewReportRepository(db *mongo.Database) repository.ReportRepository {
+	return &reportRepository{
+		invoices:   database.OpenCollection(db, "invoice"),
+		orders:     database.OpenCollection(db, "order"),
+		orderItems: database.OpenCollection(db, "orderItem"),
+		payments:   database.OpenCollection(db, "payment"),
+	}
+}
+
+func (r *reportRepository) RevenueByDay(ctx context.Context, period repository.ReportRange) ([]models.RevenueRow, error) {
+	return r.revenue(ctx, period, dateString("%Y-%m-%d", period))
+}
+
+func (r *reportRepository) RevenueByHour(ctx context.Context, period repository.ReportRange) ([]models.RevenueRow, error) {
+	return r.revenue(ctx, period, dateString("%H:00", period))
+}
+
+func (r *reportRepository) revenue(ctx context.Context, period repository.ReportRange, groupBy bson.D) ([]models.RevenueRow, error) {
+	pipeline := append(invoiceStages(period),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: groupBy},
+			{Key: "invoices", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "gross", Value: sumCents("$total_amount.amount")},
+			{Key: "net", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$net_amount.amount", bson.D{{Key: "$ifNull", Value: bson.A{"$total_amount.amount", 0}}}}}}}}},
+			{Key: "tax", Value: sumCents("$tax_amount.amount")},
+			{Key: "discounts", Value: sumCents("$discount_amount.amount")},
+			{Key: "currency", Value: bson.D{{Key: "$first", Value: "$total_amount.currency"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "period", Value: "$_id"},
+			{Key: "invoices", Value: 1},
+			{Key: "gross", Value: money("$gross")},
+			{Key: "net", Value: money("$net")},
+			{Key: "tax", Value: money("$tax")},
+			{Key: "discounts", Value: money("$discounts")},
+		}}},
+	)
+	rows := []models.RevenueRow{}
+	err := aggregate(ctx, r.invoices, pipeline, &rows)
+	return rows, err
+}
+
+func (r *reportRepository) TopFoods(ctx context.Context, period repository.ReportRange, limit int) ([]models.FoodSalesRow, error) {
+	pipeline := append(orderItemStages(period),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$food_id"},
+			{Key: "food_name", Value: bson.D{{Key: "$first", Value: "$food.name"}}},
+			{Key: "category", Value: bson.D{{Key: "$first", Value: "$menu.category"}}},
+			{Key: "quantity", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$quantity", 1}}}}}},
+			{Key: "revenue", Value: lineRevenue()},
+			{Key: "currency", Value: lineCurrency()},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "quantity", Value: -1}, {Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$limit", Value: limit}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "food_id", Value: "$_id"},
+			{Key: "food_name", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$food_name", ""}}}},
+			{Key: "category", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$category", ""}}}},
+			{Key: "quantity", Value: 1},
+			{Key: "revenue", Value: money("$revenue")},
+		}}},
+	)
+	rows := []models.FoodSalesRow{}
+	err := aggregate(ctx, r.orderItems, pipeline, &rows)
+	return rows, err
+}
+
+func (r *reportRepository) RevenueByCategory(ctx context.Context, period repository.ReportRange) ([]models.CategorySalesRow, error) {
+	pipeline := append(orderItemStages(period),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$menu.category", ""}}}},
+			{Key: "quantity", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$quantity", 1}}}}}},
+			{Key: "revenue", Value: lineRevenue()},
+			{Key: "currency", Value: lineCurrency()},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "category", Value: "$_id"},
+			{Key: "quantity", Value: 1},
+			{Key: "revenue", Value: money("$revenue")},
+		}}},
+	)
+	rows := []models.CategorySalesRow{}
+	err := aggregate(ctx, r.orderItems, pipeline, &rows)
+	return rows, err
+}
+
+func (r *reportRepository) TicketSummary(ctx context.Context, period repository.ReportRange) (models.TicketSummary, error) {
+	covers := bson.D{{Key: "$ifNull", Value: bson.A{"$order.covers", 0}}}
+	pipeline := append(invoiceStages(period),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "invoices", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "gross", Value: sumCents("$total_amount.amount")},
+			{Key: "covers", Value: bson.D{{Key: "$sum", Value: covers}}},
+			{Key: "covered_gross", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$gt", Value: bson.A{covers, 0}}},
+				bson.D{{Key: "$ifNull", Value: bson.A{"$total_amount.amount", 0}}},
+				0,
+			}}}}}},
+			{Key: "currency", Value: bson.D{{Key: "$first", Value: "$total_amount.currency"}}},
+		}}},
+	)
+	var totals []struct {
+		Invoices      int    `bson:"invoices"`
+		Gross         int64  `bson:"gross"`
+		Covers        int    `bson:"covers"`
+		Covered_gross int64  `bson:"covered_gross"`
+		Currency      string `bson:"currency"`
+	}
+	if err := aggregate(ctx, r.invoices, pipeline, &totals); err != nil {
+		return models.TicketSummary{}, err
+	}
+	if len(totals) == 0 {
+		return models.NewTicketSummary(0, models.Cents(0), 0, models.Cents(0)), nil
+	}
+	total := totals[0]
+	return models.NewTicketSummary(total.Invoices, models.Money{Amount: total.Gross, Currency: total.Currency}, total.Covers, models.Money{Amount: total.Covered_gross, Currency: total.Currency}), nil
+}
+
+func (r *reportRepository) CoversByTable(ctx context.Context, period repository.ReportRange) ([]models.TableCoversRow, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "created_at", Value: inRange(period)},
+			{Key: "status", Value: bson.D{{Key: "$nin", Value: bson.A{models.OrderCancelled, models.OrderVoided}}}},
+		}}},
+		lookup("table", "table_id", "table_id", "table"),
+		unwind("$table"),
+		lookup("invoice", "order_id", "order_id", "invoice"),
+		unwind("$invoice"),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$table_id"},
+			{Key: "table_number", Value: bson.D{{Key: "$first", Value: "$table.table_number"}}},
+			{Key: "orders", Value: bson.D{{Key: "$addToSet", Value: "$order_id"}}},
+			{Key: "covers", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$covers", 0}}}}}},
+			{Key: "revenue", Value: sumCents("$invoice.total_amount.amount")},
+			{Key: "currency", Value: bson.D{{Key: "$max", Value: "$invoice.total_amount.currency"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "table_number", Value: 1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "table_id", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$_id", ""}}}},
+			{Key: "table_number", Value: 1},
+			{Key: "orders", Value: bson.D{{Key: "$size", Value: "$orders"}}},
+			{Key: "covers", Value: 1},
+			{Key: "revenue", Value: money("$revenue")},
+		}}},
+	}
+	rows := []models.TableCoversRow{}
+	err := aggregate(ctx, r.orders, pipeline, &rows)
+	return rows, err
+}
+
+func (r *reportRepository) PaymentMix(ctx context.Context, period repository.ReportRange) ([]models.PaymentMixRow, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "created_at", Value: inRange(period)}}}},
+		lookup("invoice", "invoice_id", "invoice_id", "invoice"),
+		unwind("$invoice"),
+		lookup("order", "invoice.order_id", "order_id", "order"),
+		unwind("$order"),
+		bson.D{{Key: "$match", Value: bson.D{{Key: "order.status", Value: bson.D{{Key: "$nin", Value: bson.A{models.OrderCancelled, models.OrderVoided}}}}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$method"},
+			{Key: "payments", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "amount", Value: sumCents("$amount.amount")},
+			{Key: "currency", Value: bson.D{{Key: "$first", Value: "$amount.currency"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "amount", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "method", Value: "$_id"},
+			{Key: "payments", Value: 1},
+			{Key: "amount", Value: money("$amount")},
+		}}},
+	}
+	rows := []models.PaymentMixRow{}
+	if err := aggregate(ctx, r.payments, pipeline, &rows); err != nil {
+		return nil, err
+	}
+	models.SetPaymentShares(rows)
+	return rows, nil
+}
+
+func (r *reportRepository) Voids(ctx context.Context, period repository.ReportRange) (models.VoidSummary, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "status", Value: models.OrderVoided},
+			{Key: "status_history", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+				{Key: "to", Value: models.OrderVoided},
+				{Key: "changed_at", Value: inRange(period)},
+			}}}},
+		}}},
+		lookup("orderItem", "order_id", "order_id", "item"),
+		bson.D{{Key: "$unwind", Value: "$item"}},
+		lookup("food", "item.food_id", "food_id", "food"),
+		unwind("$food"),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "orders", Value: bson.D{{Key: "$addToSet", Value: "$order_id"}}},
+			{Key: "items", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$item.quantity", 1}}}}}},
+			{Key: "amount", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$item.line_price.amount", bson.D{{Key: "$ifNull", Value: bson.A{"$food.price.amount", 0}}}}}}}}},
+			{Key: "currency", Value: bson.D{{Key: "$first", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$item.line_price.currency", "$food.price.currency"}}}}}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "orders", Value: bson.D{{Key: "$size", Value: "$orders"}}},
+			{Key: "items", Value: 1},
+			{Key: "amount", Value: money("$amount")},
+		}}},
+	}
+	var totals []models.VoidSummary
+	if err := aggregate(ctx, r.orders, pipeline, &totals); err != nil {
+		return models.VoidSummary{}, err
+	}
+	if len(totals) == 0 {
+		return models.VoidSummary{Amount: models.Cents(0)}, nil
+	}
+	return totals[0], nil
+}
+
+// invoiceStages picks the invoices issued in period for orders that were
+// neither cancelled nor voided, with the order joined in
+func invoiceStages(period repository.ReportRange) mongo.Pipeline {
+	return mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "created_at", Value: inRange(period)}}}},
+		lookup("order", "order_id", "order_id", "order"),
+		unwind("$order"),
+		bson.D{{Key: "$match", Value: bson.D{{Key: "order.status", Value: bson.D{{Key: "$nin", Value: bson.A{models.OrderCancelled, models.OrderVoided}}}}}}},
+	}
+}
+
+// orderItemStages joins the order items ordered in period with their
+// order, food and menu the way AlltheItemsInAnOrder does
+func orderItemStages(period repository.ReportRange) mongo.Pipeline {
+	return mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "created_at", Value: inRange(period)}}}},
+		lookup("order", "order_id", "order_id", "order"),
+		unwind("$order"),
+		bson.D{{Key: "$match", Value: bson.D{{Key: "order.status", Value: bson.D{{Key: "$nin", Value: bson.A{models.OrderCancelled, models.OrderVoided}}}}}}},
+		lookup("food", "food_id", "food_id", "food"),
+		unwind("$food"),
+		lookup("menu", "food.menu_id", "menu_id", "menu"),
+		unwind("$menu"),
+	}
+}
+
+func inRange(period repository.ReportRange) bson.D {
+	return bson.D{{Key: "$gte", Value: period.From}, {Key: "$lt", Value: period.To}}
+}
+
+func dateString(format string, period repository.ReportRange) bson.D {
+	return bson.D{{Key: "$dateToString", Value: bson.D{
+		{Key: "format", Value: format},
+		{Key: "date", Value: "$created_at"},
+		{Key: "timezone", Value: period.Location.String()},
+	}}}
+}
+
+func lookup(from string, localField string, foreignField string, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: from}, {Key: "localField", Value: localField}, {Key: "foreignField", Value: foreignField}, {Key: "as", Value: as}}}}
+}
+
+func unwind(path string) bson.D {
+	return bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: path}, {Key: "preserveNullAndEmptyArrays", Value: true}}}}
+}
+
+func sumCents(path string) bson.D {
+	return bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{path, 0}}}}}
+}
+
+// lineRevenue sums line prices, items from before line prices were kept
+// are counted at the food price
+func lineRevenue() bson.D {
+	return bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$line_price.amount", bson.D{{Key: "$ifNull", Value: bson.A{"$food.price.amount", 0}}}}}}}}
+}
+
+func lineCurrency() bson.D {
+	return bson.D{{Key: "$first", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$line_price.currency", "$food.price.currency"}}}}}
+}
+
+// money rebuilds an amount document from summed cents and the currency
+// taken alongside them
+func money(cents string) bson.D {
+	return bson.D{{Key: "amount", Value: cents}, {Key: "currency", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$currency", models.DefaultCurrency}}}}}
+}
+
+func aggregate(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, v interface{}) error {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, v)
+}
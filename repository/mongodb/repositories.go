@@ -0,0 +1,165 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewRepositories stores every collection in MongoDB
+func NewRepositories(db *mongo.Database) repository.Repositories {
+	return repository.Repositories{
+		Users:        NewUserRepository(db),
+		Foods:        NewFoodRepository(db),
+		Menus:        NewMenuRepository(db),
+		Tables:       NewTableRepository(db),
+		Orders:       NewOrderRepository(db),
+		OrderItems:   NewOrderItemRepository(db),
+		Invoices:     NewInvoiceRepository(db),
+		Payments:     NewPaymentRepository(db),
+		Notes:        NewNoteRepository(db),
+		Reservations: NewReservationRepository(db),
+		Waitlist:     NewWaitlistRepository(db),
+		TaxRates:     NewTaxRateRepository(db),
+		TaxRules:     NewTaxRuleRepository(db),
+		Promotions:   NewPromotionRepository(db),
+		Ingredients:  NewIngredientRepository(db),
+		Reports:      NewReportRepository(db),
+		Drawers:      NewDrawerRepository(db),
+		ZReports:     NewZReportRepository(db),
+		Idempotency:  NewIdempotencyRepository(db),
+	}
+}
+
+// ensureIndexes creates indexes the repository relies on, the server must
+// not run without them
+func ensureIndexes(collection *mongo.Collection, indexes ...mongo.IndexModel) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Fatalf("%s indexes: %v", collection.Name(), err)
+	}
+}
+
+func findOne(ctx context.Context, collection *mongo.Collection, filter interface{}, v interface{}) error {
+	err := collection.FindOne(ctx, filter).Decode(v)
+	if err == mongo.ErrNoDocuments {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+func findAll(ctx context.Context, collection *mongo.Collection, filter interface{}, v interface{}) error {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, v)
+}
+
+func updateOne(ctx context.Context, collection *mongo.Collection, filter interface{}, updateObj primitive.D) (bool, error) {
+	result, err := collection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: updateObj}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// list answers query over the documents matching match. Documents are
+// read in sort order from after the cursor, so no page needs the ones
+// before it, and the total is counted separately.
+func list[T any](ctx context.Context, collection *mongo.Collection, match bson.D, query repository.ListQuery) (repository.Page[T], error) {
+	after, err := query.After()
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+
+	conditions := bson.A{}
+	if len(match) > 0 {
+		conditions = append(conditions, match)
+	}
+	for _, filter := range query.Filters {
+		switch filter.Op {
+		case repository.FilterEq:
+			conditions = append(conditions, bson.D{{Key: filter.Field, Value: filter.Value}})
+		case repository.FilterIn:
+			conditions = append(conditions, bson.D{{Key: filter.Field, Value: bson.D{{Key: "$in", Value: filter.Value}}}})
+		case repository.FilterGte:
+			conditions = append(conditions, bson.D{{Key: filter.Field, Value: bson.D{{Key: "$gte", Value: filter.Value}}}})
+		case repository.FilterLt:
+			conditions = append(conditions, bson.D{{Key: filter.Field, Value: bson.D{{Key: "$lt", Value: filter.Value}}}})
+		}
+	}
+	filter := bson.D{}
+	if len(conditions) > 0 {
+		filter = bson.D{{Key: "$and", Value: conditions}}
+	}
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+
+	keys := query.SortKeys()
+	if after != nil {
+		conditions = append(conditions, afterCursor(keys, after))
+		filter = bson.D{{Key: "$and", Value: conditions}}
+	}
+	sortBy := bson.D{}
+	for _, key := range keys {
+		direction := 1
+		if key.Descending {
+			direction = -1
+		}
+		sortBy = append(sortBy, bson.E{Key: key.Field, Value: direction})
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(sortBy).SetLimit(int64(query.Limit+1)))
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return repository.Page[T]{}, err
+	}
+	return repository.NewPage[T](docs, total, query)
+}
+
+// afterCursor matches the documents sorting after the cursor values: equal
+// on the first keys and after it on the next one. Null sorts first, and
+// $gt and $lt never match it, so it is handled on its own.
+func afterCursor(keys []repository.Sort, values []bson.RawValue) bson.D {
+	branches := bson.A{}
+	for i, key := range keys {
+		var after bson.D
+		isNull := values[i].Type == bson.TypeNull
+		switch {
+		case !key.Descending && isNull:
+			after = bson.D{{Key: key.Field, Value: bson.D{{Key: "$ne", Value: nil}}}}
+		case !key.Descending:
+			after = bson.D{{Key: key.Field, Value: bson.D{{Key: "$gt", Value: values[i]}}}}
+		case isNull:
+			//nothing sorts after a null in descending order
+			after = nil
+		default:
+			after = bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: key.Field, Value: bson.D{{Key: "$lt", Value: values[i]}}}},
+				bson.D{{Key: key.Field, Value: nil}},
+			}}}
+		}
+		if after != nil {
+			branch := bson.A{}
+			for j := 0; j < i; j++ {
+				branch = append(branch, bson.D{{Key: keys[j].Field, Value: values[j]}})
+			}
+			branches = append(branches, bson.D{{Key: "$and", Value: append(branch, after)}})
+		}
+	}
+	//the last key is _id, so there is always a branch
+	return bson.D{{Key: "$or", Value: branches}}
+}
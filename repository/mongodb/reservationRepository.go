@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type reservationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReservationRepository(db *mongo.Database) repository.ReservationRepository {
+	return &reservationRepository{collection: database.OpenCollection(db, "reservation")}
+}
+
+func (r *reservationRepository) List(ctx context.Context) ([]models.Reservation, error) {
+	allReservations := []models.Reservation{}
+	err := findAll(ctx, r.collection, bson.M{}, &allReservations)
+	return allReservations, err
+}
+
+func (r *reservationRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Reservation], error) {
+	return list[models.Reservation](ctx, r.collection, nil, query)
+}
+
+func (r *reservationRepository) ListOverlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := findAll(ctx, r.collection, bson.M{
+		"status":           bson.M{"$in": bson.A{models.ReservationBooked, models.ReservationSeated}},
+		"reservation_time": bson.M{"$lt": to},
+		"ends_at":          bson.M{"$gt": from},
+	}, &reservations)
+	return reservations, err
+}
+
+func (r *reservationRepository) FindByID(ctx context.Context, reservationId string) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := findOne(ctx, r.collection, bson.M{"reservation_id": reservationId}, &reservation)
+	return reservation, err
+}
+
+func (r *reservationRepository) Insert(ctx context.Context, reservation models.Reservation) error {
+	_, err := r.collection.InsertOne(ctx, reservation)
+	return err
+}
+
+func (r *reservationRepository) Update(ctx context.Context, reservationId string, update repository.ReservationUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"reservation_id": reservationId}, update.Set())
+}
+
+func (r *reservationRepository) UpdateStatus(ctx context.Context, reservationId string, from *string, update repository.ReservationUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"reservation_id": reservationId, "status": from}, update.Set())
+}
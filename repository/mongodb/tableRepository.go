@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type tableRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTableRepository(db *mongo.Database) repository.TableRepository {
+	return &tableRepository{collection: database.OpenCollection(db, "table")}
+}
+
+func (r *tableRepository) List(ctx context.Context) ([]models.Table, error) {
+	allTables := []models.Table{}
+	err := findAll(ctx, r.collection, bson.M{}, &allTables)
+	return allTables, err
+}
+
+func (r *tableRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.Table], error) {
+	return list[models.Table](ctx, r.collection, nil, query)
+}
+
+func (r *tableRepository) FindByID(ctx context.Context, tableId string) (models.Table, error) {
+	var table models.Table
+	err := findOne(ctx, r.collection, bson.M{"table_id": tableId}, &table)
+	return table, err
+}
+
+func (r *tableRepository) Insert(ctx context.Context, table models.Table) error {
+	_, err := r.collection.InsertOne(ctx, table)
+	return err
+}
+
+func (r *tableRepository) Update(ctx context.Context, tableId string, update repository.TableUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"table_id": tableId}, update.Set())
+}
+
+func (r *tableRepository) Claim(ctx context.Context, tableId string, now time.Time, until time.Time) (bool, error) {
+	filter := bson.M{"table_id": tableId, "$or": bson.A{
+		bson.M{"claimed_until": nil},
+		bson.M{"claimed_until": bson.M{"$lte": now}},
+	}}
+	return updateOne(ctx, r.collection, filter, primitive.D{{Key: "claimed_until", Value: until}})
+}
+
+func (r *tableRepository) Release(ctx context.Context, tableId string, until time.Time) error {
+	_, err := updateOne(ctx, r.collection, bson.M{"table_id": tableId, "claimed_until": until}, primitive.D{{Key: "claimed_until", Value: nil}})
+	return err
+}
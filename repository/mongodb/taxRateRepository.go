@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taxRateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaxRateRepository(db *mongo.Database) repository.TaxRateRepository {
+	return &taxRateRepository{collection: database.OpenCollection(db, "taxRate")}
+}
+
+func (r *taxRateRepository) List(ctx context.Context) ([]models.TaxRate, error) {
+	taxRates := []models.TaxRate{}
+	err := findAll(ctx, r.collection, bson.M{}, &taxRates)
+	return taxRates, err
+}
+
+func (r *taxRateRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.TaxRate], error) {
+	return list[models.TaxRate](ctx, r.collection, nil, query)
+}
+
+func (r *taxRateRepository) FindByID(ctx context.Context, taxRateId string) (models.TaxRate, error) {
+	var taxRate models.TaxRate
+	err := findOne(ctx, r.collection, bson.M{"tax_rate_id": taxRateId}, &taxRate)
+	return taxRate, err
+}
+
+func (r *taxRateRepository) Insert(ctx context.Context, taxRate models.TaxRate) error {
+	_, err := r.collection.InsertOne(ctx, taxRate)
+	return err
+}
+
+func (r *taxRateRepository) Update(ctx context.Context, taxRateId string, update repository.TaxRateUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"tax_rate_id": taxRateId}, update.Set())
+}
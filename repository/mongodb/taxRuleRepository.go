@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taxRuleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaxRuleRepository(db *mongo.Database) repository.TaxRuleRepository {
+	return &taxRuleRepository{collection: database.OpenCollection(db, "taxRule")}
+}
+
+func (r *taxRuleRepository) List(ctx context.Context) ([]models.TaxRule, error) {
+	taxRules := []models.TaxRule{}
+	err := findAll(ctx, r.collection, bson.M{}, &taxRules)
+	return taxRules, err
+}
+
+func (r *taxRuleRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.TaxRule], error) {
+	return list[models.TaxRule](ctx, r.collection, nil, query)
+}
+
+func (r *taxRuleRepository) FindByID(ctx context.Context, taxRuleId string) (models.TaxRule, error) {
+	var taxRule models.TaxRule
+	err := findOne(ctx, r.collection, bson.M{"tax_rule_id": taxRuleId}, &taxRule)
+	return taxRule, err
+}
+
+func (r *taxRuleRepository) Insert(ctx context.Context, taxRule models.TaxRule) error {
+	_, err := r.collection.InsertOne(ctx, taxRule)
+	return err
+}
+
+func (r *taxRuleRepository) Update(ctx context.Context, taxRuleId string, update repository.TaxRuleUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"tax_rule_id": taxRuleId}, update.Set())
+}
@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type userRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserRepository makes sure one user at most is marked as the first
+func NewUserRepository(db *mongo.Database) repository.UserRepository {
+	r := &userRepository{collection: database.OpenCollection(db, "user")}
+	ensureIndexes(r.collection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "first_user", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"first_user": true}),
+	})
+	return r
+}
+
+func (r *userRepository) Page(ctx context.Context, query repository.ListQuery) (repository.Page[models.User], error) {
+	return list[models.User](ctx, r.collection, nil, query)
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userId string) (models.User, error) {
+	var user models.User
+	err := findOne(ctx, r.collection, bson.M{"user_id": userId}, &user)
+	return user, err
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := findOne(ctx, r.collection, bson.M{"email": email}, &user)
+	return user, err
+}
+
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
+
+func (r *userRepository) CountByEmailOrPhone(ctx context.Context, email string, phone string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"email": email},
+		bson.M{"phone": phone},
+	}})
+}
+
+func (r *userRepository) Insert(ctx context.Context, user models.User) error {
+	_, err := r.collection.InsertOne(ctx, user)
+	return err
+}
+
+func (r *userRepository) InsertFirst(ctx context.Context, user models.User) error {
+	user.First_user = true
+	_, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userId string, role string, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"user_id": userId}, primitive.D{{Key: "role", Value: role}, {Key: "updated_at", Value: updatedAt}})
+}
+
+func (r *userRepository) SetTokens(ctx context.Context, userId string, token *string, refreshToken *string, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"user_id": userId}, tokens(token, refreshToken, updatedAt))
+}
+
+func (r *userRepository) HasToken(ctx context.Context, userId string, token string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userId, "token": token})
+	return count > 0, err
+}
+
+func (r *userRepository) RotateTokens(ctx context.Context, userId string, oldRefreshToken string, token string, refreshToken string, updatedAt time.Time) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"user_id": userId, "refresh_token": oldRefreshToken}, tokens(&token, &refreshToken, updatedAt))
+}
+
+func tokens(token *string, refreshToken *string, updatedAt time.Time) primitive.D {
+	return primitive.D{
+		{Key: "token", Value: token},
+		{Key: "refresh_token", Value: refreshToken},
+		{Key: "updated_at", Value: updatedAt},
+	}
+}
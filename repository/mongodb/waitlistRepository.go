@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	database "restaurant-management/database"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type waitlistRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWaitlistRepository(db *mongo.Database) repository.WaitlistRepository {
+	return &waitlistRepository{collection: database.OpenCollection(db, "waitlist")}
+}
+
+func (r *waitlistRepository) ListWaiting(ctx context.Context) ([]models.WaitlistEntry, error) {
+	waiting := []models.WaitlistEntry{}
+	cursor, err := r.collection.Find(ctx, bson.M{"status": models.WaitlistWaiting}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &waiting)
+	return waiting, err
+}
+
+func (r *waitlistRepository) PageWaiting(ctx context.Context, query repository.ListQuery) (repository.Page[models.WaitlistEntry], error) {
+	return list[models.WaitlistEntry](ctx, r.collection, bson.D{{Key: "status", Value: models.WaitlistWaiting}}, query)
+}
+
+func (r *waitlistRepository) FindByID(ctx context.Context, waitlistId string) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := findOne(ctx, r.collection, bson.M{"waitlist_id": waitlistId}, &entry)
+	return entry, err
+}
+
+func (r *waitlistRepository) Insert(ctx context.Context, entry models.WaitlistEntry) error {
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *waitlistRepository) UpdateStatus(ctx context.Context, waitlistId string, from *string, update repository.WaitlistUpdate) (bool, error) {
+	return updateOne(ctx, r.collection, bson.M{"waitlist_id": waitlistId, "status": from}, update.Set())
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NoteRepository interface {
+	List(ctx context.Context) ([]models.Note, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Note], error)
+	// ListBySubject returns the notes of one order, order item, table or
+	// reservation, oldest first
+	ListBySubject(ctx context.Context, subjectType string, subjectId string) ([]models.Note, error)
+	FindByID(ctx context.Context, noteId string) (models.Note, error)
+	Insert(ctx context.Context, note models.Note) error
+	Update(ctx context.Context, noteId string, update NoteUpdate) (bool, error)
+	Delete(ctx context.Context, noteId string) (bool, error)
+}
+
+// NoteUpdate changes the text or title of a note when they are not nil
+type NoteUpdate struct {
+	Text       *string
+	Title      *string
+	Updated_at time.Time
+}
+
+// Set is the update as the fields to store
+func (update NoteUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "text", update.Text)
+	fields = setGiven(fields, "title", update.Title)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
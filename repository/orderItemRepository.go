@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderItemRepository interface {
+	List(ctx context.Context) ([]models.OrderItem, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.OrderItem], error)
+	FindByID(ctx context.Context, orderItemId string) (models.OrderItem, error)
+	ListByOrder(ctx context.Context, orderId string) ([]models.OrderItem, error)
+	InsertMany(ctx context.Context, orderItems []models.OrderItem) error
+	Update(ctx context.Context, orderItemId string, update OrderItemUpdate) (bool, error)
+
+	// ListOpenKitchenItems returns items still pending or being prepared,
+	// of one station or of all stations when station is empty.
+	ListOpenKitchenItems(ctx context.Context, station string) ([]models.OrderItem, error)
+	// UpdateKitchenStatus only sets status while the stored kitchen status is still from.
+	UpdateKitchenStatus(ctx context.Context, orderItemId string, from *string, status string, updatedAt time.Time) (bool, error)
+	// UpdateStockUsed stores the ingredients the item holds, nil once they
+	// were given back to stock.
+	UpdateStockUsed(ctx context.Context, orderItemId string, stockUsed []models.StockUse, updatedAt time.Time) (bool, error)
+
+	// AlltheItemsInAnOrder joins the items of an order with their food and
+	// table and sums up the payment due.
+	AlltheItemsInAnOrder(ctx context.Context, orderId string) (models.OrderDetails, error)
+}
+
+// OrderItemUpdate prices an order item again, every field is stored so
+// that a variant, station or modifiers left out are cleared
+type OrderItemUpdate struct {
+	Food_id    *string
+	Station    *string
+	Quantity   *int
+	Variant    *string
+	Modifiers  []models.OrderItemModifier
+	Unit_price *models.Money
+	Line_price *models.Money
+	Stock_used []models.StockUse
+	Updated_at time.Time
+}
+
+// Set is the update as the fields to store
+func (update OrderItemUpdate) Set() primitive.D {
+	return primitive.D{
+		{Key: "food_id", Value: update.Food_id},
+		{Key: "station", Value: update.Station},
+		{Key: "quantity", Value: update.Quantity},
+		{Key: "variant", Value: update.Variant},
+		{Key: "modifiers", Value: update.Modifiers},
+		{Key: "unit_price", Value: update.Unit_price},
+		{Key: "line_price", Value: update.Line_price},
+		{Key: "stock_used", Value: update.Stock_used},
+		{Key: "updated_at", Value: update.Updated_at},
+	}
+}
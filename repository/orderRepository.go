@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderRepository interface {
+	List(ctx context.Context) ([]models.Order, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Order], error)
+	// ListOpen returns the orders that are not paid, cancelled or voided
+	ListOpen(ctx context.Context) ([]models.Order, error)
+	FindByID(ctx context.Context, orderId string) (models.Order, error)
+	Insert(ctx context.Context, order models.Order) error
+	Update(ctx context.Context, orderId string, update OrderUpdate) (bool, error)
+
+	// UpdateStatus only applies update while the stored status is still
+	// from, nil matching orders that have no status yet.
+	UpdateStatus(ctx context.Context, orderId string, from *string, update OrderUpdate) (bool, error)
+}
+
+// OrderUpdate changes the fields of an order that are not nil, a status is
+// stored with its Status_history
+type OrderUpdate struct {
+	Table_id       *string
+	Covers         *int
+	Status         *string
+	Status_history []models.OrderStatusChange
+	Updated_at     time.Time
+}
+
+// Set is the update as the fields to store
+func (update OrderUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "table_id", update.Table_id)
+	fields = setGiven(fields, "covers", update.Covers)
+	fields = setGiven(fields, "status", update.Status)
+	fields = setGiven(fields, "status_history", update.Status_history)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
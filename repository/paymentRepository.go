@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+)
+
+type PaymentRepository interface {
+	List(ctx context.Context) ([]models.Payment, error)
+	ListByInvoice(ctx context.Context, invoiceId string) ([]models.Payment, error)
+	PageByInvoice(ctx context.Context, invoiceId string, query ListQuery) (Page[models.Payment], error)
+	Insert(ctx context.Context, payment models.Payment) error
+	// Delete takes back a payment that was never applied to its invoice
+	Delete(ctx context.Context, paymentId string) error
+}
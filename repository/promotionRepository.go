@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromotionRepository interface {
+	List(ctx context.Context) ([]models.Promotion, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Promotion], error)
+	FindByID(ctx context.Context, promotionId string) (models.Promotion, error)
+	FindByCouponCode(ctx context.Context, couponCode string) (models.Promotion, error)
+	Insert(ctx context.Context, promotion models.Promotion) error
+	Update(ctx context.Context, promotionId string, update PromotionUpdate) (bool, error)
+	// UpdateTimesUsed stores timesUsed only while the coupon was used
+	// usedBefore times, so two tills cannot both take its last use
+	UpdateTimesUsed(ctx context.Context, promotionId string, usedBefore int, timesUsed int, updatedAt time.Time) (bool, error)
+}
+
+// PromotionUpdate changes the fields of a promotion that are not nil
+type PromotionUpdate struct {
+	Name         *string
+	Percent      *float64
+	Amount       *models.Money
+	Food_ids     []string
+	Buy_quantity *int
+	Get_quantity *int
+	Usage_limit  *int
+	Starts_at    *time.Time
+	Expires_at   *time.Time
+	Active       *bool
+	Updated_at   time.Time
+}
+
+// Set is the update as the fields to store
+func (update PromotionUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "name", update.Name)
+	fields = setGiven(fields, "percent", update.Percent)
+	fields = setGiven(fields, "amount", update.Amount)
+	fields = setGiven(fields, "food_ids", update.Food_ids)
+	fields = setGiven(fields, "buy_quantity", update.Buy_quantity)
+	fields = setGiven(fields, "get_quantity", update.Get_quantity)
+	fields = setGiven(fields, "usage_limit", update.Usage_limit)
+	fields = setGiven(fields, "starts_at", update.Starts_at)
+	fields = setGiven(fields, "expires_at", update.Expires_at)
+	fields = setGiven(fields, "active", update.Active)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+)
+
+// ReportRange is the time a report covers, From included and To not. Days
+// and hours are those of Location.
+type ReportRange struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+// ReportRepository aggregates sales across orders, order items, foods,
+// invoices and payments. Cancelled and voided orders never count.
+type ReportRepository interface {
+	RevenueByDay(ctx context.Context, period ReportRange) ([]models.RevenueRow, error)
+	// RevenueByHour adds up every day of the range by the hour of the day
+	RevenueByHour(ctx context.Context, period ReportRange) ([]models.RevenueRow, error)
+	TopFoods(ctx context.Context, period ReportRange, limit int) ([]models.FoodSalesRow, error)
+	RevenueByCategory(ctx context.Context, period ReportRange) ([]models.CategorySalesRow, error)
+	TicketSummary(ctx context.Context, period ReportRange) (models.TicketSummary, error)
+	CoversByTable(ctx context.Context, period ReportRange) ([]models.TableCoversRow, error)
+	PaymentMix(ctx context.Context, period ReportRange) ([]models.PaymentMixRow, error)
+	// Voids adds up the orders voided in period, by when they were voided
+	Voids(ctx context.Context, period ReportRange) (models.VoidSummary, error)
+}
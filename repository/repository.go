@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by every repository when no document matches.
+var ErrNotFound = errors.New("document not found")
+
+// ErrDuplicate is returned by inserts that would break a unique index.
+var ErrDuplicate = errors.New("document already exists")
+
+// Repositories bundles the storage of every collection, controllers and
+// middleware receive the ones they need from it.
+type Repositories struct {
+	Users        UserRepository
+	Foods        FoodRepository
+	Menus        MenuRepository
+	Tables       TableRepository
+	Orders       OrderRepository
+	OrderItems   OrderItemRepository
+	Invoices     InvoiceRepository
+	Payments     PaymentRepository
+	Notes        NoteRepository
+	Reservations ReservationRepository
+	Waitlist     WaitlistRepository
+	TaxRates     TaxRateRepository
+	TaxRules     TaxRuleRepository
+	Promotions   PromotionRepository
+	Ingredients  IngredientRepository
+	Reports      ReportRepository
+	Drawers      DrawerRepository
+	ZReports     ZReportRepository
+	Idempotency  IdempotencyRepository
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReservationRepository interface {
+	List(ctx context.Context) ([]models.Reservation, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Reservation], error)
+	// ListOverlapping returns the booked and seated reservations holding a
+	// table at some point between from and to
+	ListOverlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error)
+	FindByID(ctx context.Context, reservationId string) (models.Reservation, error)
+	Insert(ctx context.Context, reservation models.Reservation) error
+	Update(ctx context.Context, reservationId string, update ReservationUpdate) (bool, error)
+	UpdateStatus(ctx context.Context, reservationId string, from *string, update ReservationUpdate) (bool, error)
+}
+
+// ReservationUpdate changes the fields of a reservation that are not nil
+type ReservationUpdate struct {
+	Guest_name       *string
+	Phone            *string
+	Party_size       *int
+	Reservation_time *time.Time
+	Duration_minutes *int
+	Ends_at          *time.Time
+	Table_id         *string
+	Status           *string
+	Order_id         *string
+	Updated_at       time.Time
+}
+
+// Set is the update as the fields to store
+func (update ReservationUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "guest_name", update.Guest_name)
+	fields = setGiven(fields, "phone", update.Phone)
+	fields = setGiven(fields, "party_size", update.Party_size)
+	fields = setGiven(fields, "reservation_time", update.Reservation_time)
+	fields = setGiven(fields, "duration_minutes", update.Duration_minutes)
+	fields = setGiven(fields, "ends_at", update.Ends_at)
+	fields = setGiven(fields, "table_id", update.Table_id)
+	fields = setGiven(fields, "status", update.Status)
+	fields = setGiven(fields, "order_id", update.Order_id)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TableRepository interface {
+	List(ctx context.Context) ([]models.Table, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.Table], error)
+	FindByID(ctx context.Context, tableId string) (models.Table, error)
+	Insert(ctx context.Context, table models.Table) error
+	Update(ctx context.Context, tableId string, update TableUpdate) (bool, error)
+	// Claim marks the table as being booked until until, unless a claim
+	// that has not run out by now holds it. It is false when the table is
+	// held or does not exist.
+	Claim(ctx context.Context, tableId string, now time.Time, until time.Time) (bool, error)
+	// Release gives back the claim running until until, a claim that ran
+	// out and was taken by someone else is left alone
+	Release(ctx context.Context, tableId string, until time.Time) error
+}
+
+// TableUpdate changes the fields of a table that are not nil
+type TableUpdate struct {
+	Number_of_guests *int
+	Table_number     *int
+	Updated_at       time.Time
+}
+
+// Set is the update as the fields to store
+func (update TableUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "number_of_guests", update.Number_of_guests)
+	fields = setGiven(fields, "table_number", update.Table_number)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
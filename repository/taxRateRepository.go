@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaxRateRepository interface {
+	List(ctx context.Context) ([]models.TaxRate, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.TaxRate], error)
+	FindByID(ctx context.Context, taxRateId string) (models.TaxRate, error)
+	Insert(ctx context.Context, taxRate models.TaxRate) error
+	Update(ctx context.Context, taxRateId string, update TaxRateUpdate) (bool, error)
+}
+
+// TaxRateUpdate changes the fields of a tax rate that are not nil
+type TaxRateUpdate struct {
+	Name         *string
+	Rate         *float64
+	Inclusive    *bool
+	Jurisdiction *string
+	Active       *bool
+	Updated_at   time.Time
+}
+
+// Set is the update as the fields to store
+func (update TaxRateUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "name", update.Name)
+	fields = setGiven(fields, "rate", update.Rate)
+	fields = setGiven(fields, "inclusive", update.Inclusive)
+	fields = setGiven(fields, "jurisdiction", update.Jurisdiction)
+	fields = setGiven(fields, "active", update.Active)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaxRuleRepository interface {
+	List(ctx context.Context) ([]models.TaxRule, error)
+	Page(ctx context.Context, query ListQuery) (Page[models.TaxRule], error)
+	FindByID(ctx context.Context, taxRuleId string) (models.TaxRule, error)
+	Insert(ctx context.Context, taxRule models.TaxRule) error
+	Update(ctx context.Context, taxRuleId string, update TaxRuleUpdate) (bool, error)
+}
+
+// TaxRuleUpdate changes the fields of a tax rule that are not nil
+type TaxRuleUpdate struct {
+	Tax_rate_id *string
+	Category    *string
+	Food_id     *string
+	Active      *bool
+	Updated_at  time.Time
+}
+
+// Set is the update as the fields to store
+func (update TaxRuleUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "tax_rate_id", update.Tax_rate_id)
+	fields = setGiven(fields, "category", update.Category)
+	fields = setGiven(fields, "food_id", update.Food_id)
+	fields = setGiven(fields, "active", update.Active)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// setGiven adds key to the fields of an update when value was given, a nil
+// pointer or slice leaves the stored field as it is
+func setGiven(fields primitive.D, key string, value interface{}) primitive.D {
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Slice) && v.IsNil() {
+		return fields
+	}
+	return append(fields, primitive.E{Key: key, Value: value})
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+)
+
+type UserRepository interface {
+	Page(ctx context.Context, query ListQuery) (Page[models.User], error)
+	FindByID(ctx context.Context, userId string) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	Count(ctx context.Context) (int64, error)
+	CountByEmailOrPhone(ctx context.Context, email string, phone string) (int64, error)
+	Insert(ctx context.Context, user models.User) error
+	// InsertFirst stores the user the system is bootstrapped with, it
+	// returns ErrDuplicate when another user got there first.
+	InsertFirst(ctx context.Context, user models.User) error
+	UpdateRole(ctx context.Context, userId string, role string, updatedAt time.Time) (bool, error)
+	// SetTokens stores a token pair, nil tokens sign the user out
+	SetTokens(ctx context.Context, userId string, token *string, refreshToken *string, updatedAt time.Time) (bool, error)
+
+	// HasToken reports whether token is the access token currently stored on the user.
+	HasToken(ctx context.Context, userId string, token string) (bool, error)
+	// RotateTokens stores a new token pair only if oldRefreshToken is still the stored one.
+	RotateTokens(ctx context.Context, userId string, oldRefreshToken string, token string, refreshToken string, updatedAt time.Time) (bool, error)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"restaurant-management/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WaitlistRepository interface {
+	// ListWaiting returns the parties still waiting, first come first
+	ListWaiting(ctx context.Context) ([]models.WaitlistEntry, error)
+	PageWaiting(ctx context.Context, query ListQuery) (Page[models.WaitlistEntry], error)
+	FindByID(ctx context.Context, waitlistId string) (models.WaitlistEntry, error)
+	Insert(ctx context.Context, entry models.WaitlistEntry) error
+	UpdateStatus(ctx context.Context, waitlistId string, from *string, update WaitlistUpdate) (bool, error)
+}
+
+// WaitlistUpdate changes the fields of a waitlist entry that are not nil
+type WaitlistUpdate struct {
+	Status     *string
+	Table_id   *string
+	Order_id   *string
+	Seated_at  *time.Time
+	Updated_at time.Time
+}
+
+// Set is the update as the fields to store
+func (update WaitlistUpdate) Set() primitive.D {
+	var fields primitive.D
+	fields = setGiven(fields, "status", update.Status)
+	fields = setGiven(fields, "table_id", update.Table_id)
+	fields = setGiven(fields, "order_id", update.Order_id)
+	fields = setGiven(fields, "seated_at", update.Seated_at)
+	return append(fields, primitive.E{Key: "updated_at", Value: update.Updated_at})
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DrawerRoutes(incomingRoutes *gin.RouterGroup, drawerController *controllers.DrawerController) {
+	cashier := middleware.Authorization(models.RoleManager, models.RoleCashier)
+	incomingRoutes.GET("/drawers", cashier, drawerController.GetDrawers())
+	incomingRoutes.GET("/drawers/:drawer_id", cashier, drawerController.GetDrawer())
+	incomingRoutes.POST("/drawers", cashier, drawerController.OpenDrawer())
+	incomingRoutes.POST("/drawers/:drawer_id/movements", cashier, drawerController.AddDrawerMovement())
+	incomingRoutes.POST("/drawers/:drawer_id/close", cashier, drawerController.CloseDrawer())
+	incomingRoutes.GET("/zreports", cashier, drawerController.GetZReports())
+	incomingRoutes.GET("/zreports/:z_report_id", cashier, drawerController.GetZReport())
+}
+
+var drawerRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/drawers", Id: "getDrawers", Summary: "List cash drawers", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Query: controllers.ListParameters("/drawers"), Response: repository.Page[models.CashDrawer]{}},
+	{Method: http.MethodGet, Path: "/drawers/:drawer_id", Id: "getDrawer", Summary: "Get a cash drawer", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Response: models.CashDrawer{}},
+	{Method: http.MethodPost, Path: "/drawers", Id: "openDrawer", Summary: "Open a cash drawer with its opening float", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Body: models.CashDrawer{}, Response: models.CashDrawer{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/drawers/:drawer_id/movements", Id: "addDrawerMovement", Summary: "Record a drop or payout", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Body: models.DrawerMovement{}, Response: models.CashDrawer{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/drawers/:drawer_id/close", Id: "closeDrawer", Summary: "Close a drawer with the counted cash and write its Z report", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Body: models.CashDrawer{}, Response: controllers.ClosedDrawer{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodGet, Path: "/zreports", Id: "getZReports", Summary: "List Z reports", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Query: controllers.ListParameters("/zreports"), Response: repository.Page[models.ZReport]{}},
+	{Method: http.MethodGet, Path: "/zreports/:z_report_id", Id: "getZReport", Summary: "Get a Z report", Tag: "drawers", Roles: roles(models.RoleManager, models.RoleCashier), Response: models.ZReport{}},
+}
@@ -0,0 +1,61 @@
+package routes_test
+
+import (
+	"net/http"
+	"restaurant-management/client"
+	"restaurant-management/models"
+	"testing"
+)
+
+// TestClosedPeriod checks that the Z report locks the invoices it counted,
+// paid or not, and still lets them be paid
+func TestClosedPeriod(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	code := "SAVE2"
+	_, err := api.client.CreatePromotion(api.ctx, nil, client.Promotion{Name: "Two off", Type: models.PromotionFixed, Amount: amount("2.00"), Coupon_code: &code})
+	api.check(err)
+
+	orderId, orderItemIds := api.servedOrder(orderLine{burger, 1})
+	invoice := api.invoice(orderId)
+
+	drawer, err := api.client.OpenDrawer(api.ctx, nil, client.CashDrawer{Opening_float: amount("100.00")})
+	api.check(err)
+	_, err = api.client.CloseDrawer(api.ctx, *drawer.Drawer_id, nil, client.CashDrawer{Counted_cash: amount("100.00")})
+	api.check(err)
+
+	method := models.PaymentCash
+	refused := []struct {
+		name string
+		call func() error
+	}{
+		{"coupon", func() error {
+			_, err := api.client.ApplyCoupon(api.ctx, *invoice.Invoice_id, nil, client.CouponRedemption{Coupon_code: code})
+			return err
+		}},
+		{"comp", func() error {
+			_, err := api.client.CompInvoice(api.ctx, *invoice.Invoice_id, nil, client.CompRequest{Order_item_ids: orderItemIds, Reason: "cold"})
+			return err
+		}},
+		{"payment method", func() error {
+			_, err := api.client.UpdateInvoice(api.ctx, *invoice.Invoice_id, client.Invoice{Payment_method: &method})
+			return err
+		}},
+		{"void", func() error {
+			_, err := api.client.VoidOrder(api.ctx, orderId, nil)
+			return err
+		}},
+	}
+	for _, tt := range refused {
+		if err := tt.call(); status(err) != http.StatusConflict {
+			t.Errorf("%s after the Z report: status %d (%v), want 409", tt.name, status(err), err)
+		}
+	}
+
+	//a late payment counts in the period it is taken
+	receipt, err := api.client.CreatePayment(api.ctx, *invoice.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Amount: amount("12.50")})
+	api.check(err)
+	if *receipt.Invoice.Payment_status != models.PaymentPaid {
+		t.Errorf("invoice %s, want paid", *receipt.Invoice.Payment_status)
+	}
+}
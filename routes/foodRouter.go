@@ -1,15 +1,43 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func FoodRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/foods", controllers.GetFoods())
-	incomingRoutes.GET("/foods/:food_id", controllers.GetFood())
-	incomingRoutes.POST("/foods", controllers.CreateFood())
-	incomingRoutes.PATCH("/foods/:food_id", controllers.UpdateFood())
+func FoodRoutes(publicRoutes *gin.RouterGroup, incomingRoutes *gin.RouterGroup, foodController *controllers.FoodController) {
+	publicRoutes.GET("/foods", foodController.GetFoods())
+	publicRoutes.GET("/foods/:food_id", foodController.GetFood())
+	incomingRoutes.POST("/foods", middleware.Authorization(models.RoleManager), foodController.CreateFood())
+	incomingRoutes.PATCH("/foods/:food_id", middleware.Authorization(models.RoleManager), foodController.UpdateFood())
+	incomingRoutes.POST("/foods/:food_id/image", middleware.Authorization(models.RoleManager), foodController.UploadFoodImage())
 
 }
+
+var foodRouteDocs = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/foods", Id: "getFoods", Summary: "List food items", Tag: "foods", Public: true,
+		Query: append(controllers.ListParameters("/foods"),
+			queryParam("exclude_allergens", "allergens separated by commas, foods that declare none of them", &openapi.Schema{Type: "string"}),
+			queryParam("diet", "dietary tags separated by commas, foods that have all of them", &openapi.Schema{Type: "string"}),
+		),
+		Response: repository.Page[models.Food]{},
+	},
+	{Method: http.MethodGet, Path: "/foods/:food_id", Id: "getFood", Summary: "Get a food item", Tag: "foods", Public: true, Response: models.Food{}},
+	{Method: http.MethodPost, Path: "/foods", Id: "createFood", Summary: "Create a food item", Tag: "foods", Roles: roles(models.RoleManager), Body: models.Food{}, Response: models.Food{}},
+	{Method: http.MethodPatch, Path: "/foods/:food_id", Id: "updateFood", Summary: "Update a food item", Tag: "foods", Roles: roles(models.RoleManager), Body: models.Food{}, Response: models.Food{}},
+	{
+		Method: http.MethodPost, Path: "/foods/:food_id/image", Id: "uploadFoodImage", Summary: "Upload the image of a food item", Tag: "foods", Roles: roles(models.RoleManager),
+		Description: "JPEG, PNG or GIF in the image field, thumbnails are made of it.",
+		Body:        &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"image": {Type: "string", Format: "binary"}}, Required: []string{"image"}},
+		Body_type:   "multipart/form-data",
+		Response:    models.Food{},
+		Errors:      []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType},
+	},
+}
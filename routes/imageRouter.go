@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	"restaurant-management/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ImageRoutes(publicRoutes *gin.RouterGroup, imageController *controllers.ImageController) {
+	publicRoutes.GET("/images/*key", imageController.GetImage())
+	publicRoutes.HEAD("/images/*key", imageController.GetImage())
+}
+
+var imageRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/images/*key", Id: "getImage", Summary: "Download an uploaded image", Tag: "images", Public: true, Response_type: "image/*"},
+	{Method: http.MethodHead, Path: "/images/*key", Id: "headImage", Summary: "Check an uploaded image", Tag: "images", Public: true},
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func IngredientRoutes(incomingRoutes *gin.RouterGroup, ingredientController *controllers.IngredientController) {
+	manager := middleware.Authorization(models.RoleManager)
+	kitchen := middleware.Authorization(models.RoleManager, models.RoleCook)
+	incomingRoutes.GET("/ingredients", kitchen, ingredientController.GetIngredients())
+	incomingRoutes.GET("/ingredients/:ingredient_id", kitchen, ingredientController.GetIngredient())
+	incomingRoutes.POST("/ingredients", manager, ingredientController.CreateIngredient())
+	incomingRoutes.PATCH("/ingredients/:ingredient_id", kitchen, ingredientController.UpdateIngredient())
+	incomingRoutes.POST("/ingredients/:ingredient_id/restock", kitchen, ingredientController.RestockIngredient())
+}
+
+var ingredientRouteDocs = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/ingredients", Id: "getIngredients", Summary: "List ingredients", Tag: "ingredients", Roles: roles(models.RoleManager, models.RoleCook),
+		Query:    append(controllers.ListParameters("/ingredients"), queryParam("low_stock", "only those at or below their low stock threshold", &openapi.Schema{Type: "boolean"})),
+		Response: repository.Page[models.Ingredient]{},
+	},
+	{Method: http.MethodGet, Path: "/ingredients/:ingredient_id", Id: "getIngredient", Summary: "Get an ingredient", Tag: "ingredients", Roles: roles(models.RoleManager, models.RoleCook), Response: models.Ingredient{}},
+	{Method: http.MethodPost, Path: "/ingredients", Id: "createIngredient", Summary: "Create an ingredient", Tag: "ingredients", Roles: roles(models.RoleManager), Body: models.Ingredient{}, Response: models.Ingredient{}},
+	{Method: http.MethodPatch, Path: "/ingredients/:ingredient_id", Id: "updateIngredient", Summary: "Update an ingredient", Tag: "ingredients", Roles: roles(models.RoleManager, models.RoleCook), Body: models.Ingredient{}, Response: models.Ingredient{}},
+	{Method: http.MethodPost, Path: "/ingredients/:ingredient_id/restock", Id: "restockIngredient", Summary: "Add a delivery to the stock", Tag: "ingredients", Roles: roles(models.RoleManager, models.RoleCook), Body: controllers.RestockRequest{}, Response: models.Ingredient{}},
+}
@@ -1,15 +1,39 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func InvoiceRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/invoice", controllers.GetInvoices())
-	incomingRoutes.GET("/invoice/:invoice_id", controllers.GetInvoice())
-	incomingRoutes.POST("/invoice", controllers.CreateInvoice())
-	incomingRoutes.PATCH("/invoice/:invoice_id", controllers.UpdateInvoice())
+func InvoiceRoutes(incomingRoutes *gin.RouterGroup, invoiceController *controllers.InvoiceController) {
+	incomingRoutes.GET("/invoice", middleware.Authorization(models.RoleManager, models.RoleCashier), invoiceController.GetInvoices())
+	incomingRoutes.GET("/invoice/:invoice_id", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), invoiceController.GetInvoice())
+	incomingRoutes.GET("/invoice/:invoice_id/pdf", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), invoiceController.GetInvoicePDF())
+	incomingRoutes.GET("/invoice/:invoice_id/receipt", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), invoiceController.GetInvoiceReceipt())
+	incomingRoutes.POST("/invoice", middleware.Authorization(models.RoleManager, models.RoleCashier), invoiceController.CreateInvoice())
+	incomingRoutes.PATCH("/invoice/:invoice_id", middleware.Authorization(models.RoleManager, models.RoleCashier), invoiceController.UpdateInvoice())
 
 }
+
+var invoiceRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/invoice", Id: "getInvoices", Summary: "List invoices", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier), Query: controllers.ListParameters("/invoice"), Response: repository.Page[models.Invoice]{}},
+	{Method: http.MethodGet, Path: "/invoice/:invoice_id", Id: "getInvoice", Summary: "Get an invoice with its order items and payments", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Response: controllers.InvoiceViewFormat{}},
+	{Method: http.MethodGet, Path: "/invoice/:invoice_id/pdf", Id: "getInvoicePDF", Summary: "Download an invoice as PDF", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Response_type: "application/pdf"},
+	{
+		Method: http.MethodGet, Path: "/invoice/:invoice_id/receipt", Id: "getInvoiceReceipt", Summary: "Print a receipt of an invoice", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter),
+		Description: "Plain text, or ESC/POS commands for a thermal printer as application/octet-stream.",
+		Query: []openapi.Parameter{
+			queryParam("width", "paper width in millimetres, 80 by default", &openapi.Schema{Type: "integer", Enum: []interface{}{58, 80}}),
+			queryParam("format", "text by default", &openapi.Schema{Type: "string", Enum: []interface{}{"text", "escpos"}}),
+		},
+		Response_type: "text/plain",
+	},
+	{Method: http.MethodPost, Path: "/invoice", Id: "createInvoice", Summary: "Create the invoice of an order", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier), Body: models.Invoice{}, Response: models.Invoice{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPatch, Path: "/invoice/:invoice_id", Id: "updateInvoice", Summary: "Update an invoice", Tag: "invoices", Roles: roles(models.RoleManager, models.RoleCashier), Body: models.Invoice{}, Response: models.Invoice{}, Errors: []int{http.StatusConflict}},
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+func KitchenRoutes(incomingRoutes *gin.RouterGroup, kitchenController *controllers.KitchenController) {
+	incomingRoutes.GET("/kitchen/tickets", middleware.Authorization(models.RoleManager, models.RoleCook, models.RoleWaiter), kitchenController.GetTickets())
+	incomingRoutes.GET("/kitchen/stream", middleware.Authorization(models.RoleManager, models.RoleCook, models.RoleWaiter), kitchenController.StreamTickets())
+	incomingRoutes.POST("/kitchen/items/:orderItem_id/preparing", middleware.Authorization(models.RoleManager, models.RoleCook), kitchenController.BumpItem(models.KitchenPreparing))
+	incomingRoutes.POST("/kitchen/items/:orderItem_id/done", middleware.Authorization(models.RoleManager, models.RoleCook), kitchenController.BumpItem(models.KitchenDone))
+}
+
+var kitchenRouteDocs = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/kitchen/tickets", Id: "getKitchenTickets", Summary: "List open kitchen tickets", Tag: "kitchen", Roles: roles(models.RoleManager, models.RoleCook, models.RoleWaiter),
+		Query:    []openapi.Parameter{queryParam("station", "only the tickets of this station", &openapi.Schema{Type: "string"})},
+		Response: []models.KitchenTicket{},
+	},
+	{
+		Method: http.MethodGet, Path: "/kitchen/stream", Id: "streamKitchenTickets", Summary: "Follow kitchen tickets as Server-Sent Events", Tag: "kitchen", Roles: roles(models.RoleManager, models.RoleCook, models.RoleWaiter),
+		Description:   "Starts with a snapshot event of every open ticket, then sends a ticket event whenever an item is created, bumped or cancelled with its order.",
+		Query:         []openapi.Parameter{queryParam("station", "only the tickets of this station", &openapi.Schema{Type: "string"})},
+		Response_type: "text/event-stream",
+	},
+	{Method: http.MethodPost, Path: "/kitchen/items/:orderItem_id/preparing", Id: "startKitchenItem", Summary: "Mark an order item as being prepared", Tag: "kitchen", Roles: roles(models.RoleManager, models.RoleCook), Response: models.KitchenTicket{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/kitchen/items/:orderItem_id/done", Id: "finishKitchenItem", Summary: "Mark an order item as done", Tag: "kitchen", Roles: roles(models.RoleManager, models.RoleCook), Response: models.KitchenTicket{}, Errors: []int{http.StatusConflict}},
+}
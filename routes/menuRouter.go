@@ -1,14 +1,32 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func MenuRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/menu", controllers.GetMenus())
-	incomingRoutes.GET("/menu/:menu_id", controllers.GetMenu())
-	incomingRoutes.POST("/menu", controllers.CreateMenu())
-	incomingRoutes.PATCH("/menu/:menu_id", controllers.UpdateMenu())
+func MenuRoutes(publicRoutes *gin.RouterGroup, incomingRoutes *gin.RouterGroup, menuController *controllers.MenuController) {
+	publicRoutes.GET("/menu", menuController.GetMenus())
+	publicRoutes.GET("/menu/active", menuController.GetActiveMenus())
+	publicRoutes.GET("/menu/:menu_id", menuController.GetMenu())
+	incomingRoutes.POST("/menu", middleware.Authorization(models.RoleManager), menuController.CreateMenu())
+	incomingRoutes.PATCH("/menu/:menu_id", middleware.Authorization(models.RoleManager), menuController.UpdateMenu())
+}
+
+var menuRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/menu", Id: "getMenus", Summary: "List menus", Tag: "menus", Public: true, Query: controllers.ListParameters("/menu"), Response: repository.Page[models.Menu]{}},
+	{
+		Method: http.MethodGet, Path: "/menu/active", Id: "getActiveMenus", Summary: "List the menus being served", Tag: "menus", Public: true,
+		Query:    []openapi.Parameter{queryParam("at", "now by default", &openapi.Schema{Type: "string", Format: "date-time"})},
+		Response: []models.Menu{},
+	},
+	{Method: http.MethodGet, Path: "/menu/:menu_id", Id: "getMenu", Summary: "Get a menu", Tag: "menus", Public: true, Response: models.Menu{}},
+	{Method: http.MethodPost, Path: "/menu", Id: "createMenu", Summary: "Create a menu", Tag: "menus", Roles: roles(models.RoleManager), Body: models.Menu{}, Response: models.Menu{}},
+	{Method: http.MethodPatch, Path: "/menu/:menu_id", Id: "updateMenu", Summary: "Update a menu", Tag: "menus", Roles: roles(models.RoleManager), Body: models.Menu{}, Response: models.Menu{}},
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NoteRoutes(incomingRoutes *gin.RouterGroup, noteController *controllers.NoteController) {
+	staff := middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier)
+	incomingRoutes.GET("/notes", staff, noteController.GetNotes())
+	incomingRoutes.GET("/notes/:note_id", staff, noteController.GetNote())
+	incomingRoutes.POST("/notes", staff, noteController.CreateNote())
+	incomingRoutes.PATCH("/notes/:note_id", staff, noteController.UpdateNote())
+	incomingRoutes.DELETE("/notes/:note_id", staff, noteController.DeleteNote())
+}
+
+var noteRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/notes", Id: "getNotes", Summary: "List notes", Tag: "notes", Description: "subject_type and subject_id are given together.", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Query: controllers.ListParameters("/notes"), Response: repository.Page[models.Note]{}},
+	{Method: http.MethodGet, Path: "/notes/:note_id", Id: "getNote", Summary: "Get a note", Tag: "notes", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Response: models.Note{}},
+	{Method: http.MethodPost, Path: "/notes", Id: "createNote", Summary: "Write a note on an order, order item, table or reservation", Tag: "notes", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Body: models.Note{}, Response: models.Note{}},
+	{Method: http.MethodPatch, Path: "/notes/:note_id", Id: "updateNote", Summary: "Update a note", Tag: "notes", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Body: models.Note{}, Response: models.Note{}},
+	{Method: http.MethodDelete, Path: "/notes/:note_id", Id: "deleteNote", Summary: "Delete a note", Tag: "notes", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Response: controllers.DeletedNote{}},
+}
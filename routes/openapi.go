@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"restaurant-management/apierror"
+	"restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenAPIPath is where the document of the API is served, SwaggerUIPath
+// shows it in a browser
+const (
+	OpenAPIPath   = "/openapi.json"
+	SwaggerUIPath = "/swagger"
+)
+
+// apiVersion is the version of the API the document describes
+const apiVersion = "1.0.0"
+
+var documentRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: OpenAPIPath, Id: "getOpenAPI", Summary: "This document", Tag: "documentation", Public: true, Response: &openapi.Schema{Type: "object"}},
+	{Method: http.MethodGet, Path: SwaggerUIPath, Id: "getSwaggerUI", Summary: "Swagger UI for this document", Tag: "documentation", Public: true, Response_type: "text/html"},
+}
+
+// routeDocs are the docs of every route, each router file documents its own
+func routeDocs() []openapi.Route {
+	all := [][]openapi.Route{
+		documentRouteDocs, userRouteDocs, foodRouteDocs, imageRouteDocs, invoiceRouteDocs, paymentRouteDocs,
+		drawerRouteDocs, menuRouteDocs, promotionRouteDocs, ingredientRouteDocs, taxRouteDocs, noteRouteDocs,
+		orderItemRouteDocs, tableRouteDocs, orderRouteDocs, reportRouteDocs, reservationRouteDocs,
+		waitlistRouteDocs, kitchenRouteDocs,
+	}
+	docs := []openapi.Route{}
+	for _, routes := range all {
+		docs = append(docs, routes...)
+	}
+	return docs
+}
+
+// Document builds the document of what router registered, a route left
+// undocumented is a bug so the server does not start
+func Document(router *gin.Engine) *openapi.Document {
+	codes := []interface{}{}
+	for _, code := range apierror.Codes {
+		codes = append(codes, string(code))
+	}
+
+	doc, err := openapi.Build(openapi.Options{
+		Info: openapi.Info{
+			Title:       "Restaurant management API",
+			Description: "Errors are described in docs/errors.md. Amounts are decimals in the restaurant currency.",
+			Version:     apiVersion,
+		},
+		Types: map[reflect.Type]*openapi.Schema{
+			reflect.TypeOf(models.Money{}):       {Type: "number", Format: "decimal", Description: "an amount with two decimals"},
+			reflect.TypeOf(primitive.ObjectID{}): {Type: "string", Pattern: "^[0-9a-f]{24}$"},
+			reflect.TypeOf(apierror.Code("")):    {Type: "string", Enum: codes},
+		},
+		Error: apierror.ErrorBody{},
+		Headers: map[string][]openapi.Parameter{
+			http.MethodPost: {{
+				Name:        middleware.IdempotencyKeyHeader,
+				Description: "a POST sent again with the same key gets the first answer, marked Idempotent-Replayed: true, for idempotency_key_lifetime (24 hours by default)",
+				Schema:      &openapi.Schema{Type: "string", Pattern: "^[!-~]{1,255}$"},
+			}},
+		},
+		Errors: map[string][]int{
+			http.MethodPost: {http.StatusConflict, http.StatusRequestEntityTooLarge},
+		},
+	}, router.Routes(), routeDocs())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return doc
+}
+
+// roles are the roles a route is open to, admins always are
+func roles(allowed ...string) []string {
+	return append([]string{models.RoleAdmin}, allowed...)
+}
+
+func queryParam(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, Description: description, Schema: schema}
+}
+
+func requiredQueryParam(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, Description: description, Required: true, Schema: schema}
+}
@@ -1,15 +1,28 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func OrderItemRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/orderItems", controllers.GetOrderItems())
-	incomingRoutes.GET("/orderItems/:orderItem_id", controllers.GetOrderItem())
-	incomingRoutes.GET("/orderItems-order/:order_id", controllers.GetOrderItemsByOrderID())
-	incomingRoutes.POST("/orderItems", controllers.CreateOrderItem())
-	incomingRoutes.PATCH("/orderItems/:orderItem_id", controllers.UpdateOrderItem())
+func OrderItemRoutes(incomingRoutes *gin.RouterGroup, orderItemController *controllers.OrderItemController) {
+	incomingRoutes.GET("/orderItems", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), orderItemController.GetOrderItems())
+	incomingRoutes.GET("/orderItems/:orderItem_id", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), orderItemController.GetOrderItem())
+	incomingRoutes.GET("/orderItems-order/:order_id", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), orderItemController.GetOrderItemsByOrderID())
+	incomingRoutes.POST("/orderItems", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderItemController.CreateOrderItem())
+	incomingRoutes.PATCH("/orderItems/:orderItem_id", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook), orderItemController.UpdateOrderItem())
+}
+
+var orderItemRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/orderItems", Id: "getOrderItems", Summary: "List order items", Tag: "orderItems", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Query: controllers.ListParameters("/orderItems"), Response: repository.Page[models.OrderItem]{}},
+	{Method: http.MethodGet, Path: "/orderItems/:orderItem_id", Id: "getOrderItem", Summary: "Get an order item", Tag: "orderItems", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Response: models.OrderItem{}},
+	{Method: http.MethodGet, Path: "/orderItems-order/:order_id", Id: "getOrderItemsByOrder", Summary: "Get the order items of an order with their foods", Tag: "orderItems", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Response: models.OrderDetails{}},
+	{Method: http.MethodPost, Path: "/orderItems", Id: "createOrderItems", Summary: "Place an order with its items", Tag: "orderItems", Roles: roles(models.RoleManager, models.RoleWaiter), Body: controllers.OrderItemPack{}, Response: []models.OrderItem{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPatch, Path: "/orderItems/:orderItem_id", Id: "updateOrderItem", Summary: "Update an order item", Tag: "orderItems", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook), Body: models.OrderItem{}, Response: models.OrderItem{}, Errors: []int{http.StatusConflict}},
 }
@@ -0,0 +1,33 @@
+package routes_test
+
+import (
+	"net/http"
+	"restaurant-management/client"
+	"testing"
+)
+
+// TestUpdateOrderItem checks that items are repriced while their order is
+// open and left as billed once it is invoiced or closed
+func TestUpdateOrderItem(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+
+	_, orderItemIds := api.order(orderLine{burger, 1})
+	orderItem, err := api.client.UpdateOrderItem(api.ctx, orderItemIds[0], client.OrderItem{Food_id: burger, Quantity: 2})
+	api.check(err)
+	if text(orderItem.Line_price) != "25.00" {
+		t.Errorf("line price %s, want 25.00", text(orderItem.Line_price))
+	}
+
+	invoiced, invoicedItems := api.servedOrder(orderLine{burger, 1})
+	api.invoice(invoiced)
+	cancelled, cancelledItems := api.order(orderLine{burger, 1})
+	_, err = api.client.CancelOrder(api.ctx, cancelled, nil)
+	api.check(err)
+
+	for name, orderItemId := range map[string]string{"invoiced": invoicedItems[0], "cancelled": cancelledItems[0]} {
+		if _, err := api.client.UpdateOrderItem(api.ctx, orderItemId, client.OrderItem{Food_id: burger, Quantity: 3}); status(err) != http.StatusConflict {
+			t.Errorf("%s order: status %d (%v), want 409", name, status(err), err)
+		}
+	}
+}
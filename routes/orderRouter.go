@@ -1,14 +1,38 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func OrderRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/orders", controllers.GetOrders())
-	incomingRoutes.GET("/order/:order_id", controllers.GetOrder())
-	incomingRoutes.POST("/order", controllers.CreateOrder())
-	incomingRoutes.PATCH("/order/:order_id", controllers.OrderUpdate())
+func OrderRoutes(incomingRoutes *gin.RouterGroup, orderController *controllers.OrderController) {
+	incomingRoutes.GET("/orders", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), orderController.GetOrders())
+	incomingRoutes.GET("/order/:order_id", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), orderController.GetOrder())
+	incomingRoutes.POST("/order", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderController.CreateOrder())
+	incomingRoutes.PATCH("/order/:order_id", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderController.OrderUpdate())
+	incomingRoutes.POST("/order/:order_id/accept", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderController.TransitionOrder(models.OrderAccepted))
+	incomingRoutes.POST("/order/:order_id/prepare", middleware.Authorization(models.RoleManager, models.RoleCook), orderController.TransitionOrder(models.OrderInPreparation))
+	incomingRoutes.POST("/order/:order_id/ready", middleware.Authorization(models.RoleManager, models.RoleCook), orderController.TransitionOrder(models.OrderReady))
+	incomingRoutes.POST("/order/:order_id/serve", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderController.TransitionOrder(models.OrderServed))
+	incomingRoutes.POST("/order/:order_id/cancel", middleware.Authorization(models.RoleManager, models.RoleWaiter), orderController.TransitionOrder(models.OrderCancelled))
+	incomingRoutes.POST("/order/:order_id/void", middleware.Authorization(models.RoleManager), orderController.TransitionOrder(models.OrderVoided))
+}
+
+var orderRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/orders", Id: "getOrders", Summary: "List orders", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Query: controllers.ListParameters("/orders"), Response: repository.Page[models.Order]{}},
+	{Method: http.MethodGet, Path: "/order/:order_id", Id: "getOrder", Summary: "Get an order", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCook, models.RoleCashier), Response: models.Order{}},
+	{Method: http.MethodPost, Path: "/order", Id: "createOrder", Summary: "Open an order for a table", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter), Body: models.Order{}, Response: models.Order{}},
+	{Method: http.MethodPatch, Path: "/order/:order_id", Id: "updateOrder", Summary: "Update an order", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter), Body: models.Order{}, Response: models.Order{}},
+	{Method: http.MethodPost, Path: "/order/:order_id/accept", Id: "acceptOrder", Summary: "Accept a placed order", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Order{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/order/:order_id/prepare", Id: "prepareOrder", Summary: "Start preparing an accepted order", Tag: "orders", Roles: roles(models.RoleManager, models.RoleCook), Response: models.Order{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/order/:order_id/ready", Id: "readyOrder", Summary: "Mark an order ready to serve", Tag: "orders", Roles: roles(models.RoleManager, models.RoleCook), Response: models.Order{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/order/:order_id/serve", Id: "serveOrder", Summary: "Mark an order served", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Order{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/order/:order_id/cancel", Id: "cancelOrder", Summary: "Cancel an order the kitchen has not started", Tag: "orders", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Order{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/order/:order_id/void", Id: "voidOrder", Summary: "Void an order the kitchen has started", Tag: "orders", Roles: roles(models.RoleManager), Response: models.Order{}, Errors: []int{http.StatusConflict}},
 }
@@ -0,0 +1,248 @@
+package routes_test
+
+import (
+	"context"
+	"net/http"
+	"restaurant-management/client"
+	"restaurant-management/models"
+	"testing"
+	"time"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+
+	transitions := map[string]func(ctx context.Context, orderId string) (client.Order, error){
+		"accept": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.AcceptOrder(ctx, orderId, nil)
+		},
+		"prepare": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.PrepareOrder(ctx, orderId, nil)
+		},
+		"ready": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.ReadyOrder(ctx, orderId, nil)
+		},
+		"serve": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.ServeOrder(ctx, orderId, nil)
+		},
+		"cancel": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.CancelOrder(ctx, orderId, nil)
+		},
+		"void": func(ctx context.Context, orderId string) (client.Order, error) {
+			return api.client.VoidOrder(ctx, orderId, nil)
+		},
+	}
+
+	type step struct {
+		transition string
+		status     int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		want  string
+	}{
+		{"served", []step{{"accept", 200}, {"prepare", 200}, {"ready", 200}, {"serve", 200}}, models.OrderServed},
+		{"cancelled when placed", []step{{"cancel", 200}}, models.OrderCancelled},
+		{"cancelled when accepted", []step{{"accept", 200}, {"cancel", 200}}, models.OrderCancelled},
+		{"voided once cooking", []step{{"accept", 200}, {"prepare", 200}, {"cancel", 409}, {"void", 200}}, models.OrderVoided},
+		{"voided once served", []step{{"accept", 200}, {"prepare", 200}, {"ready", 200}, {"serve", 200}, {"void", 200}}, models.OrderVoided},
+		{"placed cannot be voided", []step{{"void", 409}}, models.OrderPlaced},
+		{"not served before ready", []step{{"accept", 200}, {"serve", 409}, {"ready", 409}}, models.OrderAccepted},
+		{"not accepted twice", []step{{"accept", 200}, {"accept", 409}}, models.OrderAccepted},
+		{"cancelled stays cancelled", []step{{"cancel", 200}, {"accept", 409}, {"cancel", 409}}, models.OrderCancelled},
+		{"served cannot be cancelled", []step{{"accept", 200}, {"prepare", 200}, {"ready", 200}, {"serve", 200}, {"cancel", 409}}, models.OrderServed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderId, _ := api.order(orderLine{burger, 1})
+
+			//every accepted step is one entry of the history after the first
+			history := []string{models.OrderPlaced}
+			for _, step := range tt.steps {
+				order, err := transitions[step.transition](api.ctx, orderId)
+				if status(err) != step.status {
+					t.Fatalf("%s: status %d (%v), want %d", step.transition, status(err), err, step.status)
+				}
+				if err == nil {
+					history = append(history, *order.Status)
+				}
+			}
+
+			order, err := api.client.GetOrder(api.ctx, orderId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *order.Status != tt.want {
+				t.Errorf("status %s, want %s", *order.Status, tt.want)
+			}
+			if len(order.Status_history) != len(history) {
+				t.Fatalf("history has %d changes, want %d", len(order.Status_history), len(history))
+			}
+			for i, change := range order.Status_history {
+				from := ""
+				if i > 0 {
+					from = history[i-1]
+				}
+				if *change.To != history[i] || stringValue(change.From) != from {
+					t.Errorf("change %d is %s to %s, want %s to %s", i, stringValue(change.From), *change.To, from, history[i])
+				}
+			}
+		})
+	}
+
+	t.Run("unknown order", func(t *testing.T) {
+		if _, err := api.client.AcceptOrder(api.ctx, "000000000000000000000000", nil); status(err) != http.StatusNotFound {
+			t.Errorf("status %d, want 404", status(err))
+		}
+	})
+}
+
+// TestOrderPaid checks that the last payment closes the order, which has
+// no route of its own
+func TestOrderPaid(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	orderId, _ := api.servedOrder(orderLine{burger, 1})
+	invoice := api.invoice(orderId)
+
+	if _, err := api.client.VoidOrder(api.ctx, orderId, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.client.CreatePayment(api.ctx, *invoice.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Amount: amount("12.50")}); status(err) != http.StatusConflict {
+		t.Errorf("paying a voided order: status %d, want 409", status(err))
+	}
+
+	orderId, _ = api.servedOrder(orderLine{burger, 1})
+	invoice = api.invoice(orderId)
+	if _, err := api.client.CreatePayment(api.ctx, *invoice.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Amount: amount("12.50")}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := api.client.GetOrder(api.ctx, orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *order.Status != models.OrderPaid {
+		t.Errorf("status %s, want %s", *order.Status, models.OrderPaid)
+	}
+	if _, err := api.client.VoidOrder(api.ctx, orderId, nil); status(err) != http.StatusConflict {
+		t.Errorf("voiding a paid order: status %d, want 409", status(err))
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func TestOrderPages(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	placed := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		orderId, _ := api.order(orderLine{burger, 1})
+		placed[orderId] = true
+	}
+	//every status is the same, so only the _id orders the pages
+	sort, limit := "status", 2
+	params := &client.GetOrdersParams{Sort: &sort, Limit: &limit}
+
+	seen := map[string]bool{}
+	pages := 0
+	for {
+		page, err := api.client.GetOrders(api.ctx, params)
+		api.check(err)
+		pages++
+		for _, order := range page.Items {
+			if seen[*order.Order_id] {
+				t.Errorf("order %s is on two pages", *order.Order_id)
+			}
+			seen[*order.Order_id] = true
+		}
+		if *page.Total_count != 5 {
+			t.Errorf("total_count %d, want 5", *page.Total_count)
+		}
+		if !*page.Has_more {
+			break
+		}
+		if pages > 5 {
+			t.Fatal("the cursor does not move on")
+		}
+		params.Cursor = page.Next_cursor
+	}
+	if pages != 3 || len(seen) != len(placed) {
+		t.Errorf("%d orders on %d pages, want 5 on 3", len(seen), pages)
+	}
+
+	//a cursor only works with the sort it was made for
+	other := "-created_at"
+	first, err := api.client.GetOrders(api.ctx, &client.GetOrdersParams{Sort: &sort, Limit: &limit})
+	api.check(err)
+	for _, cursor := range []string{*first.Next_cursor, "not-a-cursor"} {
+		cursor := cursor
+		if _, err := api.client.GetOrders(api.ctx, &client.GetOrdersParams{Sort: &other, Limit: &limit, Cursor: &cursor}); status(err) != http.StatusBadRequest {
+			t.Errorf("cursor %q: status %d, want 400", cursor, status(err))
+		}
+	}
+}
+
+// TestOrderStock checks that cancelled and voided orders give their
+// ingredients back once
+func TestOrderStock(t *testing.T) {
+	api := newAPI(t)
+	beef, err := api.client.CreateIngredient(api.ctx, nil, client.Ingredient{Name: "Beef", Unit: "kg", Stock: 10})
+	api.check(err)
+	quarter := 0.25
+	burger, err := api.client.CreateFood(api.ctx, nil, client.Food{Name: "Burger", Price: "12.50", Menu_id: api.menuId, Recipe: []client.RecipeLine{{Ingredient_id: *beef.Ingredient_id, Quantity: &quarter}}})
+	api.check(err)
+
+	stock := func(step string, want float64) {
+		t.Helper()
+		ingredient, err := api.client.GetIngredient(api.ctx, *beef.Ingredient_id)
+		api.check(err)
+		if ingredient.Stock != want {
+			t.Errorf("%s: stock %v, want %v", step, ingredient.Stock, want)
+		}
+	}
+
+	cancelled, _ := api.order(orderLine{*burger.Food_id, 4})
+	stock("ordered", 9)
+	_, err = api.client.CancelOrder(api.ctx, cancelled, nil)
+	api.check(err)
+	stock("cancelled", 10)
+	if _, err := api.client.CancelOrder(api.ctx, cancelled, nil); status(err) != http.StatusConflict {
+		t.Errorf("cancelled again: status %d, want 409", status(err))
+	}
+	stock("cancelled again", 10)
+
+	voided, _ := api.servedOrder(orderLine{*burger.Food_id, 2})
+	stock("served", 9.5)
+	_, err = api.client.VoidOrder(api.ctx, voided, nil)
+	api.check(err)
+	stock("voided", 10)
+}
+
+func TestOrderTable(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	other, err := api.client.CreateTable(api.ctx, nil, client.Table{Number_of_guests: 2, Table_number: 2})
+	api.check(err)
+
+	open, _ := api.order(orderLine{burger, 1})
+	order, err := api.client.UpdateOrder(api.ctx, open, client.Order{Table_id: *other.Table_id, Order_date: time.Now()})
+	api.check(err)
+	if order.Table_id != *other.Table_id {
+		t.Errorf("table %s, want %s", order.Table_id, *other.Table_id)
+	}
+
+	paid, _ := api.servedOrder(orderLine{burger, 1})
+	invoice := api.invoice(paid)
+	_, err = api.client.CreatePayment(api.ctx, *invoice.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Amount: amount("12.50")})
+	api.check(err)
+	if _, err := api.client.UpdateOrder(api.ctx, paid, client.Order{Table_id: *other.Table_id, Order_date: time.Now()}); status(err) != http.StatusConflict {
+		t.Errorf("table of a paid order: status %d, want 409", status(err))
+	}
+}
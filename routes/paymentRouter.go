@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PaymentRoutes(incomingRoutes *gin.RouterGroup, paymentController *controllers.PaymentController) {
+	incomingRoutes.GET("/invoice/:invoice_id/payments", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), paymentController.GetPayments())
+	incomingRoutes.POST("/invoice/:invoice_id/payments", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), paymentController.CreatePayment())
+	incomingRoutes.GET("/invoice/:invoice_id/split", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), paymentController.GetEvenSplit())
+	incomingRoutes.POST("/invoice/:invoice_id/split", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), paymentController.SplitByItems())
+}
+
+var paymentRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/invoice/:invoice_id/payments", Id: "getPayments", Summary: "List the payments of an invoice", Tag: "payments", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Query: controllers.ListParameters("/invoice/:invoice_id/payments"), Response: repository.Page[models.Payment]{}},
+	{Method: http.MethodPost, Path: "/invoice/:invoice_id/payments", Id: "createPayment", Summary: "Pay towards an invoice", Tag: "payments", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Body: models.Payment{}, Response: controllers.PaymentReceipt{}, Errors: []int{http.StatusConflict}},
+	{
+		Method: http.MethodGet, Path: "/invoice/:invoice_id/split", Id: "getEvenSplit", Summary: "Split the balance due evenly", Tag: "payments", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter),
+		Query:    []openapi.Parameter{requiredQueryParam("guests", "how many guests share the bill", &openapi.Schema{Type: "integer"})},
+		Response: controllers.EvenSplit{},
+	},
+	{Method: http.MethodPost, Path: "/invoice/:invoice_id/split", Id: "splitByItems", Summary: "Price the order items each guest had", Tag: "payments", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Body: controllers.ItemSplitRequest{}, Response: controllers.ItemSplit{}},
+}
@@ -0,0 +1,266 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"restaurant-management/client"
+	"restaurant-management/models"
+	"testing"
+)
+
+// bill is an invoice for a burger (12.50) and two colas (3.00 each)
+type bill struct {
+	invoiceId string
+	orderId   string
+	burger    string
+	colas     string
+}
+
+func (api *testAPI) bill() bill {
+	api.t.Helper()
+	orderId, orderItemIds := api.servedOrder(orderLine{api.food("Burger", "12.50"), 1}, orderLine{api.food("Cola", "3.00"), 2})
+	invoice := api.invoice(orderId)
+	if text(invoice.Total_amount) != "18.50" {
+		api.t.Fatalf("invoice total %s, want 18.50", text(invoice.Total_amount))
+	}
+	return bill{invoiceId: *invoice.Invoice_id, orderId: orderId, burger: orderItemIds[0], colas: orderItemIds[1]}
+}
+
+func TestPayments(t *testing.T) {
+	card := func(value string) client.Payment {
+		return client.Payment{Method: models.PaymentCard, Amount: amount(value)}
+	}
+	cash := func(value string, tendered string) client.Payment {
+		payment := client.Payment{Method: models.PaymentCash, Amount: amount(value)}
+		if tendered != "" {
+			payment.Tendered = amount(tendered)
+		}
+		return payment
+	}
+
+	type want struct {
+		status         int
+		amount         string
+		change         string
+		balance        string
+		payment_status string
+		method         string
+	}
+	tests := []struct {
+		name     string
+		payments []client.Payment
+		wants    []want
+	}{
+		{
+			name:     "in one go",
+			payments: []client.Payment{card("18.50")},
+			wants:    []want{{200, "18.50", "0.00", "0.00", models.PaymentPaid, models.PaymentCard}},
+		},
+		{
+			name:     "in parts",
+			payments: []client.Payment{card("10.00"), card("8.50")},
+			wants: []want{
+				{200, "10.00", "0.00", "8.50", models.PaymentPartiallyPaid, models.PaymentCard},
+				{200, "8.50", "0.00", "0.00", models.PaymentPaid, models.PaymentCard},
+			},
+		},
+		{
+			name:     "with several methods",
+			payments: []client.Payment{card("10.00"), cash("8.50", "")},
+			wants: []want{
+				{200, "10.00", "0.00", "8.50", models.PaymentPartiallyPaid, models.PaymentCard},
+				{200, "8.50", "0.00", "0.00", models.PaymentPaid, models.PaymentMixed},
+			},
+		},
+		{
+			name:     "card cannot pay more than is due",
+			payments: []client.Payment{card("20.00"), card("18.50")},
+			wants:    []want{{status: 400}, {200, "18.50", "0.00", "0.00", models.PaymentPaid, models.PaymentCard}},
+		},
+		{
+			name:     "cash beyond the balance is change",
+			payments: []client.Payment{card("10.00"), cash("10.00", "")},
+			wants: []want{
+				{200, "10.00", "0.00", "8.50", models.PaymentPartiallyPaid, models.PaymentCard},
+				{200, "8.50", "1.50", "0.00", models.PaymentPaid, models.PaymentMixed},
+			},
+		},
+		{
+			name:     "cash tendered",
+			payments: []client.Payment{cash("18.50", "50")},
+			wants:    []want{{200, "18.50", "31.50", "0.00", models.PaymentPaid, models.PaymentCash}},
+		},
+		{
+			name:     "tendered below the amount",
+			payments: []client.Payment{cash("18.50", "10.00")},
+			wants:    []want{{status: 400}},
+		},
+		{
+			name:     "tendered is cash only",
+			payments: []client.Payment{{Method: models.PaymentCard, Amount: amount("18.50"), Tendered: amount("20.00")}},
+			wants:    []want{{status: 400}},
+		},
+		{
+			name:     "amount is needed",
+			payments: []client.Payment{{Method: models.PaymentCard}},
+			wants:    []want{{status: 400}},
+		},
+		{
+			name:     "paid invoice takes no more",
+			payments: []client.Payment{card("18.50"), cash("1.00", "")},
+			wants:    []want{{200, "18.50", "0.00", "0.00", models.PaymentPaid, models.PaymentCard}, {status: 409}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPI(t)
+			b := api.bill()
+			for i, payment := range tt.payments {
+				receipt, err := api.client.CreatePayment(api.ctx, b.invoiceId, nil, payment)
+				w := tt.wants[i]
+				if status(err) != w.status {
+					t.Fatalf("payment %d: status %d (%v), want %d", i+1, status(err), err, w.status)
+				}
+				if err != nil {
+					continue
+				}
+				got := want{200, text(receipt.Payment.Amount), text(receipt.Payment.Change_due), text(receipt.Invoice.Balance_due), *receipt.Invoice.Payment_status, *receipt.Invoice.Payment_method}
+				if got != w {
+					t.Errorf("payment %d: %+v, want %+v", i+1, got, w)
+				}
+			}
+
+			//refused payments leave nothing behind
+			payments, err := api.client.GetPayments(api.ctx, b.invoiceId, nil)
+			api.check(err)
+			accepted := 0
+			for _, w := range tt.wants {
+				if w.status == 200 {
+					accepted++
+				}
+			}
+			if len(payments.Items) != accepted || *payments.Total_count != int64(accepted) {
+				t.Errorf("%d of %d payments stored, want %d", len(payments.Items), *payments.Total_count, accepted)
+			}
+		})
+	}
+}
+
+func TestEvenSplit(t *testing.T) {
+	api := newAPI(t)
+	b := api.bill()
+
+	tests := []struct {
+		paid    string
+		guests  int
+		balance string
+		shares  []string
+	}{
+		{"", 1, "18.50", []string{"18.50"}},
+		//the cent that does not divide goes to the first guests
+		{"", 3, "18.50", []string{"6.17", "6.17", "6.16"}},
+		{"10.00", 2, "8.50", []string{"4.25", "4.25"}},
+		{"", 3, "8.50", []string{"2.84", "2.83", "2.83"}},
+	}
+	for _, tt := range tests {
+		if tt.paid != "" {
+			_, err := api.client.CreatePayment(api.ctx, b.invoiceId, nil, client.Payment{Method: models.PaymentCard, Amount: amount(tt.paid)})
+			api.check(err)
+		}
+		split, err := api.client.GetEvenSplit(api.ctx, b.invoiceId, &client.GetEvenSplitParams{Guests: tt.guests})
+		api.check(err)
+		shares := []string{}
+		for _, share := range split.Shares {
+			shares = append(shares, share.String())
+		}
+		if text(split.Total_amount) != "18.50" || text(split.Balance_due) != tt.balance || !equalStrings(shares, tt.shares) {
+			t.Errorf("%d guests: total %s, balance %s, shares %v, want 18.50, %s, %v", tt.guests, text(split.Total_amount), text(split.Balance_due), shares, tt.balance, tt.shares)
+		}
+	}
+
+	for _, guests := range []int{0, 101} {
+		if _, err := api.client.GetEvenSplit(api.ctx, b.invoiceId, &client.GetEvenSplitParams{Guests: guests}); status(err) != http.StatusBadRequest {
+			t.Errorf("%d guests: status %d, want 400", guests, status(err))
+		}
+	}
+}
+
+func TestSplitByItems(t *testing.T) {
+	api := newAPI(t)
+	b := api.bill()
+
+	split, err := api.client.SplitByItems(api.ctx, b.invoiceId, nil, guests([]string{b.burger}, []string{b.colas}))
+	api.check(err)
+	if len(split.Shares) != 2 || text(split.Shares[0].Amount) != "12.50" || text(split.Shares[1].Amount) != "6.00" || text(split.Unassigned_amount) != "0.00" {
+		t.Errorf("split %+v, want 12.50 and 6.00 with nothing left", split)
+	}
+
+	split, err = api.client.SplitByItems(api.ctx, b.invoiceId, nil, guests([]string{b.burger}))
+	api.check(err)
+	if text(split.Unassigned_amount) != "6.00" {
+		t.Errorf("unassigned %s, want 6.00", text(split.Unassigned_amount))
+	}
+
+	refused := []struct {
+		name   string
+		split  client.ItemSplitRequest
+		status int
+	}{
+		{"item given twice", guests([]string{b.burger}, []string{b.burger}), http.StatusBadRequest},
+		{"item listed twice", guests([]string{b.burger, b.burger}), http.StatusBadRequest},
+		{"item of another invoice", guests([]string{"000000000000000000000000"}), http.StatusBadRequest},
+		//the document says so too, development mode answers with 422
+		{"guest without items", guests([]string{}), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range refused {
+		if _, err := api.client.SplitByItems(api.ctx, b.invoiceId, nil, tt.split); status(err) != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, status(err), tt.status)
+		}
+	}
+
+	//each guest pays their items, an item is paid once
+	receipt, err := api.client.CreatePayment(api.ctx, b.invoiceId, nil, client.Payment{Method: models.PaymentCard, Order_item_ids: []string{b.burger}})
+	api.check(err)
+	if text(receipt.Payment.Amount) != "12.50" || *receipt.Invoice.Payment_status != models.PaymentPartiallyPaid {
+		t.Errorf("burger paid %s, invoice %s, want 12.50 and partially paid", text(receipt.Payment.Amount), *receipt.Invoice.Payment_status)
+	}
+	if _, err := api.client.CreatePayment(api.ctx, b.invoiceId, nil, client.Payment{Method: models.PaymentCard, Order_item_ids: []string{b.burger}}); status(err) != http.StatusBadRequest {
+		t.Errorf("burger paid again: status %d, want 400", status(err))
+	}
+	if _, err := api.client.CreatePayment(api.ctx, b.invoiceId, nil, client.Payment{Method: models.PaymentCard, Order_item_ids: []string{b.colas}, Amount: amount("5.00")}); status(err) != http.StatusBadRequest {
+		t.Errorf("colas paid with another amount: status %d, want 400", status(err))
+	}
+
+	//paid items are left out of a new split
+	if _, err := api.client.SplitByItems(api.ctx, b.invoiceId, nil, guests([]string{b.burger})); status(err) != http.StatusBadRequest {
+		t.Errorf("split of a paid item: status %d, want 400", status(err))
+	}
+
+	receipt, err = api.client.CreatePayment(api.ctx, b.invoiceId, nil, client.Payment{Method: models.PaymentCash, Order_item_ids: []string{b.colas}, Tendered: amount("10.00")})
+	api.check(err)
+	if text(receipt.Payment.Amount) != "6.00" || text(receipt.Payment.Change_due) != "4.00" || *receipt.Invoice.Payment_status != models.PaymentPaid {
+		t.Errorf("colas paid %s with %s change, invoice %s, want 6.00, 4.00 and paid", text(receipt.Payment.Amount), text(receipt.Payment.Change_due), *receipt.Invoice.Payment_status)
+	}
+}
+
+// guests is a split giving each guest the order items listed for them
+func guests(orderItemIds ...[]string) client.ItemSplitRequest {
+	split := client.ItemSplitRequest{Guests: []json.RawMessage{}}
+	for _, ids := range orderItemIds {
+		guest, _ := json.Marshal(map[string][]string{"order_item_ids": ids})
+		split.Guests = append(split.Guests, guest)
+	}
+	return split
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PromotionRoutes(incomingRoutes *gin.RouterGroup, promotionController *controllers.PromotionController) {
+	incomingRoutes.GET("/promotions", middleware.Authorization(models.RoleManager, models.RoleCashier), promotionController.GetPromotions())
+	incomingRoutes.GET("/promotions/:promotion_id", middleware.Authorization(models.RoleManager, models.RoleCashier), promotionController.GetPromotion())
+	incomingRoutes.POST("/promotions", middleware.Authorization(models.RoleManager), promotionController.CreatePromotion())
+	incomingRoutes.PATCH("/promotions/:promotion_id", middleware.Authorization(models.RoleManager), promotionController.UpdatePromotion())
+	incomingRoutes.POST("/invoice/:invoice_id/coupons", middleware.Authorization(models.RoleManager, models.RoleCashier, models.RoleWaiter), promotionController.ApplyCoupon())
+	incomingRoutes.POST("/invoice/:invoice_id/comps", middleware.Authorization(models.RoleManager), promotionController.CompInvoice())
+}
+
+var promotionRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/promotions", Id: "getPromotions", Summary: "List promotions", Tag: "promotions", Roles: roles(models.RoleManager, models.RoleCashier), Query: controllers.ListParameters("/promotions"), Response: repository.Page[models.Promotion]{}},
+	{Method: http.MethodGet, Path: "/promotions/:promotion_id", Id: "getPromotion", Summary: "Get a promotion", Tag: "promotions", Roles: roles(models.RoleManager, models.RoleCashier), Response: models.Promotion{}},
+	{Method: http.MethodPost, Path: "/promotions", Id: "createPromotion", Summary: "Create a promotion", Tag: "promotions", Roles: roles(models.RoleManager), Body: models.Promotion{}, Response: models.Promotion{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPatch, Path: "/promotions/:promotion_id", Id: "updatePromotion", Summary: "Update a promotion", Tag: "promotions", Roles: roles(models.RoleManager), Body: models.Promotion{}, Response: models.Promotion{}},
+	{Method: http.MethodPost, Path: "/invoice/:invoice_id/coupons", Id: "applyCoupon", Summary: "Apply a coupon to an unpaid invoice", Tag: "promotions", Roles: roles(models.RoleManager, models.RoleCashier, models.RoleWaiter), Body: controllers.CouponRedemption{}, Response: models.Invoice{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/invoice/:invoice_id/comps", Id: "compInvoice", Summary: "Give away order items or an amount", Tag: "promotions", Roles: roles(models.RoleManager), Body: controllers.CompRequest{}, Response: models.Invoice{}, Errors: []int{http.StatusConflict}},
+}
@@ -0,0 +1,141 @@
+package routes_test
+
+import (
+	"net/http"
+	"restaurant-management/client"
+	"restaurant-management/models"
+	"testing"
+	"time"
+)
+
+func TestAutomaticPromotions(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	cola := api.food("Cola", "3.00")
+
+	percent := 10.0
+	buy, get := 1, 1
+	past := time.Now().Add(-time.Hour)
+	inactive := false
+	promotions := []client.Promotion{
+		{Name: "Burger week", Type: models.PromotionPercent, Percent: &percent, Food_ids: []string{burger}},
+		{Name: "Cola for two", Type: models.PromotionBuyXGetY, Buy_quantity: &buy, Get_quantity: &get, Food_ids: []string{cola}},
+		{Name: "Expired", Type: models.PromotionFixed, Amount: amount("5.00"), Expires_at: &past},
+		{Name: "Switched off", Type: models.PromotionFixed, Amount: amount("5.00"), Active: &inactive},
+	}
+	for _, promotion := range promotions {
+		_, err := api.client.CreatePromotion(api.ctx, nil, promotion)
+		api.check(err)
+	}
+
+	//two colas make one free, a single one does not
+	tests := []struct {
+		colas     int
+		discounts []string
+		total     string
+	}{
+		{2, []string{"Burger week 1.25", "Cola for two 3.00"}, "14.25"},
+		{1, []string{"Burger week 1.25"}, "14.25"},
+	}
+	for _, tt := range tests {
+		orderId, _ := api.servedOrder(orderLine{burger, 1}, orderLine{cola, tt.colas})
+		invoice := api.invoice(orderId)
+		if got := discountNames(invoice); !equalStrings(got, tt.discounts) || text(invoice.Total_amount) != tt.total {
+			t.Errorf("%d colas: discounts %q, total %s, want %q, %s", tt.colas, got, text(invoice.Total_amount), tt.discounts, tt.total)
+		}
+	}
+}
+
+func TestCoupons(t *testing.T) {
+	api := newAPI(t)
+	burger := api.food("Burger", "12.50")
+	cola := api.food("Cola", "3.00")
+	lines := []orderLine{{burger, 1}, {cola, 2}}
+
+	code := "SAVE2"
+	limit := 1
+	coupon, err := api.client.CreatePromotion(api.ctx, nil, client.Promotion{Name: "Two off", Type: models.PromotionFixed, Amount: amount("2.00"), Coupon_code: &code, Usage_limit: &limit})
+	api.check(err)
+	pizzaCode := "PIZZA"
+	pizza := "000000000000000000000000"
+	percent := 50.0
+	_, err = api.client.CreatePromotion(api.ctx, nil, client.Promotion{Name: "Half pizza", Type: models.PromotionPercent, Percent: &percent, Food_ids: []string{pizza}, Coupon_code: &pizzaCode})
+	api.check(err)
+
+	orderId, orderItemIds := api.servedOrder(lines...)
+	first := api.invoice(orderId)
+	if len(first.Discounts) != 0 {
+		t.Fatalf("coupon applied without its code: %q", discountNames(first))
+	}
+
+	//the coupon is spread over the items in proportion to their price
+	invoice, err := api.client.ApplyCoupon(api.ctx, *first.Invoice_id, nil, client.CouponRedemption{Coupon_code: code})
+	api.check(err)
+	if got := discountNames(invoice); !equalStrings(got, []string{"Two off 2.00"}) || text(invoice.Total_amount) != "16.50" || text(invoice.Balance_due) != "16.50" {
+		t.Errorf("discounts %q, total %s, balance %s, want [Two off 2.00], 16.50, 16.50", got, text(invoice.Total_amount), text(invoice.Balance_due))
+	}
+	shares := map[string]string{}
+	for _, line := range invoice.Discounts[0].Order_items {
+		shares[*line.Order_item_id] = text(line.Amount)
+	}
+	if shares[orderItemIds[0]] != "1.35" || shares[orderItemIds[1]] != "0.65" {
+		t.Errorf("discount per item %v, want 1.35 off the burger and 0.65 off the colas", shares)
+	}
+
+	orderId, _ = api.servedOrder(lines...)
+	second := api.invoice(orderId)
+
+	refused := []struct {
+		name    string
+		invoice string
+		code    string
+		status  int
+	}{
+		{"applied twice", *first.Invoice_id, code, http.StatusConflict},
+		{"used up", *second.Invoice_id, code, http.StatusConflict},
+		{"unknown code", *second.Invoice_id, "NOSUCH", http.StatusNotFound},
+		{"no item it applies to", *second.Invoice_id, pizzaCode, http.StatusBadRequest},
+	}
+	for _, tt := range refused {
+		if _, err := api.client.ApplyCoupon(api.ctx, tt.invoice, nil, client.CouponRedemption{Coupon_code: tt.code}); status(err) != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, status(err), tt.status)
+		}
+	}
+
+	promotion, err := api.client.GetPromotion(api.ctx, *coupon.Promotion_id)
+	api.check(err)
+	if *promotion.Times_used != 1 {
+		t.Errorf("coupon used %d times, want 1", *promotion.Times_used)
+	}
+
+	//paying the items one by one pays their discounted price
+	receipt, err := api.client.CreatePayment(api.ctx, *first.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Order_item_ids: orderItemIds[:1]})
+	api.check(err)
+	if text(receipt.Payment.Amount) != "11.15" {
+		t.Errorf("burger paid %s, want 11.15", text(receipt.Payment.Amount))
+	}
+	receipt, err = api.client.CreatePayment(api.ctx, *first.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Order_item_ids: orderItemIds[1:]})
+	api.check(err)
+	if text(receipt.Payment.Amount) != "5.35" || *receipt.Invoice.Payment_status != models.PaymentPaid {
+		t.Errorf("colas paid %s, invoice %s, want 5.35 and paid", text(receipt.Payment.Amount), *receipt.Invoice.Payment_status)
+	}
+
+	//nothing is taken off an invoice once it is being paid
+	limit = 5
+	_, err = api.client.UpdatePromotion(api.ctx, *coupon.Promotion_id, client.Promotion{Name: "Two off", Type: models.PromotionFixed, Usage_limit: &limit})
+	api.check(err)
+	_, err = api.client.CreatePayment(api.ctx, *second.Invoice_id, nil, client.Payment{Method: models.PaymentCard, Amount: amount("1.00")})
+	api.check(err)
+	if _, err := api.client.ApplyCoupon(api.ctx, *second.Invoice_id, nil, client.CouponRedemption{Coupon_code: code}); status(err) != http.StatusConflict {
+		t.Errorf("coupon after a payment: status %d, want 409", status(err))
+	}
+}
+
+// discountNames lists the discounts of an invoice as name and amount
+func discountNames(invoice client.Invoice) []string {
+	names := []string{}
+	for _, discount := range invoice.Discounts {
+		names = append(names, *discount.Name+" "+text(discount.Amount))
+	}
+	return names
+}
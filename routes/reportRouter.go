@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReportRoutes(incomingRoutes *gin.RouterGroup, reportController *controllers.ReportController) {
+	manager := middleware.Authorization(models.RoleManager)
+	incomingRoutes.GET("/reports/revenue/daily", manager, reportController.RevenueByDay())
+	incomingRoutes.GET("/reports/revenue/hourly", manager, reportController.RevenueByHour())
+	incomingRoutes.GET("/reports/foods/top", manager, reportController.TopFoods())
+	incomingRoutes.GET("/reports/categories", manager, reportController.RevenueByCategory())
+	incomingRoutes.GET("/reports/tickets", manager, reportController.AverageTicket())
+	incomingRoutes.GET("/reports/tables", manager, reportController.CoversByTable())
+	incomingRoutes.GET("/reports/payments", manager, reportController.PaymentMix())
+}
+
+// reportParams are taken by every report
+var reportParams = []openapi.Parameter{
+	queryParam("from", "a date in the restaurant timezone or an RFC 3339 time, 30 days before to by default", &openapi.Schema{Type: "string"}),
+	queryParam("to", "a date, included, or an RFC 3339 time, excluded, the end of today by default", &openapi.Schema{Type: "string"}),
+	queryParam("format", "json by default, csv downloads the rows", &openapi.Schema{Type: "string", Enum: []interface{}{"json", "csv"}}),
+}
+
+var reportRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/reports/revenue/daily", Id: "getRevenueByDay", Summary: "Revenue by day", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[[]models.RevenueRow]{}},
+	{Method: http.MethodGet, Path: "/reports/revenue/hourly", Id: "getRevenueByHour", Summary: "Revenue by hour of the day", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[[]models.RevenueRow]{}},
+	{
+		Method: http.MethodGet, Path: "/reports/foods/top", Id: "getTopFoods", Summary: "Foods by quantity sold", Tag: "reports", Roles: roles(models.RoleManager),
+		Query:    append([]openapi.Parameter{queryParam("limit", "how many foods, 10 by default", &openapi.Schema{Type: "integer"})}, reportParams...),
+		Response: controllers.Report[[]models.FoodSalesRow]{},
+	},
+	{Method: http.MethodGet, Path: "/reports/categories", Id: "getRevenueByCategory", Summary: "Revenue by menu category", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[[]models.CategorySalesRow]{}},
+	{Method: http.MethodGet, Path: "/reports/tickets", Id: "getAverageTicket", Summary: "Average ticket and spend per cover", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[models.TicketSummary]{}},
+	{Method: http.MethodGet, Path: "/reports/tables", Id: "getCoversByTable", Summary: "Covers and revenue by table", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[[]models.TableCoversRow]{}},
+	{Method: http.MethodGet, Path: "/reports/payments", Id: "getPaymentMix", Summary: "Payments by method", Tag: "reports", Roles: roles(models.RoleManager), Query: reportParams, Response: controllers.Report[[]models.PaymentMixRow]{}},
+}
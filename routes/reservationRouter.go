@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReservationRoutes(incomingRoutes *gin.RouterGroup, reservationController *controllers.ReservationController) {
+	incomingRoutes.GET("/reservations", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.GetReservations())
+	incomingRoutes.GET("/reservations/availability", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.GetAvailability())
+	incomingRoutes.GET("/reservation/:reservation_id", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.GetReservation())
+	incomingRoutes.POST("/reservation", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.CreateReservation())
+	incomingRoutes.PATCH("/reservation/:reservation_id", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.UpdateReservation())
+	incomingRoutes.POST("/reservation/:reservation_id/seat", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.SeatReservation())
+	incomingRoutes.POST("/reservation/:reservation_id/cancel", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.CloseReservation(models.ReservationCancelled))
+	incomingRoutes.POST("/reservation/:reservation_id/no-show", middleware.Authorization(models.RoleManager, models.RoleWaiter), reservationController.CloseReservation(models.ReservationNoShow))
+}
+
+var reservationRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/reservations", Id: "getReservations", Summary: "List reservations", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Query: controllers.ListParameters("/reservations"), Response: repository.Page[models.Reservation]{}},
+	{
+		Method: http.MethodGet, Path: "/reservations/availability", Id: "getAvailability", Summary: "Find free tables for a party", Description: "A table with an open order is taken for a table turn from now.", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter),
+		Query: []openapi.Parameter{
+			requiredQueryParam("party_size", "how many guests", &openapi.Schema{Type: "integer"}),
+			requiredQueryParam("time", "when the party arrives", &openapi.Schema{Type: "string", Format: "date-time"}),
+			queryParam("duration_minutes", "how long the table is needed, at least 15", &openapi.Schema{Type: "integer"}),
+		},
+		Response: controllers.Availability{},
+	},
+	{Method: http.MethodGet, Path: "/reservation/:reservation_id", Id: "getReservation", Summary: "Get a reservation", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Reservation{}},
+	{Method: http.MethodPost, Path: "/reservation", Id: "createReservation", Summary: "Book a table", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Body: models.Reservation{}, Response: models.Reservation{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPatch, Path: "/reservation/:reservation_id", Id: "updateReservation", Summary: "Update a reservation", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Body: models.Reservation{}, Response: models.Reservation{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/reservation/:reservation_id/seat", Id: "seatReservation", Summary: "Seat a reservation and open its order", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Response: controllers.SeatedReservation{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/reservation/:reservation_id/cancel", Id: "cancelReservation", Summary: "Cancel a reservation", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Reservation{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/reservation/:reservation_id/no-show", Id: "markReservationNoShow", Summary: "Mark a reservation as a no-show", Tag: "reservations", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.Reservation{}, Errors: []int{http.StatusConflict}},
+}
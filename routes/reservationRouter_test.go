@@ -0,0 +1,46 @@
+package routes_test
+
+import (
+	"net/http"
+	"restaurant-management/client"
+	"testing"
+	"time"
+)
+
+// TestWalkInTables checks that a table with guests at an open order is not
+// offered or booked for the next table turn, and is once they are gone
+func TestWalkInTables(t *testing.T) {
+	api := newAPI(t)
+	orderId, _ := api.order(orderLine{api.food("Burger", "12.50"), 1})
+
+	soon := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	tomorrow := soon.Add(24 * time.Hour)
+	free := func(at time.Time) bool {
+		t.Helper()
+		availability, err := api.client.GetAvailability(api.ctx, &client.GetAvailabilityParams{Party_size: 2, Time: at})
+		api.check(err)
+		for _, table := range availability.Tables {
+			if *table.Table_id == api.table {
+				return true
+			}
+		}
+		return false
+	}
+
+	if free(soon) {
+		t.Error("table with an open order is offered in 30 minutes")
+	}
+	if !free(tomorrow) {
+		t.Error("table with an open order is not offered tomorrow")
+	}
+	_, err := api.client.CreateReservation(api.ctx, nil, client.Reservation{Guest_name: "Early", Phone: "5550111", Party_size: 2, Reservation_time: soon})
+	if status(err) != http.StatusConflict {
+		t.Errorf("booking in 30 minutes: status %d (%v), want 409", status(err), err)
+	}
+
+	_, err = api.client.CancelOrder(api.ctx, orderId, nil)
+	api.check(err)
+	if !free(soon) {
+		t.Error("table is not offered in 30 minutes once its order is cancelled")
+	}
+}
@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"restaurant-management/apierror"
+	"restaurant-management/blobstore"
+	"restaurant-management/config"
+	controllers "restaurant-management/controllers"
+	"restaurant-management/helpers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter builds the whole HTTP API on top of the given storage, main
+// passes the MongoDB repositories and tests can pass the in-memory ones.
+// Uploaded images go to the images store.
+func NewRouter(repos repository.Repositories, images blobstore.Store, cfg config.Config) *gin.Engine {
+	timeout := cfg.Server.Request_timeout.Duration
+
+	router := gin.New()
+	router.Use(gin.Logger())
+	//errors added with c.Error are rendered as one JSON shape, see docs/errors.md
+	router.Use(middleware.ErrorHandler())
+
+	//the document is built once every route is registered, the validator
+	//only reads it while serving
+	doc := &openapi.Document{}
+	if cfg.Server.Mode == config.ModeDevelopment {
+		router.Use(openapi.Validator(doc))
+	}
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apierror.NotFound("route was not found"))
+	})
+
+	//signup, login and menu browsing are open to guests, everything else needs a token.
+	//a POST sent again with the same Idempotency-Key gets the first answer, a
+	//request is cut off by the write timeout so a key pending longer than
+	//that belongs to a server that stopped. No body is larger than an image
+	//upload
+	public := router.Group("/")
+	public.Use(middleware.OptionalAuthentication(repos.Users, timeout))
+	protected := router.Group("/")
+	protected.Use(middleware.Authentication(repos.Users, timeout), middleware.Idempotency(repos.Idempotency, cfg.Server.Idempotency_key_lifetime.Duration, cfg.Server.Write_timeout.Duration, timeout, controllers.MaxImageUpload(cfg.Images)))
+
+	kitchenBroker := helpers.NewKitchenBroker()
+
+	UserRoutes(public, protected, controllers.NewUserController(repos.Users, cfg.Auth.Bcrypt_cost, timeout))
+	FoodRoutes(public, protected, controllers.NewFoodController(repos.Foods, repos.Menus, repos.Ingredients, images, cfg.Images, timeout))
+	ImageRoutes(public, controllers.NewImageController(images, timeout))
+	InvoiceRoutes(protected, controllers.NewInvoiceController(repos.Invoices, repos.Orders, repos.OrderItems, repos.Payments, repos.TaxRates, repos.TaxRules, repos.Promotions, repos.ZReports, cfg.Restaurant, timeout))
+	PaymentRoutes(protected, controllers.NewPaymentController(repos.Payments, repos.Invoices, repos.Orders, repos.OrderItems, timeout))
+	DrawerRoutes(protected, controllers.NewDrawerController(repos.Drawers, repos.ZReports, repos.Reports, cfg.Restaurant.Location(), timeout))
+	MenuRoutes(public, protected, controllers.NewMenuController(repos.Menus, cfg.Restaurant.Location(), timeout))
+	PromotionRoutes(protected, controllers.NewPromotionController(repos.Promotions, repos.Invoices, repos.OrderItems, repos.TaxRates, repos.TaxRules, repos.ZReports, cfg.Restaurant.Jurisdiction, timeout))
+	IngredientRoutes(protected, controllers.NewIngredientController(repos.Ingredients, timeout))
+	TaxRoutes(protected, controllers.NewTaxController(repos.TaxRates, repos.TaxRules, repos.Foods, timeout))
+	NoteRoutes(protected, controllers.NewNoteController(repos.Notes, repos.Foods, repos.Orders, repos.OrderItems, repos.Tables, repos.Reservations, kitchenBroker, timeout))
+	OrderItemRoutes(protected, controllers.NewOrderItemController(repos.OrderItems, repos.Orders, repos.Invoices, repos.Foods, repos.Menus, repos.Ingredients, repos.Notes, kitchenBroker, cfg.Restaurant.Location(), timeout))
+	TableRoutes(protected, controllers.NewTableController(repos.Tables, timeout))
+	OrderRoutes(protected, controllers.NewOrderController(repos.Orders, repos.Tables, repos.OrderItems, repos.Ingredients, repos.Invoices, repos.ZReports, kitchenBroker, timeout))
+	ReportRoutes(protected, controllers.NewReportController(repos.Reports, cfg.Restaurant.Location(), timeout))
+	ReservationRoutes(protected, controllers.NewReservationController(repos.Reservations, repos.Tables, repos.Orders, cfg.Reservations.Default_duration.Duration, cfg.Reservations.Table_turn_time.Duration, timeout))
+	WaitlistRoutes(protected, controllers.NewWaitlistController(repos.Waitlist, repos.Reservations, repos.Tables, repos.Orders, cfg.Reservations.Table_turn_time.Duration, timeout))
+	KitchenRoutes(protected, controllers.NewKitchenController(repos.OrderItems, repos.Foods, repos.Orders, repos.Tables, repos.Notes, kitchenBroker, timeout, cfg.Server.Write_timeout.Duration*9/10))
+
+	router.GET(OpenAPIPath, openapi.Handler(doc))
+	router.GET(SwaggerUIPath, openapi.SwaggerUI("Restaurant management API", OpenAPIPath))
+	*doc = *Document(router)
+
+	return router
+}
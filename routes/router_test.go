@@ -0,0 +1,163 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"restaurant-management/client"
+	"restaurant-management/config"
+	"restaurant-management/helpers"
+	"restaurant-management/repository/memory"
+	"restaurant-management/routes"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	helpers.Configure("test secret", 30*time.Minute, 24*time.Hour)
+	os.Exit(m.Run())
+}
+
+// testAPI is the whole API on in-memory storage, called through the
+// generated client as the admin who signed up first
+type testAPI struct {
+	t      *testing.T
+	ctx    context.Context
+	client *client.Client
+	menuId string
+	table  string
+}
+
+// newServer serves the API on in-memory storage with nobody signed up
+func newServer(t *testing.T) string {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	//requests and answers are checked against the OpenAPI document too
+	cfg.Server.Mode = config.ModeDevelopment
+	cfg.Auth.Bcrypt_cost = bcrypt.MinCost
+
+	server := httptest.NewServer(routes.NewRouter(memory.NewRepositories(), nil, cfg))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newAPI(t *testing.T) *testAPI {
+	t.Helper()
+	api := &testAPI{t: t, ctx: context.Background(), client: client.New(newServer(t))}
+	admin := client.User{First_name: "Ada", Last_name: "Admin", Email: "ada@example.com", Password: "secret123", Phone: "5550100"}
+	if _, err := api.client.Signup(api.ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+	api.login(admin)
+
+	menu, err := api.client.CreateMenu(api.ctx, nil, client.Menu{Name: "All day", Category: "Mains"})
+	api.check(err)
+	api.menuId = *menu.Menu_id
+	table, err := api.client.CreateTable(api.ctx, nil, client.Table{Number_of_guests: 4, Table_number: 1})
+	api.check(err)
+	api.table = *table.Table_id
+	return api
+}
+
+// login makes the calls of api as user from now on
+func (api *testAPI) login(user client.User) {
+	api.t.Helper()
+	tokens, err := api.client.Login(api.ctx, client.LoginRequest{Email: user.Email, Password: user.Password})
+	api.check(err)
+	api.client.Token = *tokens.Token
+}
+
+// check stops the test when a call that has to work failed
+func (api *testAPI) check(err error) {
+	api.t.Helper()
+	if err != nil {
+		api.t.Fatal(err)
+	}
+}
+
+// food adds a food to the menu and returns its food_id
+func (api *testAPI) food(name string, price string) string {
+	api.t.Helper()
+	food, err := api.client.CreateFood(api.ctx, nil, client.Food{Name: name, Price: json.Number(price), Menu_id: api.menuId})
+	api.check(err)
+	return *food.Food_id
+}
+
+type orderLine struct {
+	food     string
+	quantity int
+}
+
+// order places an order of lines and returns its order_id and the
+// order_item_id of each line
+func (api *testAPI) order(lines ...orderLine) (string, []string) {
+	api.t.Helper()
+	pack := client.OrderItemPack{Table_id: &api.table}
+	for _, line := range lines {
+		pack.Order_items = append(pack.Order_items, client.OrderItem{Food_id: line.food, Quantity: line.quantity})
+	}
+	orderItems, err := api.client.CreateOrderItems(api.ctx, nil, pack)
+	api.check(err)
+	orderItemIds := []string{}
+	for _, orderItem := range orderItems {
+		orderItemIds = append(orderItemIds, *orderItem.Order_item_id)
+	}
+	return *orderItems[0].Order_id, orderItemIds
+}
+
+// servedOrder is order taken through the kitchen to the table
+func (api *testAPI) servedOrder(lines ...orderLine) (string, []string) {
+	api.t.Helper()
+	orderId, orderItemIds := api.order(lines...)
+	_, err := api.client.AcceptOrder(api.ctx, orderId, nil)
+	api.check(err)
+	_, err = api.client.PrepareOrder(api.ctx, orderId, nil)
+	api.check(err)
+	_, err = api.client.ReadyOrder(api.ctx, orderId, nil)
+	api.check(err)
+	_, err = api.client.ServeOrder(api.ctx, orderId, nil)
+	api.check(err)
+	return orderId, orderItemIds
+}
+
+// invoice bills a served order
+func (api *testAPI) invoice(orderId string) client.Invoice {
+	api.t.Helper()
+	invoice, err := api.client.CreateInvoice(api.ctx, nil, client.Invoice{Order_id: &orderId})
+	api.check(err)
+	return invoice
+}
+
+func amount(text string) *json.Number {
+	number := json.Number(text)
+	return &number
+}
+
+// text is an amount of an answer, amounts left out read as <nil>
+func text(value *json.Number) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return value.String()
+}
+
+// status is the HTTP status of a failed call, 200 when it worked
+func status(err error) int {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	if err != nil {
+		return 0
+	}
+	return 200
+}
@@ -1,14 +1,26 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func TableRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/table", controllers.GetTables())
-	incomingRoutes.GET("/table/:table_id", controllers.GetTable())
-	incomingRoutes.POST("/table", controllers.CreateTable())
-	incomingRoutes.PATCH("/table/:table_id", controllers.UpdateTable())
+func TableRoutes(incomingRoutes *gin.RouterGroup, tableController *controllers.TableController) {
+	incomingRoutes.GET("/table", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCashier), tableController.GetTables())
+	incomingRoutes.GET("/table/:table_id", middleware.Authorization(models.RoleManager, models.RoleWaiter, models.RoleCashier), tableController.GetTable())
+	incomingRoutes.POST("/table", middleware.Authorization(models.RoleManager), tableController.CreateTable())
+	incomingRoutes.PATCH("/table/:table_id", middleware.Authorization(models.RoleManager), tableController.UpdateTable())
+}
+
+var tableRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/table", Id: "getTables", Summary: "List tables", Tag: "tables", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCashier), Query: controllers.ListParameters("/table"), Response: repository.Page[models.Table]{}},
+	{Method: http.MethodGet, Path: "/table/:table_id", Id: "getTable", Summary: "Get a table", Tag: "tables", Roles: roles(models.RoleManager, models.RoleWaiter, models.RoleCashier), Response: models.Table{}},
+	{Method: http.MethodPost, Path: "/table", Id: "createTable", Summary: "Create a table", Tag: "tables", Roles: roles(models.RoleManager), Body: models.Table{}, Response: models.Table{}},
+	{Method: http.MethodPatch, Path: "/table/:table_id", Id: "updateTable", Summary: "Update a table", Tag: "tables", Roles: roles(models.RoleManager), Body: models.Table{}, Response: models.Table{}},
 }
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TaxRoutes(incomingRoutes *gin.RouterGroup, taxController *controllers.TaxController) {
+	manager := middleware.Authorization(models.RoleManager)
+	incomingRoutes.GET("/taxRates", manager, taxController.GetTaxRates())
+	incomingRoutes.GET("/taxRates/:tax_rate_id", manager, taxController.GetTaxRate())
+	incomingRoutes.POST("/taxRates", manager, taxController.CreateTaxRate())
+	incomingRoutes.PATCH("/taxRates/:tax_rate_id", manager, taxController.UpdateTaxRate())
+	incomingRoutes.GET("/taxRules", manager, taxController.GetTaxRules())
+	incomingRoutes.GET("/taxRules/:tax_rule_id", manager, taxController.GetTaxRule())
+	incomingRoutes.POST("/taxRules", manager, taxController.CreateTaxRule())
+	incomingRoutes.PATCH("/taxRules/:tax_rule_id", manager, taxController.UpdateTaxRule())
+}
+
+var taxRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/taxRates", Id: "getTaxRates", Summary: "List tax rates", Tag: "taxes", Roles: roles(models.RoleManager), Query: controllers.ListParameters("/taxRates"), Response: repository.Page[models.TaxRate]{}},
+	{Method: http.MethodGet, Path: "/taxRates/:tax_rate_id", Id: "getTaxRate", Summary: "Get a tax rate", Tag: "taxes", Roles: roles(models.RoleManager), Response: models.TaxRate{}},
+	{Method: http.MethodPost, Path: "/taxRates", Id: "createTaxRate", Summary: "Create a tax rate", Tag: "taxes", Roles: roles(models.RoleManager), Body: models.TaxRate{}, Response: models.TaxRate{}},
+	{Method: http.MethodPatch, Path: "/taxRates/:tax_rate_id", Id: "updateTaxRate", Summary: "Update a tax rate", Tag: "taxes", Roles: roles(models.RoleManager), Body: models.TaxRate{}, Response: models.TaxRate{}},
+	{Method: http.MethodGet, Path: "/taxRules", Id: "getTaxRules", Summary: "List tax rules", Tag: "taxes", Roles: roles(models.RoleManager), Query: controllers.ListParameters("/taxRules"), Response: repository.Page[models.TaxRule]{}},
+	{Method: http.MethodGet, Path: "/taxRules/:tax_rule_id", Id: "getTaxRule", Summary: "Get a tax rule", Tag: "taxes", Roles: roles(models.RoleManager), Response: models.TaxRule{}},
+	{Method: http.MethodPost, Path: "/taxRules", Id: "createTaxRule", Summary: "Create a tax rule", Tag: "taxes", Roles: roles(models.RoleManager), Body: models.TaxRule{}, Response: models.TaxRule{}},
+	{Method: http.MethodPatch, Path: "/taxRules/:tax_rule_id", Id: "updateTaxRule", Summary: "Update a tax rule", Tag: "taxes", Roles: roles(models.RoleManager), Body: models.TaxRule{}, Response: models.TaxRule{}},
+}
@@ -0,0 +1,76 @@
+package routes_test
+
+import (
+	"restaurant-management/client"
+	"testing"
+)
+
+func TestInvoiceTax(t *testing.T) {
+	api := newAPI(t)
+	drinks, err := api.client.CreateMenu(api.ctx, nil, client.Menu{Name: "Drinks", Category: "Drinks"})
+	api.check(err)
+	burger := api.food("Burger", "10.00")
+	cola, err := api.client.CreateFood(api.ctx, nil, client.Food{Name: "Cola", Price: "6.00", Menu_id: *drinks.Menu_id})
+	api.check(err)
+	water, err := api.client.CreateFood(api.ctx, nil, client.Food{Name: "Water", Price: "5.00", Menu_id: *drinks.Menu_id})
+	api.check(err)
+	lines := []orderLine{{burger, 1}, {*cola.Food_id, 1}, {*water.Food_id, 1}}
+
+	rate := func(name string, percent float64, inclusive bool) string {
+		rate, err := api.client.CreateTaxRate(api.ctx, nil, client.TaxRate{Name: name, Rate: percent, Inclusive: inclusive})
+		api.check(err)
+		return *rate.Tax_rate_id
+	}
+	rule := func(rule client.TaxRule) {
+		_, err := api.client.CreateTaxRule(api.ctx, nil, rule)
+		api.check(err)
+	}
+	sales := rate("Sales tax", 8.25, false)
+	vat := rate("VAT", 20, true)
+	reduced := rate("Reduced", 10, false)
+
+	check := func(step string, net string, tax string, total string, breakdown ...string) {
+		t.Helper()
+		orderId, _ := api.servedOrder(lines...)
+		invoice := api.invoice(orderId)
+		got := []string{}
+		for _, line := range invoice.Tax_breakdown {
+			got = append(got, *line.Name+" "+text(line.Taxable_amount)+" "+text(line.Tax_amount))
+		}
+		if text(invoice.Net_amount) != net || text(invoice.Tax_amount) != tax || text(invoice.Total_amount) != total || text(invoice.Balance_due) != total || !equalStrings(got, breakdown) {
+			t.Errorf("%s: net %s, tax %s, total %s, breakdown %q, want %s, %s, %s, %q", step,
+				text(invoice.Net_amount), text(invoice.Tax_amount), text(invoice.Total_amount), got, net, tax, total, breakdown)
+		}
+	}
+
+	check("untaxed", "21.00", "0.00", "21.00")
+
+	rule(client.TaxRule{Tax_rate_id: sales})
+	check("default rule", "21.00", "1.73", "22.73", "Sales tax 21.00 1.73")
+
+	//drinks are taxed by their category instead, VAT is in their price
+	category := "drinks"
+	rule(client.TaxRule{Tax_rate_id: vat, Category: &category})
+	check("category rule", "19.17", "2.66", "21.83", "Sales tax 10.00 0.83", "VAT 9.17 1.83")
+
+	//and water by its own rule
+	rule(client.TaxRule{Tax_rate_id: reduced, Food_id: water.Food_id})
+	check("food rule", "20.00", "2.33", "22.33", "Reduced 5.00 0.50", "Sales tax 10.00 0.83", "VAT 5.00 1.00")
+
+	inactive := false
+	_, err = api.client.UpdateTaxRate(api.ctx, reduced, client.TaxRate{Name: "Reduced", Rate: 10, Active: &inactive})
+	api.check(err)
+	//the rule of an inactive rate is skipped, water is taxed as a drink again
+	check("inactive rate", "19.17", "2.66", "21.83", "Sales tax 10.00 0.83", "VAT 9.17 1.83")
+
+	//an invoice keeps the taxes it was created with
+	orderId, _ := api.servedOrder(lines...)
+	invoice := api.invoice(orderId)
+	_, err = api.client.UpdateTaxRate(api.ctx, sales, client.TaxRate{Name: "Sales tax", Rate: 50})
+	api.check(err)
+	view, err := api.client.GetInvoice(api.ctx, *invoice.Invoice_id)
+	api.check(err)
+	if text(view.Total_amount) != "21.83" {
+		t.Errorf("total after the rate changed %s, want 21.83", text(view.Total_amount))
+	}
+}
@@ -1,14 +1,34 @@
 package routes
 
 import (
+	"net/http"
 	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UserRoutes(incomingRoutes *gin.Engine) {
-	incomingRoutes.GET("/users", controllers.GetUsers())
-	incomingRoutes.GET("/user/:user_id", controllers.GetUser())
-	incomingRoutes.POST("/user/signup", controllers.Sugnup())
-	incomingRoutes.POST("/user/login", controllers.Login())
+func UserRoutes(publicRoutes *gin.RouterGroup, incomingRoutes *gin.RouterGroup, userController *controllers.UserController) {
+	publicRoutes.POST("/user/signup", userController.Sugnup())
+	publicRoutes.POST("/user/login", userController.Login())
+	publicRoutes.POST("/user/refresh", userController.RefreshToken())
+	incomingRoutes.POST("/user/logout", userController.Logout())
+	incomingRoutes.POST("/user/:user_id/revoke", middleware.Authorization(), userController.RevokeUserSessions())
+	incomingRoutes.GET("/users", middleware.Authorization(models.RoleManager), userController.GetUsers())
+	incomingRoutes.GET("/user/:user_id", middleware.Authorization(models.RoleManager), userController.GetUser())
+	incomingRoutes.PATCH("/user/:user_id/role", middleware.Authorization(), userController.UpdateUserRole())
+}
+
+var userRouteDocs = []openapi.Route{
+	{Method: http.MethodPost, Path: "/user/signup", Id: "signup", Summary: "Create an account", Tag: "users", Public: true, Body: models.User{}, Response: controllers.SignupResult{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/user/login", Id: "login", Summary: "Sign in and get a token", Description: "A user has one session at a time, signing in again ends the previous one.", Tag: "users", Public: true, Body: controllers.LoginRequest{}, Response: controllers.TokenPair{}, Errors: []int{http.StatusUnauthorized}},
+	{Method: http.MethodPost, Path: "/user/refresh", Id: "refreshToken", Summary: "Trade a refresh token for a new pair", Tag: "users", Public: true, Body: controllers.RefreshRequest{}, Response: controllers.TokenPair{}, Errors: []int{http.StatusUnauthorized}},
+	{Method: http.MethodPost, Path: "/user/logout", Id: "logout", Summary: "Sign out of this session", Tag: "users", Response: controllers.Message{}},
+	{Method: http.MethodPost, Path: "/user/:user_id/revoke", Id: "revokeUserSessions", Summary: "Sign a user out everywhere", Tag: "users", Roles: roles(), Response: controllers.Message{}},
+	{Method: http.MethodGet, Path: "/users", Id: "getUsers", Summary: "List users", Tag: "users", Roles: roles(models.RoleManager), Query: controllers.ListParameters("/users"), Response: repository.Page[models.UserProfile]{}},
+	{Method: http.MethodGet, Path: "/user/:user_id", Id: "getUser", Summary: "Get a user", Tag: "users", Roles: roles(models.RoleManager), Response: models.UserProfile{}},
+	{Method: http.MethodPatch, Path: "/user/:user_id/role", Id: "updateUserRole", Summary: "Change the role of a user", Tag: "users", Roles: roles(), Body: models.User{}, Response: models.UserProfile{}},
 }
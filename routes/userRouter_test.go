@@ -0,0 +1,99 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"restaurant-management/client"
+	"sync"
+	"testing"
+)
+
+// TestFirstSignups checks that of users signing up at once on a new
+// system exactly one becomes the admin and the others get no role
+func TestFirstSignups(t *testing.T) {
+	url := newServer(t)
+	users := []client.User{}
+	for i := 0; i < 8; i++ {
+		users = append(users, client.User{First_name: "Staff", Last_name: "Member", Email: fmt.Sprintf("staff%d@example.com", i), Password: "secret123", Phone: fmt.Sprintf("555010%d", i)})
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(users))
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user client.User) {
+			defer wg.Done()
+			_, errs[i] = client.New(url).Signup(context.Background(), user)
+		}(i, user)
+	}
+	wg.Wait()
+
+	admins := 0
+	for i, user := range users {
+		if errs[i] != nil {
+			t.Fatalf("%s: %v", user.Email, errs[i])
+		}
+		api := &testAPI{t: t, ctx: context.Background(), client: client.New(url)}
+		api.login(user)
+		_, err := api.client.GetUsers(api.ctx, nil)
+		switch status(err) {
+		case http.StatusOK:
+			admins++
+		case http.StatusForbidden:
+		default:
+			t.Errorf("%s: status %d (%v), want 200 or 403", user.Email, status(err), err)
+		}
+	}
+	if admins != 1 {
+		t.Errorf("%d admins, want 1", admins)
+	}
+}
+
+// TestNoRole checks that a user nobody gave a role yet cannot read the
+// orders and tables of the restaurant
+func TestNoRole(t *testing.T) {
+	api := newAPI(t)
+	orderId, orderItemIds := api.order(orderLine{api.food("Burger", "12.50"), 1})
+
+	newcomer := client.User{First_name: "New", Last_name: "Comer", Email: "new@example.com", Password: "secret123", Phone: "5550199"}
+	_, err := api.client.Signup(api.ctx, newcomer)
+	api.check(err)
+	api.login(newcomer)
+
+	reads := map[string]func() error{
+		"orders": func() error {
+			_, err := api.client.GetOrders(api.ctx, nil)
+			return err
+		},
+		"order": func() error {
+			_, err := api.client.GetOrder(api.ctx, orderId)
+			return err
+		},
+		"order items": func() error {
+			_, err := api.client.GetOrderItems(api.ctx, nil)
+			return err
+		},
+		"order item": func() error {
+			_, err := api.client.GetOrderItem(api.ctx, orderItemIds[0])
+			return err
+		},
+		"order items of an order": func() error {
+			_, err := api.client.GetOrderItemsByOrder(api.ctx, orderId)
+			return err
+		},
+		"tables": func() error {
+			_, err := api.client.GetTables(api.ctx, nil)
+			return err
+		},
+		"table": func() error {
+			_, err := api.client.GetTable(api.ctx, api.table)
+			return err
+		},
+	}
+	for name, read := range reads {
+		if err := read(); status(err) != http.StatusForbidden {
+			t.Errorf("%s: status %d (%v), want 403", name, status(err), err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"net/http"
+	controllers "restaurant-management/controllers"
+	middleware "restaurant-management/middleware"
+	"restaurant-management/models"
+	"restaurant-management/openapi"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WaitlistRoutes(incomingRoutes *gin.RouterGroup, waitlistController *controllers.WaitlistController) {
+	incomingRoutes.GET("/waitlist", middleware.Authorization(models.RoleManager, models.RoleWaiter), waitlistController.GetWaitlist())
+	incomingRoutes.POST("/waitlist", middleware.Authorization(models.RoleManager, models.RoleWaiter), waitlistController.AddToWaitlist())
+	incomingRoutes.POST("/waitlist/:waitlist_id/seat", middleware.Authorization(models.RoleManager, models.RoleWaiter), waitlistController.SeatWaitlistEntry())
+	incomingRoutes.POST("/waitlist/:waitlist_id/leave", middleware.Authorization(models.RoleManager, models.RoleWaiter), waitlistController.LeaveWaitlist())
+}
+
+var waitlistRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/waitlist", Id: "getWaitlist", Summary: "List the waiting parties with a wait estimate", Tag: "waitlist", Roles: roles(models.RoleManager, models.RoleWaiter), Query: controllers.ListParameters("/waitlist"), Response: repository.Page[models.WaitlistEntry]{}},
+	{Method: http.MethodPost, Path: "/waitlist", Id: "addToWaitlist", Summary: "Add a walk-in party to the waitlist", Tag: "waitlist", Roles: roles(models.RoleManager, models.RoleWaiter), Body: models.WaitlistEntry{}, Response: models.WaitlistEntry{}},
+	{Method: http.MethodPost, Path: "/waitlist/:waitlist_id/seat", Id: "seatWaitlistEntry", Summary: "Seat a waiting party and open its order", Tag: "waitlist", Roles: roles(models.RoleManager, models.RoleWaiter), Body: controllers.WaitlistSeating{}, Response: controllers.SeatedWaitlistEntry{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/waitlist/:waitlist_id/leave", Id: "leaveWaitlist", Summary: "Take a party off the waitlist", Tag: "waitlist", Roles: roles(models.RoleManager, models.RoleWaiter), Response: models.WaitlistEntry{}, Errors: []int{http.StatusConflict}},
+}
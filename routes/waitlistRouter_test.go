@@ -0,0 +1,54 @@
+package routes_test
+
+import (
+	"restaurant-management/client"
+	"testing"
+)
+
+// TestWaitlistPages checks that a page of the waitlist estimates each wait
+// from the place in the whole queue, whatever the page is sorted by
+func TestWaitlistPages(t *testing.T) {
+	api := newAPI(t)
+	for _, name := range []string{"First", "Second", "Third"} {
+		_, err := api.client.AddToWaitlist(api.ctx, nil, client.WaitlistEntry{Guest_name: name, Party_size: 4})
+		api.check(err)
+	}
+
+	queue, err := api.client.GetWaitlist(api.ctx, nil)
+	api.check(err)
+	if len(queue.Items) != 3 || queue.Items[0].Guest_name != "First" {
+		t.Fatalf("waitlist %+v, want the three parties first come first", queue.Items)
+	}
+	estimates := map[string]int{}
+	for _, entry := range queue.Items {
+		estimates[*entry.Waitlist_id] = intValue(entry.Estimated_wait_minutes)
+	}
+
+	sort, limit := "-created_at", 2
+	params := &client.GetWaitlistParams{Sort: &sort, Limit: &limit}
+	seen := 0
+	for {
+		page, err := api.client.GetWaitlist(api.ctx, params)
+		api.check(err)
+		for _, entry := range page.Items {
+			seen++
+			if got := intValue(entry.Estimated_wait_minutes); got != estimates[*entry.Waitlist_id] {
+				t.Errorf("%s waits %d minutes on a page, %d in the queue", entry.Guest_name, got, estimates[*entry.Waitlist_id])
+			}
+		}
+		if !*page.Has_more {
+			break
+		}
+		params.Cursor = page.Next_cursor
+	}
+	if seen != 3 {
+		t.Errorf("%d parties on the pages, want 3", seen)
+	}
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}